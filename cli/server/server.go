@@ -581,6 +581,17 @@ func initBlockChain(cfg config.Config, log *zap.Logger) (*core.Blockchain, error
 	if err != nil {
 		return nil, cli.NewExitError(fmt.Errorf("could not initialize blockchain: %w", err), 1)
 	}
+	chain.SetMPTNodeCacheSize(cfg.ApplicationConfiguration.MPTNodeCacheSize)
+	if cfg.ApplicationConfiguration.ColdDBConfiguration.Type != "" {
+		cold, err := storage.NewStore(cfg.ApplicationConfiguration.ColdDBConfiguration)
+		if err != nil {
+			return nil, cli.NewExitError(fmt.Errorf("could not initialize cold storage: %w", err), 1)
+		}
+		err = chain.SetColdStorage(cold)
+		if err != nil {
+			return nil, cli.NewExitError(fmt.Errorf("could not set cold storage: %w", err), 1)
+		}
+	}
 	return chain, nil
 }
 
@@ -11,34 +11,55 @@ import (
 	"gopkg.in/yaml.v2"
 )
 
+var generatorFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "config, c",
+		Usage: "Configuration file to use",
+	},
+	cli.StringFlag{
+		Name:  "manifest, m",
+		Usage: "Read contract manifest (*.manifest.json) file",
+	},
+	cli.StringFlag{
+		Name:  "out, o",
+		Usage: "Output of the compiled contract",
+	},
+	cli.StringFlag{
+		Name:  "hash",
+		Usage: "Smart-contract hash",
+	},
+}
+
 var generateWrapperCmd = cli.Command{
 	Name:        "generate-wrapper",
 	Usage:       "generate wrapper to use in other contracts",
 	UsageText:   "neo-go contract generate-wrapper --manifest <file.json> --out <file.go> --hash <hash>",
 	Description: ``,
 	Action:      contractGenerateWrapper,
-	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name:  "config, c",
-			Usage: "Configuration file to use",
-		},
-		cli.StringFlag{
-			Name:  "manifest, m",
-			Usage: "Read contract manifest (*.manifest.json) file",
-		},
-		cli.StringFlag{
-			Name:  "out, o",
-			Usage: "Output of the compiled contract",
-		},
-		cli.StringFlag{
-			Name:  "hash",
-			Usage: "Smart-contract hash",
-		},
-	},
+	Flags:       generatorFlags,
 }
 
-// contractGenerateWrapper deploys contract.
+var generateRPCWrapperCmd = cli.Command{
+	Name:        "generate-rpcwrapper",
+	Usage:       "generate RPC wrapper to interact with contract from Go code",
+	UsageText:   "neo-go contract generate-rpcwrapper --manifest <file.json> --out <file.go> --hash <hash>",
+	Description: ``,
+	Action:      contractGenerateRPCWrapper,
+	Flags:       generatorFlags,
+}
+
+// contractGenerateWrapper generates on-chain contract wrapper.
 func contractGenerateWrapper(ctx *cli.Context) error {
+	return contractGenerateSomething(ctx, false)
+}
+
+// contractGenerateRPCWrapper generates RPC contract wrapper.
+func contractGenerateRPCWrapper(ctx *cli.Context) error {
+	return contractGenerateSomething(ctx, true)
+}
+
+// contractGenerateSomething reads generator parameters and calls the generator.
+func contractGenerateSomething(ctx *cli.Context, rpc bool) error {
 	m, _, err := readManifest(ctx.String("manifest"))
 	if err != nil {
 		return cli.NewExitError(fmt.Errorf("can't read contract manifest: %w", err), 1)
@@ -57,6 +78,7 @@ func contractGenerateWrapper(ctx *cli.Context) error {
 	}
 
 	cfg.Manifest = m
+	cfg.RPCBindings = cfg.RPCBindings || rpc
 
 	h, err := util.Uint160DecodeStringLE(strings.TrimPrefix(ctx.String("hash"), "0x"))
 	if err != nil {
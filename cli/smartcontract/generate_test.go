@@ -229,6 +229,458 @@ func MyFunc(in map[int]mycontract.Input) []mycontract.Output {
 	require.Equal(t, expected, string(data))
 }
 
+func TestGenerateStructs(t *testing.T) {
+	m := manifest.NewManifest("MyContract")
+	m.ABI.Methods = append(m.ABI.Methods,
+		manifest.Method{
+			Name:       "getOutput",
+			ReturnType: smartcontract.ArrayType,
+		},
+		manifest.Method{
+			Name:       "getOutputs",
+			ReturnType: smartcontract.ArrayType,
+		},
+		manifest.Method{
+			Name:       "getOutputPtr",
+			ReturnType: smartcontract.ArrayType,
+		},
+		manifest.Method{
+			Name:       "getUnknown",
+			ReturnType: smartcontract.ArrayType,
+		})
+
+	manifestFile := filepath.Join(t.TempDir(), "manifest.json")
+	outFile := filepath.Join(t.TempDir(), "out.go")
+
+	rawManifest, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestFile, rawManifest, os.ModePerm))
+
+	h := util.Uint160{
+		0x04, 0x08, 0x15, 0x16, 0x23, 0x42, 0x43, 0x44, 0x00, 0x01,
+		0xCA, 0xFE, 0xBA, 0xBE, 0xDE, 0xAD, 0xBE, 0xEF, 0x03, 0x04,
+	}
+	app := cli.NewApp()
+	app.Commands = []cli.Command{generateWrapperCmd}
+
+	rawCfg := `package: wrapper
+hash: ` + h.StringLE() + `
+overrides:
+    getOutput: github.com/heyitsme/mycontract.Output
+    getOutputs: "[]github.com/heyitsme/mycontract.Output"
+    getOutputPtr: "*github.com/heyitsme/mycontract.Output"
+    getUnknown: github.com/heyitsme/mycontract.Unknown
+structs:
+    github.com/heyitsme/mycontract.Output:
+      - field: Amount
+        type: int
+      - field: Owner
+        type: github.com/nspcc-dev/neo-go/pkg/interop.Hash160
+      - field: Inputs
+        type: "[]github.com/heyitsme/mycontract.Input"
+    github.com/heyitsme/mycontract.Input:
+      - field: Data
+        type: "[]byte"
+`
+	cfgPath := filepath.Join(t.TempDir(), "binding.yml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(rawCfg), os.ModePerm))
+
+	require.NoError(t, app.Run([]string{"", "generate-wrapper",
+		"--manifest", manifestFile,
+		"--config", cfgPath,
+		"--out", outFile,
+		"--hash", h.StringLE(),
+	}))
+
+	const expected = `// Package wrapper contains wrappers for MyContract contract.
+package wrapper
+
+import (
+	"github.com/heyitsme/mycontract"
+	"github.com/nspcc-dev/neo-go/pkg/interop"
+	"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+	"github.com/nspcc-dev/neo-go/pkg/interop/neogointernal"
+)
+
+// Hash contains contract hash in big-endian form.
+const Hash = "\x04\x08\x15\x16\x23\x42\x43\x44\x00\x01\xca\xfe\xba\xbe\xde\xad\xbe\xef\x03\x04"
+
+// GetOutput invokes ` + "`getOutput`" + ` method of contract.
+func GetOutput() mycontract.Output {
+	return decodeMycontractOutput(neogointernal.CallWithToken(Hash, "getOutput", int(contract.All)))
+}
+
+// GetOutputs invokes ` + "`getOutputs`" + ` method of contract.
+func GetOutputs() []mycontract.Output {
+	return decodeMycontractOutputSlice(neogointernal.CallWithToken(Hash, "getOutputs", int(contract.All)))
+}
+
+// GetOutputPtr invokes ` + "`getOutputPtr`" + ` method of contract.
+func GetOutputPtr() *mycontract.Output {
+	return decodeMycontractOutputPtr(neogointernal.CallWithToken(Hash, "getOutputPtr", int(contract.All)))
+}
+
+// GetUnknown invokes ` + "`getUnknown`" + ` method of contract.
+func GetUnknown() mycontract.Unknown {
+	return neogointernal.CallWithToken(Hash, "getUnknown", int(contract.All)).(mycontract.Unknown)
+}
+
+// decodeMycontractOutput converts contract invocation result to mycontract.Output.
+// Null is converted to the zero value.
+func decodeMycontractOutput(item interface{}) mycontract.Output {
+	if item == nil {
+		return mycontract.Output{}
+	}
+	arr := item.([]interface{})
+	return mycontract.Output{
+		Amount: arr[0].(int),
+		Owner:  arr[1].(interop.Hash160),
+		Inputs: decodeMycontractInputSlice(arr[2]),
+	}
+}
+
+// decodeMycontractInput converts contract invocation result to mycontract.Input.
+// Null is converted to the zero value.
+func decodeMycontractInput(item interface{}) mycontract.Input {
+	if item == nil {
+		return mycontract.Input{}
+	}
+	arr := item.([]interface{})
+	return mycontract.Input{
+		Data: arr[0].([]byte),
+	}
+}
+
+// decodeMycontractInputSlice converts contract invocation result to []mycontract.Input.
+// Null is converted to the zero value.
+func decodeMycontractInputSlice(item interface{}) []mycontract.Input {
+	if item == nil {
+		return nil
+	}
+	arr := item.([]interface{})
+	res := make([]mycontract.Input, len(arr))
+	for i := range arr {
+		res[i] = decodeMycontractInput(arr[i])
+	}
+	return res
+}
+
+// decodeMycontractOutputSlice converts contract invocation result to []mycontract.Output.
+// Null is converted to the zero value.
+func decodeMycontractOutputSlice(item interface{}) []mycontract.Output {
+	if item == nil {
+		return nil
+	}
+	arr := item.([]interface{})
+	res := make([]mycontract.Output, len(arr))
+	for i := range arr {
+		res[i] = decodeMycontractOutput(arr[i])
+	}
+	return res
+}
+
+// decodeMycontractOutputPtr converts contract invocation result to *mycontract.Output.
+// Null is converted to the zero value.
+func decodeMycontractOutputPtr(item interface{}) *mycontract.Output {
+	if item == nil {
+		return nil
+	}
+	arr := item.([]interface{})
+	return &mycontract.Output{
+		Amount: arr[0].(int),
+		Owner:  arr[1].(interop.Hash160),
+		Inputs: decodeMycontractInputSlice(arr[2]),
+	}
+}
+`
+
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(data))
+}
+
+func TestGenerateImportAliases(t *testing.T) {
+	m := manifest.NewManifest("MyContract")
+	m.ABI.Methods = append(m.ABI.Methods,
+		manifest.Method{
+			Name:       "getFoo",
+			ReturnType: smartcontract.ArrayType,
+		},
+		manifest.Method{
+			Name:       "getBars",
+			ReturnType: smartcontract.ArrayType,
+		},
+		manifest.Method{
+			Name: "setData",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("data", smartcontract.ArrayType),
+			},
+			ReturnType: smartcontract.VoidType,
+		})
+
+	manifestFile := filepath.Join(t.TempDir(), "manifest.json")
+	outFile := filepath.Join(t.TempDir(), "out.go")
+
+	rawManifest, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestFile, rawManifest, os.ModePerm))
+
+	h := util.Uint160{
+		0x04, 0x08, 0x15, 0x16, 0x23, 0x42, 0x43, 0x44, 0x00, 0x01,
+		0xCA, 0xFE, 0xBA, 0xBE, 0xDE, 0xAD, 0xBE, 0xEF, 0x03, 0x04,
+	}
+	app := cli.NewApp()
+	app.Commands = []cli.Command{generateWrapperCmd}
+
+	rawCfg := `package: wrapper
+hash: ` + h.StringLE() + `
+overrides:
+    getFoo: github.com/a/types.Foo
+    getBars: "[]github.com/b/types.Bar"
+    setData.data: "map[int]github.com/c/contract.Data"
+structs:
+    github.com/a/types.Foo:
+      - field: Bar
+        type: github.com/b/types.Bar
+`
+	cfgPath := filepath.Join(t.TempDir(), "binding.yml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(rawCfg), os.ModePerm))
+
+	require.NoError(t, app.Run([]string{"", "generate-wrapper",
+		"--manifest", manifestFile,
+		"--config", cfgPath,
+		"--out", outFile,
+		"--hash", h.StringLE(),
+	}))
+
+	const expected = `// Package wrapper contains wrappers for MyContract contract.
+package wrapper
+
+import (
+	types1 "github.com/a/types"
+	types2 "github.com/b/types"
+	contract1 "github.com/c/contract"
+	"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+	"github.com/nspcc-dev/neo-go/pkg/interop/neogointernal"
+)
+
+// Hash contains contract hash in big-endian form.
+const Hash = "\x04\x08\x15\x16\x23\x42\x43\x44\x00\x01\xca\xfe\xba\xbe\xde\xad\xbe\xef\x03\x04"
+
+// GetFoo invokes ` + "`getFoo`" + ` method of contract.
+func GetFoo() types1.Foo {
+	return decodeTypes1Foo(neogointernal.CallWithToken(Hash, "getFoo", int(contract.All)))
+}
+
+// GetBars invokes ` + "`getBars`" + ` method of contract.
+func GetBars() []types2.Bar {
+	return neogointernal.CallWithToken(Hash, "getBars", int(contract.All)).([]types2.Bar)
+}
+
+// SetData invokes ` + "`setData`" + ` method of contract.
+func SetData(data map[int]contract1.Data) {
+	neogointernal.CallWithTokenNoRet(Hash, "setData", int(contract.All), data)
+}
+
+// decodeTypes1Foo converts contract invocation result to types1.Foo.
+// Null is converted to the zero value.
+func decodeTypes1Foo(item interface{}) types1.Foo {
+	if item == nil {
+		return types1.Foo{}
+	}
+	arr := item.([]interface{})
+	return types1.Foo{
+		Bar: arr[0].(types2.Bar),
+	}
+}
+`
+
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(data))
+}
+
+func TestGenerateRPCBindingsDeploy(t *testing.T) {
+	m := manifest.NewManifest("MyContract")
+	m.ABI.Methods = append(m.ABI.Methods,
+		manifest.Method{
+			Name:       "touch",
+			ReturnType: smartcontract.VoidType,
+		})
+
+	manifestFile := filepath.Join(t.TempDir(), "manifest.json")
+	outFile := filepath.Join(t.TempDir(), "out.go")
+
+	rawManifest, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestFile, rawManifest, os.ModePerm))
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{generateRPCWrapperCmd, generateWrapperCmd}
+
+	rawCfg := `package: wrapper
+deploy: true
+`
+	cfgPath := filepath.Join(t.TempDir(), "binding.yml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(rawCfg), os.ModePerm))
+
+	require.NoError(t, app.Run([]string{"", "generate-rpcwrapper",
+		"--manifest", manifestFile,
+		"--config", cfgPath,
+		"--out", outFile,
+		"--hash", util.Uint160{1, 2, 3}.StringLE(),
+	}))
+
+	const expectedImports = `import (
+	"encoding/json"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+`
+	const expectedDeploy = `
+// Deploy creates a transaction deploying the contract with the given NEF file,
+// manifest and data (passed to _deploy method) and sends it to the network on
+// behalf of the given account returning its hash and ValidUntilBlock value.
+// Notice that the hash of the deployed contract depends on the sender, so
+// it's equal to Hash only if the account is the same as the one used for the
+// original deployment.
+func Deploy(actor Actor, acc *wallet.Account, nefFile *nef.File, m *manifest.Manifest, data interface{}) (util.Uint256, uint32, error) {
+	rawNef, err := nefFile.Bytes()
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	rawManifest, err := json.Marshal(m)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	w := io.NewBufBinWriter()
+	mgmtHash := state.CreateContractHash(util.Uint160{}, 0, nativenames.Management)
+	emit.AppCall(w.BinWriter, mgmtHash, "deploy", callflag.All, rawNef, rawManifest, data)
+	if w.Err != nil {
+		return util.Uint256{}, 0, w.Err
+	}
+	tx, err := actor.CreateTxFromScript(w.Bytes(), acc, -1, 0, nil)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	h, err := actor.SignAndPushTx(tx, acc, nil)
+	return h, tx.ValidUntilBlock, err
+}
+`
+
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(data), expectedImports), string(data))
+	require.True(t, strings.Contains(string(data), expectedDeploy), string(data))
+
+	t.Run("on-chain", func(t *testing.T) {
+		require.NoError(t, app.Run([]string{"", "generate-wrapper",
+			"--manifest", manifestFile,
+			"--config", cfgPath,
+			"--out", outFile,
+			"--hash", util.Uint160{1, 2, 3}.StringLE(),
+		}))
+		data, err := os.ReadFile(outFile)
+		require.NoError(t, err)
+		require.False(t, strings.Contains(string(data), "Deploy"))
+	})
+}
+
+func TestGenerateDetailedComments(t *testing.T) {
+	m := manifest.NewManifest("MyContract")
+	m.ABI.Methods = append(m.ABI.Methods,
+		manifest.Method{
+			Name:       "get",
+			Parameters: []manifest.Parameter{manifest.NewParameter("key", smartcontract.ByteArrayType)},
+			ReturnType: smartcontract.IntegerType,
+			Safe:       true,
+		},
+		manifest.Method{
+			Name: "put",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("key", smartcontract.ByteArrayType),
+				manifest.NewParameter("", smartcontract.IntegerType),
+			},
+			ReturnType: smartcontract.VoidType,
+		},
+		manifest.Method{
+			Name:       "touch",
+			ReturnType: smartcontract.VoidType,
+		})
+
+	manifestFile := filepath.Join(t.TempDir(), "manifest.json")
+	outFile := filepath.Join(t.TempDir(), "out.go")
+
+	rawManifest, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestFile, rawManifest, os.ModePerm))
+
+	rawCfg := `package: wrapper
+detailedcomments: true
+callflags:
+  touch: AllowNotify
+`
+	cfgPath := filepath.Join(t.TempDir(), "binding.yml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(rawCfg), os.ModePerm))
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{generateWrapperCmd, generateRPCWrapperCmd}
+
+	generate := func(t *testing.T, cmd string) string {
+		require.NoError(t, app.Run([]string{"", cmd,
+			"--manifest", manifestFile,
+			"--config", cfgPath,
+			"--out", outFile,
+			"--hash", util.Uint160{1, 2, 3}.StringLE(),
+		}))
+		data, err := os.ReadFile(outFile)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("on-chain", func(t *testing.T) {
+		data := generate(t, "generate-wrapper")
+		require.True(t, strings.Contains(data, `// Get invokes `+"`get`"+` method of contract.
+// It's a safe (read-only) method called with ReadOnly call flags.
+// Parameters:
+//   key ByteArray
+func Get(key []byte) int {`), data)
+		require.True(t, strings.Contains(data, `// Put invokes `+"`put`"+` method of contract.
+// It's not a safe method, it's called with All call flags.
+// Parameters:
+//   key ByteArray
+//   arg1 Integer
+func Put(key []byte, arg1 int) {`), data)
+		require.True(t, strings.Contains(data, `// Touch invokes `+"`touch`"+` method of contract.
+// It's not a safe method, it's called with AllowNotify call flags.
+func Touch() {`), data)
+	})
+	t.Run("rpc", func(t *testing.T) {
+		data := generate(t, "generate-rpcwrapper")
+		require.True(t, strings.Contains(data, `// Get invokes `+"`get`"+` method of contract.
+// It's a safe (read-only) method called with ReadOnly call flags.
+// Parameters:
+//   key ByteArray
+func (c *ContractReader) Get(key []byte) (*big.Int, error) {`), data)
+		require.True(t, strings.Contains(data, `// and sends it to the network returning its hash and ValidUntilBlock value.
+// It's not a safe method, it's called with All call flags.
+// Parameters:
+//   key ByteArray
+//   arg1 Integer
+func (c *Contract) Put(key []byte, arg1 *big.Int) (util.Uint256, uint32, error) {`), data)
+	})
+}
+
 func TestGenerateValidPackageName(t *testing.T) {
 	m := manifest.NewManifest("My space\tcontract")
 	m.ABI.Methods = append(m.ABI.Methods,
@@ -278,6 +730,433 @@ func Get() int {
 `, string(data))
 }
 
+func TestGenerateRPCBindingsStructs(t *testing.T) {
+	m := manifest.NewManifest("MyContract")
+	m.ABI.Methods = append(m.ABI.Methods,
+		manifest.Method{
+			Name:       "getOutput",
+			ReturnType: smartcontract.ArrayType,
+			Safe:       true,
+		},
+		manifest.Method{
+			Name:       "getOutputs",
+			ReturnType: smartcontract.ArrayType,
+			Safe:       true,
+		},
+		manifest.Method{
+			Name:       "getUnknown",
+			ReturnType: smartcontract.ArrayType,
+			Safe:       true,
+		},
+		manifest.Method{
+			Name:       "update",
+			ReturnType: smartcontract.ArrayType,
+		})
+
+	manifestFile := filepath.Join(t.TempDir(), "manifest.json")
+	outFile := filepath.Join(t.TempDir(), "out.go")
+
+	rawManifest, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestFile, rawManifest, os.ModePerm))
+
+	app := cli.NewApp()
+	app.Commands = []cli.Command{generateRPCWrapperCmd}
+	app.ExitErrHandler = func(*cli.Context, error) {}
+
+	generate := func(t *testing.T, rawCfg string) error {
+		cfgPath := filepath.Join(t.TempDir(), "binding.yml")
+		require.NoError(t, os.WriteFile(cfgPath, []byte(rawCfg), os.ModePerm))
+		return app.Run([]string{"", "generate-rpcwrapper",
+			"--manifest", manifestFile,
+			"--config", cfgPath,
+			"--out", outFile,
+			"--hash", util.Uint160{1, 2, 3}.StringLE(),
+		})
+	}
+
+	const overrides = `overrides:
+    getOutput: "*github.com/heyitsme/mycontract.Output"
+    getOutputs: "[]github.com/heyitsme/mycontract.Output"
+    getUnknown: github.com/heyitsme/mycontract.Unknown
+    update: github.com/heyitsme/mycontract.Output
+`
+	rawCfg := `package: wrapper
+rpc: true
+structhelpers: true
+` + overrides + `structs:
+    github.com/heyitsme/mycontract.Output:
+      - field: Amount
+        type: int
+      - field: Owner
+        type: github.com/nspcc-dev/neo-go/pkg/interop.Hash160
+      - field: Inputs
+        type: "[]github.com/heyitsme/mycontract.Input"
+    github.com/heyitsme/mycontract.Input:
+      - field: Data
+        type: "[]byte"
+`
+	require.NoError(t, generate(t, rawCfg))
+
+	const expected = `// Package wrapper contains RPC wrappers for MyContract contract.
+package wrapper
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"math/big"
+)
+
+// Hash contains contract hash.
+var Hash = util.Uint160{0x01, 0x02, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}
+
+// Invoker is used by ContractReader to call various safe methods.
+type Invoker interface {
+	InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error)
+}
+
+// Actor is used by Contract to create and send transactions.
+type Actor interface {
+	Invoker
+	CreateTxFromScript(script []byte, acc *wallet.Account, sysFee, netFee int64, cosigners []client.SignerAccount) (*transaction.Transaction, error)
+	SignAndPushTx(tx *transaction.Transaction, acc *wallet.Account, cosigners []client.SignerAccount) (util.Uint256, error)
+}
+
+// ContractReader implements safe contract methods.
+type ContractReader struct {
+	invoker Invoker
+}
+
+// Contract implements all contract methods.
+type Contract struct {
+	ContractReader
+	actor   Actor
+	account *wallet.Account
+}
+
+// NewReader creates an instance of ContractReader using Hash and the given Invoker.
+func NewReader(invoker Invoker) *ContractReader {
+	return &ContractReader{invoker}
+}
+
+// New creates an instance of Contract using Hash and the given Actor. Transactions
+// are sent on behalf of the given account, it must be unlocked.
+func New(actor Actor, acc *wallet.Account) *Contract {
+	return &Contract{ContractReader{actor}, actor, acc}
+}
+
+func script(method string, f callflag.CallFlag, args ...interface{}) ([]byte, error) {
+	w := io.NewBufBinWriter()
+	emit.AppCall(w.BinWriter, Hash, method, f, args...)
+	if w.Err != nil {
+		return nil, w.Err
+	}
+	return w.Bytes(), nil
+}
+
+func (c *ContractReader) invoke(method string, f callflag.CallFlag, args ...interface{}) (*result.Invoke, error) {
+	s, err := script(method, f, args...)
+	if err != nil {
+		return nil, err
+	}
+	return c.invoker.InvokeScript(s, nil)
+}
+
+func (c *Contract) send(method string, f callflag.CallFlag, args ...interface{}) (util.Uint256, uint32, error) {
+	s, err := script(method, f, args...)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	tx, err := c.actor.CreateTxFromScript(s, c.account, -1, 0, nil)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	h, err := c.actor.SignAndPushTx(tx, c.account, nil)
+	return h, tx.ValidUntilBlock, err
+}
+
+// GetOutput invokes ` + "`" + `getOutput` + "`" + ` method of contract.
+func (c *ContractReader) GetOutput() (*Output, error) {
+	return itemToOutput(unwrap.Item(c.invoke("getOutput", callflag.ReadOnly)))
+}
+
+// GetOutputs invokes ` + "`" + `getOutputs` + "`" + ` method of contract.
+func (c *ContractReader) GetOutputs() ([]*Output, error) {
+	return itemToOutputSlice(unwrap.Item(c.invoke("getOutputs", callflag.ReadOnly)))
+}
+
+// GetUnknown invokes ` + "`" + `getUnknown` + "`" + ` method of contract.
+func (c *ContractReader) GetUnknown() (*Unknown, error) {
+	return itemToUnknown(unwrap.Item(c.invoke("getUnknown", callflag.ReadOnly)))
+}
+
+// Update creates a transaction invoking ` + "`" + `update` + "`" + ` method of contract
+// and sends it to the network returning its hash and ValidUntilBlock value.
+func (c *Contract) Update() (util.Uint256, uint32, error) {
+	return c.send("update", callflag.All)
+}
+
+// Output is a contract-specific mycontract.Output type used by its methods.
+type Output struct {
+	Amount *big.Int
+	Owner  util.Uint160
+	Inputs []*Input
+}
+
+// itemToOutput converts stack item into *Output.
+func itemToOutput(item stackitem.Item, err error) (*Output, error) {
+	if err != nil {
+		return nil, err
+	}
+	var res = new(Output)
+	err = res.FromStackItem(item)
+	return res, err
+}
+
+// FromStackItem retrieves fields of Output from the given stack item
+// and returns an error if it's not compatible.
+func (res *Output) FromStackItem(item stackitem.Item) error {
+	arr, ok := item.Value().([]stackitem.Item)
+	if !ok {
+		return errors.New("not an array")
+	}
+	if len(arr) != 3 {
+		return errors.New("wrong number of structure elements")
+	}
+	var err error
+	res.Amount, err = arr[0].TryInteger()
+	if err != nil {
+		return fmt.Errorf("field Amount: %w", err)
+	}
+	res.Owner, err = itemToUint160(arr[1], nil)
+	if err != nil {
+		return fmt.Errorf("field Owner: %w", err)
+	}
+	res.Inputs, err = itemToInputSlice(arr[2], nil)
+	if err != nil {
+		return fmt.Errorf("field Inputs: %w", err)
+	}
+	return nil
+}
+
+// ToStackItem creates stack item from Output.
+func (res *Output) ToStackItem() (stackitem.Item, error) {
+	if res == nil {
+		return stackitem.Null{}, nil
+	}
+	var (
+		err   error
+		itm   stackitem.Item
+		items = make([]stackitem.Item, 0, 3)
+	)
+	items = append(items, stackitem.NewBigInteger(res.Amount))
+	items = append(items, stackitem.NewByteArray(res.Owner.BytesBE()))
+	itm, err = inputSliceToItem(res.Inputs)
+	if err != nil {
+		return nil, fmt.Errorf("field Inputs: %w", err)
+	}
+	items = append(items, itm)
+	return stackitem.NewStruct(items), nil
+}
+
+// Input is a contract-specific mycontract.Input type used by its methods.
+type Input struct {
+	Data []byte
+}
+
+// itemToInput converts stack item into *Input.
+func itemToInput(item stackitem.Item, err error) (*Input, error) {
+	if err != nil {
+		return nil, err
+	}
+	var res = new(Input)
+	err = res.FromStackItem(item)
+	return res, err
+}
+
+// FromStackItem retrieves fields of Input from the given stack item
+// and returns an error if it's not compatible.
+func (res *Input) FromStackItem(item stackitem.Item) error {
+	arr, ok := item.Value().([]stackitem.Item)
+	if !ok {
+		return errors.New("not an array")
+	}
+	if len(arr) != 1 {
+		return errors.New("wrong number of structure elements")
+	}
+	var err error
+	res.Data, err = arr[0].TryBytes()
+	if err != nil {
+		return fmt.Errorf("field Data: %w", err)
+	}
+	return nil
+}
+
+// ToStackItem creates stack item from Input.
+func (res *Input) ToStackItem() (stackitem.Item, error) {
+	if res == nil {
+		return stackitem.Null{}, nil
+	}
+	var items = make([]stackitem.Item, 0, 1)
+	items = append(items, stackitem.NewByteArray(res.Data))
+	return stackitem.NewStruct(items), nil
+}
+
+// Unknown is a contract-specific mycontract.Unknown type used by its methods.
+// Its fields are not described in the binding configuration, so it only
+// keeps the original stack item.
+type Unknown struct {
+	Item stackitem.Item
+}
+
+// itemToUnknown converts stack item into *Unknown.
+func itemToUnknown(item stackitem.Item, err error) (*Unknown, error) {
+	if err != nil {
+		return nil, err
+	}
+	var res = new(Unknown)
+	err = res.FromStackItem(item)
+	return res, err
+}
+
+// FromStackItem retrieves fields of Unknown from the given stack item
+// and returns an error if it's not compatible.
+func (res *Unknown) FromStackItem(item stackitem.Item) error {
+	res.Item = item
+	return nil
+}
+
+// ToStackItem creates stack item from Unknown.
+func (res *Unknown) ToStackItem() (stackitem.Item, error) {
+	if res == nil {
+		return stackitem.Null{}, nil
+	}
+	return res.Item, nil
+}
+
+// itemToInputSlice converts stack item into []*Input.
+func itemToInputSlice(item stackitem.Item, err error) ([]*Input, error) {
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := item.Value().([]stackitem.Item)
+	if !ok {
+		return nil, errors.New("not an array")
+	}
+	res := make([]*Input, len(arr))
+	for i := range arr {
+		res[i], err = itemToInput(arr[i], nil)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+// inputSliceToItem converts []*Input into stack item.
+func inputSliceToItem(s []*Input) (stackitem.Item, error) {
+	items := make([]stackitem.Item, len(s))
+	for i := range s {
+		var err error
+		items[i], err = s[i].ToStackItem()
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return stackitem.NewArray(items), nil
+}
+
+// itemToOutputSlice converts stack item into []*Output.
+func itemToOutputSlice(item stackitem.Item, err error) ([]*Output, error) {
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := item.Value().([]stackitem.Item)
+	if !ok {
+		return nil, errors.New("not an array")
+	}
+	res := make([]*Output, len(arr))
+	for i := range arr {
+		res[i], err = itemToOutput(arr[i], nil)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+// outputSliceToItem converts []*Output into stack item.
+func outputSliceToItem(s []*Output) (stackitem.Item, error) {
+	items := make([]stackitem.Item, len(s))
+	for i := range s {
+		var err error
+		items[i], err = s[i].ToStackItem()
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return stackitem.NewArray(items), nil
+}
+
+// itemToUint160 converts stack item into util.Uint160.
+func itemToUint160(item stackitem.Item, err error) (util.Uint160, error) {
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	b, err := item.TryBytes()
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	return util.Uint160DecodeBytesBE(b)
+}
+`
+
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(data))
+
+	t.Run("unused structure", func(t *testing.T) {
+		err := generate(t, rawCfg+`    github.com/heyitsme/mycontract.Other:
+      - field: Value
+        type: int
+`)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "structure github.com/heyitsme/mycontract.Other is declared, but not used"), "got: %v", err)
+	})
+	t.Run("duplicate field", func(t *testing.T) {
+		err := generate(t, `rpc: true
+structhelpers: true
+`+overrides+`structs:
+    github.com/heyitsme/mycontract.Output:
+      - field: Amount
+        type: int
+      - field: Amount
+        type: bool
+`)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "duplicate field Amount"), "got: %v", err)
+	})
+	t.Run("no fields", func(t *testing.T) {
+		err := generate(t, `rpc: true
+structhelpers: true
+`+overrides+`structs:
+    github.com/heyitsme/mycontract.Output: []
+`)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "has no fields"), "got: %v", err)
+	})
+}
+
 func TestGenerate_Errors(t *testing.T) {
 	app := cli.NewApp()
 	app.Commands = []cli.Command{generateWrapperCmd}
@@ -327,3 +1206,205 @@ callflags:
 			"--config", cfgPath)
 	})
 }
+
+func TestGenerateRPCBindings(t *testing.T) {
+	m := manifest.NewManifest("MyContract")
+	m.ABI.Methods = append(m.ABI.Methods,
+		manifest.Method{
+			Name:       manifest.MethodDeploy,
+			ReturnType: smartcontract.VoidType,
+		},
+		manifest.Method{
+			Name: "sum",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("first", smartcontract.IntegerType),
+				manifest.NewParameter("second", smartcontract.IntegerType),
+			},
+			ReturnType: smartcontract.IntegerType,
+			Safe:       true,
+		},
+		manifest.Method{
+			Name:       "getPublicKey",
+			ReturnType: smartcontract.PublicKeyType,
+			Safe:       true,
+		},
+		manifest.Method{
+			Name: "checkOwner",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("owner", smartcontract.Hash160Type),
+				manifest.NewParameter("key", smartcontract.PublicKeyType),
+			},
+			ReturnType: smartcontract.BoolType,
+		},
+		manifest.Method{
+			Name: "setValue",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("key", smartcontract.StringType),
+				manifest.NewParameter("value", smartcontract.ByteArrayType),
+			},
+			ReturnType: smartcontract.VoidType,
+		},
+		manifest.Method{
+			Name: "values",
+			Parameters: []manifest.Parameter{
+				manifest.NewParameter("", smartcontract.ArrayType),
+			},
+			ReturnType: smartcontract.ArrayType,
+			Safe:       true,
+		},
+		manifest.Method{
+			Name:       "touch",
+			ReturnType: smartcontract.VoidType,
+			Safe:       true,
+		})
+
+	manifestFile := filepath.Join(t.TempDir(), "manifest.json")
+	outFile := filepath.Join(t.TempDir(), "out.go")
+
+	rawManifest, err := json.Marshal(m)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(manifestFile, rawManifest, os.ModePerm))
+
+	h := util.Uint160{
+		0x04, 0x08, 0x15, 0x16, 0x23, 0x42, 0x43, 0x44, 0x00, 0x01,
+		0xCA, 0xFE, 0xBA, 0xBE, 0xDE, 0xAD, 0xBE, 0xEF, 0x03, 0x04,
+	}
+	app := cli.NewApp()
+	app.Commands = []cli.Command{generateRPCWrapperCmd}
+
+	rawCfg := `package: wrapper
+callflags:
+    checkOwner: ReadOnly
+`
+	cfgPath := filepath.Join(t.TempDir(), "binding.yml")
+	require.NoError(t, os.WriteFile(cfgPath, []byte(rawCfg), os.ModePerm))
+
+	require.NoError(t, app.Run([]string{"", "generate-rpcwrapper",
+		"--manifest", manifestFile,
+		"--config", cfgPath,
+		"--out", outFile,
+		"--hash", h.StringLE(),
+	}))
+
+	const expected = `// Package wrapper contains RPC wrappers for MyContract contract.
+package wrapper
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/client/unwrap"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+	"math/big"
+)
+
+// Hash contains contract hash.
+var Hash = util.Uint160{0x04, 0x08, 0x15, 0x16, 0x23, 0x42, 0x43, 0x44, 0x00, 0x01, 0xca, 0xfe, 0xba, 0xbe, 0xde, 0xad, 0xbe, 0xef, 0x03, 0x04}
+
+// Invoker is used by ContractReader to call various safe methods.
+type Invoker interface {
+	InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error)
+}
+
+// Actor is used by Contract to create and send transactions.
+type Actor interface {
+	Invoker
+	CreateTxFromScript(script []byte, acc *wallet.Account, sysFee, netFee int64, cosigners []client.SignerAccount) (*transaction.Transaction, error)
+	SignAndPushTx(tx *transaction.Transaction, acc *wallet.Account, cosigners []client.SignerAccount) (util.Uint256, error)
+}
+
+// ContractReader implements safe contract methods.
+type ContractReader struct {
+	invoker Invoker
+}
+
+// Contract implements all contract methods.
+type Contract struct {
+	ContractReader
+	actor   Actor
+	account *wallet.Account
+}
+
+// NewReader creates an instance of ContractReader using Hash and the given Invoker.
+func NewReader(invoker Invoker) *ContractReader {
+	return &ContractReader{invoker}
+}
+
+// New creates an instance of Contract using Hash and the given Actor. Transactions
+// are sent on behalf of the given account, it must be unlocked.
+func New(actor Actor, acc *wallet.Account) *Contract {
+	return &Contract{ContractReader{actor}, actor, acc}
+}
+
+func script(method string, f callflag.CallFlag, args ...interface{}) ([]byte, error) {
+	w := io.NewBufBinWriter()
+	emit.AppCall(w.BinWriter, Hash, method, f, args...)
+	if w.Err != nil {
+		return nil, w.Err
+	}
+	return w.Bytes(), nil
+}
+
+func (c *ContractReader) invoke(method string, f callflag.CallFlag, args ...interface{}) (*result.Invoke, error) {
+	s, err := script(method, f, args...)
+	if err != nil {
+		return nil, err
+	}
+	return c.invoker.InvokeScript(s, nil)
+}
+
+func (c *Contract) send(method string, f callflag.CallFlag, args ...interface{}) (util.Uint256, uint32, error) {
+	s, err := script(method, f, args...)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	tx, err := c.actor.CreateTxFromScript(s, c.account, -1, 0, nil)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	h, err := c.actor.SignAndPushTx(tx, c.account, nil)
+	return h, tx.ValidUntilBlock, err
+}
+
+// Sum invokes ` + "`" + `sum` + "`" + ` method of contract.
+func (c *ContractReader) Sum(first *big.Int, second *big.Int) (*big.Int, error) {
+	return unwrap.BigInt(c.invoke("sum", callflag.ReadOnly, first, second))
+}
+
+// GetPublicKey invokes ` + "`" + `getPublicKey` + "`" + ` method of contract.
+func (c *ContractReader) GetPublicKey() (*keys.PublicKey, error) {
+	return unwrap.PublicKey(c.invoke("getPublicKey", callflag.ReadOnly))
+}
+
+// CheckOwner invokes ` + "`" + `checkOwner` + "`" + ` method of contract.
+func (c *ContractReader) CheckOwner(owner util.Uint160, key *keys.PublicKey) (bool, error) {
+	return unwrap.Bool(c.invoke("checkOwner", callflag.ReadOnly, owner, key.Bytes()))
+}
+
+// Values invokes ` + "`" + `values` + "`" + ` method of contract.
+func (c *ContractReader) Values(arg0 []interface{}) ([]stackitem.Item, error) {
+	return unwrap.Array(c.invoke("values", callflag.ReadOnly, arg0))
+}
+
+// Touch invokes ` + "`" + `touch` + "`" + ` method of contract.
+func (c *ContractReader) Touch() error {
+	return unwrap.Nothing(c.invoke("touch", callflag.ReadOnly))
+}
+
+// SetValue creates a transaction invoking ` + "`" + `setValue` + "`" + ` method of contract
+// and sends it to the network returning its hash and ValidUntilBlock value.
+func (c *Contract) SetValue(key string, value []byte) (util.Uint256, uint32, error) {
+	return c.send("setValue", callflag.All, key, value)
+}
+`
+
+	data, err := os.ReadFile(outFile)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(data))
+}
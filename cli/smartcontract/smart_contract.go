@@ -145,6 +145,10 @@ func NewCommands() []cli.Command {
 						Name:  "debug, d",
 						Usage: "Emit debug info in a separate file",
 					},
+					cli.BoolFlag{
+						Name:  "absolute-paths",
+						Usage: "use absolute source file paths in debug info",
+					},
 					cli.StringFlag{
 						Name:  "manifest, m",
 						Usage: "Emit contract manifest (*.manifest.json) file into separate file using configuration input file (*.yml)",
@@ -183,6 +187,7 @@ func NewCommands() []cli.Command {
 				Flags:  deployFlags,
 			},
 			generateWrapperCmd,
+			generateRPCWrapperCmd,
 			{
 				Name:      "invokefunction",
 				Usage:     "invoke deployed contract on the blockchain",
@@ -503,6 +508,8 @@ func contractCompile(ctx *cli.Context) error {
 		NoStandardCheck:    ctx.Bool("no-standards"),
 		NoEventsCheck:      ctx.Bool("no-events"),
 		NoPermissionsCheck: ctx.Bool("no-permissions"),
+
+		AbsolutePaths: ctx.Bool("absolute-paths"),
 	}
 
 	if len(confFile) != 0 {
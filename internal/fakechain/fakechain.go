@@ -19,6 +19,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result/subscriptions"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
@@ -113,6 +114,11 @@ func (*FakeChain) IsExtensibleAllowed(uint160 util.Uint160) bool {
 	return true
 }
 
+// VerifyExtensible implements Ledger interface.
+func (*FakeChain) VerifyExtensible(*payload.Extensible) error {
+	return nil
+}
+
 // GetNatives implements blockchainer.Blockchainer interface.
 func (*FakeChain) GetNatives() []state.NativeContract {
 	panic("TODO")
@@ -139,6 +145,11 @@ func (chain *FakeChain) GetNotaryBalance(acc util.Uint160) *big.Int {
 	panic("TODO")
 }
 
+// GetAttributesFee implements Blockchainer interface.
+func (chain *FakeChain) GetAttributesFee(*transaction.Transaction) int64 {
+	return 0
+}
+
 // GetNotaryServiceFeePerKey implements Blockchainer interface.
 func (chain *FakeChain) GetNotaryServiceFeePerKey() int64 {
 	panic("TODO")
@@ -270,6 +281,11 @@ func (chain *FakeChain) GetHeader(hash util.Uint256) (*block.Header, error) {
 	return &b.Header, nil
 }
 
+// GetHeaderCommitment implements Blockchainer interface.
+func (chain *FakeChain) GetHeaderCommitment(index uint32) util.Uint256 {
+	return util.Uint256{}
+}
+
 // GetNextBlockValidators implements Blockchainer interface.
 func (chain *FakeChain) GetNextBlockValidators() ([]*keys.PublicKey, error) {
 	panic("TODO")
@@ -383,6 +399,15 @@ func (chain *FakeChain) PoolTx(tx *transaction.Transaction, _ ...*mempool.Pool)
 	return chain.PoolTxF(tx)
 }
 
+// PoolTxWithSource implements Blockchainer interface.
+func (chain *FakeChain) PoolTxWithSource(tx *transaction.Transaction, _ mempool.TxSource, _ ...*mempool.Pool) error {
+	return chain.PoolTxF(tx)
+}
+
+// SetTxRebroadcast implements Blockchainer interface.
+func (chain *FakeChain) SetTxRebroadcast(uint32, int, func(*transaction.Transaction)) {
+}
+
 // SetOracle implements Blockchainer interface.
 func (chain FakeChain) SetOracle(services.Oracle) {
 	panic("TODO")
@@ -5,6 +5,8 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
@@ -17,7 +19,7 @@ var (
 	goBuiltins = []string{"len", "append", "panic", "make", "copy", "recover", "delete"}
 	// Custom builtin utility functions.
 	customBuiltins = []string{
-		"FromAddress",
+		"FromAddress", "DefineMap", "DefineValue",
 	}
 )
 
@@ -231,13 +233,48 @@ func (c *codegen) visitPkg(pkg *packages.Package, seen map[string]bool) {
 }
 
 func (c *codegen) fillDocumentInfo() {
-	fset := c.buildInfo.config.Fset
+	var (
+		fset  = c.buildInfo.config.Fset
+		files []string
+		names = make(map[string]string)
+		dirs  = make(map[string]string)
+	)
+	for _, p := range c.packageCache {
+		for _, f := range p.Syntax {
+			dirs[filepath.Dir(fset.Position(f.Pos()).Filename)] = p.PkgPath
+		}
+	}
 	fset.Iterate(func(f *token.File) bool {
 		filePath := f.Position(f.Pos(0)).Filename
-		c.docIndex[filePath] = len(c.documents)
-		c.documents = append(c.documents, filePath)
+		files = append(files, filePath)
+		names[filePath] = c.documentName(filePath, dirs)
 		return true
 	})
+	// Files are parsed concurrently, so their order in the set is random.
+	sort.Slice(files, func(i, j int) bool { return names[files[i]] < names[files[j]] })
+	for _, filePath := range files {
+		c.docIndex[filePath] = len(c.documents)
+		c.documents = append(c.documents, names[filePath])
+	}
+}
+
+// documentName returns the name of the file to be used in debug info. It's
+// the path relative to the compiled package directory for files inside of it
+// and the package path with the file name for others, so that the result
+// doesn't depend on the build environment. Absolute path is used if it's
+// requested by the options or if the file package is unknown.
+func (c *codegen) documentName(filePath string, dirs map[string]string) string {
+	if c.buildInfo.options != nil && c.buildInfo.options.AbsolutePaths {
+		return filePath
+	}
+	rel, err := filepath.Rel(c.buildInfo.config.Dir, filePath)
+	if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(rel)
+	}
+	if pkgPath, ok := dirs[filepath.Dir(filePath)]; ok {
+		return pkgPath + "/" + filepath.Base(filePath)
+	}
+	return filePath
 }
 
 // analyzeFuncUsage traverses all code and returns map with functions
@@ -387,5 +424,6 @@ func canInline(s string, name string) bool {
 		return false
 	}
 	return !strings.HasPrefix(s[len(interopPrefix):], "/neogointernal") &&
-		!(strings.HasPrefix(s[len(interopPrefix):], "/util") && name == "FromAddress")
+		!(strings.HasPrefix(s[len(interopPrefix):], "/util") && name == "FromAddress") &&
+		!isSchemaDefinition(s, name)
 }
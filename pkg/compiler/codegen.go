@@ -53,6 +53,11 @@ type codegen struct {
 	globals map[string]int
 	// staticVariables contains global (static in NDX-DN11) variable names and types.
 	staticVariables []string
+	// structTypes contains field names and types of named structs.
+	structTypes map[string][]DebugParam
+
+	// storageSchema contains storage collections defined in the program.
+	storageSchema map[*ast.CallExpr]*schemaCollection
 	// initVariables contains variables local to `_initialize` method.
 	initVariables []string
 	// deployVariables contains variables local to `_initialize` method.
@@ -519,13 +524,23 @@ func (c *codegen) convertFuncDecl(file ast.Node, decl *ast.FuncDecl, pkg *types.
 	f.rng.End = uint16(c.prog.Len() - 1)
 
 	if !isLambda {
-		for _, f := range c.lambda {
-			if _, ok := c.lambda[c.getIdentName("", f.decl.Name.Name)]; !ok {
-				panic("ICE: lambda name doesn't match map key")
+		// Lambdas are emitted in the order of their appearance (labels are
+		// allocated sequentially) for the output to be deterministic, nested
+		// lambdas are added to the map during conversion.
+		for len(c.lambda) != 0 {
+			lambdas := make([]*funcScope, 0, len(c.lambda))
+			for _, f := range c.lambda {
+				if _, ok := c.lambda[c.getIdentName("", f.decl.Name.Name)]; !ok {
+					panic("ICE: lambda name doesn't match map key")
+				}
+				lambdas = append(lambdas, f)
+			}
+			sort.Slice(lambdas, func(i, j int) bool { return lambdas[i].label < lambdas[j].label })
+			for _, f := range lambdas {
+				c.convertFuncDecl(file, f.decl, pkg)
+				delete(c.lambda, c.getIdentName("", f.decl.Name.Name))
 			}
-			c.convertFuncDecl(file, f.decl, pkg)
 		}
-		c.lambda = make(map[string]*funcScope)
 	}
 
 	if !isInit && !isDeploy {
@@ -1839,6 +1854,11 @@ func (c *codegen) convertBuiltin(expr *ast.CallExpr) {
 		bytes := uint160.BytesBE()
 		emit.Bytes(c.prog.BinWriter, bytes)
 		c.emitConvert(stackitem.BufferT)
+	case "DefineMap", "DefineValue":
+		// Collection definition is checked and converted to the key
+		// prefix at compile-time, see analyzeStorageSchema.
+		sc := c.storageSchema[expr]
+		emit.Struct(c.prog.BinWriter, sc.prefix, sc.typ)
 	}
 }
 
@@ -1849,12 +1869,17 @@ func (c *codegen) convertBuiltin(expr *ast.CallExpr) {
 //    so there is no need to push parameters on stack and perform an actual call
 // 2. With panic, generated code depends on if argument was nil or a string so
 //    it should be handled accordingly.
+// 3. With storage.DefineMap and storage.DefineValue, collection is created at
+//    compile-time, so no parameters are needed.
 func transformArgs(fs *funcScope, fun ast.Expr, args []ast.Expr) []ast.Expr {
 	switch f := fun.(type) {
 	case *ast.SelectorExpr:
 		if f.Sel.Name == "FromAddress" {
 			return args[1:]
 		}
+		if fs != nil && isSchemaDefinition(fs.pkg.Path(), fs.name) {
+			return nil
+		}
 		if fs != nil && isSyscall(fs) {
 			return nil
 		}
@@ -1929,6 +1954,7 @@ func (c *codegen) convertStruct(lit *ast.CompositeLit, ptr bool) {
 		c.prog.Err = fmt.Errorf("the given literal is not of type struct: %v", lit)
 		return
 	}
+	c.registerStructType(c.typeOf(lit))
 
 	keyedLit := len(lit.Elts) > 0
 	if keyedLit {
@@ -2086,6 +2112,9 @@ func (c *codegen) compile(info *buildInfo, pkg *packages.Package) error {
 	c.mainPkg = pkg
 	c.analyzePkgOrder()
 	c.fillDocumentInfo()
+	if err := c.analyzeStorageSchema(); err != nil {
+		return err
+	}
 	funUsage := c.analyzeFuncUsage()
 
 	// Bring all imported functions into scope.
@@ -2156,6 +2185,8 @@ func newCodegen(info *buildInfo, pkg *packages.Package) *codegen {
 		emittedEvents:    make(map[string][][]string),
 		invokedContracts: make(map[util.Uint160][]string),
 		sequencePoints:   make(map[string][]DebugSeqPoint),
+		structTypes:      make(map[string][]DebugParam),
+		storageSchema:    make(map[*ast.CallExpr]*schemaCollection),
 	}
 }
 
@@ -2256,6 +2287,13 @@ func (c *codegen) writeJumps(b []byte) ([]byte, error) {
 	for _, f := range c.funcs {
 		f.rng.Start, f.rng.End = correctRange(f.rng.Start, f.rng.End, offsets)
 	}
+	// Correct sequence points offsets, so that they match the final script.
+	// Point at the removed INITSLOT ends up at the instruction following it.
+	for _, sps := range c.sequencePoints {
+		for i := range sps {
+			sps[i].Opcode -= longToShortRemoveCount * sort.SearchInts(offsets, sps[i].Opcode)
+		}
+	}
 	return shortenJumps(b, offsets), nil
 }
 
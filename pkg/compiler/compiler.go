@@ -18,6 +18,8 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest/standard"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"golang.org/x/tools/go/packages"
 	"gopkg.in/yaml.v2"
 )
@@ -75,6 +77,12 @@ type Options struct {
 
 	// BindingsFile contains configuration for smart-contract bindings generator.
 	BindingsFile string
+
+	// AbsolutePaths specifies whether absolute source file paths should be
+	// used for debug info documents. By default paths are relative to the
+	// compiled package directory (or are import path-based for files outside
+	// of it), so that debug info doesn't depend on the build environment.
+	AbsolutePaths bool
 }
 
 type buildInfo struct {
@@ -310,6 +318,9 @@ func CompileAndSave(src string, o *Options) ([]byte, error) {
 		if err != nil {
 			return f.Script, err
 		}
+		if err := ValidateMethodOffsets(f.Script, m); err != nil {
+			return f.Script, fmt.Errorf("invalid method offsets: %w", err)
+		}
 		mData, err := json.Marshal(m)
 		if err != nil {
 			return f.Script, fmt.Errorf("failed to marshal manifest to JSON: %w", err)
@@ -320,6 +331,52 @@ func CompileAndSave(src string, o *Options) ([]byte, error) {
 	return f.Script, nil
 }
 
+// ValidateMethodOffsets checks that every ABI method offset of the manifest
+// points to an instruction boundary of the script. Methods with parameters are
+// also expected to start with INITSLOT instruction having the proper number
+// of arguments, that's what the compiler always does. It can be used to check
+// any NEF script and manifest pair before deployment.
+func ValidateMethodOffsets(script []byte, m *manifest.Manifest) error {
+	var (
+		instrs = make(map[int]opcode.Opcode)
+		params = make(map[int][]byte)
+		ctx    = vm.NewContext(script)
+	)
+	for ctx.NextIP() < len(script) {
+		op, param, err := ctx.Next()
+		if err != nil {
+			return fmt.Errorf("invalid script: %w", err)
+		}
+		instrs[ctx.IP()] = op
+		if op == opcode.INITSLOT {
+			params[ctx.IP()] = param
+		}
+	}
+	for _, method := range m.ABI.Methods {
+		if method.Offset < 0 || method.Offset >= len(script) {
+			return fmt.Errorf("method %s/%d: offset %d is out of script bounds",
+				method.Name, len(method.Parameters), method.Offset)
+		}
+		op, ok := instrs[method.Offset]
+		if !ok {
+			return fmt.Errorf("method %s/%d: offset %d is not an instruction boundary",
+				method.Name, len(method.Parameters), method.Offset)
+		}
+		if len(method.Parameters) == 0 {
+			continue
+		}
+		if op != opcode.INITSLOT {
+			return fmt.Errorf("method %s/%d: expected INITSLOT at offset %d, got %s",
+				method.Name, len(method.Parameters), method.Offset, op)
+		}
+		if n := int(params[method.Offset][1]); n != len(method.Parameters) {
+			return fmt.Errorf("method %s/%d: INITSLOT at offset %d has %d arguments",
+				method.Name, len(method.Parameters), method.Offset, n)
+		}
+	}
+	return nil
+}
+
 // CreateManifest creates manifest and checks that is is valid.
 func CreateManifest(di *DebugInfo, o *Options) (*manifest.Manifest, error) {
 	m, err := di.ConvertToManifest(o)
@@ -15,6 +15,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/stretchr/testify/require"
 )
 
@@ -124,6 +125,58 @@ func TestOnPayableChecks(t *testing.T) {
 	})
 }
 
+func TestValidateMethodOffsets(t *testing.T) {
+	src := `package offsets
+		var x int
+		func _deploy(data interface{}, isUpdate bool) { x = 1 }
+		func Sum(a, b int) int { return a + b }
+		func Get() string { return "some string" }`
+	f, di, err := compiler.CompileWithOptions("offsets.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+	m, err := compiler.CreateManifest(di, &compiler.Options{})
+	require.NoError(t, err)
+
+	t.Run("valid", func(t *testing.T) {
+		require.NoError(t, compiler.ValidateMethodOffsets(f.Script, m))
+	})
+	t.Run("off-by-one", func(t *testing.T) {
+		sum := m.ABI.GetMethod("sum", 2)
+		require.NotNil(t, sum)
+		sum.Offset++
+		defer func() { sum.Offset-- }()
+		require.Error(t, compiler.ValidateMethodOffsets(f.Script, m))
+	})
+	t.Run("out of bounds", func(t *testing.T) {
+		get := m.ABI.GetMethod("get", 0)
+		require.NotNil(t, get)
+		old := get.Offset
+		get.Offset = len(f.Script)
+		defer func() { get.Offset = old }()
+		require.Error(t, compiler.ValidateMethodOffsets(f.Script, m))
+	})
+	t.Run("no INITSLOT", func(t *testing.T) {
+		sum := m.ABI.GetMethod("sum", 2)
+		get := m.ABI.GetMethod("get", 0)
+		old := sum.Offset
+		sum.Offset = get.Offset
+		defer func() { sum.Offset = old }()
+		require.Error(t, compiler.ValidateMethodOffsets(f.Script, m))
+	})
+	t.Run("PUSHDATA operand", func(t *testing.T) {
+		script := []byte{byte(opcode.PUSHDATA1), 3, 'a', 'b', 'c', byte(opcode.RET)}
+		mm := manifest.NewManifest("Test")
+		mm.ABI.Methods = []manifest.Method{{
+			Name:       "main",
+			Offset:     0,
+			ReturnType: smartcontract.ByteArrayType,
+		}}
+		require.NoError(t, compiler.ValidateMethodOffsets(script, mm))
+
+		mm.ABI.Methods[0].Offset = 2
+		require.Error(t, compiler.ValidateMethodOffsets(script, mm))
+	})
+}
+
 func TestSafeMethodWarnings(t *testing.T) {
 	src := `package payable
 		func Main() int { return 1 }`
@@ -33,6 +33,10 @@ type DebugInfo struct {
 	InvokedContracts map[util.Uint160][]string `json:"-"`
 	// StaticVariables contains list of static variable names and types.
 	StaticVariables []string `json:"static-variables"`
+	// StructTypes contains field names and types (in the order of
+	// declaration) of named structs used in the contract, keys are
+	// package-qualified type names.
+	StructTypes map[string][]DebugParam `json:"struct-types,omitempty"`
 }
 
 // MethodDebugInfo represents smart-contract's method debug information.
@@ -47,6 +51,8 @@ type MethodDebugInfo struct {
 	IsExported bool `json:"-"`
 	// IsFunction defines whether method has no receiver.
 	IsFunction bool `json:"-"`
+	// IsSafe defines whether method is marked as safe in the manifest.
+	IsSafe bool `json:"safe,omitempty"`
 	// Range is the range of smart-contract's opcodes corresponding to the method.
 	Range DebugRange `json:"range"`
 	// Parameters is a list of method's parameters.
@@ -57,7 +63,11 @@ type MethodDebugInfo struct {
 	ReturnTypeReal binding.Override `json:"-"`
 	// ReturnTypeSC is return type to use in manifest.
 	ReturnTypeSC smartcontract.ParamType `json:"-"`
-	Variables    []string                `json:"variables"`
+	// ReturnTypes is a list of method's return values for methods with
+	// multiple ones (ReturnType is Any then). Names are only set for
+	// named results.
+	ReturnTypes []DebugParam `json:"returns,omitempty"`
+	Variables   []string     `json:"variables"`
 	// SeqPoints is a map between source lines and byte-code instruction offsets.
 	SeqPoints []DebugSeqPoint `json:"sequence-points"`
 }
@@ -133,6 +143,9 @@ func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 		Documents:       c.documents,
 		StaticVariables: c.staticVariables,
 	}
+	if len(c.structTypes) != 0 {
+		d.StructTypes = c.structTypes
+	}
 	if c.initEndOffset > 0 {
 		d.Methods = append(d.Methods, MethodDebugInfo{
 			ID: manifest.MethodInit,
@@ -193,7 +206,15 @@ func (c *codegen) emitDebugInfo(contract []byte) *DebugInfo {
 		d.Methods = append(d.Methods, *m)
 	}
 	sort.Slice(d.Methods[start:], func(i, j int) bool {
-		return d.Methods[start+i].Name.Name < d.Methods[start+j].Name.Name
+		mi, mj := &d.Methods[start+i], &d.Methods[start+j]
+		if mi.Name.Name != mj.Name.Name {
+			return mi.Name.Name < mj.Name.Name
+		}
+		// Different packages can have methods with the same name.
+		if mi.Name.Namespace != mj.Name.Namespace {
+			return mi.Name.Namespace < mj.Name.Namespace
+		}
+		return mi.Range.Start < mj.Range.Start
 	})
 	d.EmittedEvents = c.emittedEvents
 	d.InvokedContracts = c.invokedContracts
@@ -209,6 +230,36 @@ func (c *codegen) registerDebugVariable(name string, expr ast.Expr) {
 	c.scope.variables = append(c.scope.variables, name+","+vt.String())
 }
 
+// registerStructType saves field names and types of t for debug info if it's a
+// named struct, it does nothing for already registered types.
+func (c *codegen) registerStructType(t types.Type) {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return
+	}
+	strct, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return
+	}
+	name := named.Obj().Pkg().Name() + "." + named.Obj().Name()
+	if _, ok := c.structTypes[name]; ok {
+		return
+	}
+	fields := make([]DebugParam, strct.NumFields())
+	// Register before processing fields, they can refer to the same type.
+	c.structTypes[name] = fields
+	for i := range fields {
+		f := strct.Field(i)
+		st, vt, rt := c.scAndVMTypeFromType(f.Type())
+		fields[i] = DebugParam{
+			Name:     f.Name(),
+			Type:     vt.String(),
+			RealType: rt,
+			TypeSC:   st,
+		}
+	}
+}
+
 func (c *codegen) methodInfoFromScope(name string, scope *funcScope) *MethodDebugInfo {
 	ps := scope.decl.Type.Params
 	params := make([]DebugParam, 0, ps.NumFields())
@@ -227,25 +278,52 @@ func (c *codegen) methodInfoFromScope(name string, scope *funcScope) *MethodDebu
 	name = ss[len(ss)-1]
 	r, n := utf8.DecodeRuneInString(name)
 	st, vt, rt := c.scAndVMReturnTypeFromScope(scope)
+	var results []DebugParam
+	if scope.decl.Type.Results.NumFields() > 1 {
+		results = c.resultsFromScope(scope)
+	}
+
+	mName := string(unicode.ToLower(r)) + name[n:]
 
 	return &MethodDebugInfo{
 		ID: name,
 		Name: DebugMethodName{
-			Name:      string(unicode.ToLower(r)) + name[n:],
+			Name:      mName,
 			Namespace: scope.pkg.Name(),
 		},
 		IsExported:     scope.decl.Name.IsExported(),
 		IsFunction:     scope.decl.Recv == nil,
+		IsSafe:         c.isSafeMethod(mName),
 		Range:          scope.rng,
 		Parameters:     params,
 		ReturnType:     vt,
 		ReturnTypeReal: rt,
 		ReturnTypeSC:   st,
+		ReturnTypes:    results,
 		SeqPoints:      c.sequencePoints[name],
 		Variables:      scope.variables,
 	}
 }
 
+// isSafeMethod returns true if the method with the given name is marked as safe
+// in compiler options. Overloaded methods are checked using the name they're
+// emitted with.
+func (c *codegen) isSafeMethod(name string) bool {
+	o := c.buildInfo.options
+	if o == nil {
+		return false
+	}
+	if emitName, ok := o.Overloads[name]; ok {
+		name = emitName
+	}
+	for i := range o.SafeMethods {
+		if name == o.SafeMethods[i] {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *codegen) scAndVMReturnTypeFromScope(scope *funcScope) (smartcontract.ParamType, string, binding.Override) {
 	results := scope.decl.Type.Results
 	switch results.NumFields() {
@@ -255,11 +333,40 @@ func (c *codegen) scAndVMReturnTypeFromScope(scope *funcScope) (smartcontract.Pa
 		st, vt, s := c.scAndVMTypeFromExpr(results.List[0].Type)
 		return st, vt.String(), s
 	default:
-		// multiple return values are not supported in debugger
+		// Multiple return values are left on the stack as separate items,
+		// they're not supported in manifest and are described by ReturnTypes
+		// for debuggers.
 		return smartcontract.AnyType, "Any", binding.Override{}
 	}
 }
 
+// resultsFromScope returns the types of all function results. The error type
+// (usually used in (T, error) idiom) is kept as is in the RealType.
+func (c *codegen) resultsFromScope(scope *funcScope) []DebugParam {
+	rs := scope.decl.Type.Results
+	results := make([]DebugParam, 0, rs.NumFields())
+	errType := types.Universe.Lookup("error").Type()
+	for i := range rs.List {
+		names := rs.List[i].Names
+		if len(names) == 0 {
+			names = []*ast.Ident{{}}
+		}
+		for j := range names {
+			st, vt, rt := c.scAndVMTypeFromExpr(rs.List[i].Type)
+			if t := c.typeOf(rs.List[i].Type); t != nil && types.Identical(t, errType) {
+				rt = binding.Override{TypeName: "error"}
+			}
+			results = append(results, DebugParam{
+				Name:     names[j].Name,
+				Type:     vt.String(),
+				RealType: rt,
+				TypeSC:   st,
+			})
+		}
+	}
+	return results
+}
+
 func scAndVMInteropTypeFromExpr(named *types.Named, isPointer bool) (smartcontract.ParamType, stackitem.Type, binding.Override) {
 	name := named.Obj().Name()
 	pkg := named.Obj().Pkg().Name()
@@ -325,6 +432,12 @@ func (c *codegen) scAndVMTypeFromType(t types.Type) (smartcontract.ParamType, st
 		switch {
 		case info&types.IsInteger != 0:
 			over.TypeName = "int"
+			if isNamed && named.Obj().Pkg() != nil {
+				// Named integer types (like `type Status int`) are kept
+				// for bindings, they're still integers for the VM.
+				over.Package = named.Obj().Pkg().Path()
+				over.TypeName = named.Obj().Pkg().Name() + "." + named.Obj().Name()
+			}
 			return smartcontract.IntegerType, stackitem.IntegerT, over
 		case info&types.IsBoolean != 0:
 			over.TypeName = "bool"
@@ -344,6 +457,7 @@ func (c *codegen) scAndVMTypeFromType(t types.Type) (smartcontract.ParamType, st
 		if isNamed {
 			over.Package = named.Obj().Pkg().Path()
 			over.TypeName = named.Obj().Pkg().Name() + "." + named.Obj().Name()
+			c.registerStructType(named)
 		}
 		return smartcontract.ArrayType, stackitem.StructT, over
 	case *types.Slice:
@@ -428,6 +542,7 @@ func (m *MethodDebugInfo) ToManifestMethod() manifest.Method {
 	result.Offset = int(m.Range.Start)
 	result.Parameters = parameters
 	result.ReturnType = m.ReturnTypeSC
+	result.Safe = m.IsSafe
 	return result
 }
 
@@ -475,6 +590,31 @@ func parsePairJSON(data []byte, sep string) (string, string, error) {
 	return ss[0], ss[1], nil
 }
 
+// PositionForOffset returns the source document, line and column of the
+// nearest sequence point at or before the given opcode offset within the
+// method containing this offset. ok is false if the offset doesn't belong to
+// any method or there is no such sequence point in it.
+func (di *DebugInfo) PositionForOffset(offset int) (doc string, line int, col int, ok bool) {
+	for i := range di.Methods {
+		m := &di.Methods[i]
+		if offset < int(m.Range.Start) || offset > int(m.Range.End) {
+			continue
+		}
+		var sp *DebugSeqPoint
+		for j := range m.SeqPoints {
+			p := &m.SeqPoints[j]
+			if p.Opcode <= offset && (sp == nil || p.Opcode > sp.Opcode) {
+				sp = p
+			}
+		}
+		if sp == nil || sp.Document < 0 || sp.Document >= len(di.Documents) {
+			return "", 0, 0, false
+		}
+		return di.Documents[sp.Document], sp.StartLine, sp.StartCol, true
+	}
+	return "", 0, 0, false
+}
+
 // ConvertToManifest converts contract to the manifest.Manifest struct for debugger.
 // Note: manifest is taken from the external source, however it can be generated ad-hoc. See #1038.
 func (di *DebugInfo) ConvertToManifest(o *Options) (*manifest.Manifest, error) {
@@ -329,6 +329,135 @@ func TestSequencePoints(t *testing.T) {
 	require.Equal(t, 6, ps[1].StartLine)
 }
 
+func TestSequencePointsImportedPackage(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/compiler/testdata/foo"
+	func Main() int {
+		return foo.Bar() + 1
+	}`
+
+	_, d, err := CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+
+	// Helper package is inside of the compiled package directory, so its
+	// document is named relative to it.
+	const helperDoc = "testdata/foo/foo.go"
+	require.Equal(t, 2, len(d.Documents))
+	require.Contains(t, d.Documents, "foo.go")
+	require.Contains(t, d.Documents, helperDoc)
+
+	var mainFound, barFound bool
+	for _, m := range d.Methods {
+		var expected string
+		switch m.ID {
+		case "Main":
+			expected, mainFound = "foo.go", true
+		case "Bar":
+			expected, barFound = helperDoc, true
+		default:
+			continue
+		}
+		require.NotEmpty(t, m.SeqPoints, m.ID)
+		for _, sp := range m.SeqPoints {
+			require.True(t, sp.Document >= 0 && sp.Document < len(d.Documents), m.ID)
+			require.Equal(t, expected, d.Documents[sp.Document], m.ID)
+		}
+	}
+	require.True(t, mainFound)
+	require.True(t, barFound)
+}
+
+func TestSequencePointsNEFOffsets(t *testing.T) {
+	src := `package foo
+	func Main(op string) bool {
+		if op == "123" {
+			return true
+		}
+		return Other() == 42
+	}
+	func Other() int {
+		return 42
+	}`
+
+	f, d, err := CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+
+	// Every sequence point here is a `return` statement pointing to RET, jumps
+	// before them are shortened and `Other` has no INITSLOT in the final script.
+	var cnt int
+	for _, m := range d.Methods {
+		for _, sp := range m.SeqPoints {
+			require.True(t, sp.Opcode < len(f.Script), sp.Opcode)
+			require.Equal(t, opcode.RET, opcode.Opcode(f.Script[sp.Opcode]),
+				"%s: line %d, offset %d", m.ID, sp.StartLine, sp.Opcode)
+			require.True(t, int(m.Range.Start) <= sp.Opcode && sp.Opcode <= int(m.Range.End))
+			cnt++
+		}
+	}
+	require.Equal(t, 3, cnt)
+}
+
+func TestDebugInfo_PositionForOffset(t *testing.T) {
+	src := `package foo
+	func Main(op string) bool {
+		if op == "123" {
+			return true
+		}
+		return Other() == 42
+	}
+	func Other() int {
+		return 42
+	}`
+
+	f, d, err := CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+
+	for _, m := range d.Methods {
+		for _, sp := range m.SeqPoints {
+			doc, line, col, ok := d.PositionForOffset(sp.Opcode)
+			require.True(t, ok)
+			require.Equal(t, d.Documents[sp.Document], doc)
+			require.Equal(t, sp.StartLine, line)
+			require.Equal(t, sp.StartCol, col)
+		}
+	}
+
+	var main, other *MethodDebugInfo
+	for i := range d.Methods {
+		switch d.Methods[i].ID {
+		case "Main":
+			main = &d.Methods[i]
+		case "Other":
+			other = &d.Methods[i]
+		}
+	}
+	require.NotNil(t, main)
+	require.NotNil(t, other)
+	require.Equal(t, 2, len(main.SeqPoints))
+
+	t.Run("before the first point", func(t *testing.T) {
+		_, _, _, ok := d.PositionForOffset(int(main.Range.Start))
+		require.False(t, ok)
+	})
+	t.Run("between points", func(t *testing.T) {
+		// The `if` body is followed by the code of the last statement.
+		_, line, _, ok := d.PositionForOffset(main.SeqPoints[1].Opcode - 1)
+		require.True(t, ok)
+		require.Equal(t, main.SeqPoints[0].StartLine, line)
+	})
+	t.Run("other method", func(t *testing.T) {
+		_, line, _, ok := d.PositionForOffset(int(other.Range.End))
+		require.True(t, ok)
+		require.Equal(t, 9, line)
+	})
+	t.Run("out of script", func(t *testing.T) {
+		_, _, _, ok := d.PositionForOffset(len(f.Script))
+		require.False(t, ok)
+		_, _, _, ok = d.PositionForOffset(-1)
+		require.False(t, ok)
+	})
+}
+
 func TestDebugInfo_MarshalJSON(t *testing.T) {
 	d := &DebugInfo{
 		Documents: []string{"/path/to/file"},
@@ -345,6 +474,7 @@ func TestDebugInfo_MarshalJSON(t *testing.T) {
 					{Name: "ok", Type: "Boolean"},
 				},
 				ReturnType: "ByteString",
+				IsSafe:     true,
 				Variables:  []string{},
 				SeqPoints: []DebugSeqPoint{
 					{
@@ -359,11 +489,61 @@ func TestDebugInfo_MarshalJSON(t *testing.T) {
 			},
 		},
 		Events: []EventDebugInfo{},
+		StructTypes: map[string][]DebugParam{
+			"foo.Token": {
+				{Name: "Owner", Type: "ByteString"},
+				{Name: "Amount", Type: "Integer"},
+			},
+		},
 	}
 
 	testserdes.MarshalUnmarshalJSON(t, d, new(DebugInfo))
 }
 
+func TestDebugInfo_StructTypes(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/interop"
+	type Token struct {
+		Owner  interop.Hash160
+		Amount int
+		Meta   Meta
+		Nested []Token
+	}
+	type Meta struct {
+		Name string
+		Tags map[string]bool
+	}
+	type unused struct { A int }
+	type Local struct { Flag bool }
+	func Main(t Token) int {
+		l := Local{Flag: true}
+		_ = l
+		return t.Amount
+	}`
+
+	_, d, err := CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+
+	require.Equal(t, 3, len(d.StructTypes), d.StructTypes)
+	token := d.StructTypes["foo.Token"]
+	require.Equal(t, []string{"Owner", "Amount", "Meta", "Nested"}, []string{token[0].Name, token[1].Name, token[2].Name, token[3].Name})
+	require.Equal(t, []string{"ByteString", "Integer", "Struct", "Array"}, []string{token[0].Type, token[1].Type, token[2].Type, token[3].Type})
+	require.Equal(t, smartcontract.Hash160Type, token[0].TypeSC)
+	require.Equal(t, "foo.Meta", token[2].RealType.TypeName)
+	require.Equal(t, "[]foo.Token", token[3].RealType.TypeName)
+
+	meta := d.StructTypes["foo.Meta"]
+	require.Equal(t, 2, len(meta))
+	require.Equal(t, DebugParam{Name: "Name", Type: "ByteString", RealType: binding.Override{TypeName: "string"}, TypeSC: smartcontract.StringType}, meta[0])
+	require.Equal(t, "Tags", meta[1].Name)
+	require.Equal(t, smartcontract.MapType, meta[1].TypeSC)
+
+	local := d.StructTypes["foo.Local"]
+	require.Equal(t, 1, len(local))
+	require.Equal(t, "Flag", local[0].Name)
+	require.Equal(t, smartcontract.BoolType, local[0].TypeSC)
+}
+
 func TestManifestOverload(t *testing.T) {
 	src := `package foo
 	func Main() int {
@@ -412,3 +592,147 @@ func TestManifestOverload(t *testing.T) {
 		require.Error(t, err)
 	})
 }
+
+func TestDebugInfo_SafeMethods(t *testing.T) {
+	src := `package foo
+	func Get() int {
+		return 1
+	}
+	func Put() int {
+		return 2
+	}
+	func Sum() int {
+		return 3
+	}
+	func SumAux(a int) int {
+		return a + 3
+	}`
+
+	_, di, err := CompileWithOptions("foo.go", strings.NewReader(src), &Options{
+		SafeMethods: []string{"get", "sum"},
+		Overloads:   map[string]string{"sumAux": "sum"},
+	})
+	require.NoError(t, err)
+
+	safe := make(map[string]bool)
+	for _, m := range di.Methods {
+		safe[m.Name.Name] = m.IsSafe
+	}
+	require.Equal(t, map[string]bool{"get": true, "put": false, "sum": true, "sumAux": true}, safe)
+
+	// Debug info is enough to restore safe flags.
+	data, err := json.Marshal(di)
+	require.NoError(t, err)
+	actual := new(DebugInfo)
+	require.NoError(t, json.Unmarshal(data, actual))
+	require.Equal(t, len(di.Methods), len(actual.Methods))
+	for i := range actual.Methods {
+		require.Equal(t, di.Methods[i].IsSafe, actual.Methods[i].IsSafe)
+		require.Equal(t, di.Methods[i].IsSafe, actual.Methods[i].ToManifestMethod().Safe)
+	}
+
+	m, err := di.ConvertToManifest(&Options{Overloads: map[string]string{"sumAux": "sum"}})
+	require.NoError(t, err)
+	require.True(t, m.ABI.GetMethod("get", 0).Safe)
+	require.False(t, m.ABI.GetMethod("put", 0).Safe)
+	require.True(t, m.ABI.GetMethod("sum", 0).Safe)
+	require.True(t, m.ABI.GetMethod("sum", 1).Safe)
+}
+
+func TestDebugInfo_NamedIntegerTypes(t *testing.T) {
+	src := `package foo
+	type Status int
+	type Amount int64
+	func Main(s Status, a Amount, i int) Status {
+		return s + Status(a) + Status(i)
+	}
+	func Amounts() []Amount { return nil }`
+
+	_, d, err := CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+
+	for _, m := range d.Methods {
+		switch m.ID {
+		case "Main":
+			require.Equal(t, 3, len(m.Parameters))
+			for i, typ := range []string{"foo.Status", "foo.Amount", "int"} {
+				require.Equal(t, typ, m.Parameters[i].RealType.TypeName)
+				require.Equal(t, smartcontract.IntegerType, m.Parameters[i].TypeSC)
+				require.Equal(t, "Integer", m.Parameters[i].Type)
+			}
+			require.NotEmpty(t, m.Parameters[0].RealType.Package)
+			require.Empty(t, m.Parameters[2].RealType.Package)
+			require.Equal(t, m.Parameters[0].RealType, m.ReturnTypeReal)
+			require.Equal(t, smartcontract.IntegerType, m.ReturnTypeSC)
+		case "Amounts":
+			require.Equal(t, "[]foo.Amount", m.ReturnTypeReal.TypeName)
+			require.Equal(t, smartcontract.ArrayType, m.ReturnTypeSC)
+		}
+	}
+}
+
+func TestDebugInfo_MultipleReturns(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/interop"
+	func Main() int {
+		a, b := Tuple()
+		_, _ = Named()
+		_, _ = WithError()
+		return a + len(b)
+	}
+	func Tuple() (int, string) { return 1, "a" }
+	func Named() (h interop.Hash160, ok bool) { return nil, true }
+	func WithError() ([]byte, error) { return nil, nil }`
+
+	_, d, err := CompileWithOptions("foo.go", strings.NewReader(src), nil)
+	require.NoError(t, err)
+
+	expected := map[string][]DebugParam{
+		"Tuple": {
+			{Type: "Integer", RealType: binding.Override{TypeName: "int"}, TypeSC: smartcontract.IntegerType},
+			{Type: "ByteString", RealType: binding.Override{TypeName: "string"}, TypeSC: smartcontract.StringType},
+		},
+		"Named": {
+			{Name: "h", Type: "ByteString", RealType: binding.Override{
+				Package:  "github.com/nspcc-dev/neo-go/pkg/interop",
+				TypeName: "interop.Hash160",
+			}, TypeSC: smartcontract.Hash160Type},
+			{Name: "ok", Type: "Boolean", RealType: binding.Override{TypeName: "bool"}, TypeSC: smartcontract.BoolType},
+		},
+		"WithError": {
+			{Type: "ByteString", RealType: binding.Override{TypeName: "[]byte"}, TypeSC: smartcontract.ByteArrayType},
+			{Type: "Any", RealType: binding.Override{TypeName: "error"}, TypeSC: smartcontract.AnyType},
+		},
+	}
+	for i := range d.Methods {
+		m := d.Methods[i]
+		results, ok := expected[m.ID]
+		if !ok {
+			require.Nil(t, m.ReturnTypes)
+			continue
+		}
+		require.Equal(t, results, m.ReturnTypes, m.ID)
+		require.Equal(t, "Any", m.ReturnType, m.ID)
+		require.Equal(t, smartcontract.AnyType, m.ReturnTypeSC, m.ID)
+	}
+
+	m, err := d.ConvertToManifest(&Options{Name: "Foo"})
+	require.NoError(t, err)
+	require.Equal(t, smartcontract.AnyType, m.ABI.GetMethod("tuple", 0).ReturnType)
+
+	t.Run("JSON", func(t *testing.T) {
+		data, err := json.Marshal(d)
+		require.NoError(t, err)
+		actual := new(DebugInfo)
+		require.NoError(t, json.Unmarshal(data, actual))
+		for i := range actual.Methods {
+			m := actual.Methods[i]
+			results := expected[m.ID]
+			require.Equal(t, len(results), len(m.ReturnTypes), m.ID)
+			for j := range results {
+				require.Equal(t, results[j].Name, m.ReturnTypes[j].Name)
+				require.Equal(t, results[j].Type, m.ReturnTypes[j].Type)
+			}
+		}
+	})
+}
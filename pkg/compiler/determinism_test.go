@@ -0,0 +1,76 @@
+package compiler_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/stretchr/testify/require"
+)
+
+const determinismDir = "testdata/determinism"
+
+// determinismGoldenNEF is the hash of NEF file compiled from determinismDir
+// with fixed compiler name. It needs to be updated if code generation changes.
+const determinismGoldenNEF = "dbe2c9fb8232d1caa2f9e22db280688554d3dd90a375683393e0b9aee5468dfc"
+
+func compileDeterminism(t *testing.T, o *compiler.Options) (*nef.File, []byte, []byte) {
+	f, di, err := compiler.CompileWithOptions(determinismDir, nil, o)
+	require.NoError(t, err)
+	b, err := f.Bytes()
+	require.NoError(t, err)
+	diJSON, err := json.Marshal(di)
+	require.NoError(t, err)
+	return f, b, diJSON
+}
+
+func TestDeterministicOutput(t *testing.T) {
+	_, expNEF, expDI := compileDeterminism(t, nil)
+
+	procs := []int{1, 2, 4, runtime.NumCPU()}
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+	for i := 0; i < 20; i++ {
+		runtime.GOMAXPROCS(procs[i%len(procs)])
+		_, b, di := compileDeterminism(t, nil)
+		require.Equal(t, expNEF, b, "NEF differs at iteration %d", i)
+		require.JSONEq(t, string(expDI), string(di), "DebugInfo differs at iteration %d", i)
+	}
+
+	t.Run("documents", func(t *testing.T) {
+		_, di, err := compiler.CompileWithOptions(determinismDir, nil, nil)
+		require.NoError(t, err)
+		require.Subset(t, di.Documents, []string{
+			"main.go",
+			"util.go",
+			"github.com/nspcc-dev/neo-go/pkg/compiler/testdata/multi/file1.go",
+			"github.com/nspcc-dev/neo-go/pkg/interop/runtime/runtime.go",
+		})
+		for _, d := range di.Documents {
+			require.False(t, filepath.IsAbs(d), d)
+		}
+
+		_, di, err = compiler.CompileWithOptions(determinismDir, nil, &compiler.Options{AbsolutePaths: true})
+		require.NoError(t, err)
+		abs, err := filepath.Abs(filepath.Join(determinismDir, "main.go"))
+		require.NoError(t, err)
+		require.Contains(t, di.Documents, abs)
+		for _, d := range di.Documents {
+			require.True(t, filepath.IsAbs(d), d)
+		}
+	})
+
+	t.Run("golden", func(t *testing.T) {
+		f, _, _ := compileDeterminism(t, nil)
+		f.Compiler = "neo-go-test"
+		f.Checksum = f.CalculateChecksum()
+		b, err := f.Bytes()
+		require.NoError(t, err)
+		h := sha256.Sum256(b)
+		require.Equal(t, determinismGoldenNEF, hex.EncodeToString(h[:]))
+	})
+}
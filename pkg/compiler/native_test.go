@@ -129,7 +129,7 @@ type nativeTestCase struct {
 
 // Here we test that corresponding method does exist, is invoked and correct value is returned.
 func TestNativeHelpersCompile(t *testing.T) {
-	cfg := config.ProtocolConfiguration{P2PSigExtensions: true}
+	cfg := config.ProtocolConfiguration{P2PSigExtensions: true, HeaderCommitment: true, PolicyExtensions: true}
 	cs := native.NewContracts(cfg)
 	u160 := `interop.Hash160("aaaaaaaaaaaaaaaaaaaa")`
 	u256 := `interop.Hash256("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa")`
@@ -143,6 +143,7 @@ func TestNativeHelpersCompile(t *testing.T) {
 		{"transfer", []string{u160, u160, "123", "nil"}},
 	}
 	runNativeTestCases(t, cs.NEO.ContractMD, "neo", append([]nativeTestCase{
+		{"getAllCandidates", nil},
 		{"getCandidates", nil},
 		{"getCommittee", nil},
 		{"getGasPerBlock", nil},
@@ -167,13 +168,26 @@ func TestNativeHelpersCompile(t *testing.T) {
 		{"getDesignatedByRole", []string{"1", "1000"}},
 	})
 	runNativeTestCases(t, cs.Policy.ContractMD, "policy", []nativeTestCase{
+		{"addMaintenanceExempt", []string{u160}},
 		{"blockAccount", []string{u160}},
+		{"disableAttribute", []string{"1"}},
+		{"enableAttribute", []string{"1"}},
+		{"getAttributeFee", []string{"1"}},
+		{"getBlockedAccounts", []string{"1", "2"}},
 		{"getExecFeeFactor", nil},
 		{"getFeePerByte", nil},
+		{"getMaintenanceMode", nil},
+		{"getMaxBlockSystemFee", nil},
 		{"getStoragePrice", nil},
 		{"isBlocked", []string{u160}},
+		{"isBlockedMulti", []string{"[]interop.Hash160{}"}},
+		{"isMaintenanceExempt", []string{u160}},
+		{"removeMaintenanceExempt", []string{u160}},
+		{"setAttributeFee", []string{"1", "42"}},
 		{"setExecFeeFactor", []string{"42"}},
 		{"setFeePerByte", []string{"42"}},
+		{"setMaintenanceMode", []string{"true"}},
+		{"setMaxBlockSystemFee", []string{"42"}},
 		{"setStoragePrice", []string{"42"}},
 		{"unblockAccount", []string{u160}},
 	})
@@ -181,8 +195,12 @@ func TestNativeHelpersCompile(t *testing.T) {
 		{"currentHash", nil},
 		{"currentIndex", nil},
 		{"getBlock", []string{"1"}},
+		{"getBlockHeader", []string{"1"}},
+		{"getHeaderCommitment", []string{"1"}},
 		{"getTransaction", []string{u256}},
+		{"getTransactionData", []string{u256}},
 		{"getTransactionFromBlock", []string{u256, "1"}},
+		{"getTransactionsFromBlock", []string{u256, "1", "2"}},
 		{"getTransactionHeight", []string{u256}},
 		{"getTransactionSigners", []string{u256}},
 		{"getTransactionVMState", []string{u256}},
@@ -0,0 +1,117 @@
+package compiler
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"math"
+)
+
+// storagePkg is the path of the package providing storage schema API.
+const storagePkg = interopPrefix + "/storage"
+
+// Storage schema field types, they must match the ones defined in
+// storage package.
+const (
+	schemaIntegerField = iota + 1
+	schemaBoolField
+	schemaBytesField
+	schemaStringField
+	schemaStructField
+)
+
+// schemaCollection is a storage collection defined with storage.DefineMap or
+// storage.DefineValue.
+type schemaCollection struct {
+	name   string
+	prefix []byte
+	typ    int64
+	pos    token.Pos
+}
+
+// isSchemaDefinition returns true if the function with the given package path
+// and name defines storage collection.
+func isSchemaDefinition(pkgPath string, name string) bool {
+	return pkgPath == storagePkg && (name == "DefineMap" || name == "DefineValue")
+}
+
+// analyzeStorageSchema finds all storage collections defined in the program,
+// checks them and generates key prefixes for them.
+func (c *codegen) analyzeStorageSchema() error {
+	var (
+		err   error
+		fset  = c.buildInfo.config.Fset
+		names = make(map[string]*schemaCollection)
+	)
+	c.ForEachFile(func(f *ast.File, _ *types.Package) {
+		ast.Inspect(f, func(node ast.Node) bool {
+			if err != nil {
+				return false
+			}
+			call, ok := node.(*ast.CallExpr)
+			if !ok || !c.isSchemaDefinitionCall(call) {
+				return true
+			}
+			sc, e := c.newSchemaCollection(call)
+			if e != nil {
+				err = fmt.Errorf("%s: %w", fset.Position(call.Pos()), e)
+				return false
+			}
+			if prev, ok := names[sc.name]; ok {
+				err = fmt.Errorf("%s: storage collection '%s' is already defined at %s",
+					fset.Position(call.Pos()), sc.name, fset.Position(prev.pos))
+				return false
+			}
+			names[sc.name] = sc
+			c.storageSchema[call] = sc
+			return true
+		})
+	})
+	return err
+}
+
+// isSchemaDefinitionCall returns true if call is storage.DefineMap or
+// storage.DefineValue invocation.
+func (c *codegen) isSchemaDefinitionCall(call *ast.CallExpr) bool {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return false
+	}
+	f, ok := c.typeInfo.Uses[ident].(*types.Func)
+	return ok && f.Pkg() != nil && isSchemaDefinition(f.Pkg().Path(), f.Name())
+}
+
+// newSchemaCollection checks collection definition parameters and creates
+// a new collection from them.
+func (c *codegen) newSchemaCollection(call *ast.CallExpr) (*schemaCollection, error) {
+	nameVal := c.typeAndValueOf(call.Args[0]).Value
+	if nameVal == nil || nameVal.Kind() != constant.String {
+		return nil, errors.New("storage collection name must be a constant string")
+	}
+	name := constant.StringVal(nameVal)
+	if len(name) == 0 || len(name) > math.MaxUint8 {
+		return nil, fmt.Errorf("storage collection name length must be in 1..%d range", math.MaxUint8)
+	}
+	typVal := c.typeAndValueOf(call.Args[1]).Value
+	if typVal == nil {
+		return nil, fmt.Errorf("storage collection '%s' field type must be a constant", name)
+	}
+	typ, ok := constant.Int64Val(typVal)
+	if !ok || typ < schemaIntegerField || typ > schemaStructField {
+		return nil, fmt.Errorf("storage collection '%s' has unsupported field type %s", name, typVal)
+	}
+	return &schemaCollection{
+		name:   name,
+		prefix: append([]byte{byte(len(name))}, name...),
+		typ:    typ,
+		pos:    call.Pos(),
+	}, nil
+}
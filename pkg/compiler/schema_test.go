@@ -0,0 +1,132 @@
+package compiler_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageSchema(t *testing.T) {
+	src := `package foo
+	import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	type Point struct {
+		X, Y int
+	}
+	var (
+		balances = storage.DefineMap("b", storage.IntegerField)
+		points   = storage.DefineMap("points", storage.StructField)
+		owner    = storage.DefineValue("owner", storage.BytesField)
+	)
+	func PutBalance(key []byte, amount int) {
+		storage.MapPut(storage.GetContext(), balances, key, amount)
+	}
+	func GetBalance(key []byte) int {
+		return storage.MapGet(storage.GetReadOnlyContext(), balances, key).(int)
+	}
+	func DeleteBalance(key []byte) {
+		storage.MapDelete(storage.GetContext(), balances, key)
+	}
+	func PutPoint(key []byte, x, y int) {
+		storage.MapPut(storage.GetContext(), points, key, Point{X: x, Y: y})
+	}
+	func GetPointSum(key []byte) int {
+		p := storage.MapGet(storage.GetReadOnlyContext(), points, key).(Point)
+		return p.X + p.Y
+	}
+	func SetOwner(o []byte) {
+		storage.ValuePut(storage.GetContext(), owner, o)
+	}
+	func GetOwner() []byte {
+		return storage.ValueGet(storage.GetReadOnlyContext(), owner).([]byte)
+	}`
+
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	c := neotest.CompileSource(t, e.CommitteeHash, strings.NewReader(src), &compiler.Options{Name: "Schema"})
+	e.DeployContract(t, c, nil)
+	inv := e.CommitteeInvoker(c.Hash)
+	id := bc.GetContractState(c.Hash).ID
+
+	inv.Invoke(t, stackitem.Null{}, "putBalance", []byte{0xab}, 1000)
+	inv.Invoke(t, stackitem.Null{}, "putPoint", []byte{0xab}, 3, 4)
+	inv.Invoke(t, stackitem.Null{}, "setOwner", []byte{1, 2, 3})
+
+	// Keys are prefixed by collection name length and name.
+	require.Equal(t, bigint.ToBytes(big.NewInt(1000)), []byte(bc.GetStorageItem(id, []byte{1, 'b', 0xab})))
+	expected, err := stackitem.Serialize(stackitem.NewStruct([]stackitem.Item{
+		stackitem.Make(3), stackitem.Make(4),
+	}))
+	require.NoError(t, err)
+	require.Equal(t, expected, []byte(bc.GetStorageItem(id, append([]byte{6}, "points\xab"...))))
+	require.Equal(t, []byte{1, 2, 3}, []byte(bc.GetStorageItem(id, append([]byte{5}, "owner"...))))
+
+	inv.Invoke(t, 1000, "getBalance", []byte{0xab})
+	inv.Invoke(t, 7, "getPointSum", []byte{0xab})
+	inv.Invoke(t, stackitem.NewBuffer([]byte{1, 2, 3}), "getOwner")
+
+	inv.Invoke(t, stackitem.Null{}, "deleteBalance", []byte{0xab})
+	require.Nil(t, bc.GetStorageItem(id, []byte{1, 'b', 0xab}))
+	inv.Invoke(t, stackitem.Null{}, "getBalance", []byte{0xab})
+}
+
+func TestStorageSchemaErrors(t *testing.T) {
+	check := func(t *testing.T, src string, pos string, msg string) {
+		_, err := compiler.Compile("foo.go", strings.NewReader(src))
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), pos), err)
+		require.True(t, strings.Contains(err.Error(), msg), err)
+	}
+	t.Run("duplicate name", func(t *testing.T) {
+		src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+		var a = storage.DefineMap("a", storage.IntegerField)
+		var b = storage.DefineValue("a", storage.BytesField)
+		func Main() int {
+			return storage.MapGet(storage.GetContext(), a, nil).(int)
+		}`
+		check(t, src, "foo.go:4:", "storage collection 'a' is already defined at")
+	})
+	t.Run("unsupported field type", func(t *testing.T) {
+		src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+		var a = storage.DefineMap("a", storage.FieldType(42))
+		func Main() int {
+			return storage.MapGet(storage.GetContext(), a, nil).(int)
+		}`
+		check(t, src, "foo.go:3:", "unsupported field type")
+	})
+	t.Run("variable field type", func(t *testing.T) {
+		src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+		func Main(typ storage.FieldType) int {
+			a := storage.DefineMap("a", typ)
+			return storage.MapGet(storage.GetContext(), a, nil).(int)
+		}`
+		check(t, src, "foo.go:4:", "field type must be a constant")
+	})
+	t.Run("variable name", func(t *testing.T) {
+		src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+		func Main(name string) int {
+			a := storage.DefineMap(name, storage.IntegerField)
+			return storage.MapGet(storage.GetContext(), a, nil).(int)
+		}`
+		check(t, src, "foo.go:4:", "name must be a constant string")
+	})
+	t.Run("empty name", func(t *testing.T) {
+		src := `package foo
+		import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+		var a = storage.DefineValue("", storage.IntegerField)
+		func Main() int {
+			return storage.ValueGet(storage.GetContext(), a).(int)
+		}`
+		check(t, src, "foo.go:3:", "name length must be in")
+	})
+}
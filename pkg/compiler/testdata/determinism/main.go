@@ -0,0 +1,29 @@
+package determinism
+
+import "github.com/nspcc-dev/neo-go/pkg/compiler/testdata/multi"
+
+var counter = multi.SomeVar12
+
+func init() {
+	counter += 2
+}
+
+// Main uses a number of (nested) lambdas.
+func Main(a int) int {
+	f := func(x int) int {
+		g := func(y int) int {
+			return y * counter
+		}
+		return g(x) + 1
+	}
+	h := func(x int) int {
+		return x - multi.Sum()
+	}
+	k := func() bool {
+		return multi.Func1()
+	}
+	if k() {
+		return f(a) + h(a) + apply(a)
+	}
+	return Sum(a, counter)
+}
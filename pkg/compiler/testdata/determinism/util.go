@@ -0,0 +1,26 @@
+package determinism
+
+import "github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+
+var handlers = []func(int) int{
+	func(a int) int { return a + 1 },
+	func(a int) int { return a * 2 },
+	func(a int) int { return a - 3 },
+}
+
+func apply(a int) int {
+	for _, h := range handlers {
+		a = h(a)
+	}
+	return a
+}
+
+// Sum has the same name as multi.Sum.
+func Sum(a, b int) int {
+	return a + b
+}
+
+// Notify emits an event.
+func Notify() {
+	runtime.Notify("Event", counter, apply(counter))
+}
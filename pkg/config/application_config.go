@@ -30,4 +30,17 @@ type ApplicationConfiguration struct {
 	StateRoot         StateRoot               `yaml:"StateRoot"`
 	// ExtensiblePoolSize is the maximum amount of the extensible payloads from a single sender.
 	ExtensiblePoolSize int `yaml:"ExtensiblePoolSize"`
+	// TxRebroadcastThreshold is the number of blocks after which locally
+	// submitted transactions still in the mempool are rebroadcasted, 0
+	// disables it.
+	TxRebroadcastThreshold uint32 `yaml:"TxRebroadcastThreshold"`
+	// TxRebroadcastMaxRetries is the maximum number of rebroadcasts for a
+	// single transaction, 0 means no limit.
+	TxRebroadcastMaxRetries int `yaml:"TxRebroadcastMaxRetries"`
+	// MPTNodeCacheSize is the maximum number of decoded MPT nodes cached by
+	// the state module, 0 disables caching.
+	MPTNodeCacheSize int `yaml:"MPTNodeCacheSize"`
+	// ColdDBConfiguration is an optional secondary database for old blocks,
+	// it's not used if Type is not set.
+	ColdDBConfiguration storage.DBConfiguration `yaml:"ColdDBConfiguration"`
 }
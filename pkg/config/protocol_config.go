@@ -15,6 +15,11 @@ type (
 	ProtocolConfiguration struct {
 		// CommitteeHistory stores committee size change history (height: size).
 		CommitteeHistory map[uint32]int `yaml:"CommitteeHistory"`
+		// ColdStorageThreshold is the number of the latest blocks that are
+		// always kept in the primary storage when the cold storage is used,
+		// older blocks are moved to the cold one. It can't be less than
+		// MaxTraceableBlocks which is also its default value.
+		ColdStorageThreshold uint32 `yaml:"ColdStorageThreshold"`
 		// GarbageCollectionPeriod sets the number of blocks to wait before
 		// starting the next MPT garbage collection cycle when RemoveUntraceableBlocks
 		// option is used.
@@ -22,7 +27,20 @@ type (
 
 		Magic       netmode.Magic `yaml:"Magic"`
 		MemPoolSize int           `yaml:"MemPoolSize"`
+		// MemPoolSweepInterval is the interval (in seconds) between
+		// background mempool cleanups removing expired and conflicting
+		// transactions without waiting for the next block, 0 disables it.
+		MemPoolSweepInterval int `yaml:"MemPoolSweepInterval"`
 
+		// HeaderCommitment enables additional 32-byte application-defined
+		// commitment field in block headers (following PrevStateRoot) and
+		// Ledger contract getHeaderCommitment method. This value should remain
+		// the same for the same database.
+		HeaderCommitment bool `yaml:"HeaderCommitment"`
+		// EventJournalRetention is the number of latest blocks which events
+		// are kept in the node-local journal for durable subscribers, 0
+		// disables the journal.
+		EventJournalRetention uint32 `yaml:"EventJournalRetention"`
 		// InitialGASSupply is the amount of GAS generated in the genesis block.
 		InitialGASSupply fixedn.Fixed8 `yaml:"InitialGASSupply"`
 		// P2PNotaryRequestPayloadPoolSize specifies the memory pool size for P2PNotaryRequestPayloads.
@@ -34,10 +52,23 @@ type (
 		KeepOnlyLatestState bool `yaml:"KeepOnlyLatestState"`
 		// RemoveUntraceableBlocks specifies if old data should be removed.
 		RemoveUntraceableBlocks bool `yaml:"RemoveUntraceableBlocks"`
+		// MaintenanceModeEvents enables Policy contract notifications on
+		// maintenance mode changes, it requires PolicyExtensions.
+		MaintenanceModeEvents bool `yaml:"MaintenanceModeEvents"`
 		// MaxBlockSize is the maximum block size in bytes.
 		MaxBlockSize uint32 `yaml:"MaxBlockSize"`
 		// MaxBlockSystemFee is the maximum overall system fee per block.
 		MaxBlockSystemFee int64 `yaml:"MaxBlockSystemFee"`
+		// MaxBlockVerificationGAS is the maximum overall GAS consumed by
+		// witness verification of all transactions in a block, 0 means
+		// no limit.
+		MaxBlockVerificationGAS int64 `yaml:"MaxBlockVerificationGAS"`
+		// MaxNotifications is the maximum number of notifications and
+		// MaxNotificationsSize is the maximum aggregate size of them (event
+		// names plus serialized items) emitted by contracts in a single
+		// execution, 0 means no limit.
+		MaxNotifications     int `yaml:"MaxNotifications"`
+		MaxNotificationsSize int `yaml:"MaxNotificationsSize"`
 		// MaxTraceableBlocks is the length of the chain accessible to smart contracts.
 		MaxTraceableBlocks uint32 `yaml:"MaxTraceableBlocks"`
 		// MaxTransactionsPerBlock is the maximum amount of transactions per block.
@@ -52,6 +83,9 @@ type (
 		P2PSigExtensions bool `yaml:"P2PSigExtensions"`
 		// P2PStateExchangeExtensions enables additional P2P MPT state data exchange logic.
 		P2PStateExchangeExtensions bool `yaml:"P2PStateExchangeExtensions"`
+		// PolicyExtensions enables additional native Policy contract methods
+		// and events. This value should remain the same for the same database.
+		PolicyExtensions bool `yaml:"PolicyExtensions"`
 		// ReservedAttributes allows to have reserved attributes range for experimental or private purposes.
 		ReservedAttributes bool `yaml:"ReservedAttributes"`
 		// SaveStorageBatch enables storage batch saving before every persist.
@@ -64,7 +98,22 @@ type (
 		// StateSyncInterval is the number of blocks between state heights available for MPT state data synchronization.
 		// It is valid only if P2PStateExchangeExtensions are enabled.
 		StateSyncInterval int `yaml:"StateSyncInterval"`
-		ValidatorsCount   int `yaml:"ValidatorsCount"`
+		// TrackTxRelations enables node-local indexes of transactions
+		// related via Conflicts and OracleResponse attributes. This value
+		// should remain the same for the same database.
+		TrackTxRelations bool `yaml:"TrackTxRelations"`
+		// TrackSupplyDeltas enables per-block GAS and NEO total supply change
+		// records. This value should remain the same for the same database.
+		TrackSupplyDeltas bool `yaml:"TrackSupplyDeltas"`
+		// ValidateCallTokensHeight is the height starting from which NEF
+		// method tokens (CALLT targets) are checked against the manifest
+		// permissions on contract deployment and update, 0 disables it.
+		ValidateCallTokensHeight uint32 `yaml:"ValidateCallTokensHeight"`
+		ValidatorsCount          int    `yaml:"ValidatorsCount"`
+		// ValidatorsChangedEvents enables NEO contract ValidatorsChanged
+		// notifications emitted on next block validators change. This value
+		// should remain the same for the same database.
+		ValidatorsChangedEvents bool `yaml:"ValidatorsChangedEvents"`
 		// Validators stores history of changes to consensus node number (height: number).
 		ValidatorsHistory map[uint32]int `yaml:"ValidatorsHistory"`
 		// Whether to verify received blocks.
@@ -89,6 +138,18 @@ func (p *ProtocolConfiguration) Validate() error {
 	if p.KeepOnlyLatestState && p.P2PStateExchangeExtensions {
 		return errors.New("can't have both KeepOnlyLatestState and P2PStateExchangeExtensions")
 	}
+	if p.MaintenanceModeEvents && !p.PolicyExtensions {
+		return errors.New("MaintenanceModeEvents can't be used without PolicyExtensions")
+	}
+	if p.MaxBlockVerificationGAS < 0 {
+		return errors.New("MaxBlockVerificationGAS can't be negative")
+	}
+	if p.MaxNotifications < 0 || p.MaxNotificationsSize < 0 {
+		return errors.New("MaxNotifications and MaxNotificationsSize can't be negative")
+	}
+	if p.MemPoolSweepInterval < 0 {
+		return errors.New("MemPoolSweepInterval can't be negative")
+	}
 	for name := range p.NativeUpdateHistories {
 		if !nativenames.IsValid(name) {
 			return fmt.Errorf("NativeActivations configuration section contains unexpected native contract name: %s", name)
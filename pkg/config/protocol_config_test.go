@@ -20,6 +20,14 @@ func TestProtocolConfigurationValidation(t *testing.T) {
 		ValidatorsCount: 1,
 	}
 	require.Error(t, p.Validate())
+	p = &ProtocolConfiguration{
+		StandbyCommittee: []string{
+			"02b3622bf4017bdfe317c58aed5f4c753f206b7db896046fa7d774bbc4bf7f8dc2",
+		},
+		ValidatorsCount:       1,
+		MaintenanceModeEvents: true,
+	}
+	require.Error(t, p.Validate())
 	p = &ProtocolConfiguration{
 		NativeUpdateHistories: map[string][]uint32{
 			"someContract": {0, 10},
@@ -57,6 +57,8 @@ type Ledger interface {
 	SubscribeForBlocks(ch chan<- *coreb.Block)
 	UnsubscribeFromBlocks(ch chan<- *coreb.Block)
 	GetBaseExecFee() int64
+	GetMaxBlockSystemFee() int64
+	GetHeaderCommitment(index uint32) util.Uint256
 	interop.Ledger
 	mempool.Feer
 }
@@ -493,9 +495,9 @@ func (s *service) verifyBlock(b block.Block) bool {
 		}
 	}
 
-	maxBlockSysFee := s.ProtocolConfiguration.MaxBlockSystemFee
+	maxBlockSysFee := s.Chain.GetMaxBlockSystemFee()
 	if fee > maxBlockSysFee {
-		s.log.Warn("proposed block system fee exceeds config MaxBlockSystemFee",
+		s.log.Warn("proposed block system fee exceeds MaxBlockSystemFee",
 			zap.Int("max system fee allowed", int(maxBlockSysFee)),
 			zap.Int("block system fee", int(fee)))
 		return false
@@ -681,6 +683,10 @@ func (s *service) newBlockFromContext(ctx *dbft.Context) block.Block {
 		block.StateRootEnabled = true
 		block.PrevStateRoot = sr.Root
 	}
+	if s.ProtocolConfiguration.HeaderCommitment {
+		block.CommitmentEnabled = true
+		block.Commitment = s.Chain.GetHeaderCommitment(ctx.BlockIndex)
+	}
 
 	var validators keys.PublicKeys
 	var err error
@@ -67,28 +67,33 @@ func (b *Block) RebuildMerkleRoot() {
 // Blocks created from trimmed data will have their Trimmed field
 // set to true.
 func NewTrimmedFromReader(stateRootEnabled bool, br *io.BinReader) (*Block, error) {
-	block := &Block{
-		Header: Header{
-			StateRootEnabled: stateRootEnabled,
-		},
-		Trimmed: true,
+	block := New(stateRootEnabled)
+	if err := block.DecodeTrimmed(br); err != nil {
+		return nil, err
 	}
+	return block, nil
+}
 
-	block.Header.DecodeBinary(br)
+// DecodeTrimmed reads trimmed block data written by EncodeTrimmed from br into
+// b, header settings (StateRootEnabled and CommitmentEnabled) must be set
+// before decoding. Trimmed field is set to true.
+func (b *Block) DecodeTrimmed(br *io.BinReader) error {
+	b.Trimmed = true
+	b.Header.DecodeBinary(br)
 	lenHashes := br.ReadVarUint()
 	if lenHashes > MaxTransactionsPerBlock {
-		return nil, ErrMaxContentsPerBlock
+		return ErrMaxContentsPerBlock
 	}
+	b.Transactions = nil
 	if lenHashes > 0 {
-		block.Transactions = make([]*transaction.Transaction, lenHashes)
+		b.Transactions = make([]*transaction.Transaction, lenHashes)
 		for i := 0; i < int(lenHashes); i++ {
 			var hash util.Uint256
 			hash.DecodeBinary(br)
-			block.Transactions[i] = transaction.NewTrimmedTX(hash)
+			b.Transactions[i] = transaction.NewTrimmedTX(hash)
 		}
 	}
-
-	return block, br.Err
+	return br.Err
 }
 
 // New creates a new blank block with proper state root setting.
@@ -209,5 +214,8 @@ func (b *Block) GetExpectedBlockSizeWithoutTransactions(txCount int) int {
 	if b.StateRootEnabled {
 		size += util.Uint256Size
 	}
+	if b.CommitmentEnabled {
+		size += util.Uint256Size
+	}
 	return size
 }
@@ -46,6 +46,11 @@ type Header struct {
 	StateRootEnabled bool
 	// PrevStateRoot is state root of the previous block.
 	PrevStateRoot util.Uint256
+	// CommitmentEnabled specifies if header contains application-defined
+	// commitment.
+	CommitmentEnabled bool
+	// Commitment is an arbitrary application-defined commitment.
+	Commitment util.Uint256
 	// PrimaryIndex is the index of primary consensus node for this block.
 	PrimaryIndex byte
 
@@ -67,6 +72,7 @@ type baseAux struct {
 	NextConsensus string                `json:"nextconsensus"`
 	PrimaryIndex  byte                  `json:"primary"`
 	PrevStateRoot *util.Uint256         `json:"previousstateroot,omitempty"`
+	Commitment    *util.Uint256         `json:"commitment,omitempty"`
 	Witnesses     []transaction.Witness `json:"witnesses"`
 }
 
@@ -125,6 +131,9 @@ func (b *Header) encodeHashableFields(bw *io.BinWriter) {
 	if b.StateRootEnabled {
 		bw.WriteBytes(b.PrevStateRoot[:])
 	}
+	if b.CommitmentEnabled {
+		bw.WriteBytes(b.Commitment[:])
+	}
 }
 
 // decodeHashableFields decodes the fields used for hashing.
@@ -141,6 +150,9 @@ func (b *Header) decodeHashableFields(br *io.BinReader) {
 	if b.StateRootEnabled {
 		br.ReadBytes(b.PrevStateRoot[:])
 	}
+	if b.CommitmentEnabled {
+		br.ReadBytes(b.Commitment[:])
+	}
 
 	// Make the hash of the block here so we dont need to do this
 	// again.
@@ -166,6 +178,9 @@ func (b Header) MarshalJSON() ([]byte, error) {
 	if b.StateRootEnabled {
 		aux.PrevStateRoot = &b.PrevStateRoot
 	}
+	if b.CommitmentEnabled {
+		aux.Commitment = &b.Commitment
+	}
 	return json.Marshal(aux)
 }
 
@@ -208,6 +223,12 @@ func (b *Header) UnmarshalJSON(data []byte) error {
 		}
 		b.PrevStateRoot = *aux.PrevStateRoot
 	}
+	if b.CommitmentEnabled {
+		if aux.Commitment == nil {
+			return errors.New("'commitment' is empty")
+		}
+		b.Commitment = *aux.Commitment
+	}
 	if !aux.Hash.Equals(b.Hash()) {
 		return errors.New("json 'hash' doesn't match block hash")
 	}
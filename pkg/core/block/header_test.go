@@ -12,7 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func testHeaderEncodeDecode(t *testing.T, stateRootEnabled bool) {
+func testHeaderEncodeDecode(t *testing.T, stateRootEnabled bool, commitmentEnabled bool) {
 	header := Header{
 		Version:       0,
 		PrevHash:      hash.Sha256([]byte("prevhash")),
@@ -29,9 +29,13 @@ func testHeaderEncodeDecode(t *testing.T, stateRootEnabled bool) {
 		header.StateRootEnabled = stateRootEnabled
 		header.PrevStateRoot = random.Uint256()
 	}
+	if commitmentEnabled {
+		header.CommitmentEnabled = commitmentEnabled
+		header.Commitment = random.Uint256()
+	}
 
 	_ = header.Hash()
-	headerDecode := &Header{StateRootEnabled: stateRootEnabled}
+	headerDecode := &Header{StateRootEnabled: stateRootEnabled, CommitmentEnabled: commitmentEnabled}
 	testserdes.EncodeDecodeBinary(t, &header, headerDecode)
 
 	assert.Equal(t, header.Version, headerDecode.Version, "expected both versions to be equal")
@@ -42,13 +46,22 @@ func testHeaderEncodeDecode(t *testing.T, stateRootEnabled bool) {
 	assert.Equal(t, header.Script.InvocationScript, headerDecode.Script.InvocationScript, "expected equal invocation scripts")
 	assert.Equal(t, header.Script.VerificationScript, headerDecode.Script.VerificationScript, "expected equal verification scripts")
 	assert.Equal(t, header.PrevStateRoot, headerDecode.PrevStateRoot, "expected equal state roots")
+	assert.Equal(t, header.Commitment, headerDecode.Commitment, "expected equal commitments")
+
+	testserdes.MarshalUnmarshalJSON(t, &header, &Header{StateRootEnabled: stateRootEnabled, CommitmentEnabled: commitmentEnabled})
 }
 
 func TestHeaderEncodeDecode(t *testing.T) {
 	t.Run("NoStateRoot", func(t *testing.T) {
-		testHeaderEncodeDecode(t, false)
+		testHeaderEncodeDecode(t, false, false)
 	})
 	t.Run("WithStateRoot", func(t *testing.T) {
-		testHeaderEncodeDecode(t, true)
+		testHeaderEncodeDecode(t, true, false)
+	})
+	t.Run("WithCommitment", func(t *testing.T) {
+		testHeaderEncodeDecode(t, false, true)
+	})
+	t.Run("WithStateRootAndCommitment", func(t *testing.T) {
+		testHeaderEncodeDecode(t, true, true)
 	})
 }
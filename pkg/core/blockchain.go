@@ -3,6 +3,7 @@ package core
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
@@ -11,6 +12,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+	"unicode/utf8"
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
@@ -36,8 +38,10 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/clock"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"go.uber.org/zap"
@@ -59,6 +63,10 @@ const (
 	// HeaderVerificationGasLimit is the maximum amount of GAS for block header verification.
 	HeaderVerificationGasLimit = 3_00000000 // 3 GAS
 	defaultStateSyncInterval   = 40000
+
+	// coldStorageBatchSize is the maximum number of blocks moved to the cold
+	// storage at once.
+	coldStorageBatchSize = 1000
 )
 
 // stateJumpStage denotes the stage of state jump process.
@@ -89,6 +97,10 @@ var (
 	// ErrPolicy is returned on attempt to add transaction that doesn't
 	// comply with node's configured policy into the mempool.
 	ErrPolicy = errors.New("not allowed by policy")
+	// ErrMaintenanceMode is returned on attempt to add transaction not signed
+	// by the committee or an exempt account into the mempool when Policy
+	// contract maintenance mode is enabled. It wraps ErrPolicy.
+	ErrMaintenanceMode = fmt.Errorf("%w: maintenance mode", ErrPolicy)
 	// ErrInvalidBlockIndex is returned when trying to add block with index
 	// other than expected height of the blockchain.
 	ErrInvalidBlockIndex = errors.New("invalid block index")
@@ -96,9 +108,16 @@ var (
 	// conflicts with other transaction in the chain or pool according to
 	// Conflicts attribute.
 	ErrHasConflicts = errors.New("has conflicts")
+	// ErrBlockVerificationGAS is returned when witness verification of the
+	// block transactions consumes more than MaxBlockVerificationGAS.
+	ErrBlockVerificationGAS = errors.New("block verification GAS limit exceeded")
+	// ErrReadOnlyMode is returned by all methods changing the chain when
+	// the Blockchain is opened with NewReadOnlyBlockchain.
+	ErrReadOnlyMode = errors.New("blockchain is in read-only mode")
 )
 var (
-	persistInterval = 1 * time.Second
+	persistInterval     = 1 * time.Second
+	coldStorageInterval = 10 * time.Second
 )
 
 // Blockchain represents the blockchain. It maintans internal state representing
@@ -130,6 +149,12 @@ type Blockchain struct {
 	// Underlying persistent store.
 	store storage.Store
 
+	// cold is an optional secondary storage for old blocks, see
+	// SetColdStorage.
+	cold *dao.Simple
+	// coldHeight is the number of blocks moved to the cold storage.
+	coldHeight uint32
+
 	// Current index/height of the highest block.
 	// Read access should always be called by BlockHeight().
 	// Write access should only happen in storeBlock().
@@ -148,16 +173,31 @@ type Blockchain struct {
 	headerHashesLock sync.RWMutex
 	headerHashes     []util.Uint256
 
+	// clock is the time source for persisting and GC scheduling.
+	clock clock.Clock
+
+	// commitmentProvider returns header commitment for the new block with
+	// the given index (see HeaderCommitment protocol setting).
+	commitmentProvider func(index uint32) util.Uint256
+
 	// Stop synchronization mechanisms.
 	stopCh      chan struct{}
 	runToExitCh chan struct{}
 
 	memPool *mempool.Pool
 
+	// readOnly is set for Blockchain instances created via
+	// NewReadOnlyBlockchain, there is no mempool, notification
+	// subsystem and Run loop for them.
+	readOnly bool
+
 	// postBlock is a set of callback methods which should be run under the Blockchain lock after new block is persisted.
 	// Block's transactions are passed via mempool.
 	postBlock []func(func(*transaction.Transaction, *mempool.Pool, bool) bool, *mempool.Pool, *block.Block)
 
+	// rebroadcast tracks locally submitted transactions for rebroadcasting.
+	rebroadcast *txRebroadcaster
+
 	log *zap.Logger
 
 	lastBatch *storage.MemBatch
@@ -166,6 +206,9 @@ type Blockchain struct {
 
 	extensible atomic.Value
 
+	extensibleHandlersLock sync.RWMutex
+	extensibleHandlers     map[string]extensibleHandler
+
 	// knownValidatorsCount is the latest known validators count used
 	// for defaultBlockWitness.
 	knownValidatorsCount atomic.Value
@@ -177,8 +220,13 @@ type Blockchain struct {
 
 	// Notification subsystem.
 	events  chan bcEvent
+	headers chan *block.Header
+	reorgs  chan *ReorgEvent
 	subCh   chan interface{}
 	unsubCh chan interface{}
+
+	// journalSubs is a set of durable events journal subscribers.
+	journalSubs journalSubscribers
 }
 
 // bcEvent is an internal event generated by the Blockchain and then
@@ -190,6 +238,33 @@ type bcEvent struct {
 	appExecResults []*state.AppExecResult
 }
 
+// ReorgEvent describes a chain reorganization, blocks after CommonAncestor
+// (listed in Orphaned) are rolled back and replaced by blocks of the other
+// fork.
+type ReorgEvent struct {
+	// CommonAncestor is the height of the last block shared by both forks.
+	CommonAncestor uint32
+	// Orphaned contains hashes of the blocks rolled back, in ascending
+	// order of their heights.
+	Orphaned []util.Uint256
+}
+
+// FeeReport contains fee statistics aggregated over a range of blocks.
+type FeeReport struct {
+	// From and To are the first and the last block of the range.
+	From uint32
+	To   uint32
+	// SystemFee and NetworkFee are the total fees paid by transactions.
+	SystemFee  int64
+	NetworkFee int64
+	// GASBurned is the total amount of GAS burned in the range.
+	GASBurned int64
+	// TxCount is the number of transactions in the range.
+	TxCount int
+	// AverageFee is the average (system plus network) fee per transaction.
+	AverageFee int64
+}
+
 // transferData is used for transfer caching during storeBlock.
 type transferData struct {
 	Info  state.TokenTransferInfo
@@ -201,6 +276,21 @@ type transferData struct {
 // given Store as its underlying storage. For it to work correctly you need
 // to spawn a goroutine for its Run method after this initialization.
 func NewBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.Logger) (*Blockchain, error) {
+	return newBlockchain(s, cfg, log, false)
+}
+
+// NewReadOnlyBlockchain returns a new blockchain object that can only be used
+// to query the data stored in the given Store. The Store must contain a chain
+// created with compatible configuration, the same consistency checks as for
+// NewBlockchain are performed. Such Blockchain has no memory pool and
+// notification subsystem and doesn't need Run to be called, all methods
+// changing the chain (like AddBlock or PoolTx) return ErrReadOnlyMode. It's
+// safe for concurrent use, Close only closes the Store.
+func NewReadOnlyBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.Logger) (*Blockchain, error) {
+	return newBlockchain(s, cfg, log, true)
+}
+
+func newBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.Logger, readOnly bool) (*Blockchain, error) {
 	if log == nil {
 		return nil, errors.New("empty logger")
 	}
@@ -229,6 +319,11 @@ func NewBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.L
 		cfg.MaxTraceableBlocks = defaultMaxTraceableBlocks
 		log.Info("MaxTraceableBlocks is not set or wrong, using default value", zap.Uint32("MaxTraceableBlocks", cfg.MaxTraceableBlocks))
 	}
+	if cfg.ColdStorageThreshold == 0 {
+		cfg.ColdStorageThreshold = cfg.MaxTraceableBlocks
+	} else if cfg.ColdStorageThreshold < cfg.MaxTraceableBlocks {
+		return nil, errors.New("ColdStorageThreshold can't be less than MaxTraceableBlocks")
+	}
 	if cfg.MaxTransactionsPerBlock == 0 {
 		cfg.MaxTransactionsPerBlock = defaultMaxTransactionsPerBlock
 		log.Info("MaxTransactionsPerBlock is not set or wrong, using default value",
@@ -264,15 +359,25 @@ func NewBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.L
 		dao:         dao.NewSimple(s, cfg.StateRootInHeader, cfg.P2PSigExtensions),
 		persistent:  dao.NewSimple(s, cfg.StateRootInHeader, cfg.P2PSigExtensions),
 		store:       s,
-		stopCh:      make(chan struct{}),
-		runToExitCh: make(chan struct{}),
-		memPool:     mempool.New(cfg.MemPoolSize, 0, false),
+		clock:       clock.Real{},
+		rebroadcast: newTxRebroadcaster(),
 		log:         log,
-		events:      make(chan bcEvent),
-		subCh:       make(chan interface{}),
-		unsubCh:     make(chan interface{}),
+		readOnly:    readOnly,
 		contracts:   *native.NewContracts(cfg),
+
+		extensibleHandlers: make(map[string]extensibleHandler),
 	}
+	if !readOnly {
+		bc.stopCh = make(chan struct{})
+		bc.runToExitCh = make(chan struct{})
+		bc.memPool = mempool.New(cfg.MemPoolSize, 0, false)
+		bc.events = make(chan bcEvent)
+		bc.headers = make(chan *block.Header)
+		bc.reorgs = make(chan *ReorgEvent)
+		bc.subCh = make(chan interface{})
+		bc.unsubCh = make(chan interface{})
+	}
+	bc.registerNodeExtensibleHandlers()
 
 	bc.stateRoot = stateroot.NewModule(bc.GetConfig(), bc.VerifyWitness, bc.log, bc.dao.Store)
 	bc.contracts.Designate.StateRootService = bc.stateRoot
@@ -284,6 +389,72 @@ func NewBlockchain(s storage.Store, cfg config.ProtocolConfiguration, log *zap.L
 	return bc, nil
 }
 
+// SetColdStorage sets the secondary storage for old blocks. Blocks older than
+// ColdStorageThreshold are moved there in background along with their
+// transactions and execution results while headers, MPT and contract storage
+// always stay in the main storage. Once some blocks are moved, the same cold
+// storage must be used with this database. It's not protected by mutex and
+// must be called before `bc.Run()` to avoid data race. The store is closed when
+// the Blockchain is closed.
+func (bc *Blockchain) SetColdStorage(s storage.Store) error {
+	if bc.readOnly {
+		return ErrReadOnlyMode
+	}
+	if bc.config.RemoveUntraceableBlocks {
+		return errors.New("cold storage can't be used with RemoveUntraceableBlocks")
+	}
+	h, err := bc.dao.GetColdStorageHeight()
+	if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+		return fmt.Errorf("failed to get cold storage height: %w", err)
+	}
+	bc.cold = dao.NewSimple(s, bc.config.StateRootInHeader, bc.config.P2PSigExtensions)
+	bc.cold.Version.HeaderCommitment = bc.config.HeaderCommitment
+	atomic.StoreUint32(&bc.coldHeight, h)
+	return nil
+}
+
+// SetMPTNodeCacheSize sets the capacity of the state module cache of decoded
+// MPT nodes used for both latest and historic state reads, zero disables
+// caching. It's not protected by mutex and must be called before `bc.Run()`
+// to avoid data race.
+func (bc *Blockchain) SetMPTNodeCacheSize(capacity int) {
+	bc.stateRoot.SetNodeCacheSize(capacity)
+}
+
+// SetClock sets the time source used to schedule persisting of the in-memory
+// state to the storage, garbage collection and moving blocks to the cold
+// storage, the system clock is used by default. It's not protected by mutex
+// and must be called before `bc.Run()` to avoid data race.
+func (bc *Blockchain) SetClock(c clock.Clock) {
+	bc.clock = c
+}
+
+// SetHeaderCommitmentProvider sets the function returning application-defined
+// commitment for the new block with the given index, it's used by consensus
+// nodes to create blocks if HeaderCommitment protocol setting is enabled. It
+// must return the same value for the same index on all consensus nodes,
+// otherwise they won't agree on the block. It's not protected by mutex and
+// must be called before `bc.Run()` to avoid data race.
+func (bc *Blockchain) SetHeaderCommitmentProvider(f func(index uint32) util.Uint256) {
+	bc.commitmentProvider = f
+}
+
+// GetHeaderCommitment returns the commitment for the new block with the given
+// index from the provider set via SetHeaderCommitmentProvider, it's zero if
+// there is no provider.
+func (bc *Blockchain) GetHeaderCommitment(index uint32) util.Uint256 {
+	if bc.commitmentProvider == nil {
+		return util.Uint256{}
+	}
+	return bc.commitmentProvider(index)
+}
+
+// GetMPTNodeCacheStats returns the usage statistics of the state module cache
+// of decoded MPT nodes.
+func (bc *Blockchain) GetMPTNodeCacheStats() mpt.NodeCacheStats {
+	return bc.stateRoot.NodeCache().Stats()
+}
+
 // SetOracle sets oracle module. It doesn't protected by mutex and
 // must be called before `bc.Run()` to avoid data race.
 func (bc *Blockchain) SetOracle(mod services.Oracle) {
@@ -308,6 +479,9 @@ func (bc *Blockchain) init() error {
 	// If we could not find the version in the Store, we know that there is nothing stored.
 	ver, err := bc.dao.GetVersion()
 	if err != nil {
+		if bc.readOnly {
+			return fmt.Errorf("%w: no storage version found", ErrReadOnlyMode)
+		}
 		bc.log.Info("no storage version found! creating genesis block")
 		ver = dao.Version{
 			StoragePrefix:              storage.STStorage,
@@ -315,6 +489,7 @@ func (bc *Blockchain) init() error {
 			P2PSigExtensions:           bc.config.P2PSigExtensions,
 			P2PStateExchangeExtensions: bc.config.P2PStateExchangeExtensions,
 			KeepOnlyLatestState:        bc.config.KeepOnlyLatestState,
+			HeaderCommitment:           bc.config.HeaderCommitment,
 			Value:                      version,
 		}
 		bc.dao.PutVersion(ver)
@@ -350,6 +525,10 @@ func (bc *Blockchain) init() error {
 		return fmt.Errorf("KeepOnlyLatestState setting mismatch (old=%v, new=%v)",
 			ver.KeepOnlyLatestState, bc.config.KeepOnlyLatestState)
 	}
+	if ver.HeaderCommitment != bc.config.HeaderCommitment {
+		return fmt.Errorf("HeaderCommitment setting mismatch (old=%t, new=%t)",
+			ver.HeaderCommitment, bc.config.HeaderCommitment)
+	}
 	bc.dao.Version = ver
 	bc.persistent.Version = ver
 
@@ -415,6 +594,9 @@ func (bc *Blockchain) init() error {
 		if len(jumpStage) != 1 {
 			return fmt.Errorf("invalid state jump stage format")
 		}
+		if bc.readOnly {
+			return fmt.Errorf("%w: state jump was not completed", ErrReadOnlyMode)
+		}
 		// State jump wasn't finished yet, thus continue it.
 		stateSyncPoint, err := bc.dao.GetStateSyncPoint()
 		if err != nil {
@@ -439,37 +621,8 @@ func (bc *Blockchain) init() error {
 	}
 
 	// Check autogenerated native contracts' manifests and NEFs against the stored ones.
-	// Need to be done after native Management cache initialisation to be able to get
-	// contract state from DAO via high-level bc API.
-	for _, c := range bc.contracts.Contracts {
-		md := c.Metadata()
-		storedCS := bc.GetContractState(md.Hash)
-		history := md.UpdateHistory
-		if len(history) == 0 || history[0] > bHeight {
-			if storedCS != nil {
-				return fmt.Errorf("native contract %s is already stored, but marked as inactive for height %d in config", md.Name, bHeight)
-			}
-			continue
-		}
-		if storedCS == nil {
-			return fmt.Errorf("native contract %s is not stored, but should be active at height %d according to config", md.Name, bHeight)
-		}
-		storedCSBytes, err := stackitem.SerializeConvertible(storedCS)
-		if err != nil {
-			return fmt.Errorf("failed to check native %s state against autogenerated one: %w", md.Name, err)
-		}
-		autogenCS := &state.Contract{
-			ContractBase:  md.ContractBase,
-			UpdateCounter: storedCS.UpdateCounter, // it can be restored only from the DB, so use the stored value.
-		}
-		autogenCSBytes, err := stackitem.SerializeConvertible(autogenCS)
-		if err != nil {
-			return fmt.Errorf("failed to check native %s state against autogenerated one: %w", md.Name, err)
-		}
-		if !bytes.Equal(storedCSBytes, autogenCSBytes) {
-			return fmt.Errorf("native %s: version mismatch (stored contract state differs from autogenerated one), "+
-				"try to resynchronize the node from the genesis", md.Name)
-		}
+	if err = bc.checkNativeDrift(bHeight); err != nil {
+		return err
 	}
 
 	return bc.updateExtensibleWhitelist(bHeight)
@@ -479,6 +632,9 @@ func (bc *Blockchain) init() error {
 // specified by the state sync point p. All the data needed for the jump must be
 // collected by the state sync module.
 func (bc *Blockchain) jumpToState(p uint32) error {
+	if bc.readOnly {
+		return ErrReadOnlyMode
+	}
 	bc.addLock.Lock()
 	bc.lock.Lock()
 	defer bc.lock.Unlock()
@@ -615,9 +771,23 @@ func (bc *Blockchain) initializeNativeCache(blockHeight uint32, d *dao.Simple) e
 // Run runs chain loop, it needs to be run as goroutine and executing it is
 // critical for correct Blockchain operation.
 func (bc *Blockchain) Run() {
-	persistTimer := time.NewTimer(persistInterval)
+	if bc.readOnly {
+		return
+	}
+	var coldToExitCh, sweepToExitCh chan struct{}
+
+	persistTimer := bc.clock.NewTimer(persistInterval)
 	defer func() {
 		persistTimer.Stop()
+		if sweepToExitCh != nil {
+			<-sweepToExitCh
+		}
+		if coldToExitCh != nil {
+			<-coldToExitCh
+			if err := bc.cold.Store.Close(); err != nil {
+				bc.log.Warn("failed to close cold db", zap.Error(err))
+			}
+		}
 		if _, err := bc.persist(true); err != nil {
 			bc.log.Warn("failed to persist", zap.Error(err))
 		}
@@ -627,12 +797,20 @@ func (bc *Blockchain) Run() {
 		close(bc.runToExitCh)
 	}()
 	go bc.notificationDispatcher()
+	if bc.cold != nil {
+		coldToExitCh = make(chan struct{})
+		go bc.coldStorageWorker(coldToExitCh)
+	}
+	if bc.config.MemPoolSweepInterval > 0 {
+		sweepToExitCh = make(chan struct{})
+		go bc.memPoolSweeper(sweepToExitCh)
+	}
 	var nextSync bool
 	for {
 		select {
 		case <-bc.stopCh:
 			return
-		case <-persistTimer.C:
+		case <-persistTimer.C():
 			var oldPersisted uint32
 			var gcDur time.Duration
 
@@ -680,18 +858,122 @@ func (bc *Blockchain) tryRunGC(old uint32) time.Duration {
 	if tgtBlock > int64(bc.config.GarbageCollectionPeriod) && new != old {
 		tgtBlock /= int64(bc.config.GarbageCollectionPeriod)
 		tgtBlock *= int64(bc.config.GarbageCollectionPeriod)
-		dur = bc.stateRoot.GC(uint32(tgtBlock), bc.store)
-		dur += bc.removeOldTransfers(uint32(tgtBlock))
+		start := bc.clock.Now()
+		bc.stateRoot.GC(uint32(tgtBlock), bc.store)
+		bc.removeOldTransfers(uint32(tgtBlock))
+		dur = bc.clock.Now().Sub(start)
 	}
 	return dur
 }
 
+// coldStorageWorker periodically moves old blocks to the cold storage until
+// the Blockchain is stopped, it closes done channel on exit.
+func (bc *Blockchain) coldStorageWorker(done chan struct{}) {
+	defer close(done)
+
+	timer := bc.clock.NewTimer(coldStorageInterval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-bc.stopCh:
+			return
+		case <-timer.C():
+			n, err := bc.moveToColdStorage(coldStorageBatchSize)
+			if err != nil {
+				bc.log.Warn("failed to move blocks to cold storage", zap.Error(err))
+			}
+			interval := coldStorageInterval
+			if n == coldStorageBatchSize {
+				interval = time.Microsecond // There are more blocks to move.
+			}
+			timer.Reset(interval)
+		}
+	}
+}
+
+// memPoolSweeper periodically removes transactions that are no longer valid
+// from the mempool, see TrimMemPool.
+func (bc *Blockchain) memPoolSweeper(done chan struct{}) {
+	defer close(done)
+
+	ticker := bc.clock.NewTicker(time.Duration(bc.config.MemPoolSweepInterval) * time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-bc.stopCh:
+			return
+		case <-ticker.C():
+			if n := bc.TrimMemPool(); n != 0 {
+				bc.log.Debug("removed stale transactions from mempool", zap.Int("count", n))
+			}
+		}
+	}
+}
+
+// moveToColdStorage moves at most max blocks that are older than
+// ColdStorageThreshold to the cold storage and returns the number of blocks
+// moved. Blocks are written into the cold storage first, so if it fails or
+// the process is interrupted, the main storage remains intact and moving
+// resumes from the same block next time.
+func (bc *Blockchain) moveToColdStorage(max int) (int, error) {
+	var (
+		from      = atomic.LoadUint32(&bc.coldHeight)
+		persisted = atomic.LoadUint32(&bc.persistedHeight)
+	)
+	if persisted < bc.config.ColdStorageThreshold {
+		return 0, nil
+	}
+	to := persisted - bc.config.ColdStorageThreshold + 1
+	if to <= from {
+		return 0, nil
+	}
+	if to-from > uint32(max) {
+		to = from + uint32(max)
+	}
+
+	hot := dao.NewSimple(bc.dao.Store, bc.config.StateRootInHeader, bc.config.P2PSigExtensions)
+	hot.Version = bc.dao.Version
+	for i := from; i < to; i++ {
+		err := hot.ArchiveBlock(bc.cold, bc.GetHeaderHash(int(i)))
+		if err != nil {
+			return 0, fmt.Errorf("failed to move block %d: %w", i, err)
+		}
+	}
+	_, err := bc.cold.PersistSync()
+	if err != nil {
+		return 0, fmt.Errorf("failed to persist cold storage: %w", err)
+	}
+	hot.PutColdStorageHeight(to)
+	_, err = hot.Persist()
+	if err != nil {
+		return 0, err
+	}
+	atomic.StoreUint32(&bc.coldHeight, to)
+	bc.log.Debug("blocks moved to cold storage", zap.Uint32("from", from), zap.Uint32("to", to-1))
+	return int(to - from), nil
+}
+
+// getBlockDAO returns DAO holding the block with the given index along with its
+// transactions and execution results.
+func (bc *Blockchain) getBlockDAO(index uint32) *dao.Simple {
+	if index < atomic.LoadUint32(&bc.coldHeight) {
+		return bc.cold
+	}
+	return bc.dao
+}
+
+// coldStorageError wraps an error returned from the cold storage for the
+// given block.
+func coldStorageError(index uint32, err error) error {
+	return fmt.Errorf("block %d is untraceable, cold storage failure: %w", index, err)
+}
+
 func (bc *Blockchain) removeOldTransfers(index uint32) time.Duration {
 	bc.log.Info("starting transfer data garbage collection", zap.Uint32("index", index))
-	start := time.Now()
+	start := bc.clock.Now()
 	h, err := bc.GetHeader(bc.GetHeaderHash(int(index)))
 	if err != nil {
-		dur := time.Since(start)
+		dur := bc.clock.Now().Sub(start)
 		bc.log.Error("failed to find block header for transfer GC", zap.Duration("time", dur), zap.Error(err))
 		return dur
 	}
@@ -729,7 +1011,7 @@ func (bc *Blockchain) removeOldTransfers(index uint32) time.Duration {
 			break
 		}
 	}
-	dur := time.Since(start)
+	dur := bc.clock.Now().Sub(start)
 	if err != nil {
 		bc.log.Error("failed to flush transfer data GC changeset", zap.Duration("time", dur), zap.Error(err))
 	} else {
@@ -748,9 +1030,11 @@ func (bc *Blockchain) notificationDispatcher() {
 		// for ease of management (not a lot of subscriptions is really
 		// expected, but maps are convenient for adding/deleting elements).
 		blockFeed        = make(map[chan<- *block.Block]bool)
+		headerFeed       = make(map[chan<- *block.Header]bool)
 		txFeed           = make(map[chan<- *transaction.Transaction]bool)
 		notificationFeed = make(map[chan<- *subscriptions.NotificationEvent]bool)
 		executionFeed    = make(map[chan<- *state.AppExecResult]bool)
+		reorgFeed        = make(map[chan<- *ReorgEvent]bool)
 	)
 	for {
 		select {
@@ -760,12 +1044,16 @@ func (bc *Blockchain) notificationDispatcher() {
 			switch ch := sub.(type) {
 			case chan<- *block.Block:
 				blockFeed[ch] = true
+			case chan<- *block.Header:
+				headerFeed[ch] = true
 			case chan<- *transaction.Transaction:
 				txFeed[ch] = true
 			case chan<- *subscriptions.NotificationEvent:
 				notificationFeed[ch] = true
 			case chan<- *state.AppExecResult:
 				executionFeed[ch] = true
+			case chan<- *ReorgEvent:
+				reorgFeed[ch] = true
 			default:
 				panic(fmt.Sprintf("bad subscription: %T", sub))
 			}
@@ -773,15 +1061,27 @@ func (bc *Blockchain) notificationDispatcher() {
 			switch ch := unsub.(type) {
 			case chan<- *block.Block:
 				delete(blockFeed, ch)
+			case chan<- *block.Header:
+				delete(headerFeed, ch)
 			case chan<- *transaction.Transaction:
 				delete(txFeed, ch)
 			case chan<- *subscriptions.NotificationEvent:
 				delete(notificationFeed, ch)
 			case chan<- *state.AppExecResult:
 				delete(executionFeed, ch)
+			case chan<- *ReorgEvent:
+				delete(reorgFeed, ch)
 			default:
 				panic(fmt.Sprintf("bad unsubscription: %T", unsub))
 			}
+		case h := <-bc.headers:
+			for ch := range headerFeed {
+				ch <- h
+			}
+		case event := <-bc.reorgs:
+			for ch := range reorgFeed {
+				ch <- event
+			}
 		case event := <-bc.events:
 			// We don't want to waste time looping through transactions when there are no
 			// subscribers.
@@ -853,6 +1153,13 @@ func (bc *Blockchain) notificationDispatcher() {
 // Close stops Blockchain's internal loop, syncs changes to persistent storage
 // and closes it. The Blockchain is no longer functional after the call to Close.
 func (bc *Blockchain) Close() {
+	bc.stopJournalSubscribers()
+	if bc.readOnly {
+		if err := bc.dao.Store.Close(); err != nil {
+			bc.log.Warn("failed to close db", zap.Error(err))
+		}
+		return
+	}
 	// If there is a block addition in progress, wait for it to finish and
 	// don't allow new ones.
 	bc.addLock.Lock()
@@ -864,6 +1171,9 @@ func (bc *Blockchain) Close() {
 // AddBlock accepts successive block for the Blockchain, verifies it and
 // stores internally. Eventually it will be persisted to the backing storage.
 func (bc *Blockchain) AddBlock(block *block.Block) error {
+	if bc.readOnly {
+		return ErrReadOnlyMode
+	}
 	bc.addLock.Lock()
 	defer bc.addLock.Unlock()
 
@@ -876,6 +1186,10 @@ func (bc *Blockchain) AddBlock(block *block.Block) error {
 		return fmt.Errorf("%w: %v != %v",
 			ErrHdrStateRootSetting, bc.config.StateRootInHeader, block.StateRootEnabled)
 	}
+	if bc.config.HeaderCommitment != block.CommitmentEnabled {
+		return fmt.Errorf("%w: %v != %v",
+			ErrHdrCommitmentSetting, bc.config.HeaderCommitment, block.CommitmentEnabled)
+	}
 
 	if block.Index == bc.HeaderHeight()+1 {
 		err := bc.addHeaders(bc.config.VerifyBlocks, &block.Header)
@@ -888,6 +1202,25 @@ func (bc *Blockchain) AddBlock(block *block.Block) error {
 		if !block.MerkleRoot.Equals(merkle) {
 			return errors.New("invalid block: MerkleRoot mismatch")
 		}
+		// The limit can only be changed by the committee with Policy
+		// extensions enabled, otherwise it's checked by consensus nodes
+		// only (as it always was).
+		if bc.config.PolicyExtensions {
+			var sysFee int64
+			for _, tx := range block.Transactions {
+				sysFee += tx.SystemFee
+			}
+			if maxFee := bc.GetMaxBlockSystemFee(); sysFee > maxFee {
+				return fmt.Errorf("invalid block: system fee %d exceeds MaxBlockSystemFee %d", sysFee, maxFee)
+			}
+		}
+		var (
+			maxGAS = bc.config.MaxBlockVerificationGAS
+			verGAS int64
+			// params are the same for all transactions of the block, so
+			// they're retrieved once when needed.
+			params *txVerifyParams
+		)
 		mp = mempool.New(len(block.Transactions), 0, false)
 		for _, tx := range block.Transactions {
 			var err error
@@ -896,15 +1229,27 @@ func (bc *Blockchain) AddBlock(block *block.Block) error {
 			// it again even if we're verifying in-block transactions.
 			if bc.memPool.ContainsKey(tx.Hash()) {
 				err = mp.Add(tx, bc)
-				if err == nil {
-					continue
+				if err == nil && maxGAS > 0 {
+					verGAS += bc.getVerificationGAS(bc.memPool, tx)
 				}
 			} else {
-				err = bc.verifyAndPoolTx(tx, mp, bc)
+				// Maintenance mode is an admission policy, it
+				// doesn't affect already produced blocks.
+				if params == nil {
+					params = bc.getTxVerifyParams()
+				}
+				err = bc.verifyAndPoolTxInternal(tx, params, mp, bc)
+				if err == nil && maxGAS > 0 {
+					verGAS += bc.getVerificationGAS(mp, tx)
+				}
 			}
 			if err != nil && bc.config.VerifyTransactions {
 				return fmt.Errorf("transaction %s failed to verify: %w", tx.Hash().StringLE(), err)
 			}
+			if maxGAS > 0 && verGAS > maxGAS {
+				return fmt.Errorf("%w: transaction %s, %d > %d", ErrBlockVerificationGAS,
+					tx.Hash().StringLE(), verGAS, maxGAS)
+			}
 		}
 	}
 	return bc.storeBlock(block, mp)
@@ -913,6 +1258,9 @@ func (bc *Blockchain) AddBlock(block *block.Block) error {
 // AddHeaders processes the given headers and add them to the
 // HeaderHashList. It expects headers to be sorted by index.
 func (bc *Blockchain) AddHeaders(headers ...*block.Header) error {
+	if bc.readOnly {
+		return ErrReadOnlyMode
+	}
 	return bc.addHeaders(bc.config.VerifyBlocks, headers...)
 }
 
@@ -920,7 +1268,7 @@ func (bc *Blockchain) AddHeaders(headers ...*block.Header) error {
 // tells it to verify or not verify given headers).
 func (bc *Blockchain) addHeaders(verify bool, headers ...*block.Header) error {
 	var (
-		start = time.Now()
+		start = bc.clock.Now()
 		batch = bc.dao.GetPrivate()
 		err   error
 	)
@@ -952,43 +1300,62 @@ func (bc *Blockchain) addHeaders(verify bool, headers ...*block.Header) error {
 		}
 	}
 
+	added, err := bc.storeHeaders(batch, start, headers)
+	if err != nil {
+		return err
+	}
+	// Header hashes lock is released at this point, so subscribers can
+	// safely access the chain while handling events.
+	for _, h := range added {
+		bc.headers <- h
+	}
+	return nil
+}
+
+// storeHeaders stores given headers (skipping the ones that don't continue
+// the chain) and returns the list of headers that were added.
+func (bc *Blockchain) storeHeaders(batch *dao.Simple, start time.Time, headers []*block.Header) ([]*block.Header, error) {
 	bc.headerHashesLock.Lock()
 	defer bc.headerHashesLock.Unlock()
 	oldlen := len(bc.headerHashes)
-	var lastHeader *block.Header
+	var (
+		lastHeader *block.Header
+		added      []*block.Header
+	)
 	for _, h := range headers {
 		if int(h.Index) != len(bc.headerHashes) {
 			continue
 		}
-		err = batch.StoreHeader(h)
+		err := batch.StoreHeader(h)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		bc.headerHashes = append(bc.headerHashes, h.Hash())
 		lastHeader = h
+		added = append(added, h)
 	}
 
 	if oldlen != len(bc.headerHashes) {
 		for int(lastHeader.Index)-headerBatchCount >= int(bc.storedHeaderCount) {
-			err = batch.StoreHeaderHashes(bc.headerHashes[bc.storedHeaderCount:bc.storedHeaderCount+headerBatchCount],
+			err := batch.StoreHeaderHashes(bc.headerHashes[bc.storedHeaderCount:bc.storedHeaderCount+headerBatchCount],
 				bc.storedHeaderCount)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			bc.storedHeaderCount += headerBatchCount
 		}
 
 		batch.PutCurrentHeader(lastHeader.Hash(), lastHeader.Index)
 		updateHeaderHeightMetric(len(bc.headerHashes) - 1)
-		if _, err = batch.Persist(); err != nil {
-			return err
+		if _, err := batch.Persist(); err != nil {
+			return nil, err
 		}
 		bc.log.Debug("done processing headers",
 			zap.Int("headerIndex", len(bc.headerHashes)-1),
 			zap.Uint32("blockHeight", bc.BlockHeight()),
-			zap.Duration("took", time.Since(start)))
+			zap.Duration("took", bc.clock.Now().Sub(start)))
 	}
-	return nil
+	return added, nil
 }
 
 // GetStateModule returns state root service instance.
@@ -996,6 +1363,20 @@ func (bc *Blockchain) GetStateModule() blockchainer.StateRoot {
 	return bc.stateRoot
 }
 
+// VerifyStateRoot checks whether the given state root matches the one computed
+// locally for the given height. It returns an error if there is no local state
+// root for this height (if it's not yet processed or if it's already removed).
+func (bc *Blockchain) VerifyStateRoot(height uint32, root util.Uint256) (bool, error) {
+	if local := bc.stateRoot.CurrentLocalHeight(); height > local {
+		return false, fmt.Errorf("no state root for height %d, current local state height is %d", height, local)
+	}
+	sr, err := bc.stateRoot.GetStateRoot(height)
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve state root for height %d: %w", height, err)
+	}
+	return sr.Root == root, nil
+}
+
 // GetStateSyncModule returns new state sync service instance.
 func (bc *Blockchain) GetStateSyncModule() *statesync.Module {
 	return statesync.NewModule(bc, bc.stateRoot, bc.log, bc.dao, bc.jumpToState)
@@ -1019,6 +1400,7 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 			txCnt        int
 			baer1, baer2 *state.AppExecResult
 			transCache   = make(map[util.Uint160]transferData)
+			journalAERs  []*state.AppExecResult
 		)
 		kvcache.StoreAsCurrentBlock(block)
 		if bc.config.RemoveUntraceableBlocks {
@@ -1033,11 +1415,27 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 						start = stop - uint32(bc.config.StateSyncInterval)
 					}
 				}
-			} else if block.Index > bc.config.MaxTraceableBlocks {
-				start = block.Index - bc.config.MaxTraceableBlocks // is at least 1
+			} else if block.Index > bc.config.MaxTraceableBlocks+1 {
+				// The block becoming untraceable with this one is removed
+				// with the next block, it's still traceable for those who
+				// see the previous height until this block is fully stored
+				// (like concurrent test invocations).
+				start = block.Index - bc.config.MaxTraceableBlocks - 1 // is at least 1
 				stop = start + 1
 			}
 			for index := start; index < stop; index++ {
+				if bc.config.TrackTxRelations {
+					// Stored block is trimmed, so full transactions are needed.
+					b, err := kvcache.GetBlock(bc.headerHashes[index])
+					if err == nil {
+						for _, t := range b.Transactions {
+							tx, _, err := kvcache.GetTransaction(t.Hash())
+							if err == nil {
+								kvcache.DeleteTxRelations(tx)
+							}
+						}
+					}
+				}
 				err := kvcache.DeleteBlock(bc.headerHashes[index])
 				if err != nil {
 					bc.log.Warn("error while removing old block",
@@ -1047,6 +1445,9 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 			}
 		}
 		for aer := range aerchan {
+			if bc.config.EventJournalRetention > 0 {
+				journalAERs = append(journalAERs, aer)
+			}
 			if aer.Container == block.Hash() {
 				if baer1 == nil {
 					baer1 = aer
@@ -1055,6 +1456,9 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 				}
 			} else {
 				err = kvcache.StoreAsTransaction(block.Transactions[txCnt], block.Index, aer)
+				if bc.config.TrackTxRelations {
+					kvcache.StoreTxRelations(block.Transactions[txCnt], block.Index)
+				}
 				txCnt++
 			}
 			if err != nil {
@@ -1088,6 +1492,13 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 				kvcache.PutTokenTransferLog(acc, trData.Info.NextNEP17NewestTimestamp, trData.Info.NextNEP17Batch, false, &trData.Log17)
 			}
 		}
+		if bc.config.EventJournalRetention > 0 {
+			err = bc.journalBlock(kvcache, block, journalAERs)
+			if err != nil {
+				aerdone <- fmt.Errorf("failed to journal events: %w", err)
+				return
+			}
+		}
 		close(aerdone)
 	}()
 	aer, err := bc.runPersist(bc.contracts.GetPersistScript(), block, cache, trigger.OnPersist)
@@ -1147,6 +1558,16 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 		<-aerdone
 		return fmt.Errorf("postPersist failed: %w", err)
 	}
+	err = bc.contracts.GAS.CommitSupplyDelta(cache, block.Index)
+	if err == nil {
+		err = bc.contracts.NEO.CommitSupplyDelta(cache, block.Index)
+	}
+	if err != nil {
+		// Release goroutines, don't care about errors, we already have one.
+		close(aerchan)
+		<-aerdone
+		return fmt.Errorf("failed to save supply delta: %w", err)
+	}
 	appExecResults = append(appExecResults, aer)
 	aerchan <- aer
 	close(aerchan)
@@ -1210,6 +1631,7 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 	for _, f := range bc.postBlock {
 		f(bc.IsTxStillRelevant, txpool, block)
 	}
+	bc.rebroadcast.onBlock(block, bc.memPool)
 	if err := bc.updateExtensibleWhitelist(block.Index); err != nil {
 		bc.lock.Unlock()
 		return err
@@ -1217,6 +1639,7 @@ func (bc *Blockchain) storeBlock(block *block.Block, txpool *mempool.Pool) error
 	bc.lock.Unlock()
 
 	updateBlockHeightMetric(block.Index)
+	bc.wakeJournalSubscribers()
 	// Genesis block is stored when Blockchain is not yet running, so there
 	// is no one to read this event. And it doesn't make much sense as event
 	// anyway.
@@ -1272,7 +1695,7 @@ func (bc *Blockchain) updateExtensibleList(s *[]util.Uint160, pubs keys.PublicKe
 func (bc *Blockchain) IsExtensibleAllowed(u util.Uint160) bool {
 	us := bc.extensible.Load().([]util.Uint160)
 	n := sort.Search(len(us), func(i int) bool { return !us[i].Less(u) })
-	return n < len(us)
+	return n < len(us) && us[n].Equals(u)
 }
 
 func (bc *Blockchain) runPersist(script []byte, block *block.Block, cache *dao.Simple, trig trigger.Type) (*state.AppExecResult, error) {
@@ -1519,6 +1942,12 @@ func (bc *Blockchain) GetNotaryBalance(acc util.Uint160) *big.Int {
 	return bc.contracts.Notary.BalanceOf(bc.dao, acc)
 }
 
+// GetAttributesFee returns the sum of Policy-defined fees for all attributes of
+// the given transaction which is a part of its network fee.
+func (bc *Blockchain) GetAttributesFee(t *transaction.Transaction) int64 {
+	return bc.contracts.Policy.GetAttributesFeeInternal(bc.dao, t)
+}
+
 // GetNotaryServiceFeePerKey returns NotaryServiceFeePerKey which is a reward per
 // notary request key for designated notary nodes.
 func (bc *Blockchain) GetNotaryServiceFeePerKey() int64 {
@@ -1538,6 +1967,11 @@ func (bc *Blockchain) GetNotaryDepositExpiration(acc util.Uint160) uint32 {
 	return bc.contracts.Notary.ExpirationOf(bc.dao, acc)
 }
 
+// GetOracleRequest returns pending Oracle request with the specified ID.
+func (bc *Blockchain) GetOracleRequest(id uint64) (*state.OracleRequest, error) {
+	return bc.contracts.Oracle.GetRequestInternal(bc.dao, id)
+}
+
 // LastBatch returns last persisted storage batch.
 func (bc *Blockchain) LastBatch() *storage.MemBatch {
 	return bc.lastBatch
@@ -1546,7 +1980,7 @@ func (bc *Blockchain) LastBatch() *storage.MemBatch {
 // persist flushes current in-memory Store contents to the persistent storage.
 func (bc *Blockchain) persist(isSync bool) (time.Duration, error) {
 	var (
-		start     = time.Now()
+		start     = bc.clock.Now()
 		duration  time.Duration
 		persisted int
 		err       error
@@ -1572,7 +2006,7 @@ func (bc *Blockchain) persist(isSync bool) (time.Duration, error) {
 		if err != nil {
 			return 0, err
 		}
-		duration = time.Since(start)
+		duration = bc.clock.Now().Sub(start)
 		bc.log.Info("persisted to disk",
 			zap.Uint32("blocks", diff),
 			zap.Int("keys", persisted),
@@ -1589,16 +2023,348 @@ func (bc *Blockchain) persist(isSync bool) (time.Duration, error) {
 
 // GetTransaction returns a TX and its height by the given hash. The height is MaxUint32 if tx is in the mempool.
 func (bc *Blockchain) GetTransaction(hash util.Uint256) (*transaction.Transaction, uint32, error) {
-	if tx, ok := bc.memPool.TryGetValue(hash); ok {
-		return tx, math.MaxUint32, nil // the height is not actually defined for memPool transaction.
+	if bc.memPool != nil {
+		if tx, ok := bc.memPool.TryGetValue(hash); ok {
+			return tx, math.MaxUint32, nil // the height is not actually defined for memPool transaction.
+		}
+	}
+	tx, height, err := bc.dao.GetTransaction(hash)
+	if bc.cold != nil && errors.Is(err, storage.ErrKeyNotFound) {
+		tx, height, err = bc.cold.GetTransaction(hash)
+		if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+			return nil, 0, fmt.Errorf("transaction %s is untraceable, cold storage failure: %w", hash.StringLE(), err)
+		}
 	}
-	return bc.dao.GetTransaction(hash)
+	return tx, height, err
 }
 
 // GetAppExecResults returns application execution results with the specified trigger by the given
 // tx hash or block hash.
 func (bc *Blockchain) GetAppExecResults(hash util.Uint256, trig trigger.Type) ([]state.AppExecResult, error) {
-	return bc.dao.GetAppExecResults(hash, trig)
+	aers, err := bc.dao.GetAppExecResults(hash, trig)
+	if bc.cold == nil {
+		return aers, err
+	}
+	switch {
+	case errors.Is(err, storage.ErrKeyNotFound):
+		// May be a transaction moved to the cold storage.
+	case err == nil && len(aers) == 0:
+		// May be a block moved to the cold storage, its header is still here.
+		b, err := bc.dao.GetBlock(hash)
+		if err != nil || b.Index >= atomic.LoadUint32(&bc.coldHeight) {
+			return aers, nil
+		}
+	default:
+		return aers, err
+	}
+	aers, err = bc.cold.GetAppExecResults(hash, trig)
+	if err != nil && !errors.Is(err, storage.ErrKeyNotFound) {
+		return nil, fmt.Errorf("execution results of %s are untraceable, cold storage failure: %w", hash.StringLE(), err)
+	}
+	return aers, err
+}
+
+// GetBlockExecResults returns all execution results of the block with the
+// given hash in the order of execution: OnPersist result, Application result
+// of every block transaction and PostPersist result.
+func (bc *Blockchain) GetBlockExecResults(hash util.Uint256) ([]*state.AppExecResult, error) {
+	b, err := bc.GetBlock(hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %s: %w", hash.StringLE(), err)
+	}
+	sys, err := bc.GetAppExecResults(hash, trigger.OnPersist|trigger.PostPersist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %s execution results: %w", hash.StringLE(), err)
+	}
+	res := make([]*state.AppExecResult, 0, len(b.Transactions)+len(sys))
+	for i := range sys {
+		if sys[i].Trigger == trigger.OnPersist {
+			res = append(res, &sys[i])
+		}
+	}
+	for _, tx := range b.Transactions {
+		aers, err := bc.GetAppExecResults(tx.Hash(), trigger.Application)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transaction %s execution result: %w", tx.Hash().StringLE(), err)
+		}
+		if len(aers) == 0 {
+			return nil, fmt.Errorf("no execution result for transaction %s", tx.Hash().StringLE())
+		}
+		res = append(res, &aers[0])
+	}
+	for i := range sys {
+		if sys[i].Trigger == trigger.PostPersist {
+			res = append(res, &sys[i])
+		}
+	}
+	return res, nil
+}
+
+// FindNotifications returns all notifications with the given name emitted by
+// the given contract in blocks from `from` to `to` (inclusive). Notifications
+// are returned in the order they were emitted. If max is positive, at most max
+// notifications are returned. Blocks removed from the storage due to
+// RemoveUntraceableBlocks setting can't be searched through.
+func (bc *Blockchain) FindNotifications(contract util.Uint160, eventName string, from, to uint32, max int) ([]state.NotificationWithContext, error) {
+	height := bc.BlockHeight()
+	if to > height {
+		to = height
+	}
+	if from > to {
+		return nil, fmt.Errorf("invalid block range: %d-%d", from, to)
+	}
+	if bc.config.RemoveUntraceableBlocks && height > bc.config.MaxTraceableBlocks &&
+		from <= height-bc.config.MaxTraceableBlocks {
+		return nil, fmt.Errorf("block %d is untraceable and may be removed from the storage", from)
+	}
+
+	var res []state.NotificationWithContext
+	collect := func(index uint32, aers []state.AppExecResult) bool {
+		for i := range aers {
+			for _, ev := range aers[i].Events {
+				if ev.Name != eventName || !ev.ScriptHash.Equals(contract) {
+					continue
+				}
+				res = append(res, state.NotificationWithContext{
+					NotificationEvent: ev,
+					BlockIndex:        index,
+					Container:         aers[i].Container,
+				})
+				if max > 0 && len(res) >= max {
+					return false
+				}
+			}
+		}
+		return true
+	}
+	for i := from; i <= to; i++ {
+		d := bc.getBlockDAO(i)
+		b, err := d.GetBlock(bc.GetHeaderHash(int(i)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block %d: %w", i, err)
+		}
+		aers, err := d.GetAppExecResults(b.Hash(), trigger.OnPersist)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get OnPersist results of block %d: %w", i, err)
+		}
+		if !collect(i, aers) {
+			return res, nil
+		}
+		for _, tx := range b.Transactions {
+			aers, err = d.GetAppExecResults(tx.Hash(), trigger.Application)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get results of transaction %s: %w", tx.Hash().StringLE(), err)
+			}
+			if !collect(i, aers) {
+				return res, nil
+			}
+		}
+		aers, err = d.GetAppExecResults(b.Hash(), trigger.PostPersist)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get PostPersist results of block %d: %w", i, err)
+		}
+		if !collect(i, aers) {
+			return res, nil
+		}
+	}
+	return res, nil
+}
+
+// GetFeeReport returns fee statistics for blocks from `from` to `to`
+// (inclusive). Burned GAS amount is calculated from GAS Transfer notifications
+// with no recipient. Blocks removed from the storage due to
+// RemoveUntraceableBlocks setting can't be included.
+func (bc *Blockchain) GetFeeReport(from, to uint32) (FeeReport, error) {
+	var (
+		height = bc.BlockHeight()
+		gas    = bc.contracts.GAS.Hash
+		rep    = FeeReport{From: from, To: to}
+	)
+	if from > to || to > height {
+		return FeeReport{}, fmt.Errorf("invalid block range: %d-%d (height is %d)", from, to, height)
+	}
+	if bc.config.RemoveUntraceableBlocks && height > bc.config.MaxTraceableBlocks &&
+		from <= height-bc.config.MaxTraceableBlocks {
+		return FeeReport{}, fmt.Errorf("block %d is untraceable and may be removed from the storage", from)
+	}
+
+	countBurned := func(aers []state.AppExecResult) {
+		for i := range aers {
+			for _, ev := range aers[i].Events {
+				if ev.Name != "Transfer" || !ev.ScriptHash.Equals(gas) {
+					continue
+				}
+				args := ev.Item.Value().([]stackitem.Item)
+				if len(args) != 3 || args[1].Type() != stackitem.AnyT {
+					continue
+				}
+				amount, err := args[2].TryInteger()
+				if err != nil || !amount.IsInt64() {
+					continue
+				}
+				rep.GASBurned += amount.Int64()
+			}
+		}
+	}
+	for i := from; i <= to; i++ {
+		d := bc.getBlockDAO(i)
+		b, err := d.GetBlock(bc.GetHeaderHash(int(i)))
+		if err != nil {
+			return FeeReport{}, fmt.Errorf("failed to get block %d: %w", i, err)
+		}
+		for _, trig := range []trigger.Type{trigger.OnPersist, trigger.PostPersist} {
+			aers, err := d.GetAppExecResults(b.Hash(), trig)
+			if err != nil {
+				return FeeReport{}, fmt.Errorf("failed to get %s results of block %d: %w", trig, i, err)
+			}
+			countBurned(aers)
+		}
+		for _, t := range b.Transactions {
+			// Blocks are stored trimmed, so transactions are to be
+			// retrieved separately.
+			tx, _, err := d.GetTransaction(t.Hash())
+			if err != nil {
+				return FeeReport{}, fmt.Errorf("failed to get transaction %s: %w", t.Hash().StringLE(), err)
+			}
+			aers, err := d.GetAppExecResults(tx.Hash(), trigger.Application)
+			if err != nil {
+				return FeeReport{}, fmt.Errorf("failed to get results of transaction %s: %w", tx.Hash().StringLE(), err)
+			}
+			countBurned(aers)
+			rep.SystemFee += tx.SystemFee
+			rep.NetworkFee += tx.NetworkFee
+		}
+		rep.TxCount += len(b.Transactions)
+	}
+	if rep.TxCount != 0 {
+		rep.AverageFee = (rep.SystemFee + rep.NetworkFee) / int64(rep.TxCount)
+	}
+	return rep, nil
+}
+
+// GetSupplyDelta returns GAS and NEO total supply changes made in the block
+// with the given height. It requires TrackSupplyDeltas to be enabled.
+func (bc *Blockchain) GetSupplyDelta(height uint32) (*state.SupplyDelta, error) {
+	if !bc.config.TrackSupplyDeltas {
+		return nil, errors.New("supply delta tracking is disabled")
+	}
+	gas, err := bc.dao.GetSupplyDelta(bc.contracts.GAS.ID, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get GAS supply delta for block %d: %w", height, err)
+	}
+	neo, err := bc.dao.GetSupplyDelta(bc.contracts.NEO.ID, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NEO supply delta for block %d: %w", height, err)
+	}
+	return &state.SupplyDelta{GAS: *gas, NEO: *neo}, nil
+}
+
+// GetConflictingTransactions returns hashes of on-chain transactions declaring
+// a conflict with the given one via Conflicts attribute. It requires
+// TrackTxRelations to be enabled.
+func (bc *Blockchain) GetConflictingTransactions(hash util.Uint256) ([]util.Uint256, error) {
+	if !bc.config.TrackTxRelations {
+		return nil, errors.New("transaction relations tracking is disabled")
+	}
+	return bc.dao.GetConflictingTransactions(hash), nil
+}
+
+// GetOracleResponseTx returns hash of the on-chain transaction containing
+// response for the oracle request with the given ID. It requires
+// TrackTxRelations to be enabled.
+func (bc *Blockchain) GetOracleResponseTx(requestID uint64) (util.Uint256, error) {
+	if !bc.config.TrackTxRelations {
+		return util.Uint256{}, errors.New("transaction relations tracking is disabled")
+	}
+	h, err := bc.dao.GetOracleResponseTx(requestID)
+	if err != nil {
+		return util.Uint256{}, fmt.Errorf("failed to get response for oracle request %d: %w", requestID, err)
+	}
+	return h, nil
+}
+
+// MaxSupplyDeltaRange is the maximum number of blocks processed by a single
+// GetSupplyDeltaRange or VerifySupplyDeltas call.
+const MaxSupplyDeltaRange = 100000
+
+// checkSupplyDeltaRange returns an error if the given block range is invalid
+// or too big.
+func checkSupplyDeltaRange(from, to uint32) error {
+	if from > to {
+		return fmt.Errorf("invalid block range: %d-%d", from, to)
+	}
+	if to-from >= MaxSupplyDeltaRange {
+		return fmt.Errorf("block range %d-%d exceeds %d blocks", from, to, MaxSupplyDeltaRange)
+	}
+	return nil
+}
+
+// GetSupplyDeltaRange returns GAS and NEO total supply changes made in blocks
+// from `from` to `to` (inclusive), Supply values correspond to the `to` block.
+// At most MaxSupplyDeltaRange blocks can be requested.
+func (bc *Blockchain) GetSupplyDeltaRange(from, to uint32) (*state.SupplyDelta, error) {
+	if err := checkSupplyDeltaRange(from, to); err != nil {
+		return nil, err
+	}
+	res := new(state.SupplyDelta)
+	for i := from; i <= to; i++ {
+		d, err := bc.GetSupplyDelta(i)
+		if err != nil {
+			return nil, err
+		}
+		res.GAS.Delta.Add(&res.GAS.Delta, &d.GAS.Delta)
+		res.NEO.Delta.Add(&res.NEO.Delta, &d.NEO.Delta)
+		if i == to {
+			res.GAS.Supply.Set(&d.GAS.Supply)
+			res.NEO.Supply.Set(&d.NEO.Supply)
+		}
+	}
+	return res, nil
+}
+
+// VerifySupplyDeltas checks supply change records of blocks from `from` to
+// `to` (inclusive) for consistency, every record should satisfy
+// supply(height) = supply(height-1) + delta(height). The record for the
+// current height is also checked against the actual total supply values. At
+// most MaxSupplyDeltaRange blocks can be checked at once.
+func (bc *Blockchain) VerifySupplyDeltas(from, to uint32) error {
+	if err := checkSupplyDeltaRange(from, to); err != nil {
+		return err
+	}
+	var prev = new(state.SupplyDelta)
+	if from > 0 {
+		var err error
+		prev, err = bc.GetSupplyDelta(from - 1)
+		if err != nil {
+			return err
+		}
+	}
+	for i := from; i <= to; i++ {
+		d, err := bc.GetSupplyDelta(i)
+		if err != nil {
+			return err
+		}
+		for _, tok := range []struct {
+			name       string
+			prev, curr *state.TokenSupplyDelta
+		}{{"GAS", &prev.GAS, &d.GAS}, {"NEO", &prev.NEO, &d.NEO}} {
+			expected := new(big.Int).Add(&tok.prev.Supply, &tok.curr.Delta)
+			if expected.Cmp(&tok.curr.Supply) != 0 {
+				return fmt.Errorf("%s supply mismatch at block %d: expected %s, recorded %s",
+					tok.name, i, expected, &tok.curr.Supply)
+			}
+		}
+		prev = d
+	}
+	if to == bc.BlockHeight() {
+		if actual := bc.contracts.GAS.GetTotalSupply(bc.dao); actual.Cmp(&prev.GAS.Supply) != 0 {
+			return fmt.Errorf("GAS supply mismatch at block %d: actual %s, recorded %s", to, actual, &prev.GAS.Supply)
+		}
+		if actual := bc.contracts.NEO.GetTotalSupply(bc.dao); actual.Cmp(&prev.NEO.Supply) != 0 {
+			return fmt.Errorf("NEO supply mismatch at block %d: actual %s, recorded %s", to, actual, &prev.NEO.Supply)
+		}
+	}
+	return nil
 }
 
 // GetStorageItem returns an item from storage.
@@ -1620,12 +2386,25 @@ func (bc *Blockchain) GetBlock(hash util.Uint256) (*block.Block, error) {
 	if err != nil {
 		return nil, err
 	}
+	var (
+		index = block.Index
+		d     = bc.getBlockDAO(index)
+	)
+	if d != bc.dao {
+		block, err = d.GetBlock(hash)
+		if err != nil {
+			return nil, coldStorageError(index, err)
+		}
+	}
 	if !block.MerkleRoot.Equals(util.Uint256{}) && len(block.Transactions) == 0 {
 		return nil, errors.New("only header is found")
 	}
 	for _, tx := range block.Transactions {
-		stx, _, err := bc.dao.GetTransaction(tx.Hash())
+		stx, _, err := d.GetTransaction(tx.Hash())
 		if err != nil {
+			if d != bc.dao {
+				err = coldStorageError(index, err)
+			}
 			return nil, err
 		}
 		*tx = *stx
@@ -1652,7 +2431,7 @@ func (bc *Blockchain) GetHeader(hash util.Uint256) (*block.Header, error) {
 // HasTransaction returns true if the blockchain contains he given
 // transaction hash.
 func (bc *Blockchain) HasTransaction(hash util.Uint256) bool {
-	if bc.memPool.ContainsKey(hash) {
+	if bc.memPool != nil && bc.memPool.ContainsKey(hash) {
 		return true
 	}
 	return bc.dao.HasTransaction(hash) == dao.ErrAlreadyExists
@@ -1685,6 +2464,13 @@ func (bc *Blockchain) CurrentHeaderHash() util.Uint256 {
 	return hash
 }
 
+// GetGenesisHash returns the hash of the genesis block of the chain. It's the
+// same as ComputeGenesisHash returns for the chain configuration, but
+// doesn't recompute it.
+func (bc *Blockchain) GetGenesisHash() util.Uint256 {
+	return bc.GetHeaderHash(0)
+}
+
 // GetHeaderHash returns hash of the header/block with specified index, if
 // Blockchain doesn't have a hash for this height, zero Uint256 value is returned.
 func (bc *Blockchain) GetHeaderHash(i int) util.Uint256 {
@@ -1720,6 +2506,19 @@ func (bc *Blockchain) GetContractState(hash util.Uint160) *state.Contract {
 	return contract
 }
 
+// GetContractMethodTokens returns method tokens (CALLT targets) from the NEF of
+// the deployed contract with the given hash. Modifying the result doesn't
+// affect the contract state.
+func (bc *Blockchain) GetContractMethodTokens(hash util.Uint160) ([]nef.MethodToken, error) {
+	contract, err := bc.contracts.Management.GetContract(bc.dao, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contract %s: %w", hash.StringLE(), err)
+	}
+	tokens := make([]nef.MethodToken, len(contract.NEF.Tokens))
+	copy(tokens, contract.NEF.Tokens)
+	return tokens, nil
+}
+
 // GetContractScriptHash returns contract script hash by its ID.
 func (bc *Blockchain) GetContractScriptHash(id int32) (util.Uint160, error) {
 	return bc.dao.GetContractScriptHash(id)
@@ -1734,6 +2533,33 @@ func (bc *Blockchain) GetNativeContractScriptHash(name string) (util.Uint160, er
 	return util.Uint160{}, errors.New("Unknown native contract")
 }
 
+// PreflightDeploy checks whether the contract with the given serialized NEF
+// file and JSON manifest can be deployed by the given sender at the current
+// chain state. It performs the same NEF and manifest checks the management
+// contract does (except for the deployment fee and _deploy method execution)
+// and returns the first problem found.
+func (bc *Blockchain) PreflightDeploy(sender util.Uint160, nefBytes, manifBytes []byte) error {
+	if len(manifBytes) > manifest.MaxManifestSize {
+		return fmt.Errorf("invalid manifest: too big (%d bytes, max %d)", len(manifBytes), manifest.MaxManifestSize)
+	}
+	neff, err := nef.FileFromBytes(nefBytes)
+	if err != nil {
+		return fmt.Errorf("invalid NEF file: %w", err)
+	}
+	if !utf8.Valid(manifBytes) {
+		return errors.New("manifest is not UTF-8 compliant")
+	}
+	manif := new(manifest.Manifest)
+	if err := json.Unmarshal(manifBytes, manif); err != nil {
+		return fmt.Errorf("invalid manifest: %w", err)
+	}
+	_, err = bc.contracts.Management.ValidateDeploy(bc.dao, sender, &neff, manif)
+	if err != nil {
+		return err
+	}
+	return bc.contracts.Management.ValidateCallTokens(bc.dao, bc.BlockHeight()+1, neff.Tokens, manif)
+}
+
 // GetNatives returns list of native contracts.
 func (bc *Blockchain) GetNatives() []state.NativeContract {
 	res := make([]state.NativeContract, 0, len(bc.contracts.Contracts))
@@ -1753,6 +2579,21 @@ func (bc *Blockchain) GetConfig() config.ProtocolConfiguration {
 // Make sure it's read from regularly as not reading these events might affect
 // other Blockchain functions.
 func (bc *Blockchain) SubscribeForBlocks(ch chan<- *block.Block) {
+	if bc.readOnly {
+		return
+	}
+	bc.subCh <- ch
+}
+
+// SubscribeForHeaders adds given channel to new header event broadcasting, so
+// when a new header is accepted by the chain (via AddHeaders or along with the
+// block) you'll receive it via this channel, it happens before the
+// corresponding block is stored. Make sure it's read from regularly as not
+// reading these events might affect other Blockchain functions.
+func (bc *Blockchain) SubscribeForHeaders(ch chan<- *block.Header) {
+	if bc.readOnly {
+		return
+	}
 	bc.subCh <- ch
 }
 
@@ -1761,6 +2602,9 @@ func (bc *Blockchain) SubscribeForBlocks(ch chan<- *block.Block) {
 // block) you'll receive it via this channel. Make sure it's read from regularly
 // as not reading these events might affect other Blockchain functions.
 func (bc *Blockchain) SubscribeForTransactions(ch chan<- *transaction.Transaction) {
+	if bc.readOnly {
+		return
+	}
 	bc.subCh <- ch
 }
 
@@ -1772,6 +2616,9 @@ func (bc *Blockchain) SubscribeForTransactions(ch chan<- *transaction.Transactio
 // read from regularly as not reading these events might affect other Blockchain
 // functions.
 func (bc *Blockchain) SubscribeForNotifications(ch chan<- *subscriptions.NotificationEvent) {
+	if bc.readOnly {
+		return
+	}
 	bc.subCh <- ch
 }
 
@@ -1780,12 +2627,43 @@ func (bc *Blockchain) SubscribeForNotifications(ch chan<- *subscriptions.Notific
 // the result of it via this channel. Make sure it's read from regularly as not
 // reading these events might affect other Blockchain functions.
 func (bc *Blockchain) SubscribeForExecutions(ch chan<- *state.AppExecResult) {
+	if bc.readOnly {
+		return
+	}
+	bc.subCh <- ch
+}
+
+// SubscribeForReorgs adds given channel to chain reorganization event
+// broadcasting, so when blocks are rolled back and replaced by the ones from
+// some other fork you'll receive the event via this channel. Notice that dBFT
+// provides single-block finality, so the current block processing never
+// replaces persisted blocks and no events are produced, but clients are still
+// advised to handle them instead of assuming finality. Make sure the channel is
+// read from regularly as not reading these events might affect other
+// Blockchain functions.
+func (bc *Blockchain) SubscribeForReorgs(ch chan<- *ReorgEvent) {
+	if bc.readOnly {
+		return
+	}
 	bc.subCh <- ch
 }
 
 // UnsubscribeFromBlocks unsubscribes given channel from new block notifications,
 // you can close it afterwards. Passing non-subscribed channel is a no-op.
 func (bc *Blockchain) UnsubscribeFromBlocks(ch chan<- *block.Block) {
+	if bc.readOnly {
+		return
+	}
+	bc.unsubCh <- ch
+}
+
+// UnsubscribeFromHeaders unsubscribes given channel from new header
+// notifications, you can close it afterwards. Passing non-subscribed channel is
+// a no-op.
+func (bc *Blockchain) UnsubscribeFromHeaders(ch chan<- *block.Header) {
+	if bc.readOnly {
+		return
+	}
 	bc.unsubCh <- ch
 }
 
@@ -1793,6 +2671,9 @@ func (bc *Blockchain) UnsubscribeFromBlocks(ch chan<- *block.Block) {
 // notifications, you can close it afterwards. Passing non-subscribed channel is
 // a no-op.
 func (bc *Blockchain) UnsubscribeFromTransactions(ch chan<- *transaction.Transaction) {
+	if bc.readOnly {
+		return
+	}
 	bc.unsubCh <- ch
 }
 
@@ -1800,6 +2681,9 @@ func (bc *Blockchain) UnsubscribeFromTransactions(ch chan<- *transaction.Transac
 // execution-generated notifications, you can close it afterwards. Passing
 // non-subscribed channel is a no-op.
 func (bc *Blockchain) UnsubscribeFromNotifications(ch chan<- *subscriptions.NotificationEvent) {
+	if bc.readOnly {
+		return
+	}
 	bc.unsubCh <- ch
 }
 
@@ -1807,6 +2691,19 @@ func (bc *Blockchain) UnsubscribeFromNotifications(ch chan<- *subscriptions.Noti
 // notifications, you can close it afterwards. Passing non-subscribed channel is
 // a no-op.
 func (bc *Blockchain) UnsubscribeFromExecutions(ch chan<- *state.AppExecResult) {
+	if bc.readOnly {
+		return
+	}
+	bc.unsubCh <- ch
+}
+
+// UnsubscribeFromReorgs unsubscribes given channel from chain reorganization
+// notifications, you can close it afterwards. Passing non-subscribed channel is
+// a no-op.
+func (bc *Blockchain) UnsubscribeFromReorgs(ch chan<- *ReorgEvent) {
+	if bc.readOnly {
+		return
+	}
 	bc.unsubCh <- ch
 }
 
@@ -1816,16 +2713,92 @@ func (bc *Blockchain) CalculateClaimable(acc util.Uint160, endHeight uint32) (*b
 	return bc.contracts.NEO.CalculateBonus(bc.dao, acc, endHeight)
 }
 
+// CalculateClaimableBreakdown returns the amount of GAS that can be claimed
+// by the given account at the given height (like CalculateClaimable does)
+// split into NEO holder reward and voter reward. Their sum is always equal to
+// the CalculateClaimable result.
+func (bc *Blockchain) CalculateClaimableBreakdown(acc util.Uint160, endHeight uint32) (holder *big.Int, voter *big.Int, err error) {
+	return bc.contracts.NEO.CalculateBonusBreakdown(bc.dao, acc, endHeight)
+}
+
+// GetGASPerBlockHistory returns GASPerBlock schedule for blocks from `from` to
+// `to` (inclusive) as a list of breakpoints, see NEO.GetGASPerBlockHistory.
+func (bc *Blockchain) GetGASPerBlockHistory(from, to uint32) ([]native.GASPerBlockRecord, error) {
+	return bc.contracts.NEO.GetGASPerBlockHistory(bc.dao, from, to)
+}
+
+// GetVoterRewardProjection returns an estimation of GAS the account gets for
+// voting (separately from NEO holder reward) up to the untilBlock (as
+// CalculateClaimable does) assuming that its vote, candidate votes and
+// committee don't change after the current height.
+func (bc *Blockchain) GetVoterRewardProjection(account util.Uint160, untilBlock uint32) (*big.Int, error) {
+	height := bc.BlockHeight()
+	if untilBlock <= height {
+		return nil, fmt.Errorf("block %d is not in the future, current height is %d", untilBlock, height)
+	}
+	return bc.contracts.NEO.CalculateVoterRewardProjection(bc.dao, account, height, untilBlock)
+}
+
 // FeePerByte returns transaction network fee per byte.
 func (bc *Blockchain) FeePerByte() int64 {
 	return bc.contracts.Policy.GetFeePerByteInternal(bc.dao)
 }
 
-// GetMemPool returns the memory pool of the blockchain.
+// GetMemPool returns the memory pool of the blockchain, it's nil for
+// read-only Blockchain.
 func (bc *Blockchain) GetMemPool() *mempool.Pool {
 	return bc.memPool
 }
 
+// GetMemPoolVerified returns a snapshot of the verified memory pool
+// transactions ordered by priority (the same order that is used for block
+// creation, highest priority first) along with their aggregate system and
+// network fees. The slice returned is a copy that is not affected by
+// subsequent mempool changes.
+func (bc *Blockchain) GetMemPoolVerified() ([]*transaction.Transaction, int64, int64) {
+	if bc.memPool == nil {
+		return nil, 0, 0
+	}
+	var (
+		txes           = bc.memPool.GetVerifiedTransactions()
+		sysFee, netFee int64
+	)
+	for _, tx := range txes {
+		sysFee += tx.SystemFee
+		netFee += tx.NetworkFee
+	}
+	return txes, sysFee, netFee
+}
+
+// TrimMemPool removes transactions that are no longer valid (expired, already
+// persisted or conflicting with the chain state) from the memory pool and
+// returns the number of transactions removed. Regular cleanup is done on
+// every block addition (and every MemPoolSweepInterval if it's set), this
+// method allows to trigger it without waiting for them.
+func (bc *Blockchain) TrimMemPool() int {
+	if bc.memPool == nil {
+		return 0
+	}
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+	return bc.memPool.Sweep(func(tx *transaction.Transaction) bool {
+		return bc.IsTxStillRelevant(tx, nil, false)
+	}, bc)
+}
+
+// GetMemPoolStateDigest returns the digest of the current memory pool
+// contents along with the blockchain height it corresponds to.
+func (bc *Blockchain) GetMemPoolStateDigest() (mempool.StateDigest, uint32) {
+	if bc.memPool == nil {
+		return mempool.StateDigest{}, bc.BlockHeight()
+	}
+	// Chain lock prevents new blocks from being stored (and pool from being
+	// updated along with it) between these two reads.
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+	return bc.memPool.StateDigest(), bc.BlockHeight()
+}
+
 // ApplyPolicyToTxSet applies configured policies to given transaction set. It
 // expects slice to be ordered by fee and returns a subslice of it.
 func (bc *Blockchain) ApplyPolicyToTxSet(txes []*transaction.Transaction) []*transaction.Transaction {
@@ -1834,7 +2807,7 @@ func (bc *Blockchain) ApplyPolicyToTxSet(txes []*transaction.Transaction) []*tra
 		txes = txes[:maxTx]
 	}
 	maxBlockSize := bc.config.MaxBlockSize
-	maxBlockSysFee := bc.config.MaxBlockSystemFee
+	maxBlockSysFee := bc.GetMaxBlockSystemFee()
 	oldVC := bc.knownValidatorsCount.Load()
 	defaultWitness := bc.defaultBlockWitness.Load()
 	curVC := bc.config.GetNumOfCNs(bc.BlockHeight() + 1)
@@ -1852,11 +2825,16 @@ func (bc *Blockchain) ApplyPolicyToTxSet(txes []*transaction.Transaction) []*tra
 		b           = &block.Block{Header: block.Header{Script: defaultWitness.(transaction.Witness)}}
 		blockSize   = uint32(b.GetExpectedBlockSizeWithoutTransactions(len(txes)))
 		blockSysFee int64
+		maxVerGAS   = bc.config.MaxBlockVerificationGAS
+		verGAS      int64
 	)
 	for i, tx := range txes {
 		blockSize += uint32(tx.Size())
 		blockSysFee += tx.SystemFee
-		if blockSize > maxBlockSize || blockSysFee > maxBlockSysFee {
+		if maxVerGAS > 0 {
+			verGAS += bc.getVerificationGAS(bc.memPool, tx)
+		}
+		if blockSize > maxBlockSize || blockSysFee > maxBlockSysFee || (maxVerGAS > 0 && verGAS > maxVerGAS) {
 			txes = txes[:i]
 			break
 		}
@@ -1866,14 +2844,19 @@ func (bc *Blockchain) ApplyPolicyToTxSet(txes []*transaction.Transaction) []*tra
 
 // Various errors that could be returns upon header verification.
 var (
-	ErrHdrHashMismatch     = errors.New("previous header hash doesn't match")
-	ErrHdrIndexMismatch    = errors.New("previous header index doesn't match")
-	ErrHdrInvalidTimestamp = errors.New("block is not newer than the previous one")
-	ErrHdrStateRootSetting = errors.New("state root setting mismatch")
-	ErrHdrInvalidStateRoot = errors.New("state root for previous block is invalid")
+	ErrHdrHashMismatch      = errors.New("previous header hash doesn't match")
+	ErrHdrIndexMismatch     = errors.New("previous header index doesn't match")
+	ErrHdrInvalidTimestamp  = errors.New("block is not newer than the previous one")
+	ErrHdrStateRootSetting  = errors.New("state root setting mismatch")
+	ErrHdrInvalidStateRoot  = errors.New("state root for previous block is invalid")
+	ErrHdrCommitmentSetting = errors.New("header commitment setting mismatch")
 )
 
 func (bc *Blockchain) verifyHeader(currHeader, prevHeader *block.Header) error {
+	if bc.config.HeaderCommitment != currHeader.CommitmentEnabled {
+		return fmt.Errorf("%w: %v != %v",
+			ErrHdrCommitmentSetting, bc.config.HeaderCommitment, currHeader.CommitmentEnabled)
+	}
 	if bc.config.StateRootInHeader {
 		if bc.stateRoot.CurrentLocalHeight() == prevHeader.Index {
 			if sr := bc.stateRoot.CurrentLocalStateRoot(); currHeader.PrevStateRoot != sr {
@@ -1903,19 +2886,68 @@ var (
 	ErrMemPoolConflict   = errors.New("invalid transaction due to conflicts with the memory pool")
 	ErrInvalidScript     = errors.New("invalid script")
 	ErrInvalidAttribute  = errors.New("invalid attribute")
+	// ErrInvalidWitnessRule is returned for transactions with signer rules
+	// exceeding witness condition limits.
+	ErrInvalidWitnessRule = errors.New("invalid witness rule")
 )
 
+// txVerifyParams contains transaction-independent chain parameters used for
+// transaction verification. They're constant while the chain lock is held,
+// so they can be shared between verifications of multiple transactions.
+type txVerifyParams struct {
+	height          uint32
+	feePerByte      int64
+	notaryFeePerKey int64
+}
+
+// getTxVerifyParams reads transaction verification parameters from the
+// current chain state, it must be called with chain lock held.
+func (bc *Blockchain) getTxVerifyParams() *txVerifyParams {
+	p := &txVerifyParams{
+		height:     bc.BlockHeight(),
+		feePerByte: bc.FeePerByte(),
+	}
+	if bc.P2PSigExtensionsEnabled() {
+		p.notaryFeePerKey = bc.contracts.Notary.GetNotaryServiceFeePerKey(bc.dao)
+	}
+	return p
+}
+
 // verifyAndPoolTx verifies whether a transaction is bonafide or not and tries
-// to add it to the mempool given.
+// to add it to the mempool given. Transactions are rejected with
+// ErrMaintenanceMode if Policy contract maintenance mode doesn't allow them.
 func (bc *Blockchain) verifyAndPoolTx(t *transaction.Transaction, pool *mempool.Pool, feer mempool.Feer, data ...interface{}) error {
+	return bc.verifyAndPoolTxWithParams(t, bc.getTxVerifyParams(), pool, feer, data...)
+}
+
+// verifyAndPoolTxWithParams is verifyAndPoolTx using already retrieved
+// verification parameters.
+func (bc *Blockchain) verifyAndPoolTxWithParams(t *transaction.Transaction, params *txVerifyParams, pool *mempool.Pool, feer mempool.Feer, data ...interface{}) error {
+	if !bc.contracts.Policy.IsAllowedInMaintenance(bc.dao, t) {
+		return fmt.Errorf("%w: no committee or exempt signers", ErrMaintenanceMode)
+	}
+	return bc.verifyAndPoolTxInternal(t, params, pool, feer, data...)
+}
+
+// verifyAndPoolTxInternal is verifyAndPoolTxWithParams without maintenance
+// mode check.
+func (bc *Blockchain) verifyAndPoolTxInternal(t *transaction.Transaction, params *txVerifyParams, pool *mempool.Pool, feer mempool.Feer, data ...interface{}) error {
 	// This code can technically be moved out of here, because it doesn't
 	// really require a chain lock.
 	err := vm.IsScriptCorrect(t.Script, nil)
 	if err != nil {
 		return fmt.Errorf("%w: %v", ErrInvalidScript, err)
 	}
+	for i := range t.Signers {
+		for j := range t.Signers[i].Rules {
+			err = t.Signers[i].Rules[j].Validate()
+			if err != nil {
+				return fmt.Errorf("%w: signer %d, rule %d: %v", ErrInvalidWitnessRule, i, j, err)
+			}
+		}
+	}
 
-	height := bc.BlockHeight()
+	height := params.height
 	isPartialTx := data != nil
 	if t.ValidUntilBlock <= height || !isPartialTx && t.ValidUntilBlock > height+bc.config.MaxValidUntilBlockIncrement {
 		return fmt.Errorf("%w: ValidUntilBlock = %d, current height = %d", ErrTxExpired, t.ValidUntilBlock, height)
@@ -1929,14 +2961,15 @@ func (bc *Blockchain) verifyAndPoolTx(t *transaction.Transaction, pool *mempool.
 	if size > transaction.MaxTransactionSize {
 		return fmt.Errorf("%w: (%d > MaxTransactionSize %d)", ErrTxTooBig, size, transaction.MaxTransactionSize)
 	}
-	needNetworkFee := int64(size) * bc.FeePerByte()
+	needNetworkFee := int64(size) * params.feePerByte
 	if bc.P2PSigExtensionsEnabled() {
 		attrs := t.GetAttributes(transaction.NotaryAssistedT)
 		if len(attrs) != 0 {
 			na := attrs[0].Value.(*transaction.NotaryAssisted)
-			needNetworkFee += (int64(na.NKeys) + 1) * bc.contracts.Notary.GetNotaryServiceFeePerKey(bc.dao)
+			needNetworkFee += (int64(na.NKeys) + 1) * params.notaryFeePerKey
 		}
 	}
+	needNetworkFee += bc.contracts.Policy.GetAttributesFeeInternal(bc.dao, t)
 	netFee := t.NetworkFee - needNetworkFee
 	if netFee < 0 {
 		return fmt.Errorf("%w: net fee is %v, need %v", ErrTxSmallNetworkFee, t.NetworkFee, needNetworkFee)
@@ -1952,7 +2985,7 @@ func (bc *Blockchain) verifyAndPoolTx(t *transaction.Transaction, pool *mempool.
 			return err
 		}
 	}
-	err = bc.verifyTxWitnesses(t, nil, isPartialTx)
+	verGAS, err := bc.verifyTxWitnesses(t, nil, isPartialTx)
 	if err != nil {
 		return err
 	}
@@ -1960,16 +2993,18 @@ func (bc *Blockchain) verifyAndPoolTx(t *transaction.Transaction, pool *mempool.
 		return err
 	}
 	err = pool.Add(t, feer, data...)
-	if err != nil {
+	if err == nil {
+		pool.SetVerificationGAS(t.Hash(), verGAS)
+	} else {
 		switch {
 		case errors.Is(err, mempool.ErrConflict):
-			return ErrMemPoolConflict
+			return fmt.Errorf("%w: %v", ErrMemPoolConflict, err)
 		case errors.Is(err, mempool.ErrDup):
 			return fmt.Errorf("mempool: %w", ErrAlreadyExists)
 		case errors.Is(err, mempool.ErrInsufficientFunds):
 			return ErrInsufficientFunds
 		case errors.Is(err, mempool.ErrOOM):
-			return ErrOOM
+			return fmt.Errorf("%w: %v", ErrOOM, err)
 		case errors.Is(err, mempool.ErrConflictsAttribute):
 			return fmt.Errorf("mempool: %w: %s", ErrHasConflicts, err)
 		default:
@@ -1982,7 +3017,11 @@ func (bc *Blockchain) verifyAndPoolTx(t *transaction.Transaction, pool *mempool.
 
 func (bc *Blockchain) verifyTxAttributes(d *dao.Simple, tx *transaction.Transaction, isPartialTx bool) error {
 	for i := range tx.Attributes {
-		switch attrType := tx.Attributes[i].Type; attrType {
+		attrType := tx.Attributes[i].Type
+		if !bc.contracts.Policy.IsAttributeEnabledInternal(d, attrType, true) {
+			return fmt.Errorf("%w: %s attribute is disabled by Policy", ErrInvalidAttribute, attrType)
+		}
+		switch attrType {
 		case transaction.HighPriority:
 			h := bc.contracts.NEO.GetCommitteeAddress(d)
 			if !tx.HasSigner(h) {
@@ -2050,7 +3089,8 @@ func (bc *Blockchain) verifyTxAttributes(d *dao.Simple, tx *transaction.Transact
 				return fmt.Errorf("%w: NotaryAssisted attribute was found, but transaction is not signed by the Notary native contract", ErrInvalidAttribute)
 			}
 		default:
-			if !bc.config.ReservedAttributes && attrType >= transaction.ReservedLowerBound && attrType <= transaction.ReservedUpperBound {
+			if attrType >= transaction.ReservedLowerBound && attrType <= transaction.ReservedUpperBound &&
+				!bc.contracts.Policy.IsAttributeEnabledInternal(d, attrType, bc.config.ReservedAttributes) {
 				return fmt.Errorf("%w: attribute of reserved type was found, but ReservedAttributes are disabled", ErrInvalidAttribute)
 			}
 		}
@@ -2058,6 +3098,42 @@ func (bc *Blockchain) verifyTxAttributes(d *dao.Simple, tx *transaction.Transact
 	return nil
 }
 
+// IsNonceAvailable checks whether a transaction with the given sender, nonce
+// and ValidUntilBlock can be created without colliding with other still valid
+// transactions of the same sender. It returns false if validUntil is out of
+// the range allowed for new transactions or if there is a transaction with the
+// same sender and nonce in the mempool or in the recent blocks (that can
+// contain transactions which are still valid, so at most
+// MaxValidUntilBlockIncrement blocks are checked).
+func (bc *Blockchain) IsNonceAvailable(sender util.Uint160, nonce uint32, validUntil uint32) bool {
+	var height = bc.BlockHeight()
+
+	if validUntil <= height || validUntil > height+bc.config.MaxValidUntilBlockIncrement {
+		return false
+	}
+	if bc.memPool != nil {
+		for _, tx := range bc.memPool.GetVerifiedTransactions() {
+			if tx.Nonce == nonce && tx.Sender().Equals(sender) {
+				return false
+			}
+		}
+	}
+	// Transactions from older blocks have already expired.
+	for i := height; i > 0 && i+bc.config.MaxValidUntilBlockIncrement > height; i-- {
+		b, err := bc.GetBlock(bc.GetHeaderHash(int(i)))
+		if err != nil {
+			// Block may be unavailable if RemoveUntraceableBlocks is enabled.
+			break
+		}
+		for _, tx := range b.Transactions {
+			if tx.ValidUntilBlock > height && tx.Nonce == nonce && tx.Sender().Equals(sender) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // IsTxStillRelevant is a callback for mempool transaction filtering after the
 // new block addition. It returns false for transactions added by the new block
 // (passed via txpool) and does witness reverification for non-standard
@@ -2071,6 +3147,9 @@ func (bc *Blockchain) IsTxStillRelevant(t *transaction.Transaction, txpool *memp
 	if t.ValidUntilBlock <= curheight {
 		return false
 	}
+	if !bc.contracts.Policy.IsAllowedInMaintenance(bc.dao, t) {
+		return false
+	}
 	if txpool == nil {
 		if bc.dao.HasTransaction(t.Hash()) != nil {
 			return false
@@ -2088,7 +3167,8 @@ func (bc *Blockchain) IsTxStillRelevant(t *transaction.Transaction, txpool *memp
 		}
 	}
 	if recheckWitness {
-		return bc.verifyTxWitnesses(t, nil, isPartialTx) == nil
+		_, err := bc.verifyTxWitnesses(t, nil, isPartialTx)
+		return err == nil
 	}
 	return true
 }
@@ -2103,9 +3183,36 @@ func (bc *Blockchain) VerifyTx(t *transaction.Transaction) error {
 	return bc.verifyAndPoolTx(t, mp, bc)
 }
 
+// VerifyTxs verifies a set of transactions the same way VerifyTx does, but
+// under a single chain lock, so all of them are checked against the same chain
+// state and transaction-independent parameters are only retrieved once. Every
+// transaction is verified in isolation (as if VerifyTx was called for it), so
+// the set itself can contain conflicting transactions. The result has an error
+// (or nil) for every transaction given, in the same order.
+func (bc *Blockchain) VerifyTxs(txes []*transaction.Transaction) []error {
+	var errs = make([]error, len(txes))
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+	params := bc.getTxVerifyParams()
+	for i, t := range txes {
+		errs[i] = bc.verifyAndPoolTxWithParams(t, params, mempool.New(1, 0, false), bc)
+	}
+	return errs
+}
+
 // PoolTx verifies and tries to add given transaction into the mempool. If not
 // given, the default mempool is used. Passing multiple pools is not supported.
 func (bc *Blockchain) PoolTx(t *transaction.Transaction, pools ...*mempool.Pool) error {
+	return bc.PoolTxWithSource(t, mempool.TxSourceNetwork, pools...)
+}
+
+// PoolTxWithSource is the same as PoolTx, but also accepts the transaction
+// source. Locally submitted transactions added to the default mempool are
+// tracked for rebroadcasting if it's enabled (see SetTxRebroadcast).
+func (bc *Blockchain) PoolTxWithSource(t *transaction.Transaction, src mempool.TxSource, pools ...*mempool.Pool) error {
+	if bc.readOnly {
+		return ErrReadOnlyMode
+	}
 	var pool = bc.memPool
 
 	bc.lock.RLock()
@@ -2117,11 +3224,38 @@ func (bc *Blockchain) PoolTx(t *transaction.Transaction, pools ...*mempool.Pool)
 	if len(pools) == 1 {
 		pool = pools[0]
 	}
-	return bc.verifyAndPoolTx(t, pool, bc)
+	err := bc.verifyAndPoolTx(t, pool, bc)
+	if err == nil && src == mempool.TxSourceLocal && pool == bc.memPool {
+		bc.rebroadcast.track(t, bc.BlockHeight())
+	}
+	return err
+}
+
+// SetTxRebroadcast enables rebroadcasting of locally submitted transactions
+// (see PoolTxWithSource) that stay in the mempool for threshold blocks, the
+// interval between subsequent rebroadcasts is doubled every time. announce is
+// called (from a separate goroutine) for every transaction to rebroadcast,
+// maxRetries limits the number of rebroadcasts per transaction (0 means no
+// limit). Tracking is cancelled when transaction is included into a block,
+// expires or is removed from the mempool for any other reason. Zero threshold
+// or nil announce disable rebroadcasting.
+func (bc *Blockchain) SetTxRebroadcast(threshold uint32, maxRetries int, announce func(*transaction.Transaction)) {
+	bc.rebroadcast.set(threshold, maxRetries, announce)
+}
+
+// GetTxRebroadcastStatus returns the rebroadcast status of locally submitted
+// transaction with the given hash. Transactions are tracked from the moment
+// they're pooled until their ValidUntilBlock, false is returned for
+// transactions not tracked.
+func (bc *Blockchain) GetTxRebroadcastStatus(h util.Uint256) (TxRebroadcastStatus, bool) {
+	return bc.rebroadcast.getStatus(h)
 }
 
 // PoolTxWithData verifies and tries to add given transaction with additional data into the mempool.
 func (bc *Blockchain) PoolTxWithData(t *transaction.Transaction, data interface{}, mp *mempool.Pool, feer mempool.Feer, verificationFunction func(tx *transaction.Transaction, data interface{}) error) error {
+	if bc.readOnly {
+		return ErrReadOnlyMode
+	}
 	bc.lock.RLock()
 	defer bc.lock.RUnlock()
 
@@ -2165,28 +3299,39 @@ func (bc *Blockchain) GetTestVM(t trigger.Type, tx *transaction.Transaction, b *
 	return systemInterop
 }
 
-// GetTestHistoricVM returns an interop context with VM set up for a test run.
-func (bc *Blockchain) GetTestHistoricVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*interop.Context, error) {
+// getHistoricDAO returns DAO backed by the MPT state of the given height.
+// Native contracts cache is not initialized for it.
+func (bc *Blockchain) getHistoricDAO(index uint32) (*dao.Simple, error) {
 	if bc.config.KeepOnlyLatestState {
 		return nil, errors.New("only latest state is supported")
 	}
-	if b == nil {
-		return nil, errors.New("block is mandatory to produce test historic VM")
-	}
 	var mode = mpt.ModeAll
 	if bc.config.RemoveUntraceableBlocks {
-		if b.Index < bc.BlockHeight()-bc.config.MaxTraceableBlocks {
-			return nil, fmt.Errorf("state for height %d is outdated and removed from the storage", b.Index)
+		if index < bc.BlockHeight()-bc.config.MaxTraceableBlocks {
+			return nil, fmt.Errorf("state for height %d is outdated and removed from the storage", index)
 		}
 		mode |= mpt.ModeGCFlag
 	}
-	sr, err := bc.stateRoot.GetStateRoot(b.Index)
+	sr, err := bc.stateRoot.GetStateRoot(index)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve stateroot for height %d: %w", b.Index, err)
+		return nil, fmt.Errorf("failed to retrieve stateroot for height %d: %w", index, err)
 	}
 	s := mpt.NewTrieStore(sr.Root, mode, storage.NewPrivateMemCachedStore(bc.dao.Store))
-	dTrie := dao.NewSimple(s, bc.config.StateRootInHeader, bc.config.P2PSigExtensions)
-	dTrie.Version = bc.dao.Version
+	s.SetNodeCache(bc.stateRoot.NodeCache())
+	d := dao.NewSimple(s, bc.config.StateRootInHeader, bc.config.P2PSigExtensions)
+	d.Version = bc.dao.Version
+	return d, nil
+}
+
+// GetTestHistoricVM returns an interop context with VM set up for a test run.
+func (bc *Blockchain) GetTestHistoricVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) (*interop.Context, error) {
+	if b == nil {
+		return nil, errors.New("block is mandatory to produce test historic VM")
+	}
+	dTrie, err := bc.getHistoricDAO(b.Index)
+	if err != nil {
+		return nil, err
+	}
 	// Initialize native cache before passing DAO to interop context constructor, because
 	// the constructor will call BaseExecFee/StoragePrice policy methods on the passed DAO.
 	err = bc.initializeNativeCache(b.Index, dTrie)
@@ -2263,8 +3408,29 @@ func (bc *Blockchain) VerifyWitness(h util.Uint160, c hash.Hashable, w *transact
 	return bc.verifyHashAgainstScript(h, w, ic, gas)
 }
 
+// VerifyWitnessDetailed verifies the witness the same way VerifyWitness does,
+// but returns the amount of GAS consumed by this witness script even if
+// verification fails. The second value is true if script execution was
+// stopped because of the given (or MaxVerificationGas policy) GAS limit.
+func (bc *Blockchain) VerifyWitnessDetailed(h util.Uint160, c hash.Hashable, w *transaction.Witness, gas int64) (int64, bool, error) {
+	ic := bc.newInteropContext(trigger.Verification, bc.dao, nil, nil)
+	ic.Container = c
+	return bc.verifyWitnessScript(h, w, ic, gas)
+}
+
 // verifyHashAgainstScript verifies given hash against the given witness and returns the amount of GAS consumed.
 func (bc *Blockchain) verifyHashAgainstScript(hash util.Uint160, witness *transaction.Witness, interopCtx *interop.Context, gas int64) (int64, error) {
+	gasConsumed, _, err := bc.verifyWitnessScript(hash, witness, interopCtx, gas)
+	if err != nil && !errors.Is(err, ErrInvalidSignature) {
+		return 0, err
+	}
+	return gasConsumed, err
+}
+
+// verifyWitnessScript runs verification of the given hash against the given
+// witness and returns the amount of GAS consumed (for failed executions too)
+// and whether the GAS limit was exceeded.
+func (bc *Blockchain) verifyWitnessScript(hash util.Uint160, witness *transaction.Witness, interopCtx *interop.Context, gas int64) (int64, bool, error) {
 	gasPolicy := bc.contracts.Policy.GetMaxVerificationGas(interopCtx.DAO)
 	if gas > gasPolicy {
 		gas = gasPolicy
@@ -2275,38 +3441,42 @@ func (bc *Blockchain) verifyHashAgainstScript(hash util.Uint160, witness *transa
 	vm.LoadToken = contract.LoadToken(interopCtx)
 	vm.GasLimit = gas
 	if err := bc.InitVerificationContext(interopCtx, hash, witness); err != nil {
-		return 0, err
+		return 0, false, err
 	}
 	err := interopCtx.Exec()
 	if vm.HasFailed() {
-		return 0, fmt.Errorf("%w: vm execution has failed: %v", ErrVerificationFailed, err)
+		return vm.GasConsumed(), gas >= 0 && vm.GasConsumed() > gas,
+			fmt.Errorf("%w: vm execution has failed: %v", ErrVerificationFailed, err)
 	}
 	estack := vm.Estack()
 	if estack.Len() > 0 {
 		resEl := estack.Pop()
 		res, err := resEl.Item().TryBool()
 		if err != nil {
-			return 0, fmt.Errorf("%w: invalid return value", ErrVerificationFailed)
+			return vm.GasConsumed(), false, fmt.Errorf("%w: invalid return value", ErrVerificationFailed)
 		}
 		if vm.Estack().Len() != 0 {
-			return 0, fmt.Errorf("%w: expected exactly one returned value", ErrVerificationFailed)
+			return vm.GasConsumed(), false, fmt.Errorf("%w: expected exactly one returned value", ErrVerificationFailed)
 		}
 		if !res {
-			return vm.GasConsumed(), ErrInvalidSignature
+			return vm.GasConsumed(), false, ErrInvalidSignature
 		}
 	} else {
-		return 0, fmt.Errorf("%w: no result returned from the script", ErrVerificationFailed)
+		return vm.GasConsumed(), false, fmt.Errorf("%w: no result returned from the script", ErrVerificationFailed)
 	}
-	return vm.GasConsumed(), nil
+	return vm.GasConsumed(), false, nil
 }
 
 // verifyTxWitnesses verifies the scripts (witnesses) that come with a given
 // transaction. It can reorder them by ScriptHash, because that's required to
 // match a slice of script hashes from the Blockchain. Block parameter
 // is used for easy interop access and can be omitted for transactions that are
-// not yet added into any block.
+// not yet added into any block. It returns the overall amount of GAS consumed
+// by witnesses verification.
 // Golang implementation of VerifyWitnesses method in C# (https://github.com/neo-project/neo/blob/master/neo/SmartContract/Helper.cs#L87).
-func (bc *Blockchain) verifyTxWitnesses(t *transaction.Transaction, block *block.Block, isPartialTx bool) error {
+func (bc *Blockchain) verifyTxWitnesses(t *transaction.Transaction, block *block.Block, isPartialTx bool) (int64, error) {
+	var total int64
+
 	interopCtx := bc.newInteropContext(trigger.Verification, bc.dao, block, t)
 	gasLimit := t.NetworkFee - int64(t.Size())*bc.FeePerByte()
 	if bc.P2PSigExtensionsEnabled() {
@@ -2316,16 +3486,29 @@ func (bc *Blockchain) verifyTxWitnesses(t *transaction.Transaction, block *block
 			gasLimit -= (int64(na.NKeys) + 1) * bc.contracts.Notary.GetNotaryServiceFeePerKey(bc.dao)
 		}
 	}
+	gasLimit -= bc.contracts.Policy.GetAttributesFeeInternal(bc.dao, t)
 	for i := range t.Signers {
 		gasConsumed, err := bc.verifyHashAgainstScript(t.Signers[i].Account, &t.Scripts[i], interopCtx, gasLimit)
+		total += gasConsumed
 		if err != nil &&
 			!(i == 0 && isPartialTx && errors.Is(err, ErrInvalidSignature)) { // it's OK for partially-filled transaction with dummy first witness.
-			return fmt.Errorf("witness #%d: %w", i, err)
+			return total, fmt.Errorf("witness #%d: %w", i, err)
 		}
 		gasLimit -= gasConsumed
 	}
 
-	return nil
+	return total, nil
+}
+
+// getVerificationGAS returns the amount of GAS consumed by the transaction
+// witnesses verification. It's taken from the pool if the transaction was
+// verified when added there, otherwise witnesses are verified.
+func (bc *Blockchain) getVerificationGAS(pool *mempool.Pool, tx *transaction.Transaction) int64 {
+	if gas, ok := pool.GetVerificationGAS(tx.Hash()); ok {
+		return gas
+	}
+	gas, _ := bc.verifyTxWitnesses(tx, nil, false)
+	return gas
 }
 
 // verifyHeaderWitnesses is a block-specific implementation of VerifyWitnesses logic.
@@ -2399,6 +3582,13 @@ func (bc *Blockchain) GetBaseExecFee() int64 {
 	return bc.contracts.Policy.GetExecFeeFactorInternal(bc.dao)
 }
 
+// GetMaxBlockSystemFee returns the maximum overall system fee of transactions
+// in a block. It's MaxBlockSystemFee configuration value unless it's changed
+// via Policy contract (see PolicyExtensions setting).
+func (bc *Blockchain) GetMaxBlockSystemFee() int64 {
+	return bc.contracts.Policy.GetMaxBlockSystemFeeInternal(bc.dao)
+}
+
 // GetMaxVerificationGAS returns maximum verification GAS Policy limit.
 func (bc *Blockchain) GetMaxVerificationGAS() int64 {
 	return bc.contracts.Policy.GetMaxVerificationGas(bc.dao)
@@ -2419,3 +3609,46 @@ func (bc *Blockchain) GetStoragePrice() int64 {
 	}
 	return bc.contracts.Policy.GetStoragePriceInternal(bc.dao)
 }
+
+// GetStoragePriceAt returns storage price that was in effect after the block
+// with the given height was processed. Prices for heights other than the
+// current one are only available if KeepOnlyLatestState is disabled.
+func (bc *Blockchain) GetStoragePriceAt(height uint32) (int64, error) {
+	current := bc.BlockHeight()
+	if height > current {
+		return 0, fmt.Errorf("block %d is not yet processed", height)
+	}
+	if height == current {
+		return bc.GetStoragePrice(), nil
+	}
+	if bc.config.KeepOnlyLatestState {
+		return 0, errors.New("old storage prices are not available with KeepOnlyLatestState")
+	}
+	sr, err := bc.stateRoot.GetStateRoot(height)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get state root for block %d: %w", height, err)
+	}
+	price, err := bc.contracts.Policy.GetStoragePriceFromState(bc.stateRoot, sr.Root)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get storage price for block %d: %w", height, err)
+	}
+	return price, nil
+}
+
+// ComputeStorageCost returns the amount of GAS charged by System.Storage.Put
+// for a new storage item with the given key and value lengths at the current
+// storage price. Overwriting an existing item costs less.
+func (bc *Blockchain) ComputeStorageCost(keyLen, valueLen int) int64 {
+	return int64(keyLen+valueLen) * bc.GetStoragePrice()
+}
+
+// ComputeStorageCostAt is similar to ComputeStorageCost, but it uses storage
+// price that was in effect after the block with the given height was
+// processed (see GetStoragePriceAt).
+func (bc *Blockchain) ComputeStorageCostAt(height uint32, keyLen, valueLen int) (int64, error) {
+	price, err := bc.GetStoragePriceAt(height)
+	if err != nil {
+		return 0, err
+	}
+	return int64(keyLen+valueLen) * price, nil
+}
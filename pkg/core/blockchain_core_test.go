@@ -4,7 +4,9 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -340,3 +343,187 @@ func TestBlockchain_BaseExecFeeBaseStoragePrice_Compat(t *testing.T) {
 		check(t)
 	})
 }
+
+func TestBlockchain_VerifySupplyDeltas(t *testing.T) {
+	bc := newTestChainWithCustomCfg(t, func(c *config.Config) {
+		c.ProtocolConfiguration.TrackSupplyDeltas = true
+	})
+	_, err := bc.genBlocks(5)
+	require.NoError(t, err)
+	require.NoError(t, bc.VerifySupplyDeltas(0, bc.BlockHeight()))
+
+	d, err := bc.dao.GetSupplyDelta(bc.contracts.GAS.ID, 3)
+	require.NoError(t, err)
+	d.Delta.Add(&d.Delta, big.NewInt(1))
+	require.NoError(t, bc.dao.PutSupplyDelta(bc.contracts.GAS.ID, 3, d))
+	require.Error(t, bc.VerifySupplyDeltas(0, bc.BlockHeight()))
+	require.Error(t, bc.VerifySupplyDeltas(3, 3))
+	require.NoError(t, bc.VerifySupplyDeltas(4, bc.BlockHeight()))
+
+	d, err = bc.dao.GetSupplyDelta(bc.contracts.NEO.ID, bc.BlockHeight())
+	require.NoError(t, err)
+	d.Delta.Add(&d.Delta, big.NewInt(1))
+	d.Supply.Add(&d.Supply, big.NewInt(1))
+	require.NoError(t, bc.dao.PutSupplyDelta(bc.contracts.NEO.ID, bc.BlockHeight(), d))
+	require.Error(t, bc.VerifySupplyDeltas(bc.BlockHeight(), bc.BlockHeight()))
+}
+
+func TestBlockchain_SubscribeForReorgs(t *testing.T) {
+	bc := newTestChain(t)
+	ch := make(chan *ReorgEvent, 1)
+	bc.SubscribeForReorgs(ch)
+
+	_, err := bc.genBlocks(2)
+	require.NoError(t, err)
+	require.Empty(t, ch)
+
+	ev := &ReorgEvent{
+		CommonAncestor: 1,
+		Orphaned:       []util.Uint256{bc.GetHeaderHash(2)},
+	}
+	bc.reorgs <- ev
+	require.Equal(t, ev, <-ch)
+
+	bc.UnsubscribeFromReorgs(ch)
+	bc.reorgs <- ev
+	require.Empty(t, ch)
+}
+
+// coldStore is an instrumented cold storage that can also emulate failures.
+type coldStore struct {
+	storage.Store
+	gets int32
+	fail bool
+}
+
+func (s *coldStore) Get(key []byte) ([]byte, error) {
+	atomic.AddInt32(&s.gets, 1)
+	if s.fail {
+		return nil, errors.New("cold storage is unavailable")
+	}
+	return s.Store.Get(key)
+}
+
+func (s *coldStore) PutChangeSet(puts map[string][]byte, stor map[string][]byte) error {
+	if s.fail {
+		return errors.New("cold storage is unavailable")
+	}
+	return s.Store.PutChangeSet(puts, stor)
+}
+
+func TestBlockchain_ColdStorage(t *testing.T) {
+	bc := initTestChain(t, nil, func(c *config.Config) {
+		c.ProtocolConfiguration.MaxTraceableBlocks = 2
+		c.ProtocolConfiguration.ColdStorageThreshold = 3
+	})
+	cold := &coldStore{Store: storage.NewMemoryStore()}
+	require.NoError(t, bc.SetColdStorage(cold))
+	// Blocks are moved manually, so the chain is not run, but someone needs
+	// to consume events.
+	go bc.notificationDispatcher()
+	t.Cleanup(func() { close(bc.stopCh) })
+
+	txes := make([]*transaction.Transaction, 2)
+	blocks := make([]*block.Block, 2)
+	for i := range txes {
+		var err error
+		txes[i], err = testchain.NewTransferFromOwner(bc, bc.contracts.NEO.Hash, util.Uint160{1, 2, 3}, 1, uint32(i), 100)
+		require.NoError(t, err)
+		blocks[i] = bc.newBlock(txes[i])
+		require.NoError(t, bc.AddBlock(blocks[i]))
+	}
+	_, err := bc.genBlocks(4)
+	require.NoError(t, err)
+	_, err = bc.persist(true)
+	require.NoError(t, err)
+
+	// Blocks 0-3 can be moved, but batch size is limited.
+	n, err := bc.moveToColdStorage(2)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	h, err := bc.dao.GetColdStorageHeight()
+	require.NoError(t, err)
+	require.Equal(t, uint32(2), h)
+
+	// Failed cold storage write leaves everything as is.
+	cold.fail = true
+	_, err = bc.moveToColdStorage(2)
+	require.Error(t, err)
+	require.Equal(t, uint32(2), atomic.LoadUint32(&bc.coldHeight))
+	cold.fail = false
+	b, err := bc.GetBlock(blocks[1].Hash())
+	require.NoError(t, err)
+	require.Equal(t, txes[1].Hash(), b.Transactions[0].Hash())
+
+	// Restart resumes from the stored height.
+	require.NoError(t, bc.SetColdStorage(cold))
+	n, err = bc.moveToColdStorage(10)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	n, err = bc.moveToColdStorage(10)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+
+	for i := range txes {
+		_, _, err = bc.dao.GetTransaction(txes[i].Hash())
+		require.True(t, errors.Is(err, storage.ErrKeyNotFound))
+		b, err = bc.dao.GetBlock(blocks[i].Hash())
+		require.NoError(t, err)
+		require.Equal(t, 0, len(b.Transactions)) // Only header is kept.
+
+		b, err = bc.GetBlock(blocks[i].Hash())
+		require.NoError(t, err)
+		require.Equal(t, blocks[i].Hash(), b.Hash())
+		require.Equal(t, 1, len(b.Transactions))
+		require.Equal(t, txes[i].Hash(), b.Transactions[0].Hash())
+
+		tx, height, err := bc.GetTransaction(txes[i].Hash())
+		require.NoError(t, err)
+		require.Equal(t, txes[i].Hash(), tx.Hash())
+		require.Equal(t, blocks[i].Index, height)
+
+		aers, err := bc.GetAppExecResults(txes[i].Hash(), trigger.Application)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(aers))
+		require.Equal(t, vm.HaltState, aers[0].VMState, aers[0].FaultException)
+
+		aers, err = bc.GetAppExecResults(blocks[i].Hash(), trigger.All)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(aers))
+	}
+	ns, err := bc.FindNotifications(bc.contracts.NEO.Hash, "Transfer", 1, bc.BlockHeight(), 0)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(ns))
+
+	t.Run("recent blocks", func(t *testing.T) {
+		atomic.StoreInt32(&cold.gets, 0)
+		for i := uint32(4); i <= bc.BlockHeight(); i++ {
+			b, err := bc.GetBlock(bc.GetHeaderHash(int(i)))
+			require.NoError(t, err)
+			aers, err := bc.GetAppExecResults(b.Hash(), trigger.All)
+			require.NoError(t, err)
+			require.Equal(t, 2, len(aers))
+		}
+		_, err := bc.GetHeader(blocks[0].Hash())
+		require.NoError(t, err)
+		require.Equal(t, int32(0), atomic.LoadInt32(&cold.gets))
+	})
+	t.Run("cold storage failure", func(t *testing.T) {
+		cold.fail = true
+		defer func() { cold.fail = false }()
+
+		_, err := bc.GetBlock(blocks[0].Hash())
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "untraceable"))
+		_, _, err = bc.GetTransaction(txes[0].Hash())
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "untraceable"))
+		_, err = bc.GetAppExecResults(blocks[0].Hash(), trigger.All)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "untraceable"))
+
+		require.NoError(t, bc.AddBlock(bc.newBlock()))
+		_, err = bc.GetBlock(bc.CurrentBlockHash())
+		require.NoError(t, err)
+	})
+}
@@ -1,17 +1,23 @@
 package core_test
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/binary"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/nspcc-dev/neo-go/internal/contracts"
 	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
 	"github.com/nspcc-dev/neo-go/pkg/compiler"
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
@@ -35,10 +41,14 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
 	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result/subscriptions"
-	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/services/oracle"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/clock"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
@@ -46,6 +56,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
 )
 
 func TestBlockchain_DumpAndRestore(t *testing.T) {
@@ -73,6 +84,42 @@ func TestBlockchain_DumpAndRestore(t *testing.T) {
 	})
 }
 
+func TestBlockchain_DumpAndRestoreCompressed(t *testing.T) {
+	bc, validators, committee := chain.NewMulti(t)
+	e := neotest.NewExecutor(t, bc, validators, committee)
+	e.GenerateNewBlocks(t, 10)
+
+	raw := io.NewBufBinWriter()
+	require.NoError(t, chaindump.Dump(bc, raw.BinWriter, 0, bc.BlockHeight()+1))
+	require.NoError(t, raw.Err)
+
+	compressed := new(bytes.Buffer)
+	require.NoError(t, chaindump.DumpCompressed(bc, compressed, 0, bc.BlockHeight()+1, gzip.BestCompression))
+	require.True(t, compressed.Len() < raw.Len())
+
+	check := func(t *testing.T, data []byte) {
+		bc2, _, _ := chain.NewMulti(t)
+		require.NoError(t, chaindump.RestoreCompressed(bc2, bytes.NewReader(data), 0, bc.BlockHeight()+1, nil))
+		require.Equal(t, bc.BlockHeight(), bc2.BlockHeight())
+		require.Equal(t, bc.CurrentBlockHash(), bc2.CurrentBlockHash())
+	}
+	t.Run("compressed", func(t *testing.T) {
+		check(t, compressed.Bytes())
+	})
+	t.Run("raw", func(t *testing.T) {
+		check(t, raw.Bytes())
+	})
+	t.Run("invalid level", func(t *testing.T) {
+		require.Error(t, chaindump.DumpCompressed(bc, new(bytes.Buffer), 0, 1, 42))
+	})
+	t.Run("bad version", func(t *testing.T) {
+		data := append([]byte{}, compressed.Bytes()...)
+		data[4]++
+		bc2, _, _ := chain.NewMulti(t)
+		require.Error(t, chaindump.RestoreCompressed(bc2, bytes.NewReader(data), 0, 1, nil))
+	})
+}
+
 func testDumpAndRestore(t *testing.T, dumpF, restoreF func(c *config.ProtocolConfiguration)) {
 	if restoreF == nil {
 		restoreF = dumpF
@@ -123,6 +170,40 @@ func testDumpAndRestore(t *testing.T, dumpF, restoreF func(c *config.ProtocolCon
 			require.Equal(t, bc.BlockHeight()-1, lastIndex)
 		})
 	})
+	t.Run("progress", func(t *testing.T) {
+		bc2, _, _ := chain.NewMultiWithCustomConfig(t, restoreF)
+
+		var (
+			last       chaindump.Progress
+			errStopped = errors.New("stopped")
+			stopAt     = bc.BlockHeight() - 1
+		)
+		f := func(b *block.Block, p chaindump.Progress) error {
+			require.Equal(t, last.Count+1, p.Count)
+			w := io.NewBufBinWriter()
+			b.EncodeBinary(w.BinWriter)
+			require.NoError(t, w.Err)
+			require.Equal(t, last.Offset+4+uint64(w.Len()), p.Offset)
+			last = p
+			if b.Index >= stopAt {
+				return errStopped
+			}
+			return nil
+		}
+		err := chaindump.RestoreWithProgress(bc2, bytes.NewReader(buf), 0, bc.BlockHeight()+1, f)
+		require.True(t, errors.Is(err, errStopped))
+		require.Equal(t, stopAt+1, last.Count)
+
+		// Offset is relative to the reader position and includes skipped blocks.
+		skip := bc2.BlockHeight() + 1
+		err = chaindump.RestoreWithProgress(bc2, bytes.NewReader(buf), skip, 1, func(b *block.Block, p chaindump.Progress) error {
+			require.Equal(t, uint32(1), p.Count)
+			require.Equal(t, uint64(len(buf)), p.Offset)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, bc.BlockHeight(), bc2.BlockHeight())
+	})
 }
 
 func newLevelDBForTestingWithPath(t testing.TB, dbPath string) (storage.Store, string) {
@@ -218,6 +299,15 @@ func TestBlockchain_StartFromExistingDB(t *testing.T) {
 		require.Error(t, err)
 		require.True(t, strings.Contains(err.Error(), "KeepOnlyLatestState setting mismatch"), err)
 	})
+	t.Run("mismatch HeaderCommitment", func(t *testing.T) {
+		ps = newPS(t)
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, func(c *config.ProtocolConfiguration) {
+			customConfig(c)
+			c.HeaderCommitment = true
+		}, ps)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "HeaderCommitment setting mismatch"), err)
+	})
 	t.Run("corrupted headers", func(t *testing.T) {
 		ps = newPS(t)
 
@@ -357,6 +447,97 @@ func TestBlockchain_StartFromExistingDB(t *testing.T) {
 		require.True(t, strings.Contains(err.Error(), fmt.Sprintf("native %s: version mismatch (stored contract state differs from autogenerated one)", nativenames.CryptoLib)), err)
 	})
 
+	putNativeState := func(t *testing.T, cache *storage.MemCachedStore, cs *state.Contract) {
+		key := make([]byte, 1+4+1+20)
+		key[0] = byte(storage.STStorage)
+		binary.LittleEndian.PutUint32(key[1:], uint32(managementID))
+		key[5] = byte(managementContractPrefix)
+		copy(key[6:], cs.Hash.BytesBE())
+		csBytes, err := stackitem.SerializeConvertible(cs)
+		require.NoError(t, err)
+		cache.Put(key, csBytes)
+	}
+	t.Run("additive native contract's state drift", func(t *testing.T) {
+		// Stored CryptoLib state lacks the last method.
+		cs := *cryptoLibState
+		methods := cs.Manifest.ABI.Methods
+		cs.Manifest.ABI.Methods = methods[:len(methods)-1]
+		missing := methods[len(methods)-1]
+
+		ps = newPS(t)
+		cache := storage.NewMemCachedStore(ps) // Extra wrapper to avoid good DB corruption.
+		putNativeState(t, cache, &cs)
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, customConfig, cache)
+		require.True(t, errors.Is(err, core.ErrNativeDrift), err)
+		var dErr *core.NativeDriftError
+		require.True(t, errors.As(err, &dErr))
+		require.Equal(t, 1, len(dErr.Drifts))
+		d := dErr.Drifts[0]
+		require.Equal(t, nativenames.CryptoLib, d.Name)
+		require.Equal(t, []string{fmt.Sprintf("%s/%d", missing.Name, len(missing.Parameters))}, d.AddedMethods)
+		require.True(t, d.IsAdditive())
+		require.Equal(t, "", d.ConfigSetting)
+	})
+	t.Run("incompatible native contract's state drift", func(t *testing.T) {
+		// Stored CryptoLib state has an additional method.
+		cs := *cryptoLibState
+		extra := cs.Manifest.ABI.Methods[0]
+		extra.Name = "extraMethod"
+		cs.Manifest.ABI.Methods = append([]manifest.Method{extra}, cs.Manifest.ABI.Methods...)
+
+		ps = newPS(t)
+		cache := storage.NewMemCachedStore(ps) // Extra wrapper to avoid good DB corruption.
+		putNativeState(t, cache, &cs)
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, customConfig, cache)
+		var dErr *core.NativeDriftError
+		require.True(t, errors.As(err, &dErr), err)
+		require.Equal(t, 1, len(dErr.Drifts))
+		require.Equal(t, []string{fmt.Sprintf("extraMethod/%d", len(extra.Parameters))}, dErr.Drifts[0].RemovedMethods)
+		require.False(t, dErr.IsAdditive())
+		require.True(t, strings.Contains(err.Error(), "incompatible"), err)
+	})
+	t.Run("native contract's state drift explained by config", func(t *testing.T) {
+		// Stored Policy state is generated with PolicyExtensions enabled.
+		cfg := bc.GetConfig()
+		cfg.PolicyExtensions = true
+		policy := native.NewContracts(cfg).Policy.Metadata()
+		cs := bc.GetContractState(policy.Hash)
+		require.NotNil(t, cs)
+		cs.ContractBase = policy.ContractBase
+
+		ps = newPS(t)
+		cache := storage.NewMemCachedStore(ps) // Extra wrapper to avoid good DB corruption.
+		putNativeState(t, cache, cs)
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, customConfig, cache)
+		var dErr *core.NativeDriftError
+		require.True(t, errors.As(err, &dErr), err)
+		require.Equal(t, 1, len(dErr.Drifts))
+		require.Equal(t, nativenames.Policy, dErr.Drifts[0].Name)
+		require.Equal(t, "PolicyExtensions", dErr.Drifts[0].ConfigSetting)
+		require.NotEmpty(t, dErr.Drifts[0].RemovedMethods)
+		require.NotEmpty(t, dErr.Drifts[0].RemovedEvents)
+	})
+	t.Run("native contract's state drift explained by HeaderCommitment", func(t *testing.T) {
+		// Stored Ledger state is generated with HeaderCommitment enabled.
+		cfg := bc.GetConfig()
+		cfg.HeaderCommitment = true
+		ledger := native.NewContracts(cfg).Ledger.Metadata()
+		cs := bc.GetContractState(ledger.Hash)
+		require.NotNil(t, cs)
+		cs.ContractBase = ledger.ContractBase
+
+		ps = newPS(t)
+		cache := storage.NewMemCachedStore(ps) // Extra wrapper to avoid good DB corruption.
+		putNativeState(t, cache, cs)
+		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, customConfig, cache)
+		var dErr *core.NativeDriftError
+		require.True(t, errors.As(err, &dErr), err)
+		require.Equal(t, 1, len(dErr.Drifts))
+		require.Equal(t, nativenames.Ledger, dErr.Drifts[0].Name)
+		require.Equal(t, "HeaderCommitment", dErr.Drifts[0].ConfigSetting)
+		require.NotEmpty(t, dErr.Drifts[0].RemovedMethods)
+	})
+
 	t.Run("good", func(t *testing.T) {
 		ps = newPS(t)
 		_, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, customConfig, ps)
@@ -384,6 +565,9 @@ func TestBlockchain_AddHeaders(t *testing.T) {
 	h2 := newHeader(t, h1.Index+1, h1.Hash(), h1.Timestamp+1)
 	h3 := newHeader(t, h2.Index+1, h2.Hash(), h2.Timestamp+1)
 
+	headerCh := make(chan *block.Header, 4)
+	bc.SubscribeForHeaders(headerCh)
+
 	require.NoError(t, bc.AddHeaders())
 	require.NoError(t, bc.AddHeaders(h1, h2))
 	require.NoError(t, bc.AddHeaders(h2, h3))
@@ -392,6 +576,11 @@ func TestBlockchain_AddHeaders(t *testing.T) {
 	assert.Equal(t, uint32(0), bc.BlockHeight())
 	assert.Equal(t, h3.Hash(), bc.CurrentHeaderHash())
 
+	// Events are delivered for accepted headers before blocks arrive.
+	for _, h := range []*block.Header{h1, h2, h3} {
+		require.Equal(t, h.Hash(), (<-headerCh).Hash())
+	}
+
 	// Add them again, they should not be added.
 	require.NoError(t, bc.AddHeaders(h3, h2, h1))
 
@@ -413,6 +602,15 @@ func TestBlockchain_AddHeaders(t *testing.T) {
 	assert.Equal(t, h3.Index, bc.HeaderHeight())
 	assert.Equal(t, uint32(0), bc.BlockHeight())
 	assert.Equal(t, h3.Hash(), bc.CurrentHeaderHash())
+
+	// Neither duplicate nor invalid headers produce events.
+	require.Empty(t, headerCh)
+
+	bc.UnsubscribeFromHeaders(headerCh)
+	h4 := newHeader(t, h3.Index+1, h3.Hash(), h3.Timestamp+1)
+	require.NoError(t, bc.AddHeaders(h4))
+	assert.Equal(t, h4.Index, bc.HeaderHeight())
+	require.Empty(t, headerCh)
 }
 
 func TestBlockchain_AddBlockStateRoot(t *testing.T) {
@@ -444,6 +642,80 @@ func TestBlockchain_AddBlockStateRoot(t *testing.T) {
 	require.NoError(t, bc.AddBlock(b))
 }
 
+func TestBlockchain_VerifyStateRoot(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	e.GenerateNewBlocks(t, 3)
+
+	for i := uint32(0); i <= bc.BlockHeight(); i++ {
+		sr, err := bc.GetStateModule().GetStateRoot(i)
+		require.NoError(t, err)
+		ok, err := bc.VerifyStateRoot(i, sr.Root)
+		require.NoError(t, err)
+		require.True(t, ok, i)
+
+		u := sr.Root
+		u[0] ^= 0xFF
+		ok, err = bc.VerifyStateRoot(i, u)
+		require.NoError(t, err)
+		require.False(t, ok, i)
+	}
+	_, err := bc.VerifyStateRoot(bc.BlockHeight()+1, util.Uint256{})
+	require.Error(t, err)
+}
+
+func TestBlockchain_MPTNodeCache(t *testing.T) {
+	neoCommitteeKey := []byte{0xfb, 0xff, 0xff, 0xff, 0x0e}
+	for _, capacity := range []int{1, 1000} {
+		t.Run(fmt.Sprint(capacity), func(t *testing.T) {
+			bc, acc := chain.NewSingleWithCustomConfigAndStore(t, func(c *config.ProtocolConfiguration) {
+				c.MaxTraceableBlocks = 2
+				c.GarbageCollectionPeriod = 2
+				c.RemoveUntraceableBlocks = true
+			}, nil, false)
+			bc.SetMPTNodeCacheSize(capacity)
+			go bc.Run()
+			t.Cleanup(bc.Close)
+			e := neotest.NewExecutor(t, bc, acc, acc)
+			neoValidatorInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Neo))
+			sm := bc.GetStateModule()
+
+			for i := 0; i < 3; i++ {
+				neoValidatorInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+				si := bc.GetStorageItem(int32(binary.LittleEndian.Uint32(neoCommitteeKey)), neoCommitteeKey[4:])
+				require.NotNil(t, si)
+				for j := 0; j < 2; j++ {
+					v, err := sm.GetState(sm.CurrentLocalStateRoot(), neoCommitteeKey)
+					require.NoError(t, err)
+					require.Equal(t, []byte(si), v)
+				}
+			}
+			sRoot, err := sm.GetStateRoot(bc.BlockHeight())
+			require.NoError(t, err)
+			expected, err := sm.GetState(sRoot.Root, neoCommitteeKey)
+			require.NoError(t, err)
+
+			e.GenerateNewBlocks(t, 1)
+			actual, err := sm.GetState(sRoot.Root, neoCommitteeKey)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+
+			st := bc.GetMPTNodeCacheStats()
+			require.True(t, st.Len <= capacity)
+			if capacity > 1 {
+				require.NotZero(t, st.Hits)
+			}
+
+			// Nodes removed by GC must not be returned from the cache.
+			e.GenerateNewBlocks(t, 4)
+			require.Eventually(t, func() bool {
+				_, err = sm.GetState(sRoot.Root, neoCommitteeKey)
+				return err != nil
+			}, 2*bcPersistInterval, 10*time.Millisecond)
+		})
+	}
+}
+
 func TestBlockchain_AddHeadersStateRoot(t *testing.T) {
 	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
 		c.StateRootInHeader = true
@@ -531,6 +803,299 @@ func TestBlockchain_GetHeader(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestBlockchain_GetGenesisHash(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	e.AddNewBlock(t)
+
+	expected, err := core.ComputeGenesisHash(bc.GetConfig())
+	require.NoError(t, err)
+	require.Equal(t, expected, bc.GetGenesisHash())
+	require.Equal(t, bc.GetHeaderHash(0), bc.GetGenesisHash())
+}
+
+func TestBlockchain_MaxBlockVerificationGAS(t *testing.T) {
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		c.MaxBlockVerificationGAS = 1_500_000 // Enough for a single standard witness only.
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasHash := e.NativeHash(t, nativenames.Gas)
+
+	tx1 := e.NewTx(t, []neotest.Signer{acc}, gasHash, "transfer", acc.ScriptHash(), util.Uint160{1}, 1, nil)
+	tx2 := e.NewTx(t, []neotest.Signer{acc}, gasHash, "transfer", acc.ScriptHash(), util.Uint160{2}, 1, nil)
+
+	require.Equal(t, 1, len(bc.ApplyPolicyToTxSet([]*transaction.Transaction{tx1, tx2})))
+
+	b := e.NewUnsignedBlock(t, tx1, tx2)
+	e.SignBlock(b)
+	require.ErrorIs(t, bc.AddBlock(b), core.ErrBlockVerificationGAS)
+
+	// Verification GAS of pooled transactions is taken from the pool.
+	require.NoError(t, bc.PoolTx(tx1))
+	require.NoError(t, bc.PoolTx(tx2))
+	require.Equal(t, 1, len(bc.ApplyPolicyToTxSet(bc.GetMemPool().GetVerifiedTransactions())))
+	b = e.NewUnsignedBlock(t, tx1, tx2)
+	e.SignBlock(b)
+	require.ErrorIs(t, bc.AddBlock(b), core.ErrBlockVerificationGAS)
+
+	b = e.NewUnsignedBlock(t, tx1)
+	e.SignBlock(b)
+	require.NoError(t, bc.AddBlock(b))
+}
+
+func TestBlockchain_EventJournal(t *testing.T) {
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		c.EventJournalRetention = 5
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasInvoker := e.CommitteeInvoker(e.NativeHash(t, nativenames.Gas))
+
+	collect := func(t *testing.T, ch <-chan *core.JournalEvent) []*core.JournalEvent {
+		var res []*core.JournalEvent
+		for {
+			select {
+			case ev := <-ch:
+				res = append(res, ev)
+			case <-time.After(200 * time.Millisecond):
+				return res
+			}
+		}
+	}
+	// checkSequence checks that events follow prev without gaps and
+	// duplicates.
+	checkSequence := func(t *testing.T, prev core.JournalCursor, events []*core.JournalEvent) {
+		for _, ev := range events {
+			if ev.Cursor.Index == prev.Index {
+				require.Equal(t, prev.Ordinal+1, ev.Cursor.Ordinal)
+			} else {
+				require.Equal(t, prev.Index+1, ev.Cursor.Index)
+				require.Equal(t, uint32(0), ev.Cursor.Ordinal)
+				require.Equal(t, core.JournalBlock, ev.Type)
+				require.Equal(t, bc.GetHeaderHash(int(ev.Cursor.Index)), ev.Block)
+			}
+			prev = ev.Cursor
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		bc, _ := chain.NewSingle(t)
+		require.ErrorIs(t, bc.SubscribeJournal("sub", make(chan *core.JournalEvent)), core.ErrJournalDisabled)
+		require.ErrorIs(t, bc.AckJournal("sub", core.JournalCursor{}), core.ErrJournalDisabled)
+	})
+
+	gasInvoker.Invoke(t, true, "transfer", e.CommitteeHash, util.Uint160{1, 2, 3}, 1, nil)
+	gasInvoker.Invoke(t, true, "transfer", e.CommitteeHash, util.Uint160{1, 2, 3}, 1, nil)
+
+	ch := make(chan *core.JournalEvent)
+	require.NoError(t, bc.SubscribeJournal("indexer", ch))
+	first := collect(t, ch)
+	require.True(t, len(first) > 0)
+	require.Equal(t, core.JournalCursor{}, first[0].Cursor)
+	require.Equal(t, core.JournalBlock, first[0].Type)
+	checkSequence(t, first[0].Cursor, first[1:])
+	require.Equal(t, bc.BlockHeight(), first[len(first)-1].Cursor.Index)
+	var hasNotification bool
+	for _, ev := range first {
+		if ev.Type == core.JournalNotification && ev.Notification.Name == "Transfer" {
+			hasNotification = true
+		}
+	}
+	require.True(t, hasNotification)
+
+	// Acknowledge some event in the middle of the last block and "crash".
+	acked := first[len(first)-3].Cursor
+	require.NoError(t, bc.AckJournal("indexer", acked))
+	bc.UnsubscribeJournal(ch)
+
+	gasInvoker.Invoke(t, true, "transfer", e.CommitteeHash, util.Uint160{1, 2, 3}, 1, nil)
+	e.AddNewBlock(t)
+
+	ch = make(chan *core.JournalEvent)
+	require.NoError(t, bc.SubscribeJournal("indexer", ch))
+	second := collect(t, ch)
+	checkSequence(t, acked, second)
+	require.Equal(t, first[len(first)-2:], second[:2])
+	require.Equal(t, bc.BlockHeight(), second[len(second)-1].Cursor.Index)
+
+	// Live events are delivered to the same subscriber.
+	e.AddNewBlock(t)
+	live := collect(t, ch)
+	checkSequence(t, second[len(second)-1].Cursor, live)
+	require.Equal(t, bc.BlockHeight(), live[len(live)-1].Cursor.Index)
+	bc.UnsubscribeJournal(ch)
+
+	t.Run("pruned", func(t *testing.T) {
+		e.GenerateNewBlocks(t, 10)
+		require.ErrorIs(t, bc.SubscribeJournal("indexer", make(chan *core.JournalEvent)), core.ErrJournalPruned)
+
+		ch := make(chan *core.JournalEvent)
+		require.NoError(t, bc.SubscribeJournal("fresh", ch))
+		events := collect(t, ch)
+		bc.UnsubscribeJournal(ch)
+		require.True(t, len(events) > 0)
+		require.Equal(t, core.JournalCursor{Index: bc.BlockHeight() - 4}, events[0].Cursor)
+		checkSequence(t, events[0].Cursor, events[1:])
+	})
+
+	t.Run("oldest block acknowledged", func(t *testing.T) {
+		oldest := bc.BlockHeight() - 4
+		var last core.JournalCursor
+		ch := make(chan *core.JournalEvent)
+		require.NoError(t, bc.SubscribeJournal("complete", ch))
+		for _, ev := range collect(t, ch) {
+			if ev.Cursor.Index == oldest {
+				last = ev.Cursor
+			}
+		}
+		bc.UnsubscribeJournal(ch)
+		require.Equal(t, oldest, last.Index)
+		require.NoError(t, bc.AckJournal("complete", last))
+		// Every block has at least OnPersist and PostPersist executions.
+		require.NoError(t, bc.AckJournal("partial", core.JournalCursor{Index: oldest, Ordinal: last.Ordinal - 1}))
+		e.AddNewBlock(t)
+
+		ch = make(chan *core.JournalEvent)
+		require.NoError(t, bc.SubscribeJournal("complete", ch))
+		events := collect(t, ch)
+		bc.UnsubscribeJournal(ch)
+		require.True(t, len(events) > 0)
+		require.Equal(t, core.JournalCursor{Index: oldest + 1}, events[0].Cursor)
+		checkSequence(t, events[0].Cursor, events[1:])
+
+		require.ErrorIs(t, bc.SubscribeJournal("partial", make(chan *core.JournalEvent)), core.ErrJournalPruned)
+	})
+}
+
+func TestBlockchain_ReadOnly(t *testing.T) {
+	ps, path := newLevelDBForTestingWithPath(t, "")
+	bc, acc := chain.NewSingleWithCustomConfigAndStore(t, nil, ps, false)
+	go bc.Run()
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasHash := e.NativeHash(t, nativenames.Gas)
+	to := random.Uint160()
+	txHash := e.CommitteeInvoker(gasHash).Invoke(t, true, "transfer", acc.ScriptHash(), to, 1_0000_0000, nil)
+	e.AddNewBlock(t)
+
+	cfg := bc.GetConfig()
+	var (
+		h         = bc.BlockHeight()
+		topHash   = bc.CurrentBlockHash()
+		toBalance = bc.GetUtilityTokenBalance(to)
+	)
+	bc.Close()
+
+	newRO := func(t *testing.T, cfg config.ProtocolConfiguration) (*core.Blockchain, error) {
+		ps, _ := newLevelDBForTestingWithPath(t, path)
+		ro, err := core.NewReadOnlyBlockchain(ps, cfg, zaptest.NewLogger(t))
+		if err != nil {
+			require.NoError(t, ps.Close())
+			return nil, err
+		}
+		t.Cleanup(ro.Close)
+		return ro, nil
+	}
+
+	t.Run("read", func(t *testing.T) {
+		ro, err := newRO(t, cfg)
+		require.NoError(t, err)
+		ro.Run() // No-op, doesn't block.
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.Equal(t, h, ro.BlockHeight())
+				assert.Equal(t, h, ro.HeaderHeight())
+				assert.Equal(t, topHash, ro.CurrentBlockHash())
+				assert.Equal(t, topHash, ro.GetHeaderHash(int(h)))
+				assert.Equal(t, toBalance, ro.GetUtilityTokenBalance(to))
+			}()
+		}
+		wg.Wait()
+
+		b, err := ro.GetBlock(topHash)
+		require.NoError(t, err)
+		require.Equal(t, h, b.Index)
+		hdr, err := ro.GetHeader(topHash)
+		require.NoError(t, err)
+		require.Equal(t, b.Hash(), hdr.Hash())
+		require.True(t, ro.HasBlock(topHash))
+		require.Equal(t, bc.GetGenesisHash(), ro.GetGenesisHash())
+
+		tx, height, err := ro.GetTransaction(txHash)
+		require.NoError(t, err)
+		require.Equal(t, txHash, tx.Hash())
+		require.Equal(t, h-1, height)
+		require.True(t, ro.HasTransaction(txHash))
+		aers, err := ro.GetAppExecResults(txHash, trigger.Application)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(aers))
+		require.Equal(t, vm.HaltState, aers[0].VMState)
+
+		require.NotNil(t, ro.GetContractState(gasHash))
+		require.Contains(t, ro.GetNEP17Contracts(), gasHash)
+		sr, err := ro.GetStateModule().GetStateRoot(h)
+		require.NoError(t, err)
+		require.Equal(t, h, sr.Index)
+
+		var transfers int
+		require.NoError(t, ro.ForEachNEP17Transfer(to, b.Timestamp+1, func(tr *state.NEP17Transfer) (bool, error) {
+			transfers++
+			return true, nil
+		}))
+		require.Equal(t, 1, transfers)
+
+		require.Nil(t, ro.GetMemPool())
+		txes, sysFee, netFee := ro.GetMemPoolVerified()
+		require.Equal(t, 0, len(txes))
+		require.Equal(t, int64(0), sysFee)
+		require.Equal(t, int64(0), netFee)
+		_, digestHeight := ro.GetMemPoolStateDigest()
+		require.Equal(t, h, digestHeight)
+		require.True(t, ro.IsNonceAvailable(acc.ScriptHash(), tx.Nonce+1, h+1))
+
+		// Subscriptions are no-op for read-only chain.
+		ch := make(chan *block.Block)
+		ro.SubscribeForBlocks(ch)
+		ro.UnsubscribeFromBlocks(ch)
+	})
+	t.Run("write", func(t *testing.T) {
+		ro, err := newRO(t, cfg)
+		require.NoError(t, err)
+
+		b, err := ro.GetBlock(topHash)
+		require.NoError(t, err)
+		require.ErrorIs(t, ro.AddBlock(b), core.ErrReadOnlyMode)
+		require.ErrorIs(t, ro.AddHeaders(&b.Header), core.ErrReadOnlyMode)
+		require.ErrorIs(t, ro.PoolTx(b.Transactions[0]), core.ErrReadOnlyMode)
+		require.ErrorIs(t, ro.PoolTxWithSource(b.Transactions[0], mempool.TxSourceLocal), core.ErrReadOnlyMode)
+		require.ErrorIs(t, ro.PoolTxWithData(b.Transactions[0], nil, mempool.New(1, 0, false), nil, nil), core.ErrReadOnlyMode)
+		require.ErrorIs(t, ro.SetColdStorage(storage.NewMemoryStore()), core.ErrReadOnlyMode)
+		_, err = ro.ImportContract(bytes.NewReader(nil))
+		require.ErrorIs(t, err, core.ErrReadOnlyMode)
+		require.Equal(t, h, ro.BlockHeight())
+	})
+	t.Run("empty DB", func(t *testing.T) {
+		_, err := core.NewReadOnlyBlockchain(storage.NewMemoryStore(), cfg, zaptest.NewLogger(t))
+		require.ErrorIs(t, err, core.ErrReadOnlyMode)
+	})
+	t.Run("mismatch KeepOnlyLatestState", func(t *testing.T) {
+		c := cfg
+		c.KeepOnlyLatestState = true
+		_, err := newRO(t, c)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "KeepOnlyLatestState setting mismatch"), err)
+	})
+	t.Run("mismatch StateRootInHeader", func(t *testing.T) {
+		c := cfg
+		c.StateRootInHeader = true
+		_, err := newRO(t, c)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "StateRootInHeader setting mismatch"), err)
+	})
+}
+
 func TestBlockchain_GetBlock(t *testing.T) {
 	bc, acc := chain.NewSingle(t)
 	e := neotest.NewExecutor(t, bc, acc, acc)
@@ -657,6 +1222,47 @@ func TestBlockchain_VerifyHashAgainstScript(t *testing.T) {
 	})
 }
 
+func TestBlockchain_VerifyWitnessDetailed(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	// Multisignature and simple signature witnesses have different costs.
+	simple := e.NewAccount(t)
+	signers := []neotest.Signer{acc, simple}
+	tx := e.PrepareInvocation(t, []byte{byte(opcode.PUSH1)}, signers)
+	require.Equal(t, 2, len(tx.Scripts))
+
+	gas := bc.GetMaxVerificationGAS()
+	costs := make([]int64, len(signers))
+	for i, s := range signers {
+		expected, _ := fee.Calculate(bc.GetBaseExecFee(), s.Script())
+		consumed, limitHit, err := bc.VerifyWitnessDetailed(tx.Signers[i].Account, tx, &tx.Scripts[i], gas)
+		require.NoError(t, err)
+		require.False(t, limitHit)
+		require.Equal(t, expected, consumed)
+
+		total, err := bc.VerifyWitness(tx.Signers[i].Account, tx, &tx.Scripts[i], gas)
+		require.NoError(t, err)
+		require.Equal(t, total, consumed)
+		costs[i] = consumed
+	}
+	require.NotEqual(t, costs[0], costs[1])
+
+	t.Run("gas limit", func(t *testing.T) {
+		consumed, limitHit, err := bc.VerifyWitnessDetailed(tx.Signers[1].Account, tx, &tx.Scripts[1], costs[1]-1)
+		require.True(t, errors.Is(err, core.ErrVerificationFailed))
+		require.True(t, limitHit)
+		require.True(t, consumed > costs[1]-1)
+	})
+	t.Run("invalid signature", func(t *testing.T) {
+		// Signature of the other container.
+		consumed, limitHit, err := bc.VerifyWitnessDetailed(tx.Signers[1].Account, block.New(false), &tx.Scripts[1], gas)
+		require.True(t, errors.Is(err, core.ErrInvalidSignature))
+		require.False(t, limitHit)
+		require.Equal(t, costs[1], consumed)
+	})
+}
+
 func TestBlockchain_IsTxStillRelevant(t *testing.T) {
 	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
 		c.P2PSigExtensions = true
@@ -745,6 +1351,102 @@ func TestBlockchain_IsTxStillRelevant(t *testing.T) {
 	})
 }
 
+func TestBlockchain_IsNonceAvailable(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	sender := acc.ScriptHash()
+	maxVUB := bc.GetConfig().MaxValidUntilBlockIncrement
+
+	newTx := func(t *testing.T, nonce uint32) *transaction.Transaction {
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		tx.Nonce = nonce
+		tx.ValidUntilBlock = bc.BlockHeight() + 3
+		e.SignTx(t, tx, -1, acc)
+		return tx
+	}
+
+	t.Run("bad ValidUntilBlock", func(t *testing.T) {
+		require.False(t, bc.IsNonceAvailable(sender, 1, bc.BlockHeight()))
+		require.False(t, bc.IsNonceAvailable(sender, 1, bc.BlockHeight()+maxVUB+1))
+		require.True(t, bc.IsNonceAvailable(sender, 1, bc.BlockHeight()+maxVUB))
+	})
+	t.Run("mempool", func(t *testing.T) {
+		tx := newTx(t, 2)
+		require.True(t, bc.IsNonceAvailable(sender, 2, bc.BlockHeight()+1))
+		require.NoError(t, bc.PoolTx(tx))
+		require.False(t, bc.IsNonceAvailable(sender, 2, bc.BlockHeight()+1))
+		require.True(t, bc.IsNonceAvailable(sender, 3, bc.BlockHeight()+1))
+		require.True(t, bc.IsNonceAvailable(util.Uint160{1, 2, 3}, 2, bc.BlockHeight()+1))
+	})
+	t.Run("recent blocks", func(t *testing.T) {
+		tx := newTx(t, 4)
+		e.AddNewBlock(t, tx)
+		require.False(t, bc.IsNonceAvailable(sender, 4, bc.BlockHeight()+1))
+		require.True(t, bc.IsNonceAvailable(util.Uint160{1, 2, 3}, 4, bc.BlockHeight()+1))
+
+		// Transaction is expired.
+		e.GenerateNewBlocks(t, 2)
+		require.True(t, bc.IsNonceAvailable(sender, 4, bc.BlockHeight()+1))
+	})
+}
+
+func TestBlockchain_TxRelations(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		bc, _ := chain.NewSingle(t)
+		_, err := bc.GetConflictingTransactions(util.Uint256{1, 2, 3})
+		require.Error(t, err)
+		_, err = bc.GetOracleResponseTx(0)
+		require.Error(t, err)
+	})
+
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		c.P2PSigExtensions = true
+		c.TrackTxRelations = true
+		c.MaxTraceableBlocks = 4
+		c.RemoveUntraceableBlocks = true
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	newConflictsTx := func(t *testing.T, hashes ...util.Uint256) *transaction.Transaction {
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		tx.Nonce = neotest.Nonce()
+		tx.ValidUntilBlock = bc.BlockHeight() + 1
+		for _, h := range hashes {
+			tx.Attributes = append(tx.Attributes, transaction.Attribute{
+				Type:  transaction.ConflictsT,
+				Value: &transaction.Conflicts{Hash: h},
+			})
+		}
+		e.SignTx(t, tx, -1, acc)
+		return tx
+	}
+	h1, h2 := util.Uint256{1, 2, 3}, util.Uint256{4, 5, 6}
+	tx1 := newConflictsTx(t, h1)
+	tx2 := newConflictsTx(t, h1, h2)
+	e.AddNewBlock(t, tx1, tx2)
+
+	res, err := bc.GetConflictingTransactions(h1)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []util.Uint256{tx1.Hash(), tx2.Hash()}, res)
+	res, err = bc.GetConflictingTransactions(h2)
+	require.NoError(t, err)
+	require.Equal(t, []util.Uint256{tx2.Hash()}, res)
+	res, err = bc.GetConflictingTransactions(util.Uint256{7, 8, 9})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(res))
+	_, err = bc.GetOracleResponseTx(0)
+	require.Error(t, err)
+
+	// Relations are removed along with the block.
+	e.GenerateNewBlocks(t, int(bc.GetConfig().MaxTraceableBlocks)+1)
+	res, err = bc.GetConflictingTransactions(h1)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(res))
+	res, err = bc.GetConflictingTransactions(h2)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(res))
+}
+
 func TestBlockchain_MemPoolRemoval(t *testing.T) {
 	const added = 16
 	const notAdded = 32
@@ -771,6 +1473,63 @@ func TestBlockchain_MemPoolRemoval(t *testing.T) {
 	}
 }
 
+func TestBlockchain_GetMemPoolStateDigest(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	e.GenerateNewBlocks(t, 2)
+
+	d, h := bc.GetMemPoolStateDigest()
+	require.Equal(t, bc.BlockHeight(), h)
+	require.Equal(t, 0, d.Count)
+
+	tx := e.NewUnsignedTx(t, e.NativeHash(t, nativenames.Neo), "totalSupply")
+	e.SignTx(t, tx, 1_0000_0000, acc)
+	require.NoError(t, bc.PoolTx(tx))
+	d, _ = bc.GetMemPoolStateDigest()
+	require.Equal(t, 1, d.Count)
+	require.Equal(t, tx.SystemFee+tx.NetworkFee, d.TotalFee)
+}
+
+func TestBlockchain_ComputeStorageCost(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	policy := e.CommitteeInvoker(e.NativeHash(t, nativenames.Policy))
+
+	require.Equal(t, int64(30*native.DefaultStoragePrice), bc.ComputeStorageCost(10, 20))
+
+	policy.Invoke(t, stackitem.Null{}, "setStoragePrice", 1000)
+	oldHeight := bc.BlockHeight() - 1
+	e.AddNewBlock(t)
+	require.Equal(t, int64(30*1000), bc.ComputeStorageCost(10, 20))
+
+	cost, err := bc.ComputeStorageCostAt(oldHeight, 10, 20)
+	require.NoError(t, err)
+	require.Equal(t, int64(30*native.DefaultStoragePrice), cost)
+	cost, err = bc.ComputeStorageCostAt(oldHeight+1, 10, 20)
+	require.NoError(t, err)
+	require.Equal(t, int64(30*1000), cost)
+	cost, err = bc.ComputeStorageCostAt(bc.BlockHeight(), 10, 20)
+	require.NoError(t, err)
+	require.Equal(t, int64(30*1000), cost)
+
+	_, err = bc.ComputeStorageCostAt(bc.BlockHeight()+1, 10, 20)
+	require.Error(t, err)
+
+	t.Run("KeepOnlyLatestState", func(t *testing.T) {
+		bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+			c.KeepOnlyLatestState = true
+		})
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		e.AddNewBlock(t)
+
+		_, err := bc.ComputeStorageCostAt(0, 10, 20)
+		require.Error(t, err)
+		cost, err := bc.ComputeStorageCostAt(bc.BlockHeight(), 10, 20)
+		require.NoError(t, err)
+		require.Equal(t, int64(30*native.DefaultStoragePrice), cost)
+	})
+}
+
 func TestBlockchain_HasBlock(t *testing.T) {
 	bc, acc := chain.NewSingle(t)
 	e := neotest.NewExecutor(t, bc, acc, acc)
@@ -815,12 +1574,544 @@ func TestBlockchain_GetClaimable(t *testing.T) {
 	})
 }
 
+func TestBlockchain_FindNotifications(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	neoHash := e.NativeHash(t, nativenames.Neo)
+	neoValidatorInvoker := e.ValidatorInvoker(neoHash)
+
+	e.GenerateNewBlocks(t, 2)
+	tx1 := neoValidatorInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+	h1 := bc.BlockHeight()
+	e.GenerateNewBlocks(t, 2)
+	tx2 := neoValidatorInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), util.Uint160{3, 2, 1}, 2, nil)
+	h2 := bc.BlockHeight()
+
+	t.Run("all", func(t *testing.T) {
+		res, err := bc.FindNotifications(neoHash, "Transfer", h1, h2+100, 0)
+		require.NoError(t, err)
+		require.Equal(t, 2, len(res))
+		require.Equal(t, h1, res[0].BlockIndex)
+		require.Equal(t, tx1, res[0].Container)
+		require.Equal(t, neoHash, res[0].ScriptHash)
+		require.Equal(t, "Transfer", res[0].Name)
+		require.Equal(t, h2, res[1].BlockIndex)
+		require.Equal(t, tx2, res[1].Container)
+		require.Equal(t, stackitem.Make(2), res[1].Item.Value().([]stackitem.Item)[2])
+	})
+	t.Run("limit", func(t *testing.T) {
+		res, err := bc.FindNotifications(neoHash, "Transfer", 1, h2, 1)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(res))
+		require.Equal(t, tx1, res[0].Container)
+	})
+	t.Run("range", func(t *testing.T) {
+		res, err := bc.FindNotifications(neoHash, "Transfer", h1+1, h2-1, 0)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(res))
+	})
+	t.Run("other event", func(t *testing.T) {
+		res, err := bc.FindNotifications(neoHash, "Unknown", 0, h2, 0)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(res))
+	})
+	t.Run("block containers", func(t *testing.T) {
+		gasHash := e.NativeHash(t, nativenames.Gas)
+		res, err := bc.FindNotifications(gasHash, "Transfer", h1, h1, 0)
+		require.NoError(t, err)
+		require.NotEqual(t, 0, len(res))
+		var blockLevel bool
+		for _, n := range res {
+			if n.Container.Equals(bc.GetHeaderHash(int(h1))) {
+				blockLevel = true
+			}
+		}
+		require.True(t, blockLevel)
+	})
+	t.Run("bad range", func(t *testing.T) {
+		_, err := bc.FindNotifications(neoHash, "Transfer", h2, h1, 0)
+		require.Error(t, err)
+	})
+	t.Run("pruned", func(t *testing.T) {
+		bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+			c.MaxTraceableBlocks = 2
+			c.GarbageCollectionPeriod = 2
+			c.RemoveUntraceableBlocks = true
+		})
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		e.GenerateNewBlocks(t, 5)
+		_, err := bc.FindNotifications(neoHash, "Transfer", 1, bc.BlockHeight(), 0)
+		require.Error(t, err)
+		_, err = bc.FindNotifications(neoHash, "Transfer", bc.BlockHeight()-1, bc.BlockHeight(), 0)
+		require.NoError(t, err)
+	})
+}
+
+func TestBlockchain_GetBlockExecResults(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasInv := e.CommitteeInvoker(e.NativeHash(t, nativenames.Gas))
+
+	tx1 := gasInv.PrepareInvoke(t, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+	tx2 := gasInv.PrepareInvoke(t, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1000_0000_0000_0000, nil)
+	b := e.AddNewBlock(t, tx1, tx2)
+
+	aers, err := bc.GetBlockExecResults(b.Hash())
+	require.NoError(t, err)
+	require.Equal(t, 4, len(aers))
+	require.Equal(t, trigger.OnPersist, aers[0].Trigger)
+	require.Equal(t, b.Hash(), aers[0].Container)
+	for i, tx := range []*transaction.Transaction{tx1, tx2} {
+		require.Equal(t, trigger.Application, aers[i+1].Trigger)
+		require.Equal(t, tx.Hash(), aers[i+1].Container)
+		expected, err := bc.GetAppExecResults(tx.Hash(), trigger.Application)
+		require.NoError(t, err)
+		require.Equal(t, expected[0], *aers[i+1])
+	}
+	require.Equal(t, trigger.PostPersist, aers[3].Trigger)
+	require.Equal(t, b.Hash(), aers[3].Container)
+
+	aers, err = bc.GetBlockExecResults(bc.GetHeaderHash(0))
+	require.NoError(t, err)
+	require.Equal(t, 2, len(aers))
+	require.Equal(t, trigger.OnPersist, aers[0].Trigger)
+	require.Equal(t, trigger.PostPersist, aers[1].Trigger)
+
+	_, err = bc.GetBlockExecResults(util.Uint256{1, 2, 3})
+	require.Error(t, err)
+}
+
+func TestBlockchain_GetBountySchedule(t *testing.T) {
+	// checkSchedule compares the schedule with GAS minted in PostPersist.
+	checkSchedule := func(t *testing.T, bc *core.Blockchain, from, to uint32) []core.BountyEntry {
+		gasHash := bc.UtilityTokenHash()
+		schedule, err := bc.GetBountySchedule(from, to)
+		require.NoError(t, err)
+		require.Equal(t, int(to-from+1), len(schedule))
+		for i, entry := range schedule {
+			require.Equal(t, from+uint32(i), entry.Index)
+			aers, err := bc.GetBlockExecResults(bc.GetHeaderHash(int(entry.Index)))
+			require.NoError(t, err)
+			pp := aers[len(aers)-1]
+			require.Equal(t, 1, len(pp.Events))
+			ev := pp.Events[0]
+			require.Equal(t, gasHash, ev.ScriptHash)
+			require.Equal(t, "Transfer", ev.Name)
+			arr := ev.Item.Value().([]stackitem.Item)
+			require.Equal(t, stackitem.Null{}, arr[0])
+			require.Equal(t, entry.Recipient.GetScriptHash().BytesBE(), arr[1].Value())
+			require.Equal(t, entry.Amount, arr[2].Value())
+		}
+		return schedule
+	}
+
+	t.Run("round-robin", func(t *testing.T) {
+		bc, validators, committee := chain.NewMulti(t)
+		e := neotest.NewExecutor(t, bc, validators, committee)
+		neoInv := e.CommitteeInvoker(e.NativeHash(t, nativenames.Neo))
+		e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas)).Invoke(t, true, "transfer", e.Validator.ScriptHash(), e.CommitteeHash, 100_0000_0000, nil)
+		e.GenerateNewBlocks(t, 3)
+		neoInv.Invoke(t, stackitem.Null{}, "setGasPerBlock", 2*native.GASFactor)
+		e.GenerateNewBlocks(t, 8)
+
+		schedule := checkSchedule(t, bc, 0, bc.BlockHeight())
+		pubs, err := keys.NewPublicKeysFromStrings(bc.GetConfig().StandbyCommittee)
+		require.NoError(t, err)
+		recipients := make(map[string]bool)
+		amounts := make(map[int64]bool)
+		for _, entry := range schedule {
+			recipients[string(entry.Recipient.Bytes())] = true
+			amounts[entry.Amount.Int64()] = true
+		}
+		require.Equal(t, len(pubs), len(recipients))
+		for _, p := range pubs {
+			require.True(t, recipients[string(p.Bytes())])
+		}
+		require.Equal(t, map[int64]bool{native.GASFactor / 2: true, native.GASFactor / 5: true}, amounts)
+
+		checkSchedule(t, bc, 5, 5)
+		checkSchedule(t, bc, 2, 7)
+
+		_, err = bc.GetBountySchedule(3, 2)
+		require.Error(t, err)
+		_, err = bc.GetBountySchedule(0, bc.BlockHeight()+1)
+		require.Error(t, err)
+	})
+	t.Run("committee change", func(t *testing.T) {
+		bc, acc := chain.NewSingle(t)
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		neoInv := e.CommitteeInvoker(e.NativeHash(t, nativenames.Neo))
+		candidate := e.NewAccount(t, 2000_0000_0000)
+		candidatePub := candidate.(neotest.SingleSigner).Account().PrivateKey().PublicKey()
+
+		neoInv.WithSigners(candidate).Invoke(t, true, "registerCandidate", candidatePub.Bytes())
+		neoInv.Invoke(t, true, "vote", acc.ScriptHash(), candidatePub.Bytes())
+		voteHeight := bc.BlockHeight()
+		e.GenerateNewBlocks(t, 2)
+
+		schedule := checkSchedule(t, bc, 0, bc.BlockHeight())
+		for _, entry := range schedule {
+			if entry.Index <= voteHeight {
+				require.NotEqual(t, candidatePub, entry.Recipient)
+			} else {
+				require.Equal(t, candidatePub, entry.Recipient)
+			}
+		}
+	})
+	t.Run("KeepOnlyLatestState", func(t *testing.T) {
+		bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+			c.KeepOnlyLatestState = true
+		})
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		e.AddNewBlock(t)
+		_, err := bc.GetBountySchedule(0, 1)
+		require.Error(t, err)
+	})
+}
+
+func TestBlockchain_IsExtensibleAllowed(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+
+	require.True(t, bc.IsExtensibleAllowed(acc.ScriptHash()))
+	require.False(t, bc.IsExtensibleAllowed(util.Uint160{}))
+	var max util.Uint160
+	for i := range max {
+		max[i] = 0xff
+	}
+	require.False(t, bc.IsExtensibleAllowed(max))
+}
+
+func TestBlockchain_ExtensiblePayloadHandlers(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	newPayload := func(category string, sender util.Uint160, data []byte) *payload.Extensible {
+		p := payload.NewExtensible()
+		p.Category = category
+		p.Sender = sender
+		p.Data = data
+		return p
+	}
+	other := util.Uint160{1, 2, 3}
+
+	t.Run("node categories", func(t *testing.T) {
+		for _, c := range []string{"dBFT", "StateService"} {
+			require.NoError(t, bc.VerifyExtensible(newPayload(c, acc.ScriptHash(), nil)))
+			require.True(t, bc.IsExtensibleAllowed(acc.ScriptHash()))
+			err := bc.VerifyExtensible(newPayload(c, other, nil))
+			require.True(t, errors.Is(err, core.ErrDisallowedExtensibleSender), err)
+
+			err = bc.RegisterExtensiblePayloadHandler(c, nil, func(uint32) []util.Uint160 { return nil })
+			require.True(t, errors.Is(err, core.ErrExtensibleCategoryExists), err)
+		}
+	})
+
+	err := bc.VerifyExtensible(newPayload("Custom", other, nil))
+	require.True(t, errors.Is(err, core.ErrUnknownExtensibleCategory), err)
+
+	var heights []uint32
+	errInvalid := errors.New("invalid data")
+	require.Error(t, bc.RegisterExtensiblePayloadHandler("Custom", nil, nil))
+	require.NoError(t, bc.RegisterExtensiblePayloadHandler("Custom", func(p *payload.Extensible) error {
+		if len(p.Data) == 0 {
+			return errInvalid
+		}
+		return nil
+	}, func(h uint32) []util.Uint160 {
+		heights = append(heights, h)
+		if h%2 == 0 {
+			return []util.Uint160{other}
+		}
+		return nil
+	}))
+	err = bc.RegisterExtensiblePayloadHandler("Custom", nil, func(uint32) []util.Uint160 { return nil })
+	require.True(t, errors.Is(err, core.ErrExtensibleCategoryExists), err)
+
+	e.GenerateNewBlocks(t, int(2-bc.BlockHeight()%2))
+	require.NoError(t, bc.VerifyExtensible(newPayload("Custom", other, []byte{1})))
+	err = bc.VerifyExtensible(newPayload("Custom", other, nil))
+	require.True(t, errors.Is(err, errInvalid), err)
+	err = bc.VerifyExtensible(newPayload("Custom", acc.ScriptHash(), []byte{1}))
+	require.True(t, errors.Is(err, core.ErrDisallowedExtensibleSender), err)
+
+	// Allowed senders depend on the current height.
+	e.AddNewBlock(t)
+	err = bc.VerifyExtensible(newPayload("Custom", other, []byte{1}))
+	require.True(t, errors.Is(err, core.ErrDisallowedExtensibleSender), err)
+	require.Equal(t, bc.BlockHeight(), heights[len(heights)-1])
+
+	// Node categories are not affected.
+	require.NoError(t, bc.VerifyExtensible(newPayload("dBFT", acc.ScriptHash(), nil)))
+}
+
+func TestBlockchain_GetContractMethodTokens(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package foo
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop/native/gas"
+		"github.com/nspcc-dev/neo-go/pkg/interop/native/ledger"
+	)
+	func Main() int {
+		return ledger.CurrentIndex() + gas.BalanceOf(nil)
+	}`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "TokensContract"})
+	e.DeployContract(t, c, nil)
+
+	tokens, err := bc.GetContractMethodTokens(c.Hash)
+	require.NoError(t, err)
+	require.Equal(t, c.NEF.Tokens, tokens)
+	require.Equal(t, 2, len(tokens))
+	require.Equal(t, e.NativeHash(t, nativenames.Ledger), tokens[0].Hash)
+	require.Equal(t, "currentIndex", tokens[0].Method)
+	require.Equal(t, e.NativeHash(t, nativenames.Gas), tokens[1].Hash)
+	require.Equal(t, "balanceOf", tokens[1].Method)
+	require.Equal(t, uint16(1), tokens[1].ParamCount)
+	require.True(t, tokens[1].HasReturn)
+
+	tokens, err = bc.GetContractMethodTokens(e.NativeHash(t, nativenames.Neo))
+	require.NoError(t, err)
+	require.Equal(t, 0, len(tokens))
+
+	_, err = bc.GetContractMethodTokens(util.Uint160{1, 2, 3})
+	require.True(t, errors.Is(err, storage.ErrKeyNotFound), err)
+}
+
+func TestBlockchain_VMSnapshot(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package foo
+	func Main(n int) []int {
+		res := []int{}
+		for i := 0; i < n; i++ {
+			res = append(res, fib(i))
+		}
+		return res
+	}
+	func fib(n int) int {
+		if n < 2 {
+			return n
+		}
+		return fib(n-1) + fib(n-2)
+	}`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "Fib"})
+	e.DeployContract(t, c, nil)
+	inv := e.CommitteeInvoker(c.Hash)
+
+	expected, err := inv.TestInvoke(t, "main", 8)
+	require.NoError(t, err)
+
+	data := inv.TestInvokeUntil(t, func(v *vm.VM) bool {
+		return v.Istack().Len() > 5
+	}, "main", 8)
+
+	tx := inv.PrepareInvokeNoSign(t, "main", 8)
+	ic := bc.GetTestVM(trigger.Application, tx, e.NewUnsignedBlock(t, tx))
+	t.Cleanup(ic.Finalize)
+	require.NoError(t, ic.VM.LoadSnapshot(data))
+	require.Equal(t, 6, ic.VM.Istack().Len())
+	require.NoError(t, ic.VM.Run())
+	require.Equal(t, expected.ToArray(), ic.VM.Estack().ToArray())
+}
+
+func TestBlockchain_GetMemPoolVerified(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	txes, sysFee, netFee := bc.GetMemPoolVerified()
+	require.Empty(t, txes)
+	require.Zero(t, sysFee)
+	require.Zero(t, netFee)
+
+	// Transactions of the same size differ by the network fee only.
+	extraFees := []int64{300, 100, 400, 200}
+	pooled := make([]*transaction.Transaction, len(extraFees))
+	for i, extra := range extraFees {
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		tx.Nonce = neotest.Nonce()
+		tx.ValidUntilBlock = bc.BlockHeight() + 1
+		tx.NetworkFee = extra
+		e.SignTx(t, tx, -1, acc)
+		require.NoError(t, bc.PoolTx(tx))
+		pooled[i] = tx
+	}
+
+	txes, sysFee, netFee = bc.GetMemPoolVerified()
+	require.Equal(t, []*transaction.Transaction{pooled[2], pooled[0], pooled[3], pooled[1]}, txes)
+	var expSys, expNet int64
+	for _, tx := range pooled {
+		expSys += tx.SystemFee
+		expNet += tx.NetworkFee
+	}
+	require.Equal(t, expSys, sysFee)
+	require.Equal(t, expNet, netFee)
+
+	// Snapshot is not affected by mempool changes.
+	e.AddNewBlock(t, pooled...)
+	require.Equal(t, 0, bc.GetMemPool().Count())
+	require.Equal(t, len(pooled), len(txes))
+	require.Equal(t, pooled[2], txes[0])
+}
+
+func TestBlockchain_GetFeeReport(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	neoValidatorInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Neo))
+
+	e.GenerateNewBlocks(t, 2)
+	h1 := bc.BlockHeight() + 1
+	var sysFee, netFee int64
+	for i := 0; i < 3; i++ {
+		h := neoValidatorInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+		tx, _, err := bc.GetTransaction(h)
+		require.NoError(t, err)
+		sysFee += tx.SystemFee
+		netFee += tx.NetworkFee
+	}
+	h2 := bc.BlockHeight()
+	e.GenerateNewBlocks(t, 2)
+
+	t.Run("good", func(t *testing.T) {
+		rep, err := bc.GetFeeReport(h1, h2)
+		require.NoError(t, err)
+		require.Equal(t, core.FeeReport{
+			From:       h1,
+			To:         h2,
+			SystemFee:  sysFee,
+			NetworkFee: netFee,
+			GASBurned:  sysFee + netFee,
+			TxCount:    3,
+			AverageFee: (sysFee + netFee) / 3,
+		}, rep)
+	})
+	t.Run("empty blocks", func(t *testing.T) {
+		rep, err := bc.GetFeeReport(h2+1, bc.BlockHeight())
+		require.NoError(t, err)
+		require.Equal(t, core.FeeReport{From: h2 + 1, To: bc.BlockHeight()}, rep)
+	})
+	t.Run("bad range", func(t *testing.T) {
+		_, err := bc.GetFeeReport(h2, h1)
+		require.Error(t, err)
+		_, err = bc.GetFeeReport(h1, bc.BlockHeight()+1)
+		require.Error(t, err)
+	})
+	t.Run("pruned", func(t *testing.T) {
+		bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+			c.MaxTraceableBlocks = 2
+			c.GarbageCollectionPeriod = 2
+			c.RemoveUntraceableBlocks = true
+		})
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		e.GenerateNewBlocks(t, 5)
+		_, err := bc.GetFeeReport(1, bc.BlockHeight())
+		require.Error(t, err)
+		_, err = bc.GetFeeReport(bc.BlockHeight()-1, bc.BlockHeight())
+		require.NoError(t, err)
+	})
+}
+
+func TestBlockchain_SupplyDeltas(t *testing.T) {
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		c.TrackSupplyDeltas = true
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+	neoInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Neo))
+
+	var gasSupply, neoSupply []*big.Int
+	getSupply := func(t *testing.T, inv *neotest.ContractInvoker) *big.Int {
+		stack, err := inv.TestInvoke(t, "totalSupply")
+		require.NoError(t, err)
+		return stack.Pop().BigInt()
+	}
+	checkEqual := func(t *testing.T, expected, actual *big.Int, h uint32) {
+		require.Equal(t, 0, expected.Cmp(actual), "height %d: expected %s, got %s", h, expected, actual)
+	}
+	record := func(t *testing.T) {
+		require.Equal(t, int(bc.BlockHeight()), len(gasSupply))
+		gasSupply = append(gasSupply, getSupply(t, gasInvoker))
+		neoSupply = append(neoSupply, getSupply(t, neoInvoker))
+	}
+	record(t) // Genesis.
+
+	for i := 0; i < 3; i++ {
+		gasInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1000, nil)
+		record(t)
+		// NEO transfer also mints GAS reward for the sender.
+		neoInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+		record(t)
+		// Fees of failed transaction are burnt anyway.
+		gasInvoker.InvokeFail(t, "expected byte size of 20", "transfer", []byte{1, 2, 3}, acc.ScriptHash(), 1000, nil)
+		record(t)
+		e.AddNewBlock(t)
+		record(t)
+	}
+
+	d, err := bc.GetSupplyDelta(0)
+	require.NoError(t, err)
+	checkEqual(t, gasSupply[0], &d.GAS.Delta, 0)
+	checkEqual(t, neoSupply[0], &d.NEO.Delta, 0)
+	for i := uint32(1); i <= bc.BlockHeight(); i++ {
+		d, err := bc.GetSupplyDelta(i)
+		require.NoError(t, err)
+		checkEqual(t, new(big.Int).Sub(gasSupply[i], gasSupply[i-1]), &d.GAS.Delta, i)
+		checkEqual(t, gasSupply[i], &d.GAS.Supply, i)
+		checkEqual(t, new(big.Int).Sub(neoSupply[i], neoSupply[i-1]), &d.NEO.Delta, i)
+		checkEqual(t, neoSupply[i], &d.NEO.Supply, i)
+	}
+	require.NoError(t, bc.VerifySupplyDeltas(0, bc.BlockHeight()))
+
+	top := bc.BlockHeight()
+	d, err = bc.GetSupplyDeltaRange(1, top)
+	require.NoError(t, err)
+	checkEqual(t, new(big.Int).Sub(gasSupply[top], gasSupply[0]), &d.GAS.Delta, top)
+	checkEqual(t, gasSupply[top], &d.GAS.Supply, top)
+	require.Equal(t, 0, d.NEO.Delta.Sign())
+
+	_, err = bc.GetSupplyDelta(top + 1)
+	require.Error(t, err)
+	_, err = bc.GetSupplyDeltaRange(top, top-1)
+	require.Error(t, err)
+	_, err = bc.GetSupplyDeltaRange(0, core.MaxSupplyDeltaRange)
+	require.Error(t, err)
+	require.Error(t, bc.VerifySupplyDeltas(1, math.MaxUint32))
+
+	t.Run("disabled", func(t *testing.T) {
+		bc, _ := chain.NewSingle(t)
+		_, err := bc.GetSupplyDelta(0)
+		require.Error(t, err)
+	})
+}
+
+// persistWithClock makes the Blockchain running with the given clock persist
+// its changes once and waits for it to finish.
+func persistWithClock(clk *clock.Manual) {
+	clk.BlockUntil(1) // Wait for the persist timer to be started.
+	clk.Advance(bcPersistInterval)
+	clk.BlockUntil(1) // Wait for the persist timer to be reset after persisting.
+}
+
+// getPersistedHeight returns the height of the last block persisted to the store.
+func getPersistedHeight(t *testing.T, st storage.Store) uint32 {
+	h, err := dao.NewSimple(st, false, false).GetCurrentBlockHeight()
+	require.NoError(t, err)
+	return h
+}
+
 func TestBlockchain_Close(t *testing.T) {
 	st := storage.NewMemoryStore()
+	clk := clock.NewManual(time.Unix(0, 0))
 	bc, acc := chain.NewSingleWithCustomConfigAndStore(t, nil, st, false)
+	bc.SetClock(clk)
 	e := neotest.NewExecutor(t, bc, acc, acc)
 	go bc.Run()
-	e.GenerateNewBlocks(t, 10)
+	e.GenerateNewBlocks(t, 5)
+	persistWithClock(clk)
+	require.Equal(t, uint32(5), getPersistedHeight(t, st))
+	e.GenerateNewBlocks(t, 5)
+	require.Equal(t, uint32(5), getPersistedHeight(t, st))
 	bc.Close()
 	// It's a hack, but we use internal knowledge of MemoryStore
 	// implementation which makes it completely unusable (up to panicing)
@@ -977,11 +2268,12 @@ func TestBlockchain_RemoveUntraceable(t *testing.T) {
 		}
 	}
 	t.Run("P2PStateExchangeExtensions off", func(t *testing.T) {
-		bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		clk := clock.NewManual(time.Unix(0, 0))
+		bc, acc := chain.NewSingleWithCustomConfigAndClock(t, func(c *config.ProtocolConfiguration) {
 			c.MaxTraceableBlocks = 2
 			c.GarbageCollectionPeriod = 2
 			c.RemoveUntraceableBlocks = true
-		})
+		}, clk)
 		e := neotest.NewExecutor(t, bc, acc, acc)
 		neoValidatorInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Neo))
 
@@ -1000,11 +2292,7 @@ func TestBlockchain_RemoveUntraceable(t *testing.T) {
 		check(t, bc, tx1Hash, b1.Hash(), sRoot.Root, false)
 		e.GenerateNewBlocks(t, 4)
 
-		sm := bc.GetStateModule()
-		require.Eventually(t, func() bool {
-			_, err = sm.GetState(sRoot.Root, neoCommitteeKey)
-			return err != nil
-		}, 2*bcPersistInterval, 10*time.Millisecond)
+		persistWithClock(clk)
 		check(t, bc, tx1Hash, b1.Hash(), sRoot.Root, true)
 	})
 	t.Run("P2PStateExchangeExtensions on", func(t *testing.T) {
@@ -1048,6 +2336,197 @@ func TestBlockchain_RemoveUntraceable(t *testing.T) {
 	})
 }
 
+func TestBlockchain_TrimMemPool(t *testing.T) {
+	const sweepInterval = 10
+	st := storage.NewMemoryStore()
+	clk := clock.NewManual(time.Unix(0, 0))
+	bc, acc := chain.NewSingleWithCustomConfigAndStore(t, func(c *config.ProtocolConfiguration) {
+		c.MemPoolSweepInterval = sweepInterval
+	}, st, false)
+	bc.SetClock(clk)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	go bc.Run()
+	t.Cleanup(bc.Close)
+
+	mp := bc.GetMemPool()
+	// Expired transactions can't be pooled via PoolTx, so they're put into
+	// the mempool directly.
+	addExpired := func(t *testing.T) *transaction.Transaction {
+		tx := e.PrepareInvocation(t, []byte{byte(opcode.PUSH1)}, []neotest.Signer{acc}, bc.BlockHeight())
+		require.NoError(t, mp.Add(tx, bc))
+		return tx
+	}
+	valid := e.PrepareInvocation(t, []byte{byte(opcode.PUSH1)}, []neotest.Signer{acc}, bc.BlockHeight()+10)
+	require.NoError(t, bc.PoolTx(valid))
+
+	t.Run("manual", func(t *testing.T) {
+		expired := addExpired(t)
+		require.Equal(t, 2, mp.Count())
+		require.Equal(t, 1, bc.TrimMemPool())
+		require.False(t, mp.ContainsKey(expired.Hash()))
+		require.True(t, mp.ContainsKey(valid.Hash()))
+		require.Equal(t, 0, bc.TrimMemPool())
+	})
+	t.Run("background", func(t *testing.T) {
+		expired := addExpired(t)
+		h := bc.BlockHeight()
+		clk.BlockUntil(2) // Persist timer and sweep ticker.
+		clk.Advance(sweepInterval * time.Second)
+		require.Eventually(t, func() bool { return !mp.ContainsKey(expired.Hash()) }, time.Second, 10*time.Millisecond)
+		require.True(t, mp.ContainsKey(valid.Hash()))
+		require.Equal(t, h, bc.BlockHeight())
+	})
+}
+
+func TestBlockchain_PersistInterval(t *testing.T) {
+	st := storage.NewMemoryStore()
+	clk := clock.NewManual(time.Unix(0, 0))
+	bc, acc := chain.NewSingleWithCustomConfigAndStore(t, nil, st, false)
+	bc.SetClock(clk)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	go bc.Run()
+	t.Cleanup(bc.Close)
+
+	persistWithClock(clk) // Genesis block.
+	require.Equal(t, uint32(0), getPersistedHeight(t, st))
+
+	e.GenerateNewBlocks(t, 3)
+	clk.Advance(bcPersistInterval - 1)
+	require.Equal(t, uint32(0), getPersistedHeight(t, st))
+
+	clk.Advance(1)
+	clk.BlockUntil(1)
+	require.Equal(t, uint32(3), getPersistedHeight(t, st))
+
+	e.GenerateNewBlocks(t, 2)
+	clk.Advance(bcPersistInterval / 2)
+	require.Equal(t, uint32(3), getPersistedHeight(t, st))
+	clk.Advance(bcPersistInterval / 2)
+	clk.BlockUntil(1)
+	require.Equal(t, uint32(5), getPersistedHeight(t, st))
+}
+
+func TestBlockchain_GarbageCollectionPeriod(t *testing.T) {
+	clk := clock.NewManual(time.Unix(0, 0))
+	bc, acc := chain.NewSingleWithCustomConfigAndClock(t, func(c *config.ProtocolConfiguration) {
+		c.MaxTraceableBlocks = 2
+		c.GarbageCollectionPeriod = 4
+		c.RemoveUntraceableBlocks = true
+	}, clk)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	sm := bc.GetStateModule()
+	neoCommitteeKey := []byte{0xfb, 0xff, 0xff, 0xff, 0x0e}
+
+	e.GenerateNewBlocks(t, 1)
+	sRoot, err := sm.GetStateRoot(1)
+	require.NoError(t, err)
+	checkState := func(t *testing.T, removed bool) {
+		_, err := sm.GetState(sRoot.Root, neoCommitteeKey)
+		if removed {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+		}
+	}
+
+	// The first period is passed, but GC target is not yet beyond it.
+	e.GenerateNewBlocks(t, 4)
+	persistWithClock(clk)
+	checkState(t, false)
+
+	// GC target is beyond the first period, but no new period is reached.
+	e.GenerateNewBlocks(t, 2)
+	persistWithClock(clk)
+	checkState(t, false)
+
+	// New period is reached, GC removes everything up to block 8.
+	e.GenerateNewBlocks(t, 3)
+	persistWithClock(clk)
+	checkState(t, true)
+}
+
+func TestBlockchain_RemoveUntraceableLedger(t *testing.T) {
+	// check ensures that Ledger answers match blocks retrievability for all
+	// blocks in the chain.
+	check := func(t *testing.T, e *neotest.Executor) {
+		bc := e.Chain
+		ledger := e.CommitteeInvoker(e.NativeHash(t, nativenames.Ledger))
+		height := bc.BlockHeight()
+		mtb := bc.GetConfig().MaxTraceableBlocks
+		for i := uint32(0); i <= height; i++ {
+			traceable := i+mtb > height
+			stack, err := ledger.TestInvoke(t, "getBlock", i)
+			require.NoError(t, err)
+			res := stack.Pop().Item()
+			_, err = bc.GetBlock(bc.GetHeaderHash(int(i)))
+			if traceable {
+				require.NoError(t, err, "block %d is traceable, but not retrievable", i)
+				require.NotEqual(t, stackitem.Null{}, res, i)
+				arr := res.Value().([]stackitem.Item)
+				require.Equal(t, bc.GetHeaderHash(int(i)).BytesBE(), arr[0].Value().([]byte), i)
+			} else {
+				require.Equal(t, stackitem.Null{}, res, i)
+			}
+		}
+		// The oldest traceable block is removed by the block being persisted,
+		// but it still should be accessible for transactions from this block.
+		if height+1 >= mtb {
+			oldest := height + 1 - mtb
+			ledger.InvokeAndCheck(t, func(t testing.TB, stack []stackitem.Item) {
+				require.Equal(t, 1, len(stack))
+				arr, ok := stack[0].Value().([]stackitem.Item)
+				require.True(t, ok, "block %d is not accessible at the boundary", oldest)
+				require.Equal(t, bc.GetHeaderHash(int(oldest)).BytesBE(), arr[0].Value().([]byte))
+			}, "getBlock", oldest)
+			require.Equal(t, height+1, bc.BlockHeight())
+		}
+		// Readers that don't yet see the latest block (like test invocations
+		// racing with block persistence) can still get blocks that are
+		// traceable at the previous height.
+		height = bc.BlockHeight()
+		if height >= mtb {
+			oldest := height - mtb
+			tx := ledger.PrepareInvokeNoSign(t, "getBlock", oldest)
+			ic := bc.GetTestVM(trigger.Application, tx, &block.Block{Header: block.Header{Index: height}})
+			t.Cleanup(ic.Finalize)
+			ic.VM.LoadScriptWithFlags(tx.Script, callflag.All)
+			require.NoError(t, ic.VM.Run())
+			arr, ok := ic.VM.Estack().Pop().Item().Value().([]stackitem.Item)
+			require.True(t, ok, "block %d is not accessible for the previous height", oldest)
+			require.Equal(t, bc.GetHeaderHash(int(oldest)).BytesBE(), arr[0].Value().([]byte))
+		}
+	}
+	t.Run("P2PStateExchangeExtensions off", func(t *testing.T) {
+		bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+			c.MaxTraceableBlocks = 2
+			c.GarbageCollectionPeriod = 2
+			c.RemoveUntraceableBlocks = true
+		})
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		neoValidatorInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Neo))
+		for i := 0; i < 6; i++ {
+			neoValidatorInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+			check(t, e)
+		}
+	})
+	t.Run("P2PStateExchangeExtensions on", func(t *testing.T) {
+		bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+			c.MaxTraceableBlocks = 2
+			c.GarbageCollectionPeriod = 2
+			c.RemoveUntraceableBlocks = true
+			c.P2PStateExchangeExtensions = true
+			c.StateSyncInterval = 2
+			c.StateRootInHeader = true
+		})
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		neoValidatorInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Neo))
+		for i := 0; i < 8; i++ {
+			neoValidatorInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), util.Uint160{1, 2, 3}, 1, nil)
+			check(t, e)
+		}
+	})
+}
+
 func TestBlockchain_InvalidNotification(t *testing.T) {
 	bc, acc := chain.NewSingle(t)
 	e := neotest.NewExecutor(t, bc, acc, acc)
@@ -1279,6 +2758,29 @@ func TestBlockchain_VerifyTx(t *testing.T) {
 		require.NoError(t, accs[0].SignTx(netmode.UnitTestNet, tx))
 		checkErr(t, core.ErrInvalidScript, tx)
 	})
+	t.Run("InvalidWitnessRule", func(t *testing.T) {
+		cond := transaction.ConditionBoolean(true)
+		check := func(t *testing.T, c transaction.WitnessCondition) {
+			tx := newTestTx(t, h, testScript)
+			tx.Signers[0].Scopes = transaction.Rules
+			tx.Signers[0].Rules = []transaction.WitnessRule{{
+				Action:    transaction.WitnessAllow,
+				Condition: c,
+			}}
+			require.NoError(t, accs[0].SignTx(netmode.UnitTestNet, tx))
+			checkErr(t, core.ErrInvalidWitnessRule, tx)
+		}
+		t.Run("too deep", func(t *testing.T) {
+			check(t, &transaction.ConditionNot{Condition: &transaction.ConditionAnd{&cond}})
+		})
+		t.Run("too many subitems", func(t *testing.T) {
+			subs := make(transaction.ConditionOr, transaction.MaxConditionSubitems+1)
+			for i := range subs {
+				subs[i] = &cond
+			}
+			check(t, &subs)
+		})
+	})
 	t.Run("InvalidVerificationScript", func(t *testing.T) {
 		tx := newTestTx(t, h, testScript)
 		verif := []byte{byte(opcode.JMP), 3, 0xff, byte(opcode.PUSHT)}
@@ -1321,6 +2823,7 @@ func TestBlockchain_VerifyTx(t *testing.T) {
 		require.NoError(t, accs[0].SignTx(netmode.UnitTestNet, tx2))
 		err := bc.PoolTx(tx2)
 		require.True(t, errors.Is(err, core.ErrMemPoolConflict))
+		require.Contains(t, err.Error(), tx.Hash().StringLE())
 	})
 	t.Run("InvalidWitnessHash", func(t *testing.T) {
 		tx := newTestTx(t, h, testScript)
@@ -1370,6 +2873,7 @@ func TestBlockchain_VerifyTx(t *testing.T) {
 		require.NoError(t, accs[0].SignTx(netmode.UnitTestNet, tx2))
 		err := bc.PoolTx(tx2, mp)
 		require.True(t, errors.Is(err, core.ErrOOM))
+		require.Contains(t, err.Error(), tx1.Hash().StringLE())
 	})
 	t.Run("Attribute", func(t *testing.T) {
 		t.Run("InvalidHighPriority", func(t *testing.T) {
@@ -1409,37 +2913,15 @@ func TestBlockchain_VerifyTx(t *testing.T) {
 			const gasForResponse int64 = 10_000_000
 			putOracleRequest(t, cInvoker, "https://get.1234", new(string), "handle", []byte{}, gasForResponse)
 
-			oracleScript, err := smartcontract.CreateMajorityMultiSigRedeemScript(oraclePubs)
-			require.NoError(t, err)
-			oracleMultisigHash := hash.Hash160(oracleScript)
-
-			respScript := native.CreateOracleResponseScript(oracleHash)
-
 			// We need to create new transaction,
 			// because hashes are cached after signing.
 			getOracleTx := func(t *testing.T) *transaction.Transaction {
-				tx := transaction.New(respScript, 1000_0000)
-				tx.Nonce = neotest.Nonce()
-				tx.ValidUntilBlock = bc.BlockHeight() + 1
-				resp := &transaction.OracleResponse{
-					ID:     0,
-					Code:   transaction.Success,
-					Result: []byte{1, 2, 3},
-				}
-				tx.Attributes = []transaction.Attribute{{
-					Type:  transaction.OracleResponseT,
-					Value: resp,
-				}}
-				tx.NetworkFee += 4_000_000 // multisig check
-				tx.SystemFee = gasForResponse - tx.NetworkFee
-				tx.Signers = []transaction.Signer{{
-					Account: oracleMultisigHash,
-					Scopes:  transaction.None,
-				}}
-				size := io.GetVarSize(tx)
-				netFee, sizeDelta := fee.Calculate(bc.GetBaseExecFee(), oracleScript)
-				tx.NetworkFee += netFee
-				tx.NetworkFee += int64(size+sizeDelta) * bc.FeePerByte()
+				tx, err := oracle.BuildResponseTx(bc, 0, transaction.Success, []byte{1, 2, 3}, oracleAcc)
+				require.NoError(t, err)
+				// Spare some GAS for additional witnesses and script
+				// modifications made by tests.
+				tx.NetworkFee += 1_000_000
+				tx.SystemFee -= 1_000_000
 				return tx
 			}
 
@@ -1462,23 +2944,19 @@ func TestBlockchain_VerifyTx(t *testing.T) {
 				require.NoError(t, bc.VerifyTx(tx))
 
 				t.Run("NativeVerify", func(t *testing.T) {
-					tx.Signers = append(tx.Signers, transaction.Signer{
-						Account: oracleHash,
-						Scopes:  transaction.None,
-					})
-					tx.Scripts = append(tx.Scripts, transaction.Witness{})
+					require.Equal(t, oracleHash, tx.Sender())
 					t.Run("NonZeroVerification", func(t *testing.T) {
 						w := io.NewBufBinWriter()
 						emit.Opcodes(w.BinWriter, opcode.ABORT)
 						emit.Bytes(w.BinWriter, util.Uint160{}.BytesBE())
 						emit.Int(w.BinWriter, 0)
 						emit.String(w.BinWriter, nativenames.Oracle)
-						tx.Scripts[len(tx.Scripts)-1].VerificationScript = w.Bytes()
+						tx.Scripts[0].VerificationScript = w.Bytes()
 						err := bc.VerifyTx(tx)
 						require.True(t, errors.Is(err, core.ErrNativeContractWitness), "got: %v", err)
 					})
 					t.Run("Good", func(t *testing.T) {
-						tx.Scripts[len(tx.Scripts)-1].VerificationScript = nil
+						tx.Scripts[0].VerificationScript = nil
 						require.NoError(t, bc.VerifyTx(tx))
 					})
 				})
@@ -1503,7 +2981,8 @@ func TestBlockchain_VerifyTx(t *testing.T) {
 			})
 			t.Run("InvalidSigner", func(t *testing.T) {
 				tx := getOracleTx(t)
-				tx.Signers[0].Account = accs[0].Contract.ScriptHash()
+				tx.Signers[1].Account = accs[0].Contract.ScriptHash()
+				tx.Scripts = tx.Scripts[:1]
 				require.NoError(t, accs[0].SignTx(netmode.UnitTestNet, tx))
 				checkErr(t, core.ErrInvalidAttribute, tx)
 			})
@@ -1513,6 +2992,20 @@ func TestBlockchain_VerifyTx(t *testing.T) {
 				require.NoError(t, oracleAcc.SignTx(netmode.UnitTestNet, tx))
 				checkErr(t, core.ErrInvalidAttribute, tx)
 			})
+			t.Run("InsufficientFunds", func(t *testing.T) {
+				tx, err := oracle.BuildResponseTx(bc, 0, transaction.Success, make([]byte, transaction.MaxOracleResultSize), oracleAcc)
+				require.NoError(t, err)
+				resp := tx.Attributes[0].Value.(*transaction.OracleResponse)
+				require.Equal(t, transaction.InsufficientFunds, resp.Code)
+				require.Nil(t, resp.Result)
+				require.Equal(t, gasForResponse, tx.SystemFee+tx.NetworkFee)
+				require.NoError(t, oracleAcc.SignTx(netmode.UnitTestNet, tx))
+				require.NoError(t, bc.VerifyTx(tx))
+			})
+			t.Run("MissingRequest", func(t *testing.T) {
+				_, err := oracle.BuildResponseTx(bc, 2, transaction.Success, []byte{1, 2, 3}, oracleAcc)
+				require.Error(t, err)
+			})
 		})
 		t.Run("NotValidBefore", func(t *testing.T) {
 			getNVBTx := func(e *neotest.Executor, height uint32) *transaction.Transaction {
@@ -1974,3 +3467,558 @@ func TestBlockchain_Bug1728(t *testing.T) {
 	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "TestContract"})
 	managementInvoker.DeployContract(t, c, nil)
 }
+
+func TestBlockchain_VerifyBlockStateTransition(t *testing.T) {
+	ps, path := newLevelDBForTestingWithPath(t, "")
+	customConfig := func(c *config.ProtocolConfiguration) {
+		c.StateRootInHeader = true
+		c.P2PSigExtensions = true // Need for basic chain initializer.
+	}
+	bc, validators, committee, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, customConfig, ps)
+	require.NoError(t, err)
+	go bc.Run()
+	e := neotest.NewExecutor(t, bc, validators, committee)
+	initBasicChain(t, e)
+
+	h := bc.BlockHeight()
+	for i := uint32(1); i <= h; i++ {
+		require.NoError(t, bc.VerifyBlockStateTransition(i), i)
+	}
+	require.Error(t, bc.VerifyBlockStateTransition(0))
+	require.Error(t, bc.VerifyBlockStateTransition(h+1))
+
+	corrupted := h / 2
+	sr, err := bc.GetStateModule().GetStateRoot(corrupted)
+	require.NoError(t, err)
+	bc.Close() // Ensure persist is done and persistent store is properly closed.
+
+	t.Run("corrupted state root", func(t *testing.T) {
+		ps, _ = newLevelDBForTestingWithPath(t, path)
+		t.Cleanup(func() { require.NoError(t, ps.Close()) })
+
+		cache := storage.NewMemCachedStore(ps) // Extra wrapper to avoid good DB corruption.
+		key := make([]byte, 5)
+		key[0] = byte(storage.DataMPTAux)
+		binary.BigEndian.PutUint32(key[1:], corrupted)
+		sr.Root[0] ^= 0xFF
+		w := io.NewBufBinWriter()
+		sr.EncodeBinary(w.BinWriter)
+		require.NoError(t, w.Err)
+		cache.Put(key, w.Bytes())
+
+		bc, _, _, err := chain.NewMultiWithCustomConfigAndStoreNoCheck(t, customConfig, cache)
+		require.NoError(t, err)
+		require.NoError(t, bc.VerifyBlockStateTransition(corrupted-1))
+		err = bc.VerifyBlockStateTransition(corrupted)
+		require.True(t, errors.Is(err, core.ErrStateTransitionMismatch), err)
+		require.Error(t, bc.VerifyBlockStateTransition(corrupted+1))
+	})
+	t.Run("KeepOnlyLatestState", func(t *testing.T) {
+		bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+			c.KeepOnlyLatestState = true
+		})
+		neotest.NewExecutor(t, bc, acc, acc).AddNewBlock(t)
+		require.Error(t, bc.VerifyBlockStateTransition(1))
+	})
+}
+
+func TestBlockchain_ExportImportContract(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package kv
+	import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	func Put(k, v []byte) {
+		storage.Put(storage.GetContext(), k, v)
+	}
+	func Get(k []byte) interface{} {
+		return storage.Get(storage.GetReadOnlyContext(), k)
+	}`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{
+		Name:        "KV",
+		Permissions: []manifest.Permission{*manifest.NewPermission(manifest.PermissionWildcard)},
+	})
+	e.DeployContract(t, c, nil)
+	inv := e.CommitteeInvoker(c.Hash)
+	inv.Invoke(t, stackitem.Null{}, "put", []byte("key1"), []byte("value1"))
+	inv.Invoke(t, stackitem.Null{}, "put", []byte("key2"), []byte("value2"))
+
+	w := new(bytes.Buffer)
+	require.Error(t, bc.ExportContract(util.Uint160{1, 2, 3}, w))
+	require.NoError(t, bc.ExportContract(c.Hash, w))
+	bundle := w.Bytes()
+
+	t.Run("duplicate", func(t *testing.T) {
+		_, err := bc.ImportContract(bytes.NewReader(bundle))
+		require.Error(t, err)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, b := range [][]byte{
+			nil,
+			{1, 2, 3, 4, 0},
+			bundle[:len(bundle)-1],
+		} {
+			_, err := bc.ImportContract(bytes.NewReader(b))
+			require.True(t, errors.Is(err, core.ErrInvalidContractBundle), err)
+		}
+	})
+
+	bc2, acc2 := chain.NewSingle(t)
+	e2 := neotest.NewExecutor(t, bc2, acc2, acc2)
+	cs, err := bc2.ImportContract(bytes.NewReader(bundle))
+	require.NoError(t, err)
+	require.Equal(t, c.Hash, cs.Hash)
+	require.Equal(t, *c.NEF, cs.NEF)
+	require.Equal(t, bc2.GetContractState(c.Hash), cs)
+	id, err := bc2.GetContractScriptHash(cs.ID)
+	require.NoError(t, err)
+	require.Equal(t, c.Hash, id)
+
+	inv2 := e2.CommitteeInvoker(c.Hash)
+	inv2.Invoke(t, []byte("value1"), "get", []byte("key1"))
+	inv2.Invoke(t, []byte("value2"), "get", []byte("key2"))
+	inv2.Invoke(t, stackitem.Null{}, "put", []byte("key1"), []byte("new"))
+	inv2.Invoke(t, []byte("new"), "get", []byte("key1"))
+
+	w = new(bytes.Buffer)
+	require.NoError(t, bc2.ExportContract(c.Hash, w))
+	_, err = bc2.ImportContract(bytes.NewReader(w.Bytes()))
+	require.Error(t, err)
+}
+
+func TestBlockchain_DiffContractStorage(t *testing.T) {
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		c.MaxTraceableBlocks = 10
+		c.RemoveUntraceableBlocks = true
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package kv
+	import "github.com/nspcc-dev/neo-go/pkg/interop/storage"
+	func Put(k, v []byte) {
+		storage.Put(storage.GetContext(), k, v)
+	}
+	func Delete(k []byte) {
+		storage.Delete(storage.GetContext(), k)
+	}`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{
+		Name: "KV",
+	})
+	e.DeployContract(t, c, nil)
+	id := bc.GetContractState(c.Hash).ID
+	inv := e.CommitteeInvoker(c.Hash)
+	h0 := bc.BlockHeight()
+
+	inv.Invoke(t, stackitem.Null{}, "put", []byte("key1"), []byte("value1"))
+	inv.Invoke(t, stackitem.Null{}, "put", []byte("key2"), []byte("value2"))
+	inv.Invoke(t, stackitem.Null{}, "put", []byte("key3"), []byte("value3"))
+	h1 := bc.BlockHeight()
+
+	added, modified, deleted, err := bc.DiffContractStorage(id, h0, h1)
+	require.NoError(t, err)
+	require.Equal(t, []core.StorageKV{
+		{Key: []byte("key1"), Value: []byte("value1")},
+		{Key: []byte("key2"), Value: []byte("value2")},
+		{Key: []byte("key3"), Value: []byte("value3")},
+	}, added)
+	require.Nil(t, modified)
+	require.Nil(t, deleted)
+
+	inv.Invoke(t, stackitem.Null{}, "delete", []byte("key1"))
+	inv.Invoke(t, stackitem.Null{}, "put", []byte("key2"), []byte("new"))
+	inv.Invoke(t, stackitem.Null{}, "put", []byte("key4"), []byte("value4"))
+	h2 := bc.BlockHeight()
+
+	added, modified, deleted, err = bc.DiffContractStorage(id, h1, h2)
+	require.NoError(t, err)
+	require.Equal(t, []core.StorageKV{{Key: []byte("key4"), Value: []byte("value4")}}, added)
+	require.Equal(t, []core.StorageKV{{Key: []byte("key2"), Value: []byte("new"), PrevValue: []byte("value2")}}, modified)
+	require.Equal(t, []core.StorageKV{{Key: []byte("key1"), PrevValue: []byte("value1")}}, deleted)
+
+	// Reversed order.
+	added, modified, deleted, err = bc.DiffContractStorage(id, h2, h1)
+	require.NoError(t, err)
+	require.Equal(t, []core.StorageKV{{Key: []byte("key1"), Value: []byte("value1")}}, added)
+	require.Equal(t, []core.StorageKV{{Key: []byte("key2"), Value: []byte("value2"), PrevValue: []byte("new")}}, modified)
+	require.Equal(t, []core.StorageKV{{Key: []byte("key4"), PrevValue: []byte("value4")}}, deleted)
+
+	added, modified, deleted, err = bc.DiffContractStorage(id, h2, h2)
+	require.NoError(t, err)
+	require.Nil(t, added)
+	require.Nil(t, modified)
+	require.Nil(t, deleted)
+
+	_, _, _, err = bc.DiffContractStorage(id, h1, bc.BlockHeight()+1)
+	require.Error(t, err)
+
+	e.GenerateNewBlocks(t, int(h1+10-bc.BlockHeight()))
+	_, _, _, err = bc.DiffContractStorage(id, h1, h2)
+	require.Error(t, err)
+	_, _, _, err = bc.DiffContractStorage(id, h2, bc.BlockHeight())
+	require.NoError(t, err)
+}
+
+func TestBlockchain_PreflightDeploy(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package foo
+	func Main() int {
+		return 42
+	}`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "Foo"})
+	nefBytes, err := c.NEF.Bytes()
+	require.NoError(t, err)
+	manifBytes, err := json.Marshal(c.Manifest)
+	require.NoError(t, err)
+
+	require.NoError(t, bc.PreflightDeploy(acc.ScriptHash(), nefBytes, manifBytes))
+
+	check := func(t *testing.T, nefBytes []byte, m *manifest.Manifest, errText string) {
+		manifBytes, err := json.Marshal(m)
+		require.NoError(t, err)
+		err = bc.PreflightDeploy(acc.ScriptHash(), nefBytes, manifBytes)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), errText)
+	}
+	t.Run("invalid NEF", func(t *testing.T) {
+		check(t, nefBytes[:len(nefBytes)-1], c.Manifest, "invalid NEF file")
+	})
+	t.Run("invalid manifest", func(t *testing.T) {
+		require.Error(t, bc.PreflightDeploy(acc.ScriptHash(), nefBytes, []byte("{")))
+		require.Error(t, bc.PreflightDeploy(acc.ScriptHash(), nefBytes, []byte{0xff, 0xfe}))
+		require.Error(t, bc.PreflightDeploy(acc.ScriptHash(), nefBytes, make([]byte, manifest.MaxManifestSize+1)))
+
+		m := *c.Manifest
+		m.ABI.Methods = append(m.ABI.Methods, m.ABI.Methods[0])
+		check(t, nefBytes, &m, "duplicate method")
+
+		m = *c.Manifest
+		m.Permissions = []manifest.Permission{*manifest.NewPermission(manifest.PermissionWildcard), *manifest.NewPermission(manifest.PermissionWildcard)}
+		check(t, nefBytes, &m, "invalid manifest")
+
+		m = *c.Manifest
+		m.ABI.Methods = []manifest.Method{m.ABI.Methods[0]}
+		m.ABI.Methods[0].Offset = len(c.NEF.Script)
+		check(t, nefBytes, &m, "out of bounds method offset")
+	})
+
+	e.DeployContract(t, c, nil)
+	err = bc.PreflightDeploy(acc.ScriptHash(), nefBytes, manifBytes)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "contract already exists")
+	// Another sender can deploy the same contract.
+	require.NoError(t, bc.PreflightDeploy(util.Uint160{1, 2, 3}, nefBytes, manifBytes))
+}
+
+func TestBlockchain_VerifyTxs(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasInvoker := e.ValidatorInvoker(e.NativeHash(t, nativenames.Gas))
+	policyInvoker := e.CommitteeInvoker(e.NativeHash(t, nativenames.Policy))
+
+	blocked := util.Uint160{1, 2, 3}
+	policyInvoker.Invoke(t, true, "blockAccount", blocked)
+	onChainTx := e.PrepareInvocation(t, []byte{byte(opcode.PUSH1)}, []neotest.Signer{acc}, bc.BlockHeight()+10)
+	e.AddNewBlock(t, onChainTx)
+
+	newTx := func() *transaction.Transaction {
+		return gasInvoker.PrepareInvoke(t, "transfer", acc.ScriptHash(), util.Uint160{3, 2, 1}, 1, nil)
+	}
+	valid := newTx()
+	expired := newTx()
+	expired.ValidUntilBlock = bc.BlockHeight()
+	blockedTx := newTx()
+	blockedTx.Signers = []transaction.Signer{{Account: blocked}}
+	smallFee := newTx()
+	smallFee.NetworkFee = 0
+	badScript := newTx()
+	badScript.Script = []byte{0xff}
+
+	txes := []*transaction.Transaction{valid, expired, blockedTx, smallFee, badScript, onChainTx, valid}
+	expected := []error{nil, core.ErrTxExpired, core.ErrPolicy, core.ErrTxSmallNetworkFee,
+		core.ErrInvalidScript, core.ErrAlreadyExists, nil}
+	errs := bc.VerifyTxs(txes)
+	require.Equal(t, len(txes), len(errs))
+	for i := range txes {
+		if expected[i] == nil {
+			require.NoError(t, errs[i], i)
+		} else {
+			require.True(t, errors.Is(errs[i], expected[i]), "%d: expected %v, got %v", i, expected[i], errs[i])
+		}
+		single := bc.VerifyTx(txes[i])
+		if errs[i] == nil {
+			require.NoError(t, single, i)
+		} else {
+			require.Equal(t, single.Error(), errs[i].Error(), i)
+		}
+	}
+	require.Empty(t, bc.VerifyTxs(nil))
+	require.Equal(t, 0, bc.GetMemPool().Count())
+}
+
+func TestBlockchain_TxRebroadcast(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	var (
+		lock      sync.Mutex
+		announced = make(map[util.Uint256]int)
+	)
+	announcedCount := func(h util.Uint256) int {
+		lock.Lock()
+		defer lock.Unlock()
+		return announced[h]
+	}
+	checkAnnounced := func(t *testing.T, h util.Uint256, n int) {
+		require.Eventually(t, func() bool { return announcedCount(h) == n }, time.Second, 10*time.Millisecond)
+	}
+	newTx := func(vub uint32) *transaction.Transaction {
+		return e.PrepareInvocation(t, []byte{byte(opcode.PUSH1)}, []neotest.Signer{acc}, vub)
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		tx := newTx(bc.BlockHeight() + 10)
+		require.NoError(t, bc.PoolTxWithSource(tx, mempool.TxSourceLocal))
+		_, ok := bc.GetTxRebroadcastStatus(tx.Hash())
+		require.False(t, ok)
+		e.AddNewBlock(t, tx)
+	})
+
+	const (
+		threshold  = 2
+		maxRetries = 3
+	)
+	bc.SetTxRebroadcast(threshold, maxRetries, func(tx *transaction.Transaction) {
+		lock.Lock()
+		announced[tx.Hash()]++
+		lock.Unlock()
+	})
+
+	h0 := bc.BlockHeight()
+	local := newTx(h0 + 30)
+	remote := newTx(h0 + 30)
+	confirmed := newTx(h0 + 30)
+	expired := newTx(h0 + 3)
+	dropped := newTx(h0 + 30)
+	for _, tx := range []*transaction.Transaction{local, confirmed, expired, dropped} {
+		require.NoError(t, bc.PoolTxWithSource(tx, mempool.TxSourceLocal))
+	}
+	require.NoError(t, bc.PoolTx(remote))
+
+	checkStatus := func(t *testing.T, h util.Uint256, state core.TxRebroadcastState, retries int) core.TxRebroadcastStatus {
+		st, ok := bc.GetTxRebroadcastStatus(h)
+		require.True(t, ok)
+		require.Equal(t, state, st.State, st.State.String())
+		require.Equal(t, retries, st.Retries)
+		require.Equal(t, h0, st.PooledAt)
+		return st
+	}
+	st := checkStatus(t, local.Hash(), core.TxRebroadcastPending, 0)
+	require.Equal(t, h0+threshold, st.NextAt)
+	_, ok := bc.GetTxRebroadcastStatus(remote.Hash())
+	require.False(t, ok)
+
+	// Block h0+1: inclusion and removal from the mempool cancel tracking.
+	bc.GetMemPool().Remove(dropped.Hash(), bc)
+	e.AddNewBlock(t, confirmed)
+	checkStatus(t, confirmed.Hash(), core.TxRebroadcastConfirmed, 0)
+	checkStatus(t, dropped.Hash(), core.TxRebroadcastDropped, 0)
+	checkStatus(t, local.Hash(), core.TxRebroadcastPending, 0)
+
+	// Block h0+2: the first rebroadcast, the next one is scheduled after
+	// twice the threshold.
+	e.AddNewBlock(t)
+	checkAnnounced(t, local.Hash(), 1)
+	checkAnnounced(t, expired.Hash(), 1)
+	st = checkStatus(t, local.Hash(), core.TxRebroadcastPending, 1)
+	require.Equal(t, h0+2+2*threshold, st.NextAt)
+
+	// Block h0+3: expired transaction is removed from the mempool.
+	e.AddNewBlock(t)
+	checkStatus(t, expired.Hash(), core.TxRebroadcastExpired, 1)
+
+	// Block h0+4: expired status is dropped after its ValidUntilBlock.
+	e.AddNewBlock(t)
+	_, ok = bc.GetTxRebroadcastStatus(expired.Hash())
+	require.False(t, ok)
+
+	for bc.BlockHeight() < h0+5 {
+		e.AddNewBlock(t)
+	}
+	require.Equal(t, 1, announcedCount(local.Hash()))
+	e.AddNewBlock(t) // h0+6
+	checkAnnounced(t, local.Hash(), 2)
+	st = checkStatus(t, local.Hash(), core.TxRebroadcastPending, 2)
+	require.Equal(t, h0+6+4*threshold, st.NextAt)
+
+	for bc.BlockHeight() < h0+13 {
+		e.AddNewBlock(t)
+	}
+	require.Equal(t, 2, announcedCount(local.Hash()))
+	e.AddNewBlock(t) // h0+14
+	checkAnnounced(t, local.Hash(), 3)
+	checkStatus(t, local.Hash(), core.TxRebroadcastExhausted, 3)
+
+	// No more rebroadcasts, but inclusion is still tracked.
+	for bc.BlockHeight() < h0+25 {
+		e.AddNewBlock(t)
+	}
+	e.AddNewBlock(t, local)
+	checkStatus(t, local.Hash(), core.TxRebroadcastConfirmed, 3)
+	require.Equal(t, 3, announcedCount(local.Hash()))
+	require.Equal(t, 0, announcedCount(remote.Hash()))
+	require.Equal(t, 0, announcedCount(confirmed.Hash()))
+	require.Equal(t, 0, announcedCount(dropped.Hash()))
+
+	// Disabling drops all statuses.
+	bc.SetTxRebroadcast(0, 0, nil)
+	_, ok = bc.GetTxRebroadcastStatus(local.Hash())
+	require.False(t, ok)
+}
+
+func TestBlockchain_HeaderCommitment(t *testing.T) {
+	commitment := func(index uint32) util.Uint256 {
+		return hash.Sha256([]byte{byte(index)})
+	}
+	bc, acc := chain.NewSingleWithCustomConfigAndStore(t, func(c *config.ProtocolConfiguration) {
+		c.HeaderCommitment = true
+	}, nil, false)
+	bc.SetHeaderCommitmentProvider(commitment)
+	go bc.Run()
+	t.Cleanup(bc.Close)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	b := e.AddNewBlock(t)
+	require.True(t, b.CommitmentEnabled)
+	require.Equal(t, commitment(1), b.Commitment)
+	require.Equal(t, commitment(1), bc.GetHeaderCommitment(1))
+
+	t.Run("GetHeader", func(t *testing.T) {
+		h, err := bc.GetHeader(b.Hash())
+		require.NoError(t, err)
+		require.True(t, h.CommitmentEnabled)
+		require.Equal(t, commitment(1), h.Commitment)
+
+		genesis, err := bc.GetHeader(bc.GetHeaderHash(0))
+		require.NoError(t, err)
+		require.True(t, genesis.CommitmentEnabled)
+		require.Equal(t, util.Uint256{}, genesis.Commitment)
+	})
+
+	t.Run("serialization", func(t *testing.T) {
+		data, err := testserdes.EncodeBinary(&b.Header)
+		require.NoError(t, err)
+		actual := &block.Header{CommitmentEnabled: true}
+		require.NoError(t, testserdes.DecodeBinary(data, actual))
+		require.Equal(t, b.Hash(), actual.Hash())
+		require.Equal(t, b.Commitment, actual.Commitment)
+
+		require.Error(t, testserdes.DecodeBinary(data, new(block.Header)))
+
+		actual = &block.Header{CommitmentEnabled: true}
+		testserdes.MarshalUnmarshalJSON(t, &b.Header, actual)
+	})
+
+	t.Run("hash", func(t *testing.T) {
+		bcOff, _ := chain.NewSingle(t)
+		require.NotEqual(t, bcOff.GetHeaderHash(0), bc.GetHeaderHash(0))
+
+		h := b.Header
+		h.Commitment = util.Uint256{1, 2, 3}
+		data, err := testserdes.EncodeBinary(&h)
+		require.NoError(t, err)
+		actual := &block.Header{CommitmentEnabled: true}
+		require.NoError(t, testserdes.DecodeBinary(data, actual))
+		require.NotEqual(t, b.Hash(), actual.Hash())
+	})
+
+	t.Run("AddHeaders", func(t *testing.T) {
+		bad := e.NewUnsignedBlock(t)
+		bad.CommitmentEnabled = false
+		e.SignBlock(bad)
+		require.ErrorIs(t, bc.AddHeaders(&bad.Header), core.ErrHdrCommitmentSetting)
+		require.ErrorIs(t, bc.AddBlock(bad), core.ErrHdrCommitmentSetting)
+
+		good := e.NewUnsignedBlock(t)
+		e.SignBlock(good)
+		require.NoError(t, bc.AddHeaders(&good.Header))
+		require.NoError(t, bc.AddBlock(good))
+	})
+
+	t.Run("Ledger", func(t *testing.T) {
+		ledger := e.CommitteeInvoker(e.NativeHash(t, nativenames.Ledger))
+		ledger.Invoke(t, commitment(1).BytesBE(), "getHeaderCommitment", 1)
+		ledger.Invoke(t, commitment(1).BytesBE(), "getHeaderCommitment", b.Hash())
+		ledger.Invoke(t, stackitem.Null{}, "getHeaderCommitment", random.Uint256())
+
+		bcOff, acc := chain.NewSingle(t)
+		eOff := neotest.NewExecutor(t, bcOff, acc, acc)
+		ledgerOff := eOff.CommitteeInvoker(eOff.NativeHash(t, nativenames.Ledger))
+		ledgerOff.InvokeFail(t, "method not found: getHeaderCommitment/1", "getHeaderCommitment", 1)
+	})
+}
+
+func TestBlockchain_TraceTransaction(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	gasInvoker := e.CommitteeInvoker(e.NativeHash(t, nativenames.Gas))
+	to := random.Uint160()
+
+	transferTx := gasInvoker.PrepareInvoke(t, "transfer", acc.ScriptHash(), to, 1000, nil)
+	balanceTx := gasInvoker.PrepareInvoke(t, "balanceOf", to)
+	faultTx := e.PrepareInvocation(t, []byte{byte(opcode.PUSH1), byte(opcode.ABORT)}, []neotest.Signer{acc})
+	e.AddNewBlock(t, transferTx, balanceTx, faultTx)
+	e.CheckHalt(t, balanceTx.Hash(), stackitem.Make(1000))
+	e.CheckFault(t, faultTx.Hash(), "ABORT")
+
+	height := bc.BlockHeight()
+	root, err := bc.GetStateModule().GetStateRoot(height)
+	require.NoError(t, err)
+
+	t.Run("HALT", func(t *testing.T) {
+		trace, err := bc.TraceTransaction(balanceTx.Hash())
+		require.NoError(t, err)
+		aer := e.GetTxExecResult(t, balanceTx.Hash())
+		require.Equal(t, balanceTx.Hash(), trace.Container)
+		require.Equal(t, height, trace.Block)
+		require.Equal(t, vm.HaltState, trace.VMState)
+		require.Equal(t, aer.GasConsumed, trace.GasConsumed)
+		require.Empty(t, trace.FaultException)
+
+		require.True(t, len(trace.Steps) > 0)
+		var gas int64
+		for _, s := range trace.Steps {
+			gas += s.GasConsumed
+		}
+		require.Equal(t, trace.GasConsumed, gas)
+		first, last := trace.Steps[0], trace.Steps[len(trace.Steps)-1]
+		require.Equal(t, hash.Hash160(balanceTx.Script), first.ScriptHash)
+		require.Equal(t, 0, first.IP)
+		require.Equal(t, opcode.Opcode(balanceTx.Script[0]), first.Opcode)
+		require.Equal(t, opcode.RET, last.Opcode)
+		require.Equal(t, aer.Stack, last.Stack)
+	})
+	t.Run("FAULT", func(t *testing.T) {
+		trace, err := bc.TraceTransaction(faultTx.Hash())
+		require.NoError(t, err)
+		require.Equal(t, vm.FaultState, trace.VMState)
+		require.True(t, strings.Contains(trace.FaultException, "ABORT"), trace.FaultException)
+		require.Equal(t, 2, len(trace.Steps))
+		require.Equal(t, opcode.PUSH1, trace.Steps[0].Opcode)
+		require.Equal(t, []stackitem.Item{stackitem.Make(1)}, trace.Steps[0].Stack)
+		require.Equal(t, opcode.ABORT, trace.Steps[1].Opcode)
+		require.Equal(t, 1, trace.Steps[1].IP)
+	})
+	t.Run("unknown transaction", func(t *testing.T) {
+		_, err := bc.TraceTransaction(random.Uint256())
+		require.Error(t, err)
+	})
+
+	// Nothing is changed by tracing.
+	require.Equal(t, height, bc.BlockHeight())
+	actual, err := bc.GetStateModule().GetStateRoot(height)
+	require.NoError(t, err)
+	require.Equal(t, root, actual)
+	e.CheckGASBalance(t, to, big.NewInt(1000))
+}
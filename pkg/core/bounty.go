@@ -0,0 +1,61 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+)
+
+// BountyEntry is the committee GAS bounty paid in some block returned from
+// GetBountySchedule.
+type BountyEntry struct {
+	// Index is the block index.
+	Index uint32
+	// Recipient is the committee member that received the bounty.
+	Recipient *keys.PublicKey
+	// Amount is the amount of GAS minted to the recipient.
+	Amount *big.Int
+}
+
+// GetBountySchedule returns committee GAS bounties paid in the blocks from
+// `from` to `to` (inclusive). The bounty of every block goes to committee
+// members in a round-robin fashion, the committee of every height is taken
+// from the MPT state of this height, so the state must be available for the
+// whole range (it's not with KeepOnlyLatestState and it can be removed with
+// RemoveUntraceableBlocks).
+func (bc *Blockchain) GetBountySchedule(from, to uint32) ([]BountyEntry, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid range: %d > %d", from, to)
+	}
+	if height := bc.BlockHeight(); to > height {
+		return nil, fmt.Errorf("block %d is not yet processed, current height is %d", to, height)
+	}
+	var (
+		res       = make([]BountyEntry, 0, to-from+1)
+		committee keys.PublicKeys
+	)
+	for i := from; ; i++ {
+		// Committee is only changed in NEO OnPersist, so there is no need
+		// to retrieve it for every block.
+		if committee == nil || bc.config.ShouldUpdateCommitteeAt(i) {
+			d, err := bc.getHistoricDAO(i)
+			if err != nil {
+				return nil, err
+			}
+			committee, err = bc.contracts.NEO.GetStoredCommittee(d)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get committee for height %d: %w", i, err)
+			}
+		}
+		res = append(res, BountyEntry{
+			Index:     i,
+			Recipient: committee[int(i)%bc.config.GetCommitteeSize(i)],
+			Amount:    bc.contracts.NEO.GetCommitteeBounty(bc.dao, i),
+		})
+		if i == to {
+			break
+		}
+	}
+	return res, nil
+}
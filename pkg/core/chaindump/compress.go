@@ -0,0 +1,92 @@
+package chaindump
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	gio "io"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+)
+
+// compressedMagic is the prefix of compressed dumps, it's followed by the
+// format version byte and gzip stream with the raw dump data. It can't be
+// confused with the beginning of raw dumps in practice, since raw dumps
+// start with a little-endian block count (or index) and these four bytes
+// correspond to more than 1.5 billion blocks.
+var compressedMagic = []byte("NGDZ")
+
+// compressedVersion is the current compressed dump format version.
+const compressedVersion = 1
+
+// NewCompressedWriter writes compressed dump header to w and returns a writer
+// that compresses everything written to it with the given gzip level (see
+// compress/gzip constants). Clients can write their own header to it before
+// calling Dump. It must be closed to flush all the data, w is not closed by
+// it.
+func NewCompressedWriter(w gio.Writer, level int) (gio.WriteCloser, error) {
+	zw, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return nil, err
+	}
+	hdr := make([]byte, 0, len(compressedMagic)+1)
+	hdr = append(hdr, compressedMagic...)
+	hdr = append(hdr, compressedVersion)
+	_, err = w.Write(hdr)
+	if err != nil {
+		return nil, err
+	}
+	return zw, nil
+}
+
+// NewReader returns a reader of the raw dump data from r. Compressed dumps
+// (see NewCompressedWriter) are detected and decompressed transparently, raw
+// dumps are read as is.
+func NewReader(r gio.Reader) (gio.Reader, error) {
+	br := bufio.NewReader(r)
+	hdr, err := br.Peek(len(compressedMagic) + 1)
+	if err != nil && !errors.Is(err, gio.EOF) {
+		return nil, err
+	}
+	if len(hdr) <= len(compressedMagic) || !bytes.Equal(hdr[:len(compressedMagic)], compressedMagic) {
+		return br, nil
+	}
+	if v := hdr[len(compressedMagic)]; v != compressedVersion {
+		return nil, fmt.Errorf("unsupported compressed dump version %d", v)
+	}
+	_, _ = br.Discard(len(hdr)) // Can't fail, the data is buffered.
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return nil, fmt.Errorf("invalid compressed dump: %w", err)
+	}
+	return zr, nil
+}
+
+// DumpCompressed is similar to Dump, but it writes compressed dump to the
+// provided writer using the given gzip level (see compress/gzip constants).
+// The result can be restored with RestoreCompressed.
+func DumpCompressed(bc DumperRestorer, w gio.Writer, start, count uint32, level int) error {
+	zw, err := NewCompressedWriter(w, level)
+	if err != nil {
+		return err
+	}
+	err = Dump(bc, io.NewBinWriterFromIO(zw), start, count)
+	if err != nil {
+		_ = zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+// RestoreCompressed is similar to Restore, but it accepts both compressed
+// (see DumpCompressed) and raw dumps detecting the format automatically.
+func RestoreCompressed(bc DumperRestorer, r gio.Reader, skip, count uint32, f func(b *block.Block) error) error {
+	dr, err := NewReader(r)
+	if err != nil {
+		return err
+	}
+	return Restore(bc, io.NewBinReaderFromIO(dr), skip, count, f)
+}
@@ -2,6 +2,7 @@ package chaindump
 
 import (
 	"fmt"
+	gio "io"
 
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/block"
@@ -38,9 +39,52 @@ func Dump(bc DumperRestorer, w *io.BinWriter, start, count uint32) error {
 	return nil
 }
 
+// Progress describes the state of Restore operation.
+type Progress struct {
+	// Offset is the number of bytes read from the source reader by
+	// RestoreWithProgress so far (including skipped blocks), it's the
+	// position of the next block in the stream relative to the initial
+	// reader position.
+	Offset uint64
+	// Count is the number of blocks processed so far (not including skipped
+	// ones).
+	Count uint32
+}
+
+// countingReader counts bytes read from the underlying reader.
+type countingReader struct {
+	r gio.Reader
+	n uint64
+}
+
+// Read implements io.Reader interface.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += uint64(n)
+	return n, err
+}
+
 // Restore restores blocks from provided reader.
 // f is called after addition of every block.
 func Restore(bc DumperRestorer, r *io.BinReader, skip, count uint32, f func(b *block.Block) error) error {
+	var pf func(*block.Block, Progress) error
+	if f != nil {
+		pf = func(b *block.Block, _ Progress) error {
+			return f(b)
+		}
+	}
+	return restore(bc, r, skip, count, nil, pf)
+}
+
+// RestoreWithProgress is similar to Restore, but f also receives the current
+// restore progress, which allows to estimate the remaining time. f is called
+// after addition of every block, restore stops if it returns an error.
+func RestoreWithProgress(bc DumperRestorer, r gio.Reader, skip, count uint32, f func(b *block.Block, p Progress) error) error {
+	cr := &countingReader{r: r}
+	return restore(bc, io.NewBinReaderFromIO(cr), skip, count, cr, f)
+}
+
+func restore(bc DumperRestorer, r *io.BinReader, skip, count uint32, cr *countingReader, f func(b *block.Block, p Progress) error) error {
 	readBlock := func(r *io.BinReader) ([]byte, error) {
 		var size = r.ReadU32LE()
 		buf := make([]byte, size)
@@ -56,14 +100,15 @@ func Restore(bc DumperRestorer, r *io.BinReader, skip, count uint32, f func(b *b
 		}
 	}
 
-	stateRootInHeader := bc.GetConfig().StateRootInHeader
+	cfg := bc.GetConfig()
 
 	for ; i < skip+count; i++ {
 		buf, err := readBlock(r)
 		if err != nil {
 			return err
 		}
-		b := block.New(stateRootInHeader)
+		b := block.New(cfg.StateRootInHeader)
+		b.CommitmentEnabled = cfg.HeaderCommitment
 		r := io.NewBinReaderFromBuf(buf)
 		b.DecodeBinary(r)
 		if r.Err != nil {
@@ -76,7 +121,11 @@ func Restore(bc DumperRestorer, r *io.BinReader, skip, count uint32, f func(b *b
 			}
 		}
 		if f != nil {
-			if err := f(b); err != nil {
+			p := Progress{Count: i - skip + 1}
+			if cr != nil {
+				p.Offset = cr.n
+			}
+			if err := f(b, p); err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	gio "io"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/util/slice"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"go.uber.org/zap"
+)
+
+const (
+	// contractBundleMagic is the first field of every contract bundle
+	// ("NCTB" in little-endian).
+	contractBundleMagic uint32 = 0x4254434e
+	// contractBundleVersion is the current contract bundle format version.
+	contractBundleVersion byte = 0
+)
+
+// ErrInvalidContractBundle is returned from ImportContract when the data
+// provided is not a valid contract bundle.
+var ErrInvalidContractBundle = errors.New("invalid contract bundle")
+
+// ExportContract writes the state of the deployed contract with the given hash
+// (NEF, manifest and all of its storage items) to w as a contract bundle that
+// can be imported into another chain via ImportContract. The bundle has the
+// following format (all fields are serialized the same way io.BinWriter does):
+//
+//	magic          uint32, 0x4254434e
+//	version        byte, 0
+//	hash           Uint160, contract hash
+//	update counter uint16
+//	NEF            var bytes, serialized NEF file
+//	manifest       var bytes, JSON-serialized manifest
+//	storage        var uint number of items followed by key/value pairs
+//	               (both are var bytes)
+//
+// Contract ID is not a part of the bundle since it's chain-specific.
+func (bc *Blockchain) ExportContract(h util.Uint160, w gio.Writer) error {
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+
+	cs, err := bc.contracts.Management.GetContract(bc.dao, h)
+	if err != nil {
+		return fmt.Errorf("failed to get contract %s: %w", h.StringLE(), err)
+	}
+	nefBytes, err := cs.NEF.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to serialize NEF: %w", err)
+	}
+	manifBytes, err := json.Marshal(cs.Manifest)
+	if err != nil {
+		return fmt.Errorf("failed to serialize manifest: %w", err)
+	}
+	var items []storage.KeyValue
+	bc.dao.Seek(cs.ID, storage.SeekRange{}, func(k, v []byte) bool {
+		items = append(items, storage.KeyValue{
+			Key:   slice.Copy(k),
+			Value: slice.Copy(v),
+		})
+		return true
+	})
+
+	bw := io.NewBinWriterFromIO(w)
+	bw.WriteU32LE(contractBundleMagic)
+	bw.WriteB(contractBundleVersion)
+	cs.Hash.EncodeBinary(bw)
+	bw.WriteU16LE(cs.UpdateCounter)
+	bw.WriteVarBytes(nefBytes)
+	bw.WriteVarBytes(manifBytes)
+	bw.WriteVarUint(uint64(len(items)))
+	for i := range items {
+		bw.WriteVarBytes(items[i].Key)
+		bw.WriteVarBytes(items[i].Value)
+	}
+	return bw.Err
+}
+
+// ImportContract reads contract bundle created by ExportContract from r and
+// deploys the contract with all of its storage items into the chain using the
+// next available contract ID. The contract keeps its original hash, so it
+// fails if there is a contract with the same hash already. Imported contract
+// is not a part of any block or transaction, it's put directly into the
+// current chain state (without _deploy method call and without
+// notifications), it's not reflected in the current state root and can't be
+// reproduced by other nodes, so this method is only suitable for private
+// networks (development and testing). It returns the imported contract state.
+func (bc *Blockchain) ImportContract(r gio.Reader) (*state.Contract, error) {
+	if bc.readOnly {
+		return nil, ErrReadOnlyMode
+	}
+	br := io.NewBinReaderFromIO(r)
+	magic := br.ReadU32LE()
+	version := br.ReadB()
+	if br.Err == nil {
+		if magic != contractBundleMagic {
+			return nil, fmt.Errorf("%w: bad magic %x", ErrInvalidContractBundle, magic)
+		}
+		if version != contractBundleVersion {
+			return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidContractBundle, version)
+		}
+	}
+	var hash util.Uint160
+	hash.DecodeBinary(br)
+	updateCounter := br.ReadU16LE()
+	nefBytes := br.ReadVarBytes(stackitem.MaxSize)
+	manifBytes := br.ReadVarBytes(manifest.MaxManifestSize)
+	n := br.ReadVarUint()
+	var items []storage.KeyValue
+	for i := uint64(0); i < n && br.Err == nil; i++ {
+		k := br.ReadVarBytes(storage.MaxStorageKeyLen)
+		v := br.ReadVarBytes(storage.MaxStorageValueLen)
+		items = append(items, storage.KeyValue{Key: k, Value: v})
+	}
+	if br.Err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidContractBundle, br.Err)
+	}
+	neff, err := nef.FileFromBytes(nefBytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid NEF: %v", ErrInvalidContractBundle, err)
+	}
+	manif := new(manifest.Manifest)
+	if err := json.Unmarshal(manifBytes, manif); err != nil {
+		return nil, fmt.Errorf("%w: invalid manifest: %v", ErrInvalidContractBundle, err)
+	}
+	if err := manif.IsValid(hash); err != nil {
+		return nil, fmt.Errorf("%w: invalid manifest: %v", ErrInvalidContractBundle, err)
+	}
+
+	bc.addLock.Lock()
+	defer bc.addLock.Unlock()
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	if _, err := bc.contracts.Management.GetContract(bc.dao, hash); err == nil {
+		return nil, fmt.Errorf("contract %s already exists", hash.StringLE())
+	}
+	cache := bc.dao.GetPrivate()
+	id, err := bc.contracts.Management.GetNextContractID(cache)
+	if err != nil {
+		return nil, err
+	}
+	cs := &state.Contract{
+		ContractBase: state.ContractBase{
+			ID:       id,
+			Hash:     hash,
+			NEF:      neff,
+			Manifest: *manif,
+		},
+		UpdateCounter: updateCounter,
+	}
+	if err := bc.contracts.Management.PutContractState(cache, cs); err != nil {
+		return nil, fmt.Errorf("failed to store contract state: %w", err)
+	}
+	if updateCounter != 0 {
+		cache.PutContractID(id, hash) // PutContractState only does it for new contracts.
+	}
+	for i := range items {
+		cache.PutStorageItem(id, items[i].Key, items[i].Value)
+	}
+	if _, err := cache.Persist(); err != nil {
+		return nil, fmt.Errorf("failed to persist imported contract: %w", err)
+	}
+	bc.log.Info("contract imported",
+		zap.Stringer("hash", hash),
+		zap.Int32("id", id),
+		zap.Int("storage items", len(items)))
+	return cs, nil
+}
@@ -267,6 +267,36 @@ func (dao *Simple) PutTokenTransferLog(acc util.Uint160, start uint64, index uin
 
 // -- end transfer log.
 
+// -- start supply delta.
+
+func (dao *Simple) makeSupplyDeltaKey(id int32, index uint32) []byte {
+	key := dao.getKeyBuf(1 + 4 + 4)
+	key[0] = byte(storage.STSupplyDelta)
+	binary.BigEndian.PutUint32(key[1:], uint32(id))
+	binary.BigEndian.PutUint32(key[5:], index)
+	return key
+}
+
+// GetSupplyDelta returns total supply change of the native token with the
+// given ID made in the block with the given index. storage.ErrKeyNotFound is
+// returned if there is no such record.
+func (dao *Simple) GetSupplyDelta(id int32, index uint32) (*state.TokenSupplyDelta, error) {
+	d := new(state.TokenSupplyDelta)
+	err := dao.GetAndDecode(d, dao.makeSupplyDeltaKey(id, index))
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// PutSupplyDelta saves total supply change of the native token with the given
+// ID made in the block with the given index.
+func (dao *Simple) PutSupplyDelta(id int32, index uint32, d *state.TokenSupplyDelta) error {
+	return dao.putWithBuffer(d, dao.makeSupplyDeltaKey(id, index), dao.getDataBuf())
+}
+
+// -- end supply delta.
+
 // -- start notification event.
 
 func (dao *Simple) makeExecutableKey(hash util.Uint256) []byte {
@@ -291,7 +321,7 @@ func (dao *Simple) GetAppExecResults(hash util.Uint256, trig trigger.Type) ([]st
 	case storage.ExecBlock:
 		r := io.NewBinReaderFromBuf(bs)
 		_ = r.ReadB()
-		_, err = block.NewTrimmedFromReader(dao.Version.StateRootInHeader, r)
+		err = dao.newBlock().DecodeTrimmed(r)
 		if err != nil {
 			return nil, err
 		}
@@ -439,13 +469,21 @@ func (dao *Simple) getBlock(key []byte) (*block.Block, error) {
 		// It may be a transaction.
 		return nil, storage.ErrKeyNotFound
 	}
-	block, err := block.NewTrimmedFromReader(dao.Version.StateRootInHeader, r)
-	if err != nil {
+	block := dao.newBlock()
+	if err := block.DecodeTrimmed(r); err != nil {
 		return nil, err
 	}
 	return block, nil
 }
 
+// newBlock returns a new blank block with header settings matching the DAO
+// version.
+func (dao *Simple) newBlock() *block.Block {
+	b := block.New(dao.Version.StateRootInHeader)
+	b.CommitmentEnabled = dao.Version.HeaderCommitment
+	return b
+}
+
 // Version represents current dao version.
 type Version struct {
 	StoragePrefix              storage.KeyPrefix
@@ -453,6 +491,7 @@ type Version struct {
 	P2PSigExtensions           bool
 	P2PStateExchangeExtensions bool
 	KeepOnlyLatestState        bool
+	HeaderCommitment           bool
 	Value                      string
 }
 
@@ -461,6 +500,7 @@ const (
 	p2pSigExtensionsBit
 	p2pStateExchangeExtensionsBit
 	keepOnlyLatestStateBit
+	headerCommitmentBit
 )
 
 // FromBytes decodes v from a byte-slice.
@@ -487,6 +527,7 @@ func (v *Version) FromBytes(data []byte) error {
 	v.P2PSigExtensions = data[i+2]&p2pSigExtensionsBit != 0
 	v.P2PStateExchangeExtensions = data[i+2]&p2pStateExchangeExtensionsBit != 0
 	v.KeepOnlyLatestState = data[i+2]&keepOnlyLatestStateBit != 0
+	v.HeaderCommitment = data[i+2]&headerCommitmentBit != 0
 	return nil
 }
 
@@ -505,6 +546,9 @@ func (v *Version) Bytes() []byte {
 	if v.KeepOnlyLatestState {
 		mask |= keepOnlyLatestStateBit
 	}
+	if v.HeaderCommitment {
+		mask |= headerCommitmentBit
+	}
 	return append([]byte(v.Value), '\x00', byte(v.StoragePrefix), mask)
 }
 
@@ -568,6 +612,15 @@ func (dao *Simple) GetStateSyncCurrentBlockHeight() (uint32, error) {
 	return binary.LittleEndian.Uint32(b), nil
 }
 
+// GetColdStorageHeight returns the number of blocks moved to the cold storage.
+func (dao *Simple) GetColdStorageHeight() (uint32, error) {
+	b, err := dao.Store.Get(dao.mkKeyPrefix(storage.SYSColdStorageHeight))
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
 // GetHeaderHashes returns a sorted list of header hashes retrieved from
 // the given underlying store.
 func (dao *Simple) GetHeaderHashes() ([]util.Uint256, error) {
@@ -649,6 +702,13 @@ func (dao *Simple) PutStateSyncCurrentBlockHeight(h uint32) {
 	dao.Store.Put(dao.mkKeyPrefix(storage.SYSStateSyncCurrentBlockHeight), buf.Bytes())
 }
 
+// PutColdStorageHeight stores the number of blocks moved to the cold storage.
+func (dao *Simple) PutColdStorageHeight(h uint32) {
+	buf := dao.getDataBuf()
+	buf.WriteU32LE(h)
+	dao.Store.Put(dao.mkKeyPrefix(storage.SYSColdStorageHeight), buf.Bytes())
+}
+
 // read2000Uint256Hashes attempts to read 2000 Uint256 hashes from
 // the given byte array.
 func read2000Uint256Hashes(b []byte) ([]util.Uint256, error) {
@@ -752,6 +812,39 @@ func (dao *Simple) DeleteBlock(h util.Uint256) error {
 	return nil
 }
 
+// ArchiveBlock copies block with the given hash along with its transactions
+// and execution results into the given DAO and leaves only block header in the
+// current one (the same way DeleteBlock does). Conflict records are not moved.
+// It's not atomic, so make sure you're using wrapped MemCached instances here.
+func (dao *Simple) ArchiveBlock(to *Simple, h util.Uint256) error {
+	key := dao.makeExecutableKey(h)
+
+	raw, err := dao.Store.Get(key)
+	if err != nil {
+		return err
+	}
+	b, err := dao.getBlock(key)
+	if err != nil {
+		return err
+	}
+	if !b.MerkleRoot.Equals(util.Uint256{}) && len(b.Transactions) == 0 {
+		return fmt.Errorf("%w: block %s is already archived or removed", ErrInternalDBInconsistency, h.StringLE())
+	}
+	to.Store.Put(key, raw)
+
+	for _, tx := range b.Transactions {
+		txKey := dao.makeExecutableKey(tx.Hash())
+		raw, err = dao.Store.Get(txKey)
+		if err != nil {
+			return fmt.Errorf("failed to get transaction %s: %w", tx.Hash().StringLE(), err)
+		}
+		to.Store.Put(txKey, raw)
+		dao.Store.Delete(txKey)
+	}
+
+	return dao.storeHeader(key, &b.Header)
+}
+
 // StoreHeader saves block header into the store.
 func (dao *Simple) StoreHeader(h *block.Header) error {
 	return dao.storeHeader(dao.makeExecutableKey(h.Hash()), h)
@@ -815,6 +908,197 @@ func (dao *Simple) StoreAsTransaction(tx *transaction.Transaction, index uint32,
 	return nil
 }
 
+func (dao *Simple) makeConflictRelationKey(target, tx util.Uint256) []byte {
+	key := dao.getKeyBuf(1 + 2*util.Uint256Size)
+	key[0] = byte(storage.IXConflicts)
+	copy(key[1:], target.BytesBE())
+	copy(key[1+util.Uint256Size:], tx.BytesBE())
+	return key
+}
+
+func (dao *Simple) makeOracleResponseKey(id uint64) []byte {
+	key := dao.getKeyBuf(1 + 8)
+	key[0] = byte(storage.IXOracleResponses)
+	binary.BigEndian.PutUint64(key[1:], id)
+	return key
+}
+
+// StoreTxRelations stores relation index records for Conflicts and
+// OracleResponse attributes of the given transaction included into the block
+// with the given index.
+func (dao *Simple) StoreTxRelations(tx *transaction.Transaction, index uint32) {
+	h := tx.Hash()
+	for _, attr := range tx.Attributes {
+		switch attr.Type {
+		case transaction.ConflictsT:
+			value := make([]byte, 4)
+			binary.LittleEndian.PutUint32(value, index)
+			dao.Store.Put(dao.makeConflictRelationKey(attr.Value.(*transaction.Conflicts).Hash, h), value)
+		case transaction.OracleResponseT:
+			dao.Store.Put(dao.makeOracleResponseKey(attr.Value.(*transaction.OracleResponse).ID), h.BytesBE())
+		}
+	}
+}
+
+// DeleteTxRelations removes relation index records stored for the given
+// transaction by StoreTxRelations.
+func (dao *Simple) DeleteTxRelations(tx *transaction.Transaction) {
+	h := tx.Hash()
+	for _, attr := range tx.Attributes {
+		switch attr.Type {
+		case transaction.ConflictsT:
+			dao.Store.Delete(dao.makeConflictRelationKey(attr.Value.(*transaction.Conflicts).Hash, h))
+		case transaction.OracleResponseT:
+			dao.Store.Delete(dao.makeOracleResponseKey(attr.Value.(*transaction.OracleResponse).ID))
+		}
+	}
+}
+
+// GetConflictingTransactions returns hashes of transactions that declare a
+// conflict with the given one via Conflicts attribute.
+func (dao *Simple) GetConflictingTransactions(hash util.Uint256) []util.Uint256 {
+	var (
+		res    []util.Uint256
+		prefix = slice.Copy(dao.makeConflictRelationKey(hash, util.Uint256{})[:1+util.Uint256Size])
+	)
+	dao.Store.Seek(storage.SeekRange{Prefix: prefix}, func(k, _ []byte) bool {
+		h, err := util.Uint256DecodeBytesBE(k[len(prefix):])
+		if err == nil {
+			res = append(res, h)
+		}
+		return true
+	})
+	return res
+}
+
+// GetOracleResponseTx returns hash of the transaction containing response for
+// the oracle request with the given ID. storage.ErrKeyNotFound is returned if
+// there is no such transaction.
+func (dao *Simple) GetOracleResponseTx(id uint64) (util.Uint256, error) {
+	b, err := dao.Store.Get(dao.makeOracleResponseKey(id))
+	if err != nil {
+		return util.Uint256{}, err
+	}
+	return util.Uint256DecodeBytesBE(b)
+}
+
+// -- start event journal.
+
+func (dao *Simple) makeJournalKey(index, ordinal uint32) []byte {
+	key := dao.getKeyBuf(1 + 4 + 4)
+	key[0] = byte(storage.IXEventJournal)
+	binary.BigEndian.PutUint32(key[1:], index)
+	binary.BigEndian.PutUint32(key[5:], ordinal)
+	return key
+}
+
+// PutJournalEvent saves serialized event with the given ordinal number
+// produced by the block with the given index into the events journal.
+func (dao *Simple) PutJournalEvent(index, ordinal uint32, data []byte) {
+	dao.Store.Put(dao.makeJournalKey(index, ordinal), data)
+}
+
+// SeekJournal iterates over the events journal starting from the event with
+// the given block index and ordinal number (inclusive) in ascending order
+// until f returns false.
+func (dao *Simple) SeekJournal(index, ordinal uint32, f func(index, ordinal uint32, data []byte) bool) {
+	start := dao.makeJournalKey(index, ordinal)
+	dao.Store.Seek(storage.SeekRange{
+		Prefix: []byte{byte(storage.IXEventJournal)},
+		Start:  slice.Copy(start[1:]),
+	}, func(k, v []byte) bool {
+		if len(k) != 9 {
+			return true
+		}
+		return f(binary.BigEndian.Uint32(k[1:]), binary.BigEndian.Uint32(k[5:]), v)
+	})
+}
+
+// DeleteJournalEvents removes all events produced by the blocks with index
+// lower than the given one from the events journal.
+func (dao *Simple) DeleteJournalEvents(to uint32) {
+	dao.deleteJournalRange(storage.IXEventJournal, to)
+}
+
+func (dao *Simple) makeJournalCountKey(index uint32) []byte {
+	key := dao.getKeyBuf(1 + 4)
+	key[0] = byte(storage.IXEventJournalCount)
+	binary.BigEndian.PutUint32(key[1:], index)
+	return key
+}
+
+// PutJournalEventCount saves the number of events produced by the block with
+// the given index into the events journal.
+func (dao *Simple) PutJournalEventCount(index, count uint32) {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, count)
+	dao.Store.Put(dao.makeJournalCountKey(index), b)
+}
+
+// GetJournalEventCount returns the number of events produced by the block
+// with the given index. storage.ErrKeyNotFound is returned if it's not known.
+func (dao *Simple) GetJournalEventCount(index uint32) (uint32, error) {
+	b, err := dao.Store.Get(dao.makeJournalCountKey(index))
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 4 {
+		return 0, fmt.Errorf("%w: bad journal event count length %d", ErrInternalDBInconsistency, len(b))
+	}
+	return binary.BigEndian.Uint32(b), nil
+}
+
+// DeleteJournalEventCounts removes event counts of the blocks with index
+// lower than the given one.
+func (dao *Simple) DeleteJournalEventCounts(to uint32) {
+	dao.deleteJournalRange(storage.IXEventJournalCount, to)
+}
+
+// deleteJournalRange removes all items with the given prefix and block index
+// lower than the given one.
+func (dao *Simple) deleteJournalRange(prefix storage.KeyPrefix, to uint32) {
+	var keys [][]byte
+	dao.Store.Seek(storage.SeekRange{Prefix: []byte{byte(prefix)}}, func(k, _ []byte) bool {
+		if len(k) < 5 || binary.BigEndian.Uint32(k[1:]) >= to {
+			return false
+		}
+		keys = append(keys, slice.Copy(k))
+		return true
+	})
+	for _, k := range keys {
+		dao.Store.Delete(k)
+	}
+}
+
+func makeJournalCursorKey(id string) []byte {
+	return append([]byte{byte(storage.IXJournalCursor)}, id...)
+}
+
+// GetJournalCursor returns the block index and ordinal number of the last
+// journal event acknowledged by the subscriber with the given ID.
+// storage.ErrKeyNotFound is returned if there is no such subscriber.
+func (dao *Simple) GetJournalCursor(id string) (uint32, uint32, error) {
+	b, err := dao.Store.Get(makeJournalCursorKey(id))
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(b) != 8 {
+		return 0, 0, fmt.Errorf("%w: bad journal cursor length %d", ErrInternalDBInconsistency, len(b))
+	}
+	return binary.BigEndian.Uint32(b), binary.BigEndian.Uint32(b[4:]), nil
+}
+
+// PutJournalCursor saves the block index and ordinal number of the last
+// journal event acknowledged by the subscriber with the given ID.
+func (dao *Simple) PutJournalCursor(id string, index, ordinal uint32) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b, index)
+	binary.BigEndian.PutUint32(b[4:], ordinal)
+	dao.Store.Put(makeJournalCursorKey(id), b)
+}
+
+// -- end event journal.
+
 func (dao *Simple) getKeyBuf(len int) []byte {
 	if dao.private {
 		if dao.keyBuf == nil {
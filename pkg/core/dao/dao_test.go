@@ -123,6 +123,7 @@ func TestGetVersion(t *testing.T) {
 		StoragePrefix:     0x42,
 		P2PSigExtensions:  true,
 		StateRootInHeader: true,
+		HeaderCommitment:  true,
 		Value:             "testVersion",
 	}
 	dao.PutVersion(expected)
@@ -229,6 +230,47 @@ func TestStoreAsTransaction(t *testing.T) {
 	})
 }
 
+func TestStoreTxRelations(t *testing.T) {
+	dao := NewSimple(storage.NewMemoryStore(), false, true)
+	target := util.Uint256{1, 2, 3}
+	newTx := func(attrs ...transaction.Attribute) *transaction.Transaction {
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 1)
+		tx.Signers = append(tx.Signers, transaction.Signer{})
+		tx.Scripts = append(tx.Scripts, transaction.Witness{})
+		tx.Attributes = attrs
+		return tx
+	}
+	conflicts := transaction.Attribute{Type: transaction.ConflictsT, Value: &transaction.Conflicts{Hash: target}}
+	tx1 := newTx(conflicts)
+	tx2 := newTx(conflicts, transaction.Attribute{Type: transaction.HighPriority})
+	tx2.Nonce++
+	resp := newTx(transaction.Attribute{
+		Type:  transaction.OracleResponseT,
+		Value: &transaction.OracleResponse{ID: 42, Code: transaction.Success},
+	})
+
+	require.Nil(t, dao.GetConflictingTransactions(target))
+	_, err := dao.GetOracleResponseTx(42)
+	require.True(t, errors.Is(err, storage.ErrKeyNotFound))
+
+	dao.StoreTxRelations(tx1, 1)
+	dao.StoreTxRelations(tx2, 2)
+	dao.StoreTxRelations(resp, 2)
+	require.ElementsMatch(t, []util.Uint256{tx1.Hash(), tx2.Hash()}, dao.GetConflictingTransactions(target))
+	require.Nil(t, dao.GetConflictingTransactions(tx1.Hash()))
+	h, err := dao.GetOracleResponseTx(42)
+	require.NoError(t, err)
+	require.Equal(t, resp.Hash(), h)
+	_, err = dao.GetOracleResponseTx(43)
+	require.Error(t, err)
+
+	dao.DeleteTxRelations(tx1)
+	dao.DeleteTxRelations(resp)
+	require.Equal(t, []util.Uint256{tx2.Hash()}, dao.GetConflictingTransactions(target))
+	_, err = dao.GetOracleResponseTx(42)
+	require.True(t, errors.Is(err, storage.ErrKeyNotFound))
+}
+
 func BenchmarkStoreAsTransaction(b *testing.B) {
 	dao := NewSimple(storage.NewMemoryStore(), false, true)
 	tx := transaction.New([]byte{byte(opcode.PUSH1)}, 1)
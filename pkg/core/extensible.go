@@ -0,0 +1,102 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// Extensible payload categories handled by the node itself, handlers for them
+// are registered when Blockchain is created.
+const (
+	// consensusCategory is the category of dBFT payloads.
+	consensusCategory = "dBFT"
+	// stateRootCategory is the category of state service payloads.
+	stateRootCategory = "StateService"
+)
+
+var (
+	// ErrExtensibleCategoryExists is returned from
+	// RegisterExtensiblePayloadHandler when the category is already registered.
+	ErrExtensibleCategoryExists = errors.New("extensible payload category is already registered")
+	// ErrUnknownExtensibleCategory is returned from VerifyExtensible for
+	// payloads of categories without registered handler.
+	ErrUnknownExtensibleCategory = errors.New("unknown extensible payload category")
+	// ErrDisallowedExtensibleSender is returned from VerifyExtensible when the
+	// payload sender is not allowed to send payloads of its category.
+	ErrDisallowedExtensibleSender = errors.New("disallowed extensible payload sender")
+)
+
+// extensibleHandler contains extensible payload category verification
+// callbacks.
+type extensibleHandler struct {
+	validator      func(*payload.Extensible) error
+	allowedSenders func(height uint32) []util.Uint160
+}
+
+// RegisterExtensiblePayloadHandler registers extensible payload category.
+// Payloads of this category are accepted only from the senders returned by
+// allowedSenders for the current chain height and only if validator (which is
+// optional) doesn't return an error. Payloads of unregistered categories are
+// rejected. It's an error to register the same category twice.
+func (bc *Blockchain) RegisterExtensiblePayloadHandler(category string, validator func(*payload.Extensible) error,
+	allowedSenders func(height uint32) []util.Uint160) error {
+	if allowedSenders == nil {
+		return errors.New("allowed senders callback is mandatory")
+	}
+	bc.extensibleHandlersLock.Lock()
+	defer bc.extensibleHandlersLock.Unlock()
+	if _, ok := bc.extensibleHandlers[category]; ok {
+		return fmt.Errorf("%w: %s", ErrExtensibleCategoryExists, category)
+	}
+	bc.extensibleHandlers[category] = extensibleHandler{
+		validator:      validator,
+		allowedSenders: allowedSenders,
+	}
+	return nil
+}
+
+// VerifyExtensible checks that the category of extensible payload is
+// registered, the payload sender is allowed to send payloads of this category
+// at the current height and the payload content is valid. Payload witness and
+// validity heights are not checked.
+func (bc *Blockchain) VerifyExtensible(e *payload.Extensible) error {
+	bc.extensibleHandlersLock.RLock()
+	h, ok := bc.extensibleHandlers[e.Category]
+	bc.extensibleHandlersLock.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownExtensibleCategory, e.Category)
+	}
+	var allowed bool
+	for _, u := range h.allowedSenders(bc.BlockHeight()) {
+		if u.Equals(e.Sender) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s", ErrDisallowedExtensibleSender, e.Sender.StringLE())
+	}
+	if h.validator != nil {
+		if err := h.validator(e); err != nil {
+			return fmt.Errorf("invalid %s payload: %w", e.Category, err)
+		}
+	}
+	return nil
+}
+
+// registerNodeExtensibleHandlers registers handlers for the categories used by
+// the node services. They share the same list of allowed senders (committee,
+// validators and state validators).
+func (bc *Blockchain) registerNodeExtensibleHandlers() {
+	whitelist := func(uint32) []util.Uint160 {
+		return bc.extensible.Load().([]util.Uint160)
+	}
+	for _, c := range []string{consensusCategory, stateRootCategory} {
+		if err := bc.RegisterExtensiblePayloadHandler(c, nil, whitelist); err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	gio "io"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// headerSnapshotVersion is the current header snapshot format version.
+const headerSnapshotVersion = 1
+
+// headerSnapshotInterval is the distance between checkpoint headers which
+// hashes are included into the snapshot.
+const headerSnapshotInterval = 200
+
+// Header snapshot header settings flags.
+const (
+	snapshotStateRootInHeaderBit = 1 << iota
+	snapshotHeaderCommitmentBit
+)
+
+// HeaderSnapshot is the contents of a header snapshot: hashes of checkpoint
+// headers and some number of the latest headers.
+type HeaderSnapshot struct {
+	Network           uint32
+	StateRootInHeader bool
+	HeaderCommitment  bool
+	// Count is the number of headers in the chain.
+	Count uint32
+	// Interval is the distance between checkpoint headers.
+	Interval uint32
+	// Checkpoints contains hashes of headers with indexes multiple of
+	// Interval followed by the hash of the last header (if it's not a
+	// multiple of Interval).
+	Checkpoints []util.Uint256
+	// Headers contains the latest headers, the last one has Count-1 index.
+	Headers []*block.Header
+}
+
+// HeaderSnapshotBatchError is returned from VerifyHeaderSnapshot when a
+// checkpoint hash doesn't match its digest.
+type HeaderSnapshotBatchError struct {
+	// Batch is the checkpoint number.
+	Batch int
+	// From and To are the first and the last header indexes covered by the
+	// checkpoint (the ones following the previous checkpoint).
+	From uint32
+	To   uint32
+}
+
+// ErrInvalidHeaderSnapshot is returned from VerifyHeaderSnapshot when the
+// snapshot is malformed or inconsistent.
+var ErrInvalidHeaderSnapshot = errors.New("invalid header snapshot")
+
+// Error implements error interface.
+func (e *HeaderSnapshotBatchError) Error() string {
+	return fmt.Sprintf("header hashes batch %d (headers %d-%d) digest mismatch", e.Batch, e.From, e.To)
+}
+
+// Unwrap allows to check for ErrInvalidHeaderSnapshot with errors.Is.
+func (e *HeaderSnapshotBatchError) Unwrap() error {
+	return ErrInvalidHeaderSnapshot
+}
+
+// ExportHeaderSnapshot writes hashes of every 200th header (and the last
+// one) along with the last tailHeaders headers into w, the result can be
+// checked and loaded with VerifyHeaderSnapshot without any database. Every
+// checkpoint hash is followed by a digest chained from the previous one, so
+// that corruptions can be localized. The snapshot takes about 320 KB per
+// million headers plus the size of included headers.
+func (bc *Blockchain) ExportHeaderSnapshot(w gio.Writer, tailHeaders int) error {
+	if tailHeaders < 0 {
+		return errors.New("negative number of headers")
+	}
+	var (
+		height = bc.HeaderHeight()
+		count  = int(height) + 1
+		bw     = io.NewBinWriterFromIO(w)
+		digest util.Uint256
+	)
+	if tailHeaders > count {
+		tailHeaders = count
+	}
+	bw.WriteB(headerSnapshotVersion)
+	bw.WriteU32LE(uint32(bc.config.Magic))
+	var flags byte
+	if bc.config.StateRootInHeader {
+		flags |= snapshotStateRootInHeaderBit
+	}
+	if bc.config.HeaderCommitment {
+		flags |= snapshotHeaderCommitmentBit
+	}
+	bw.WriteB(flags)
+	bw.WriteU32LE(uint32(count))
+	bw.WriteU32LE(headerSnapshotInterval)
+	buf := make([]byte, 2*util.Uint256Size)
+	for i := uint32(0); ; i = nextCheckpoint(i, uint32(count), headerSnapshotInterval) {
+		copy(buf, digest.BytesBE())
+		copy(buf[util.Uint256Size:], bc.GetHeaderHash(int(i)).BytesBE())
+		digest = hash.Sha256(buf)
+		bw.WriteBytes(buf[util.Uint256Size:])
+		bw.WriteBytes(digest.BytesBE())
+		if bw.Err != nil {
+			return bw.Err
+		}
+		if i == uint32(count-1) {
+			break
+		}
+	}
+	bw.WriteU32LE(uint32(tailHeaders))
+	for i := count - tailHeaders; i < count; i++ {
+		h, err := bc.GetHeader(bc.GetHeaderHash(i))
+		if err != nil {
+			return fmt.Errorf("failed to get header %d: %w", i, err)
+		}
+		h.EncodeBinary(bw)
+	}
+	return bw.Err
+}
+
+// nextCheckpoint returns the index of the checkpoint header following the
+// given one in the chain of count headers.
+func nextCheckpoint(index, count, interval uint32) uint32 {
+	if next := index + interval; next > index && next < count {
+		return next
+	}
+	return count - 1
+}
+
+// VerifyHeaderSnapshot reads the header snapshot made by ExportHeaderSnapshot
+// from r and checks its consistency: network magic, checkpoint digests,
+// hashes and links of the included headers (the last one always matches the
+// last checkpoint). It returns the snapshot contents if everything is OK.
+func VerifyHeaderSnapshot(r gio.Reader, expectedNetwork uint32) (*HeaderSnapshot, error) {
+	var (
+		br     = io.NewBinReaderFromIO(r)
+		s      = new(HeaderSnapshot)
+		digest util.Uint256
+	)
+	if v := br.ReadB(); br.Err == nil && v != headerSnapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidHeaderSnapshot, v)
+	}
+	s.Network = br.ReadU32LE()
+	flags := br.ReadB()
+	s.StateRootInHeader = flags&snapshotStateRootInHeaderBit != 0
+	s.HeaderCommitment = flags&snapshotHeaderCommitmentBit != 0
+	s.Count = br.ReadU32LE()
+	s.Interval = br.ReadU32LE()
+	if br.Err != nil {
+		return nil, br.Err
+	}
+	if s.Network != expectedNetwork {
+		return nil, fmt.Errorf("%w: network mismatch: %d vs %d", ErrInvalidHeaderSnapshot, s.Network, expectedNetwork)
+	}
+	if s.Count == 0 {
+		return nil, fmt.Errorf("%w: no headers", ErrInvalidHeaderSnapshot)
+	}
+	if s.Interval == 0 {
+		return nil, fmt.Errorf("%w: zero checkpoint interval", ErrInvalidHeaderSnapshot)
+	}
+
+	var (
+		buf         = make([]byte, 2*util.Uint256Size)
+		checkpoints = make(map[uint32]util.Uint256)
+		from        uint32
+	)
+	for i, index := 0, uint32(0); ; i, index = i+1, nextCheckpoint(index, s.Count, s.Interval) {
+		copy(buf, digest.BytesBE())
+		br.ReadBytes(buf[util.Uint256Size:])
+		br.ReadBytes(digest[:])
+		if br.Err != nil {
+			return nil, br.Err
+		}
+		if hash.Sha256(buf) != digest {
+			return nil, &HeaderSnapshotBatchError{Batch: i, From: from, To: index}
+		}
+		var h util.Uint256
+		copy(h[:], buf[util.Uint256Size:])
+		s.Checkpoints = append(s.Checkpoints, h)
+		checkpoints[index] = h
+		if index == s.Count-1 {
+			break
+		}
+		from = index + 1
+	}
+
+	tail := br.ReadU32LE()
+	if br.Err != nil {
+		return nil, br.Err
+	}
+	if tail > s.Count {
+		return nil, fmt.Errorf("%w: %d headers for %d hashes", ErrInvalidHeaderSnapshot, tail, s.Count)
+	}
+	s.Headers = make([]*block.Header, tail)
+	for i := range s.Headers {
+		h := &block.Header{
+			StateRootEnabled:  s.StateRootInHeader,
+			CommitmentEnabled: s.HeaderCommitment,
+		}
+		h.DecodeBinary(br)
+		if br.Err != nil {
+			return nil, br.Err
+		}
+		index := s.Count - tail + uint32(i)
+		if h.Index != index {
+			return nil, fmt.Errorf("%w: header %d has index %d", ErrInvalidHeaderSnapshot, index, h.Index)
+		}
+		if cp, ok := checkpoints[index]; ok && !h.Hash().Equals(cp) {
+			return nil, fmt.Errorf("%w: header %d hash mismatch", ErrInvalidHeaderSnapshot, index)
+		}
+		if i > 0 {
+			if !h.PrevHash.Equals(s.Headers[i-1].Hash()) {
+				return nil, fmt.Errorf("%w: header %d previous hash mismatch", ErrInvalidHeaderSnapshot, index)
+			}
+			if !h.Script.ScriptHash().Equals(s.Headers[i-1].NextConsensus) {
+				return nil, fmt.Errorf("%w: header %d witness doesn't match previous NextConsensus", ErrInvalidHeaderSnapshot, index)
+			}
+		}
+		s.Headers[i] = h
+	}
+	return s, nil
+}
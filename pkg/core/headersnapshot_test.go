@@ -0,0 +1,91 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockchain_HeaderSnapshot(t *testing.T) {
+	bc := newTestChain(t)
+	_, err := bc.genBlocks(headerBatchCount + 10)
+	require.NoError(t, err)
+	network := uint32(bc.config.Magic)
+	count := int(bc.HeaderHeight()) + 1
+
+	const tail = 5
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, bc.ExportHeaderSnapshot(buf, tail))
+	data := buf.Bytes()
+
+	t.Run("good", func(t *testing.T) {
+		s, err := VerifyHeaderSnapshot(bytes.NewReader(data), network)
+		require.NoError(t, err)
+		require.Equal(t, network, s.Network)
+		require.Equal(t, uint32(count), s.Count)
+		require.Equal(t, uint32(headerSnapshotInterval), s.Interval)
+		require.Equal(t, count/headerSnapshotInterval+2, len(s.Checkpoints))
+		for i := 0; i < len(s.Checkpoints)-1; i++ {
+			require.Equal(t, bc.GetHeaderHash(i*headerSnapshotInterval), s.Checkpoints[i])
+		}
+		require.Equal(t, bc.GetHeaderHash(count-1), s.Checkpoints[len(s.Checkpoints)-1])
+		require.Equal(t, tail, len(s.Headers))
+		for i, h := range s.Headers {
+			expected, err := bc.GetHeader(bc.GetHeaderHash(count - tail + i))
+			require.NoError(t, err)
+			require.Equal(t, expected.Hash(), h.Hash())
+			require.Equal(t, expected.Index, h.Index)
+			require.Equal(t, expected.Script, h.Script)
+		}
+	})
+	t.Run("all headers", func(t *testing.T) {
+		buf := bytes.NewBuffer(nil)
+		require.NoError(t, bc.ExportHeaderSnapshot(buf, count+10))
+		s, err := VerifyHeaderSnapshot(buf, network)
+		require.NoError(t, err)
+		require.Equal(t, count, len(s.Headers))
+	})
+	t.Run("wrong network", func(t *testing.T) {
+		_, err := VerifyHeaderSnapshot(bytes.NewReader(data), network+1)
+		require.True(t, errors.Is(err, ErrInvalidHeaderSnapshot), err)
+	})
+	// Snapshot header (14 bytes) is followed by checkpoint hashes, each with
+	// its digest.
+	const checkpointsOffset = 14
+	checkpointsCount := count/headerSnapshotInterval + 2
+	t.Run("corrupted hash", func(t *testing.T) {
+		corrupted := append([]byte{}, data...)
+		corrupted[checkpointsOffset+3*2*util.Uint256Size+1] ^= 0xFF
+
+		_, err := VerifyHeaderSnapshot(bytes.NewReader(corrupted), network)
+		require.True(t, errors.Is(err, ErrInvalidHeaderSnapshot), err)
+		var batchErr *HeaderSnapshotBatchError
+		require.True(t, errors.As(err, &batchErr), err)
+		require.Equal(t, 3, batchErr.Batch)
+		require.Equal(t, uint32(2*headerSnapshotInterval+1), batchErr.From)
+		require.Equal(t, uint32(3*headerSnapshotInterval), batchErr.To)
+	})
+	t.Run("corrupted header", func(t *testing.T) {
+		corrupted := append([]byte{}, data...)
+		// Checkpoints, headers number and the version of the last
+		// header, so it's the previous hash.
+		offset := len(corrupted) - len(data[checkpointsOffset+checkpointsCount*2*util.Uint256Size+4:])/tail + 4
+		corrupted[offset] ^= 0xFF
+		_, err := VerifyHeaderSnapshot(bytes.NewReader(corrupted), network)
+		require.True(t, errors.Is(err, ErrInvalidHeaderSnapshot), err)
+
+		// Witness doesn't affect the hash, but it's checked against
+		// the previous header.
+		corrupted = append([]byte{}, data...)
+		corrupted[len(corrupted)-1] ^= 0xFF
+		_, err = VerifyHeaderSnapshot(bytes.NewReader(corrupted), network)
+		require.True(t, errors.Is(err, ErrInvalidHeaderSnapshot), err)
+	})
+	t.Run("truncated", func(t *testing.T) {
+		_, err := VerifyHeaderSnapshot(bytes.NewReader(data[:len(data)-10]), network)
+		require.Error(t, err)
+	})
+}
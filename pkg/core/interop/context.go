@@ -64,6 +64,15 @@ type Context struct {
 	baseExecFee    int64
 	baseStorageFee int64
 	signers        []transaction.Signer
+
+	// MaxNotifications is the maximum number of notifications and
+	// MaxNotificationsSize is the maximum aggregate size of them allowed
+	// to be added with AddNotification, 0 means no limit.
+	MaxNotifications     int
+	MaxNotificationsSize int
+	// notificationsSize is the aggregate size of notifications added with
+	// AddNotification.
+	notificationsSize int
 }
 
 // NewContext returns new interop context.
@@ -71,9 +80,10 @@ func NewContext(trigger trigger.Type, bc Ledger, d *dao.Simple, baseExecFee, bas
 	getContract func(*dao.Simple, util.Uint160) (*state.Contract, error), natives []Contract,
 	block *block.Block, tx *transaction.Transaction, log *zap.Logger) *Context {
 	dao := d.GetPrivate()
+	cfg := bc.GetConfig()
 	return &Context{
 		Chain:          bc,
-		Network:        uint32(bc.GetConfig().Magic),
+		Network:        uint32(cfg.Magic),
 		Natives:        natives,
 		Trigger:        trigger,
 		Block:          block,
@@ -84,7 +94,32 @@ func NewContext(trigger trigger.Type, bc Ledger, d *dao.Simple, baseExecFee, bas
 		getContract:    getContract,
 		baseExecFee:    baseExecFee,
 		baseStorageFee: baseStorageFee,
+
+		MaxNotifications:     cfg.MaxNotifications,
+		MaxNotificationsSize: cfg.MaxNotificationsSize,
+	}
+}
+
+// AddNotification adds notification to the context checking it against
+// MaxNotifications and MaxNotificationsSize limits. The size of notification
+// is the length of its name plus the length of its serialized item.
+func (ic *Context) AddNotification(ne state.NotificationEvent) error {
+	if ic.MaxNotifications > 0 && len(ic.Notifications) >= ic.MaxNotifications {
+		return fmt.Errorf("notifications limit exceeded: %d", ic.MaxNotifications)
 	}
+	if ic.MaxNotificationsSize > 0 {
+		b, err := stackitem.Serialize(ne.Item)
+		if err != nil {
+			return fmt.Errorf("bad notification: %w", err)
+		}
+		size := ic.notificationsSize + len(ne.Name) + len(b)
+		if size > ic.MaxNotificationsSize {
+			return fmt.Errorf("notifications size limit exceeded: %d > %d", size, ic.MaxNotificationsSize)
+		}
+		ic.notificationsSize = size
+	}
+	ic.Notifications = append(ic.Notifications, ne)
+	return nil
 }
 
 // InitNonceData initializes nonce to be used in `GetRandom` calculations.
@@ -160,7 +195,18 @@ type ContractMD struct {
 
 // NewContractMD returns Contract with the specified list of methods.
 func NewContractMD(name string, id int32) *ContractMD {
-	c := &ContractMD{Name: name}
+	return NewContractMDWithCapacity(name, id, 0)
+}
+
+// NewContractMDWithCapacity is similar to NewContractMD, but it also
+// preallocates space for methodCount methods, so that subsequent AddMethod
+// calls don't need to grow method lists. It's just a hint, more methods can
+// still be added.
+func NewContractMDWithCapacity(name string, id int32, methodCount int) *ContractMD {
+	c := &ContractMD{
+		Name:    name,
+		Methods: make([]MethodAndPrice, 0, methodCount),
+	}
 
 	c.ID = id
 
@@ -171,6 +217,7 @@ func NewContractMD(name string, id int32) *ContractMD {
 	c.NEF.Tokens = []nef.MethodToken{} // avoid `nil` result during JSON marshalling
 	c.Hash = state.CreateContractHash(util.Uint160{}, 0, c.Name)
 	c.Manifest = *manifest.DefaultManifest(name)
+	c.Manifest.ABI.Methods = make([]manifest.Method, 0, methodCount)
 
 	return c
 }
@@ -195,7 +242,9 @@ func (c *ContractMD) UpdateHash() {
 	c.NEF.Checksum = c.NEF.CalculateChecksum()
 }
 
-// AddMethod adds new method to a native contract.
+// AddMethod adds new method to a native contract. It panics if the contract
+// already has a method with the same name and number of parameters, overloads
+// must differ in parameter count.
 func (c *ContractMD) AddMethod(md *MethodAndPrice, desc *manifest.Method) {
 	md.MD = desc
 	desc.Safe = md.RequiredFlags&(callflag.All^callflag.ReadOnly) == 0
@@ -207,6 +256,12 @@ func (c *ContractMD) AddMethod(md *MethodAndPrice, desc *manifest.Method) {
 		}
 		return len(md.Parameters) > len(desc.Parameters)
 	})
+	if index > 0 {
+		prev := c.Manifest.ABI.Methods[index-1]
+		if prev.Name == desc.Name && len(prev.Parameters) == len(desc.Parameters) {
+			panic(fmt.Errorf("%s: duplicate method %s with %d parameters", c.Name, desc.Name, len(desc.Parameters)))
+		}
+	}
 	c.Manifest.ABI.Methods = append(c.Manifest.ABI.Methods, manifest.Method{})
 	copy(c.Manifest.ABI.Methods[index+1:], c.Manifest.ABI.Methods[index:])
 	c.Manifest.ABI.Methods[index] = *desc
@@ -294,6 +349,24 @@ func (ic *Context) BaseStorageFee() int64 {
 	return ic.baseStorageFee
 }
 
+// GasLeft returns the amount of GAS left for the current VM execution or -1
+// if it's unlimited. It's only meaningful when there is a VM spawned and it's
+// most useful for Application trigger executions (native contract methods can
+// use it to fail early before doing some expensive work), other triggers
+// don't usually have limits.
+func (ic *Context) GasLeft() int64 {
+	if ic.VM.GasLimit == -1 {
+		return -1
+	}
+	return ic.VM.GasLimit - ic.VM.GasConsumed()
+}
+
+// CurrentDepth returns the current invocation depth, it's 1 for the entry
+// script and every contract call (re-entrant ones included) increments it.
+func (ic *Context) CurrentDepth() int {
+	return ic.VM.InvocationDepth()
+}
+
 // SyscallHandler handles syscall with id.
 func (ic *Context) SyscallHandler(_ *vm.VM, id uint32) error {
 	f := ic.GetFunction(id)
@@ -0,0 +1,59 @@
+package interop
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractMD_AddMethod(t *testing.T) {
+	newDesc := func(name string, params ...string) *manifest.Method {
+		ps := make([]manifest.Parameter, len(params))
+		for i := range params {
+			ps[i] = manifest.NewParameter(params[i], smartcontract.IntegerType)
+		}
+		return &manifest.Method{Name: name, Parameters: ps, ReturnType: smartcontract.VoidType}
+	}
+
+	c := NewContractMD("Test", -100)
+	c.AddMethod(&MethodAndPrice{}, newDesc("foo"))
+	c.AddMethod(&MethodAndPrice{}, newDesc("foo", "a", "b"))
+	c.AddMethod(&MethodAndPrice{}, newDesc("foo", "a"))
+	c.AddMethod(&MethodAndPrice{}, newDesc("bar", "a"))
+	require.Len(t, c.Methods, 4)
+	require.Len(t, c.Manifest.ABI.Methods, 4)
+
+	for _, n := range []int{0, 1, 2} {
+		md, ok := c.GetMethod("foo", n)
+		require.True(t, ok)
+		require.Equal(t, n, len(md.MD.Parameters))
+	}
+
+	t.Run("duplicate", func(t *testing.T) {
+		require.Panics(t, func() {
+			c.AddMethod(&MethodAndPrice{}, newDesc("foo", "x"))
+		})
+		require.Panics(t, func() {
+			c.AddMethod(&MethodAndPrice{}, newDesc("bar", "x"))
+		})
+		require.Len(t, c.Methods, 4)
+		require.Len(t, c.Manifest.ABI.Methods, 4)
+	})
+}
+
+func TestNewContractMDWithCapacity(t *testing.T) {
+	c := NewContractMDWithCapacity("Test", -100, 2)
+	require.Equal(t, NewContractMD("Test", -100).Hash, c.Hash)
+	require.Equal(t, 0, len(c.Methods))
+	require.Equal(t, 2, cap(c.Methods))
+	require.Equal(t, 0, len(c.Manifest.ABI.Methods))
+	require.Equal(t, 2, cap(c.Manifest.ABI.Methods))
+
+	c.AddMethod(&MethodAndPrice{}, &manifest.Method{Name: "foo"})
+	c.AddMethod(&MethodAndPrice{}, &manifest.Method{Name: "bar"})
+	c.AddMethod(&MethodAndPrice{}, &manifest.Method{Name: "baz"})
+	require.Equal(t, 3, len(c.Methods))
+	require.Equal(t, 3, len(c.Manifest.ABI.Methods))
+}
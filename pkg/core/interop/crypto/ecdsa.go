@@ -13,6 +13,11 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 )
 
+// parMultisigThreshold is the minimum number of signatures that are checked
+// concurrently, smaller sets are checked serially as they don't benefit from
+// concurrency much. Both ways produce the same results and cost the same GAS.
+const parMultisigThreshold = 4
+
 // ECDSASecp256r1CheckMultisig checks multiple ECDSA signatures at once using
 // Secp256r1 elliptic curve.
 func ECDSASecp256r1CheckMultisig(ic *interop.Context) error {
@@ -32,7 +37,15 @@ func ECDSASecp256r1CheckMultisig(ic *interop.Context) error {
 	if len(pkeys) < len(sigs) {
 		return errors.New("more signatures than there are keys")
 	}
-	sigok := vm.CheckMultisigPar(ic.VM, elliptic.P256(), hash.NetSha256(ic.Network, ic.Container).BytesBE(), pkeys, sigs)
+	var (
+		h     = hash.NetSha256(ic.Network, ic.Container).BytesBE()
+		sigok bool
+	)
+	if len(sigs) < parMultisigThreshold {
+		sigok = vm.CheckMultisig(ic.VM, elliptic.P256(), h, pkeys, sigs)
+	} else {
+		sigok = vm.CheckMultisigPar(ic.VM, elliptic.P256(), h, pkeys, sigs)
+	}
 	ic.VM.Estack().PushItem(stackitem.Bool(sigok))
 	return nil
 }
@@ -3,6 +3,7 @@ package crypto
 import (
 	"encoding/binary"
 	"fmt"
+	"strconv"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/fakechain"
@@ -173,6 +174,32 @@ func testCurveCHECKMULTISIGBad(t *testing.T) {
 	})
 }
 
+func TestECDSASecp256r1CheckMultisigGas(t *testing.T) {
+	for _, n := range []int{parMultisigThreshold - 1, parMultisigThreshold, 11, 21} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			all := make([]int, n)
+			for i := range all {
+				all[i] = i
+			}
+			reversed := make([]int, n)
+			for i := range reversed {
+				reversed[i] = n - 1 - i
+			}
+			sigSets := [][]int{all, reversed, all[1:], all[:n-1], reversed[1:]}
+
+			var gas int64
+			for _, is := range sigSets {
+				v := initCHECKMULTISIGVM(t, n, nil, is)
+				require.NoError(t, v.Run())
+				if gas == 0 {
+					gas = v.GasConsumed()
+				}
+				require.Equal(t, gas, v.GasConsumed())
+			}
+		})
+	}
+}
+
 func TestCheckSig(t *testing.T) {
 	priv, err := keys.NewPrivateKey()
 	require.NoError(t, err)
@@ -155,6 +155,33 @@ func TestNotify(t *testing.T) {
 		ic := newIC("event", arr)
 		require.Error(t, Notify(ic))
 	})
+	t.Run("limits", func(t *testing.T) {
+		arr := stackitem.NewArray([]stackitem.Item{stackitem.Make(42)})
+		data, err := stackitem.Serialize(arr)
+		require.NoError(t, err)
+		size := len("event") + len(data)
+
+		check := func(t *testing.T, ic *interop.Context) {
+			require.NoError(t, Notify(ic))
+			for i := 0; i < 2; i++ {
+				ic.VM.Estack().PushVal(arr)
+				ic.VM.Estack().PushVal("event")
+			}
+			require.NoError(t, Notify(ic))
+			require.Error(t, Notify(ic))
+			require.Equal(t, 2, len(ic.Notifications))
+		}
+		t.Run("count", func(t *testing.T) {
+			ic := newIC("event", arr)
+			ic.MaxNotifications = 2
+			check(t, ic)
+		})
+		t.Run("size", func(t *testing.T) {
+			ic := newIC("event", arr)
+			ic.MaxNotificationsSize = 2*size + 1
+			check(t, ic)
+		})
+	})
 	t.Run("good", func(t *testing.T) {
 		arr := stackitem.NewArray([]stackitem.Item{stackitem.Make(42)})
 		ic := newIC("good event", arr)
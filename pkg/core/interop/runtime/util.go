@@ -17,11 +17,7 @@ import (
 
 // GasLeft returns remaining amount of GAS.
 func GasLeft(ic *interop.Context) error {
-	if ic.VM.GasLimit == -1 {
-		ic.VM.Estack().PushItem(stackitem.NewBigInteger(big.NewInt(ic.VM.GasLimit)))
-	} else {
-		ic.VM.Estack().PushItem(stackitem.NewBigInteger(big.NewInt(ic.VM.GasLimit - ic.VM.GasConsumed())))
-	}
+	ic.VM.Estack().PushItem(stackitem.NewBigInteger(big.NewInt(ic.GasLeft())))
 	return nil
 }
 
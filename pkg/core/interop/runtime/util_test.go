@@ -19,6 +19,7 @@ func TestGasLeft(t *testing.T) {
 		ic := &interop.Context{VM: vm.New()}
 		ic.VM.GasLimit = -1
 		ic.VM.AddGas(58)
+		require.Equal(t, int64(-1), ic.GasLeft())
 		require.NoError(t, GasLeft(ic))
 		checkStack(t, ic.VM, -1)
 	})
@@ -26,6 +27,7 @@ func TestGasLeft(t *testing.T) {
 		ic := &interop.Context{VM: vm.New()}
 		ic.VM.GasLimit = 100
 		ic.VM.AddGas(58)
+		require.Equal(t, int64(42), ic.GasLeft())
 		require.NoError(t, GasLeft(ic))
 		checkStack(t, ic.VM, 42)
 	})
@@ -529,6 +529,32 @@ func TestStorageFind(t *testing.T) {
 
 // Helper functions to create VM, InteropContext, TX, Account, Contract.
 
+func TestStorageFindSnapshot(t *testing.T) {
+	v, contractState, context, chain := createVMAndContractState(t)
+	require.NoError(t, chain.contracts.Management.PutContractState(chain.dao, contractState))
+	context.DAO.PutStorageItem(contractState.ID, []byte{1}, []byte{2})
+	v.LoadScriptWithHash(contractState.NEF.Script, contractState.Hash, callflag.All)
+
+	v.Estack().PushVal(istorage.FindDefault)
+	v.Estack().PushVal([]byte{1})
+	v.Estack().PushVal(stackitem.NewInterop(&StorageContext{ID: contractState.ID}))
+	require.NoError(t, storageFind(context))
+	v.Estack().PushVal(42)
+	_, err := v.Snapshot()
+	require.True(t, errors.Is(err, vm.ErrInteropInSnapshot), err)
+	require.Contains(t, err.Error(), "evaluation stack 0 item 0: *storage.Iterator")
+
+	require.NoError(t, storageGetContext(context))
+	_, err = v.Snapshot()
+	require.True(t, errors.Is(err, vm.ErrInteropInSnapshot), err)
+	require.Contains(t, err.Error(), "evaluation stack 0 item 0: *storage.Iterator")
+	require.Contains(t, err.Error(), "evaluation stack 0 item 2: *core.StorageContext")
+
+	v.Estack().Clear()
+	_, err = v.Snapshot()
+	require.NoError(t, err)
+}
+
 func createVM(t testing.TB) (*vm.VM, *interop.Context, *Blockchain) {
 	chain := newTestChain(t)
 	context := chain.newInteropContext(trigger.Application,
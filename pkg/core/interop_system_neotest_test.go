@@ -4,9 +4,11 @@ import (
 	"encoding/json"
 	"math"
 	"math/big"
+	"strings"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/contracts"
+	"github.com/nspcc-dev/neo-go/pkg/compiler"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
@@ -18,7 +20,11 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"github.com/stretchr/testify/require"
@@ -254,3 +260,78 @@ func TestSystemRuntimeBurnGas(t *testing.T) {
 		cInvoker.InvokeFail(t, "GAS must be positive", "burnGas", int64(0))
 	})
 }
+
+func TestSystemContractCallStruct(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package structarg
+	type Point struct {
+		X, Y int
+		Name string
+	}
+	func Sum(p Point) int {
+		return p.X + p.Y
+	}
+	func Get(p Point) Point {
+		return p
+	}`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{Name: "StructArg"})
+	e.DeployContract(t, c, nil)
+
+	w := io.NewBufBinWriter()
+	emit.AppCallStruct(w.BinWriter, c.Hash, "sum", callflag.All, 1, 2, "point")
+	require.NoError(t, w.Err)
+	e.InvokeScriptCheckHALT(t, w.Bytes(), []neotest.Signer{e.Validator}, stackitem.Make(3))
+
+	w.Reset()
+	emit.AppCallStruct(w.BinWriter, c.Hash, "get", callflag.All, 1, 2, "point")
+	require.NoError(t, w.Err)
+	e.InvokeScriptCheckHALT(t, w.Bytes(), []neotest.Signer{e.Validator}, stackitem.NewStruct([]stackitem.Item{
+		stackitem.Make(1), stackitem.Make(2), stackitem.Make("point"),
+	}))
+}
+
+func TestSystemContractCallInvocationDepth(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+
+	src := `package reentrant
+	import (
+		"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+		"github.com/nspcc-dev/neo-go/pkg/interop/runtime"
+	)
+	func Recurse(n int) {
+		runtime.Log("depth")
+		if n > 0 {
+			contract.Call(runtime.GetExecutingScriptHash(), "recurse", contract.All, n-1)
+		}
+	}`
+	c := neotest.CompileSource(t, acc.ScriptHash(), strings.NewReader(src), &compiler.Options{
+		Name:        "Reentrant",
+		Permissions: []manifest.Permission{*manifest.NewPermission(manifest.PermissionWildcard)},
+	})
+	e.DeployContract(t, c, nil)
+
+	ic := bc.GetTestVM(trigger.Application, nil, nil)
+	var (
+		depths []int
+		logID  = interopnames.ToID([]byte(interopnames.SystemRuntimeLog))
+	)
+	ic.VM.SyscallHandler = func(v *vm.VM, id uint32) error {
+		if id == logID {
+			depths = append(depths, ic.CurrentDepth())
+		}
+		return ic.SyscallHandler(v, id)
+	}
+
+	w := io.NewBufBinWriter()
+	emit.AppCall(w.BinWriter, c.Hash, "recurse", callflag.All, 3)
+	require.NoError(t, w.Err)
+	ic.VM.LoadScriptWithFlags(w.Bytes(), callflag.All)
+	require.Equal(t, 1, ic.CurrentDepth())
+	require.NoError(t, ic.VM.Run())
+	// Entry script is at depth 1, every (re-entrant) call adds one more level.
+	require.Equal(t, []int{2, 3, 4, 5}, depths)
+	require.Equal(t, 0, ic.CurrentDepth())
+}
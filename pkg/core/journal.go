@@ -0,0 +1,334 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result/subscriptions"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"go.uber.org/zap"
+)
+
+// JournalEventType is the type of event stored in the events journal.
+type JournalEventType byte
+
+const (
+	// JournalBlock is the event produced for every stored block, it's
+	// always the first one for the block.
+	JournalBlock JournalEventType = iota
+	// JournalExecution is the event produced for every execution (OnPersist,
+	// transactions and PostPersist) of the block.
+	JournalExecution
+	// JournalNotification is the event produced for every notification of
+	// successful execution, it follows the corresponding JournalExecution.
+	JournalNotification
+)
+
+// journalBatchSize is the maximum number of events read from the journal at
+// once by a subscriber.
+const journalBatchSize = 64
+
+var (
+	// ErrJournalDisabled is returned by durable subscription methods if the
+	// events journal is not enabled in the configuration.
+	ErrJournalDisabled = errors.New("events journal is disabled")
+	// ErrJournalPruned is returned on attempt to subscribe with a cursor
+	// pointing to events that are already removed from the journal.
+	ErrJournalPruned = errors.New("events following the cursor are pruned from the journal")
+)
+
+// JournalCursor is the position of event in the events journal.
+type JournalCursor struct {
+	// Index is the index of the block the event is produced by.
+	Index uint32
+	// Ordinal is the number of event among the ones produced by the block.
+	Ordinal uint32
+}
+
+// JournalEvent is an event stored in the events journal. Only one of Block,
+// Execution and Notification is set depending on Type.
+type JournalEvent struct {
+	Cursor JournalCursor
+	Type   JournalEventType
+	// Block is the hash of the block for JournalBlock events.
+	Block        util.Uint256
+	Execution    *state.AppExecResult
+	Notification *subscriptions.NotificationEvent
+}
+
+// journalSubscriber is a durable subscriber delivering events from the
+// journal to its channel starting from the next cursor.
+type journalSubscriber struct {
+	ch   chan<- *JournalEvent
+	next JournalCursor
+	// wake is signalled (without blocking) when new events are journaled.
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// journalSubscribers is a set of durable subscribers.
+type journalSubscribers struct {
+	lock sync.Mutex
+	subs map[chan<- *JournalEvent]*journalSubscriber
+}
+
+// EncodeBinary implements io.Serializable interface. Cursor is not encoded,
+// it's a part of the journal key.
+func (e *JournalEvent) EncodeBinary(w *io.BinWriter) {
+	w.WriteB(byte(e.Type))
+	switch e.Type {
+	case JournalBlock:
+		w.WriteBytes(e.Block[:])
+	case JournalExecution:
+		e.Execution.EncodeBinary(w)
+	case JournalNotification:
+		w.WriteBytes(e.Notification.Container[:])
+		e.Notification.NotificationEvent.EncodeBinary(w)
+	default:
+		w.Err = fmt.Errorf("unknown journal event type %d", e.Type)
+	}
+}
+
+// DecodeBinary implements io.Serializable interface.
+func (e *JournalEvent) DecodeBinary(r *io.BinReader) {
+	e.Type = JournalEventType(r.ReadB())
+	switch e.Type {
+	case JournalBlock:
+		r.ReadBytes(e.Block[:])
+	case JournalExecution:
+		e.Execution = new(state.AppExecResult)
+		e.Execution.DecodeBinary(r)
+	case JournalNotification:
+		e.Notification = new(subscriptions.NotificationEvent)
+		r.ReadBytes(e.Notification.Container[:])
+		e.Notification.NotificationEvent.DecodeBinary(r)
+	default:
+		if r.Err == nil {
+			r.Err = fmt.Errorf("unknown journal event type %d", e.Type)
+		}
+	}
+}
+
+// journalBlock saves events produced by the block into the journal and
+// removes the ones that are out of the retention window now.
+func (bc *Blockchain) journalBlock(d *dao.Simple, b *block.Block, aers []*state.AppExecResult) error {
+	var (
+		ordinal uint32
+		w       = io.NewBufBinWriter()
+	)
+	put := func(e *JournalEvent) error {
+		w.Reset()
+		e.EncodeBinary(w.BinWriter)
+		if w.Err != nil {
+			return w.Err
+		}
+		d.PutJournalEvent(b.Index, ordinal, w.Bytes())
+		ordinal++
+		return nil
+	}
+	err := put(&JournalEvent{Type: JournalBlock, Block: b.Hash()})
+	if err != nil {
+		return err
+	}
+	for _, aer := range aers {
+		err = put(&JournalEvent{Type: JournalExecution, Execution: aer})
+		if err != nil {
+			return err
+		}
+		if aer.VMState != vm.HaltState {
+			continue
+		}
+		for i := range aer.Events {
+			err = put(&JournalEvent{Type: JournalNotification, Notification: &subscriptions.NotificationEvent{
+				Container:         aer.Container,
+				NotificationEvent: aer.Events[i],
+			}})
+			if err != nil {
+				return err
+			}
+		}
+	}
+	d.PutJournalEventCount(b.Index, ordinal)
+	if retention := bc.config.EventJournalRetention; b.Index >= retention {
+		// Retention can be lowered between node restarts, so everything below
+		// the window is removed. Event count of the last removed block is kept
+		// to resume subscribers that have acknowledged all of its events.
+		start := b.Index - retention + 1
+		d.DeleteJournalEvents(start)
+		d.DeleteJournalEventCounts(start - 1)
+	}
+	return nil
+}
+
+// journalStart returns the index of the oldest block which events are kept in
+// the journal.
+func (bc *Blockchain) journalStart() uint32 {
+	var (
+		height    = bc.BlockHeight()
+		retention = bc.config.EventJournalRetention
+	)
+	if height < retention {
+		return 0
+	}
+	return height - retention + 1
+}
+
+// SubscribeJournal attaches durable subscriber with the given ID to the events
+// journal (see EventJournalRetention configuration setting). Events following
+// the cursor acknowledged by this subscriber (via AckJournal) are sent to the
+// given channel, new subscribers get all events kept in the journal. Replayed
+// events are followed by new ones as they're added, every event is delivered
+// exactly once as long as the subscriber keeps up with the retention window.
+// Unlike other subscriptions, slow subscribers don't affect other Blockchain
+// functions. ErrJournalPruned is returned if some of the events following
+// the acknowledged cursor are already removed from the journal.
+func (bc *Blockchain) SubscribeJournal(id string, ch chan<- *JournalEvent) error {
+	if bc.config.EventJournalRetention == 0 {
+		return ErrJournalDisabled
+	}
+	var next JournalCursor
+	index, ordinal, err := bc.dao.GetJournalCursor(id)
+	switch {
+	case err == nil:
+		next = JournalCursor{Index: index, Ordinal: ordinal + 1}
+		if start := bc.journalStart(); index < start {
+			// Block events are already removed, but it's fine if the last
+			// one is acknowledged and the next block is still journaled.
+			count, err := bc.dao.GetJournalEventCount(index)
+			if index+1 < start || err != nil || ordinal+1 < count {
+				return fmt.Errorf("%w: cursor %d/%d, oldest journaled block %d", ErrJournalPruned, index, ordinal, start)
+			}
+			next = JournalCursor{Index: start}
+		}
+	case errors.Is(err, storage.ErrKeyNotFound):
+		next = JournalCursor{Index: bc.journalStart()}
+	default:
+		return err
+	}
+	sub := &journalSubscriber{
+		ch:   ch,
+		next: next,
+		wake: make(chan struct{}, 1),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	bc.journalSubs.lock.Lock()
+	if bc.journalSubs.subs == nil {
+		bc.journalSubs.subs = make(map[chan<- *JournalEvent]*journalSubscriber)
+	}
+	if _, ok := bc.journalSubs.subs[ch]; ok {
+		bc.journalSubs.lock.Unlock()
+		return errors.New("channel is already subscribed")
+	}
+	bc.journalSubs.subs[ch] = sub
+	bc.journalSubs.lock.Unlock()
+	go bc.runJournalSubscriber(sub)
+	return nil
+}
+
+// UnsubscribeJournal detaches durable subscriber using the given channel, you
+// can close it afterwards. Events sent, but not acknowledged, are delivered
+// again on the next SubscribeJournal call. Passing non-subscribed channel is a
+// no-op.
+func (bc *Blockchain) UnsubscribeJournal(ch chan<- *JournalEvent) {
+	bc.journalSubs.lock.Lock()
+	sub, ok := bc.journalSubs.subs[ch]
+	delete(bc.journalSubs.subs, ch)
+	bc.journalSubs.lock.Unlock()
+	if ok {
+		close(sub.stop)
+		<-sub.done
+	}
+}
+
+// AckJournal saves the cursor of the last event processed by the durable
+// subscriber with the given ID, subsequent SubscribeJournal calls for this ID
+// start with the event following it.
+func (bc *Blockchain) AckJournal(id string, c JournalCursor) error {
+	if bc.readOnly {
+		return ErrReadOnlyMode
+	}
+	if bc.config.EventJournalRetention == 0 {
+		return ErrJournalDisabled
+	}
+	bc.dao.PutJournalCursor(id, c.Index, c.Ordinal)
+	return nil
+}
+
+// wakeJournalSubscribers notifies all durable subscribers about new events.
+func (bc *Blockchain) wakeJournalSubscribers() {
+	bc.journalSubs.lock.Lock()
+	for _, sub := range bc.journalSubs.subs {
+		select {
+		case sub.wake <- struct{}{}:
+		default:
+		}
+	}
+	bc.journalSubs.lock.Unlock()
+}
+
+// stopJournalSubscribers detaches all durable subscribers.
+func (bc *Blockchain) stopJournalSubscribers() {
+	bc.journalSubs.lock.Lock()
+	subs := bc.journalSubs.subs
+	bc.journalSubs.subs = nil
+	bc.journalSubs.lock.Unlock()
+	for _, sub := range subs {
+		close(sub.stop)
+		<-sub.done
+	}
+}
+
+// runJournalSubscriber delivers journaled events to the subscriber until it's
+// stopped.
+func (bc *Blockchain) runJournalSubscriber(sub *journalSubscriber) {
+	defer close(sub.done)
+	for {
+		events := bc.readJournal(sub.next, journalBatchSize)
+		for _, e := range events {
+			select {
+			case sub.ch <- e:
+			case <-sub.stop:
+				return
+			}
+			sub.next = JournalCursor{Index: e.Cursor.Index, Ordinal: e.Cursor.Ordinal + 1}
+		}
+		if len(events) != 0 {
+			continue
+		}
+		select {
+		case <-sub.wake:
+		case <-sub.stop:
+			return
+		}
+	}
+}
+
+// readJournal returns up to limit events from the journal starting from the
+// given cursor (inclusive).
+func (bc *Blockchain) readJournal(from JournalCursor, limit int) []*JournalEvent {
+	var res []*JournalEvent
+	bc.dao.SeekJournal(from.Index, from.Ordinal, func(index, ordinal uint32, data []byte) bool {
+		e := &JournalEvent{Cursor: JournalCursor{Index: index, Ordinal: ordinal}}
+		r := io.NewBinReaderFromBuf(data)
+		e.DecodeBinary(r)
+		if r.Err != nil {
+			bc.log.Error("failed to decode journal event",
+				zap.Uint32("index", index),
+				zap.Uint32("ordinal", ordinal),
+				zap.Error(r.Err))
+			return false
+		}
+		res = append(res, e)
+		return len(res) < limit
+	})
+	return res
+}
@@ -0,0 +1,119 @@
+package mempool
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// StateDigest is a compact summary of the verified transaction set of the
+// pool. Two pools containing the same set of transactions have equal digests
+// irrespective of the order transactions were added in, so digests can be used
+// to cheaply compare pools of different nodes.
+type StateDigest struct {
+	// Hash is a SHA256 hash of sorted transaction hashes.
+	Hash util.Uint256 `json:"hash"`
+	// Count is the number of verified transactions in the pool.
+	Count int `json:"count"`
+	// TotalFee is the sum of system and network fees of all transactions.
+	TotalFee int64 `json:"totalfee"`
+}
+
+// Snapshot is a sorted set of hashes of verified transactions from the pool.
+// It can be serialized and compared with a snapshot of some other pool via
+// Diff.
+type Snapshot []util.Uint256
+
+// StateDigest computes the digest of the current pool contents.
+func (mp *Pool) StateDigest() StateDigest {
+	mp.lock.RLock()
+	var (
+		hashes = make([]util.Uint256, len(mp.verifiedTxes))
+		fee    int64
+	)
+	for i := range mp.verifiedTxes {
+		hashes[i] = mp.verifiedTxes[i].txn.Hash()
+		fee += mp.verifiedTxes[i].txn.SystemFee + mp.verifiedTxes[i].txn.NetworkFee
+	}
+	mp.lock.RUnlock()
+
+	sortHashes(hashes)
+	buf := make([]byte, 0, len(hashes)*util.Uint256Size)
+	for i := range hashes {
+		buf = append(buf, hashes[i].BytesBE()...)
+	}
+	return StateDigest{
+		Hash:     hash.Sha256(buf),
+		Count:    len(hashes),
+		TotalFee: fee,
+	}
+}
+
+// ExportSnapshot returns a sorted set of hashes of verified transactions
+// currently present in the pool.
+func (mp *Pool) ExportSnapshot() Snapshot {
+	mp.lock.RLock()
+	s := make(Snapshot, len(mp.verifiedTxes))
+	for i := range mp.verifiedTxes {
+		s[i] = mp.verifiedTxes[i].txn.Hash()
+	}
+	mp.lock.RUnlock()
+
+	sortHashes(s)
+	return s
+}
+
+// EncodeBinary implements io.Serializable interface.
+func (s *Snapshot) EncodeBinary(w *io.BinWriter) {
+	w.WriteArray(*s)
+}
+
+// DecodeBinary implements io.Serializable interface.
+func (s *Snapshot) DecodeBinary(r *io.BinReader) {
+	var hashes []util.Uint256
+	r.ReadArray(&hashes)
+	if r.Err != nil {
+		return
+	}
+	sortHashes(hashes)
+	for i := 1; i < len(hashes); i++ {
+		if hashes[i].Equals(hashes[i-1]) {
+			r.Err = fmt.Errorf("duplicate hash %s in snapshot", hashes[i].StringLE())
+			return
+		}
+	}
+	*s = hashes
+}
+
+// Diff compares s with other and returns hashes present in s only and
+// hashes present in other only. Both lists are sorted.
+func (s Snapshot) Diff(other Snapshot) ([]util.Uint256, []util.Uint256) {
+	var onlyS, onlyOther []util.Uint256
+
+	i, j := 0, 0
+	for i < len(s) && j < len(other) {
+		switch c := s[i].CompareTo(other[j]); {
+		case c < 0:
+			onlyS = append(onlyS, s[i])
+			i++
+		case c > 0:
+			onlyOther = append(onlyOther, other[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	onlyS = append(onlyS, s[i:]...)
+	onlyOther = append(onlyOther, other[j:]...)
+	return onlyS, onlyOther
+}
+
+func sortHashes(hashes []util.Uint256) {
+	sort.Slice(hashes, func(i, j int) bool {
+		return hashes[i].CompareTo(hashes[j]) < 0
+	})
+}
@@ -0,0 +1,69 @@
+package mempool
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/testserdes"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateDigest(t *testing.T) {
+	fs := &FeerStub{balance: 10000}
+	txes := make([]*transaction.Transaction, 6)
+	for i := range txes {
+		txes[i] = transaction.New([]byte{byte(opcode.PUSH1)}, int64(i))
+		txes[i].Nonce = uint32(i)
+		txes[i].NetworkFee = 10
+		txes[i].Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+	}
+
+	mp1 := New(10, 0, false)
+	mp2 := New(10, 0, false)
+	require.Equal(t, mp1.StateDigest(), mp2.StateDigest())
+
+	// Same set, different order.
+	for i := 0; i < 4; i++ {
+		require.NoError(t, mp1.Add(txes[i], fs))
+		require.NoError(t, mp2.Add(txes[3-i], fs))
+	}
+	d1 := mp1.StateDigest()
+	require.Equal(t, d1, mp2.StateDigest())
+	require.Equal(t, 4, d1.Count)
+	require.Equal(t, int64(0+1+2+3+4*10), d1.TotalFee)
+
+	s1, s2 := mp1.ExportSnapshot(), mp2.ExportSnapshot()
+	onlyFirst, onlySecond := s1.Diff(s2)
+	require.Equal(t, 0, len(onlyFirst))
+	require.Equal(t, 0, len(onlySecond))
+
+	// Overlapping sets.
+	mp1.Remove(txes[0].Hash(), fs)
+	require.NoError(t, mp1.Add(txes[4], fs))
+	require.NoError(t, mp2.Add(txes[5], fs))
+	d1, d2 := mp1.StateDigest(), mp2.StateDigest()
+	require.NotEqual(t, d1.Hash, d2.Hash)
+	require.Equal(t, 4, d1.Count)
+	require.Equal(t, 5, d2.Count)
+
+	s1, s2 = mp1.ExportSnapshot(), mp2.ExportSnapshot()
+	onlyFirst, onlySecond = s1.Diff(s2)
+	require.Equal(t, []util.Uint256{txes[4].Hash()}, onlyFirst)
+	require.ElementsMatch(t, []util.Uint256{txes[0].Hash(), txes[5].Hash()}, onlySecond)
+
+	t.Run("serialization", func(t *testing.T) {
+		actual := new(Snapshot)
+		testserdes.EncodeDecodeBinary(t, &s2, actual)
+
+		onlyFirst, onlySecond = s1.Diff(*actual)
+		require.Equal(t, []util.Uint256{txes[4].Hash()}, onlyFirst)
+		require.ElementsMatch(t, []util.Uint256{txes[0].Hash(), txes[5].Hash()}, onlySecond)
+
+		dup := append(Snapshot{s2[len(s2)-1]}, s2...)
+		data, err := testserdes.EncodeBinary(&dup)
+		require.NoError(t, err)
+		require.Error(t, testserdes.DecodeBinary(data, new(Snapshot)))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/bits"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/holiman/uint256"
@@ -37,11 +38,30 @@ var (
 	ErrOracleResponse = errors.New("conflicts with memory pool due to OracleResponse attribute")
 )
 
+// maxConflictErrorHashes is the maximum number of pooled transaction hashes
+// listed in ErrConflict details.
+const maxConflictErrorHashes = 16
+
+// TxSource denotes the origin of the transaction being pooled.
+type TxSource byte
+
+const (
+	// TxSourceNetwork is used for transactions received from the P2P network
+	// (or of unknown origin).
+	TxSourceNetwork TxSource = iota
+	// TxSourceLocal is used for transactions submitted to this node directly
+	// (via RPC for example).
+	TxSourceLocal
+)
+
 // item represents a transaction in the the Memory pool.
 type item struct {
 	txn        *transaction.Transaction
 	blockStamp uint32
 	data       interface{}
+	// verificationGAS is the GAS consumed by transaction witnesses
+	// verification, 0 if unknown.
+	verificationGAS int64
 }
 
 // items is a slice of item.
@@ -210,7 +230,7 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...interface{}) e
 		mp.lock.Unlock()
 		return ErrDup
 	}
-	conflictsToBeRemoved, err := mp.checkTxConflicts(t, fee)
+	conflictsToBeRemoved, err := mp.checkTxConflicts(t, fee, true)
 	if err != nil {
 		mp.lock.Unlock()
 		return err
@@ -248,8 +268,10 @@ func (mp *Pool) Add(t *transaction.Transaction, fee Feer, data ...interface{}) e
 	if len(mp.verifiedTxes) == mp.capacity {
 		// Less prioritized than the least prioritized we already have, won't fit.
 		if n == len(mp.verifiedTxes) {
+			lowest := mp.verifiedTxes[len(mp.verifiedTxes)-1].txn
 			mp.lock.Unlock()
-			return ErrOOM
+			return fmt.Errorf("%w: fee too low, the least prioritized pooled transaction %s has network fee %d (%d per byte)",
+				ErrOOM, lowest.Hash().StringLE(), lowest.NetworkFee, lowest.FeePerByte())
 		}
 		// Ditch the last one.
 		unlucky := mp.verifiedTxes[len(mp.verifiedTxes)-1]
@@ -349,7 +371,21 @@ func (mp *Pool) removeInternal(hash util.Uint256, feer Feer) {
 // only the transactions for which it returns a true result. It's used to quickly
 // drop part of the mempool that is now invalid after the block acceptance.
 func (mp *Pool) RemoveStale(isOK func(*transaction.Transaction) bool, feer Feer) {
+	mp.removeStale(isOK, feer, true)
+}
+
+// Sweep filters verified transactions the same way RemoveStale does, but it's
+// not tied to the block acceptance, so transactions are not scheduled for
+// resending. It returns the number of transactions removed.
+func (mp *Pool) Sweep(isOK func(*transaction.Transaction) bool, feer Feer) int {
+	return mp.removeStale(isOK, feer, false)
+}
+
+// removeStale implements RemoveStale and Sweep, it returns the number of
+// transactions removed.
+func (mp *Pool) removeStale(isOK func(*transaction.Transaction) bool, feer Feer, resend bool) int {
 	mp.lock.Lock()
+	oldLen := len(mp.verifiedTxes)
 	policyChanged := mp.loadPolicy(feer)
 	// We can reuse already allocated slice
 	// because items are iterated one-by-one in increasing order.
@@ -371,7 +407,7 @@ func (mp *Pool) RemoveStale(isOK func(*transaction.Transaction) bool, feer Feer)
 					mp.conflicts[hash] = append(mp.conflicts[hash], itm.txn.Hash())
 				}
 			}
-			if mp.resendThreshold != 0 {
+			if resend && mp.resendThreshold != 0 {
 				// item is resend at resendThreshold, 2*resendThreshold, 4*resendThreshold ...
 				// so quotient must be a power of two.
 				diff := (height - itm.blockStamp)
@@ -398,6 +434,7 @@ func (mp *Pool) RemoveStale(isOK func(*transaction.Transaction) bool, feer Feer)
 	}
 	mp.verifiedTxes = newVerifiedTxes
 	mp.lock.Unlock()
+	return oldLen - len(newVerifiedTxes)
 }
 
 // loadPolicy updates feePerByte field and returns whether policy has been
@@ -439,6 +476,49 @@ func New(capacity int, payerIndex int, enableSubscriptions bool) *Pool {
 	return mp
 }
 
+// Resize changes the pool capacity, it can be done while the pool is in use.
+// If the new capacity is less than the number of transactions in the pool,
+// the least prioritized ones are removed from it and returned (most
+// prioritized first). It panics if the capacity is not positive.
+func (mp *Pool) Resize(capacity int) []*transaction.Transaction {
+	if capacity <= 0 {
+		panic("invalid mempool capacity")
+	}
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	mp.capacity = capacity
+	if len(mp.verifiedTxes) <= capacity {
+		return nil
+	}
+	evicted := make([]*transaction.Transaction, 0, len(mp.verifiedTxes)-capacity)
+	for i := capacity; i < len(mp.verifiedTxes); i++ {
+		itm := mp.verifiedTxes[i]
+		tx := itm.txn
+		delete(mp.verifiedMap, tx.Hash())
+		payer := tx.Signers[mp.payerIndex].Account
+		senderFee := mp.fees[payer]
+		senderFee.feeSum.SubUint64(&senderFee.feeSum, uint64(tx.SystemFee+tx.NetworkFee))
+		mp.fees[payer] = senderFee
+		mp.removeConflictsOf(tx)
+		if attrs := tx.GetAttributes(transaction.OracleResponseT); len(attrs) != 0 {
+			delete(mp.oracleResp, attrs[0].Value.(*transaction.OracleResponse).ID)
+		}
+		if mp.subscriptionsOn.Load() {
+			mp.events <- mempoolevent.Event{
+				Type: mempoolevent.TransactionRemoved,
+				Tx:   itm.txn,
+				Data: itm.data,
+			}
+		}
+		mp.verifiedTxes[i] = item{} // Don't keep references.
+		evicted = append(evicted, tx)
+	}
+	mp.verifiedTxes = mp.verifiedTxes[:capacity]
+	updateMempoolMetrics(len(mp.verifiedTxes))
+	return evicted
+}
+
 // SetResendThreshold sets threshold after which transaction will be considered stale
 // and returned for retransmission by `GetStaleTransactions`.
 func (mp *Pool) SetResendThreshold(h uint32, f func(*transaction.Transaction, interface{})) {
@@ -469,26 +549,58 @@ func (mp *Pool) TryGetValue(hash util.Uint256) (*transaction.Transaction, bool)
 func (mp *Pool) TryGetData(hash util.Uint256) (interface{}, bool) {
 	mp.lock.RLock()
 	defer mp.lock.RUnlock()
-	if tx, ok := mp.verifiedMap[hash]; ok {
-		itm := item{txn: tx}
-		n := sort.Search(len(mp.verifiedTxes), func(n int) bool {
-			return itm.CompareTo(mp.verifiedTxes[n]) >= 0
-		})
-		if n < len(mp.verifiedTxes) {
-			for i := n; i < len(mp.verifiedTxes); i++ { // items may have equal priority, so `n` is the left bound of the items which are as prioritized as the desired `itm`.
-				if mp.verifiedTxes[i].txn.Hash() == hash {
-					return mp.verifiedTxes[i].data, ok
-				}
-				if itm.CompareTo(mp.verifiedTxes[i]) != 0 {
-					break
-				}
-			}
-		}
+	if itm := mp.findItem(hash); itm != nil {
+		return itm.data, true
 	}
 
 	return nil, false
 }
 
+// SetVerificationGAS stores the amount of GAS consumed by witnesses
+// verification of the transaction with the specified hash if it exists in
+// the memory pool.
+func (mp *Pool) SetVerificationGAS(hash util.Uint256, gas int64) {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+	if itm := mp.findItem(hash); itm != nil {
+		itm.verificationGAS = gas
+	}
+}
+
+// GetVerificationGAS returns the amount of GAS consumed by witnesses
+// verification of the transaction with the specified hash if it exists in
+// the memory pool and this amount was set via SetVerificationGAS.
+func (mp *Pool) GetVerificationGAS(hash util.Uint256) (int64, bool) {
+	mp.lock.RLock()
+	defer mp.lock.RUnlock()
+	if itm := mp.findItem(hash); itm != nil && itm.verificationGAS != 0 {
+		return itm.verificationGAS, true
+	}
+	return 0, false
+}
+
+// findItem returns the pool item of the transaction with the specified hash
+// or nil if there is no such transaction. It must be called with lock held.
+func (mp *Pool) findItem(hash util.Uint256) *item {
+	tx, ok := mp.verifiedMap[hash]
+	if !ok {
+		return nil
+	}
+	itm := item{txn: tx}
+	n := sort.Search(len(mp.verifiedTxes), func(n int) bool {
+		return itm.CompareTo(mp.verifiedTxes[n]) >= 0
+	})
+	for i := n; i < len(mp.verifiedTxes); i++ { // items may have equal priority, so `n` is the left bound of the items which are as prioritized as the desired `itm`.
+		if mp.verifiedTxes[i].txn.Hash() == hash {
+			return &mp.verifiedTxes[i]
+		}
+		if itm.CompareTo(mp.verifiedTxes[i]) != 0 {
+			break
+		}
+	}
+	return nil
+}
+
 // GetVerifiedTransactions returns a slice of transactions with their fees.
 func (mp *Pool) GetVerifiedTransactions() []*transaction.Transaction {
 	mp.lock.RLock()
@@ -505,7 +617,9 @@ func (mp *Pool) GetVerifiedTransactions() []*transaction.Transaction {
 
 // checkTxConflicts is an internal unprotected version of Verify. It takes into
 // consideration conflicting transactions which are about to be removed from mempool.
-func (mp *Pool) checkTxConflicts(tx *transaction.Transaction, fee Feer) ([]*transaction.Transaction, error) {
+// If details is true, ErrConflict returned contains the list of pooled
+// transactions of the same payer.
+func (mp *Pool) checkTxConflicts(tx *transaction.Transaction, fee Feer, details bool) ([]*transaction.Transaction, error) {
 	payer := tx.Signers[mp.payerIndex].Account
 	actualSenderFee, ok := mp.fees[payer]
 	if !ok {
@@ -552,9 +666,41 @@ func (mp *Pool) checkTxConflicts(tx *transaction.Transaction, fee Feer) ([]*tran
 		expectedSenderFee = actualSenderFee
 	}
 	_, err := checkBalance(tx, expectedSenderFee)
+	if details && errors.Is(err, ErrConflict) {
+		err = mp.feeConflictError(tx, payer, expectedSenderFee, conflictsToBeRemoved)
+	}
 	return conflictsToBeRemoved, err
 }
 
+// feeConflictError returns ErrConflict with the details on the pooled
+// transactions of the same payer that prevent tx from being added, no more
+// than maxConflictErrorHashes of them are listed.
+func (mp *Pool) feeConflictError(tx *transaction.Transaction, payer util.Uint160, fees utilityBalanceAndFees, removed []*transaction.Transaction) error {
+	var pooled []string
+	for _, itm := range mp.verifiedTxes {
+		if !itm.txn.Signers[mp.payerIndex].Account.Equals(payer) {
+			continue
+		}
+		var isRemoved bool
+		for _, r := range removed {
+			if r == itm.txn {
+				isRemoved = true
+				break
+			}
+		}
+		if isRemoved {
+			continue
+		}
+		if len(pooled) == maxConflictErrorHashes {
+			pooled = append(pooled, "...")
+			break
+		}
+		pooled = append(pooled, itm.txn.Hash().StringLE())
+	}
+	return fmt.Errorf("%w: same payer %s with balance %s already pays %s for pooled transactions [%s], transaction fee is %d",
+		ErrConflict, payer.StringLE(), fees.balance.ToBig(), fees.feeSum.ToBig(), strings.Join(pooled, ", "), tx.SystemFee+tx.NetworkFee)
+}
+
 // Verify checks if a Sender of tx is able to pay for it (and all the other
 // transactions in the pool). If yes, the transaction tx is a valid
 // transaction and the function returns true. If no, the transaction tx is
@@ -562,7 +708,7 @@ func (mp *Pool) checkTxConflicts(tx *transaction.Transaction, fee Feer) ([]*tran
 func (mp *Pool) Verify(tx *transaction.Transaction, feer Feer) bool {
 	mp.lock.RLock()
 	defer mp.lock.RUnlock()
-	_, err := mp.checkTxConflicts(tx, feer)
+	_, err := mp.checkTxConflicts(tx, feer, false)
 	return err == nil
 }
 
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"math/big"
 	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -150,7 +152,9 @@ func TestOverCapacity(t *testing.T) {
 	tx.Nonce = txcnt
 	tx.Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
 	txcnt++
-	require.Error(t, mp.Add(tx, fs))
+	err := mp.Add(tx, fs)
+	require.ErrorIs(t, err, ErrOOM)
+	require.Contains(t, err.Error(), mp.verifiedTxes[mempoolSize-1].txn.Hash().StringLE())
 	require.Equal(t, mempoolSize, mp.Count())
 	require.Equal(t, mempoolSize, len(mp.verifiedMap))
 	require.Equal(t, mempoolSize, len(mp.verifiedTxes))
@@ -214,6 +218,165 @@ func TestGetVerified(t *testing.T) {
 	require.Equal(t, 0, len(verTxes))
 }
 
+func TestResize(t *testing.T) {
+	var (
+		fs     = &FeerStub{balance: 100000000, p2pSigExt: true}
+		sender = util.Uint160{1, 2, 3}
+	)
+	const mempoolSize = 10
+	mp := New(mempoolSize, 0, false)
+
+	newTx := func(nonce uint32, netFee int64) *transaction.Transaction {
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		tx.Nonce = nonce
+		tx.NetworkFee = netFee
+		tx.Signers = []transaction.Signer{{Account: sender}}
+		return tx
+	}
+	txes := make([]*transaction.Transaction, mempoolSize)
+	for i := range txes {
+		txes[i] = newTx(uint32(i), int64(i+1)*1000)
+	}
+	// The least prioritized one has oracle response and conflicts attributes.
+	txes[0].Attributes = []transaction.Attribute{
+		{Type: transaction.OracleResponseT, Value: &transaction.OracleResponse{ID: 1}},
+		{Type: transaction.ConflictsT, Value: &transaction.Conflicts{Hash: util.Uint256{1}}},
+	}
+	for _, tx := range txes {
+		require.NoError(t, mp.Add(tx, fs))
+	}
+
+	require.Nil(t, mp.Resize(mempoolSize))
+	evicted := mp.Resize(6)
+	require.Equal(t, []*transaction.Transaction{txes[3], txes[2], txes[1], txes[0]}, evicted)
+	require.Equal(t, 6, mp.Count())
+	require.Equal(t, 6, len(mp.verifiedMap))
+	for _, tx := range evicted {
+		require.False(t, mp.ContainsKey(tx.Hash()))
+	}
+	require.Equal(t, txes[4:], reverseTxes(mp.GetVerifiedTransactions()))
+	require.Empty(t, mp.oracleResp)
+	require.Empty(t, mp.conflicts)
+	var feeSum int64
+	for _, tx := range txes[4:] {
+		feeSum += tx.NetworkFee
+	}
+	sf := mp.fees[sender]
+	require.Equal(t, uint64(feeSum), sf.feeSum.Uint64())
+
+	// New capacity is respected.
+	require.ErrorIs(t, mp.Add(newTx(100, 500), fs), ErrOOM)
+	require.NoError(t, mp.Add(newTx(101, 100000), fs))
+	require.Equal(t, 6, mp.Count())
+	require.False(t, mp.ContainsKey(txes[4].Hash()))
+
+	require.Nil(t, mp.Resize(20))
+	for i := 0; i < 14; i++ {
+		require.NoError(t, mp.Add(newTx(uint32(200+i), 500), fs))
+	}
+	require.Equal(t, 20, mp.Count())
+	require.ErrorIs(t, mp.Add(newTx(300, 100), fs), ErrOOM)
+
+	require.Panics(t, func() { mp.Resize(0) })
+}
+
+func reverseTxes(txes []*transaction.Transaction) []*transaction.Transaction {
+	for i, j := 0, len(txes)-1; i < j; i, j = i+1, j-1 {
+		txes[i], txes[j] = txes[j], txes[i]
+	}
+	return txes
+}
+
+func TestResizeConcurrent(t *testing.T) {
+	var fs = &FeerStub{balance: 100000000}
+	mp := New(100, 0, false)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+				tx.Nonce = uint32(g*1000 + i)
+				tx.NetworkFee = int64(i)
+				tx.Signers = []transaction.Signer{{Account: util.Uint160{byte(g)}}}
+				_ = mp.Add(tx, fs)
+			}
+		}(g)
+	}
+	for _, c := range []int{50, 10, 200, 30} {
+		mp.Resize(c)
+	}
+	wg.Wait()
+	require.LessOrEqual(t, mp.Count(), 30)
+	require.Equal(t, mp.Count(), len(mp.verifiedMap))
+	require.True(t, sort.IsSorted(sort.Reverse(mp.verifiedTxes)))
+}
+
+func TestMemPoolSweep(t *testing.T) {
+	mp := New(5, 0, false)
+	txs := make([]*transaction.Transaction, 4)
+	for i := range txs {
+		txs[i] = transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		txs[i].Nonce = uint32(i)
+		txs[i].Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+		require.NoError(t, mp.Add(txs[i], &FeerStub{}))
+	}
+
+	resent := make(chan *transaction.Transaction, len(txs))
+	mp.SetResendThreshold(5, func(tx *transaction.Transaction, _ interface{}) {
+		resent <- tx
+	})
+
+	isValid := func(tx *transaction.Transaction) bool {
+		return tx.Nonce%2 == 0
+	}
+	// Sweeping is not bound to the block acceptance, so nothing is resent
+	// even at the resend height.
+	require.Equal(t, 2, mp.Sweep(isValid, &FeerStub{blockHeight: 5}))
+	require.Equal(t, 2, mp.Count())
+	require.True(t, mp.ContainsKey(txs[0].Hash()))
+	require.False(t, mp.ContainsKey(txs[1].Hash()))
+	require.Equal(t, 0, mp.Sweep(isValid, &FeerStub{blockHeight: 5}))
+	time.Sleep(10 * time.Millisecond)
+	require.Equal(t, 0, len(resent))
+}
+
+func TestMemPoolVerificationGAS(t *testing.T) {
+	mp := New(5, 0, false)
+	txs := make([]*transaction.Transaction, 3)
+	for i := range txs {
+		txs[i] = transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		txs[i].Nonce = uint32(i)
+		txs[i].Signers = []transaction.Signer{{Account: util.Uint160{1, 2, 3}}}
+		require.NoError(t, mp.Add(txs[i], &FeerStub{}))
+	}
+	_, ok := mp.GetVerificationGAS(txs[0].Hash())
+	require.False(t, ok)
+
+	for i := range txs {
+		mp.SetVerificationGAS(txs[i].Hash(), int64(i+1)*100)
+	}
+	for i := range txs {
+		gas, ok := mp.GetVerificationGAS(txs[i].Hash())
+		require.True(t, ok)
+		require.Equal(t, int64(i+1)*100, gas)
+	}
+
+	// Unknown transactions are ignored.
+	mp.SetVerificationGAS(util.Uint256{1}, 1)
+	_, ok = mp.GetVerificationGAS(util.Uint256{1})
+	require.False(t, ok)
+
+	mp.Remove(txs[1].Hash(), &FeerStub{})
+	_, ok = mp.GetVerificationGAS(txs[1].Hash())
+	require.False(t, ok)
+	gas, ok := mp.GetVerificationGAS(txs[2].Hash())
+	require.True(t, ok)
+	require.Equal(t, int64(300), gas)
+}
+
 func TestRemoveStale(t *testing.T) {
 	var fs = &FeerStub{}
 	const mempoolSize = 10
@@ -249,6 +412,33 @@ func TestRemoveStale(t *testing.T) {
 	}
 }
 
+func TestMemPoolFeeConflictErrorLimit(t *testing.T) {
+	const n = maxConflictErrorHashes + 2
+	mp := New(n+1, 0, false)
+	fs := &FeerStub{balance: n}
+	sender := util.Uint160{1, 2, 3}
+	newTx := func(nonce uint32) *transaction.Transaction {
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		tx.Nonce = nonce
+		tx.NetworkFee = 1
+		tx.Signers = []transaction.Signer{{Account: sender}}
+		return tx
+	}
+	for i := 0; i < n; i++ {
+		require.NoError(t, mp.Add(newTx(uint32(i)), fs))
+	}
+	err := mp.Add(newTx(n), fs)
+	require.ErrorIs(t, err, ErrConflict)
+	var listed int
+	for _, tx := range mp.GetVerifiedTransactions() {
+		if strings.Contains(err.Error(), tx.Hash().StringLE()) {
+			listed++
+		}
+	}
+	require.Equal(t, maxConflictErrorHashes, listed)
+	require.Contains(t, err.Error(), ", ...]")
+}
+
 func TestMemPoolFees(t *testing.T) {
 	mp := New(10, 0, false)
 	fs := &FeerStub{balance: 10000000}
@@ -290,7 +480,11 @@ func TestMemPoolFees(t *testing.T) {
 	tx3.NetworkFee = 1
 	tx3.Signers = []transaction.Signer{{Account: sender0}}
 	require.Equal(t, false, mp.Verify(tx3, fs))
-	require.Error(t, mp.Add(tx3, fs))
+	err := mp.Add(tx3, fs)
+	require.ErrorIs(t, err, ErrConflict)
+	require.Contains(t, err.Error(), sender0.StringLE())
+	require.Contains(t, err.Error(), tx1.Hash().StringLE())
+	require.Contains(t, err.Error(), tx2.Hash().StringLE())
 	require.Equal(t, 1, len(mp.fees))
 	require.Equal(t, utilityBalanceAndFees{
 		balance: *uint256.NewInt(uint64(fs.balance)),
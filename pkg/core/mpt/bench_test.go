@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/stretchr/testify/require"
 )
 
 func benchmarkBytes(b *testing.B, n Node) {
@@ -37,3 +38,35 @@ func BenchmarkBytes(b *testing.B) {
 		n.Children[8] = NewLeafNode(random.Bytes(10))
 	})
 }
+
+func benchmarkTrieGet(b *testing.B, c *NodeCache) {
+	const count = 1000
+
+	tr := NewTrie(nil, ModeLatest, newTestStore())
+	keys := make([][]byte, count)
+	for i := range keys {
+		keys[i] = random.Bytes(1 + i%10)
+		require.NoError(b, tr.Put(keys[i], random.Bytes(10)))
+	}
+	tr.Flush(0)
+	root := tr.StateRoot()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr := NewTrie(NewHashNode(root), ModeLatest, tr.Store)
+		tr.SetNodeCache(c)
+		for _, k := range keys {
+			_, _ = tr.Get(k)
+		}
+	}
+}
+
+func BenchmarkTrie_Get(b *testing.B) {
+	b.Run("uncached", func(b *testing.B) {
+		benchmarkTrieGet(b, nil)
+	})
+	b.Run("cached", func(b *testing.B) {
+		benchmarkTrieGet(b, NewNodeCache(10000))
+	})
+}
@@ -0,0 +1,176 @@
+package mpt
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"go.uber.org/atomic"
+)
+
+// NodeCache is a bounded LRU cache of decoded MPT nodes keyed by node hash.
+// It can be shared between multiple tries working over the same storage, nodes
+// never change (their hash is their key), so the only reason to remove them
+// from the cache is their removal from the storage. All methods are safe for
+// concurrent use and can be called on nil NodeCache (which is a no-op cache).
+type NodeCache struct {
+	lock     sync.Mutex
+	capacity int
+	lru      *list.List
+	nodes    map[util.Uint256]*list.Element
+	// gc is the number of running garbage collections, no nodes are added
+	// while it's not zero.
+	gc int
+	// gen is incremented on every garbage collection start and end, nodes
+	// read from the storage before that are not added.
+	gen atomic.Uint64
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// NodeCacheStats contains node cache usage statistics.
+type NodeCacheStats struct {
+	// Hits is the number of nodes retrieved from the cache.
+	Hits uint64
+	// Misses is the number of nodes that were not found in the cache.
+	Misses uint64
+	// Len is the current number of nodes in the cache.
+	Len int
+}
+
+type nodeCacheItem struct {
+	hash util.Uint256
+	node Node
+}
+
+// NewNodeCache returns a new node cache holding up to capacity nodes, nil is
+// returned for non-positive capacity.
+func NewNodeCache(capacity int) *NodeCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &NodeCache{
+		capacity: capacity,
+		lru:      list.New(),
+		nodes:    make(map[util.Uint256]*list.Element),
+	}
+}
+
+// get returns a copy of the node with the specified hash or nil if it's not
+// cached. A copy is returned because tries modify the nodes they work with.
+func (c *NodeCache) get(h util.Uint256) Node {
+	if c == nil {
+		return nil
+	}
+	c.lock.Lock()
+	e, ok := c.nodes[h]
+	if ok {
+		c.lru.MoveToFront(e)
+	}
+	c.lock.Unlock()
+	if !ok {
+		c.misses.Inc()
+		return nil
+	}
+	c.hits.Inc()
+	return e.Value.(*nodeCacheItem).node.Clone()
+}
+
+// generation returns the current cache generation, it must be retrieved
+// before reading the node from the storage and passed to add.
+func (c *NodeCache) generation() uint64 {
+	if c == nil {
+		return 0
+	}
+	return c.gen.Load()
+}
+
+// add puts a copy of the node with the specified hash into the cache evicting
+// the least recently used node if the cache is full. The node is not added if
+// garbage collection was running since gen was retrieved.
+func (c *NodeCache) add(h util.Uint256, n Node, gen uint64) {
+	if c == nil {
+		return
+	}
+	n = n.Clone()
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.gc != 0 || c.gen.Load() != gen {
+		return
+	}
+	if e, ok := c.nodes[h]; ok {
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.nodes[h] = c.lru.PushFront(&nodeCacheItem{hash: h, node: n})
+	if c.lru.Len() > c.capacity {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		delete(c.nodes, e.Value.(*nodeCacheItem).hash)
+	}
+}
+
+// Remove removes the node with the specified hash from the cache.
+func (c *NodeCache) Remove(h util.Uint256) {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if e, ok := c.nodes[h]; ok {
+		c.lru.Remove(e)
+		delete(c.nodes, h)
+	}
+}
+
+// BeginGC must be called before the storage garbage collection, removed nodes
+// should then be dropped from the cache via Remove and EndGC called after the
+// changes are committed to the storage. No nodes are added to the cache in
+// between, so removed nodes can't get into the cache again.
+func (c *NodeCache) BeginGC() {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	c.gc++
+	c.gen.Inc()
+	c.lock.Unlock()
+}
+
+// EndGC finishes garbage collection started with BeginGC.
+func (c *NodeCache) EndGC() {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	c.gc--
+	c.gen.Inc()
+	c.lock.Unlock()
+}
+
+// Clear removes all nodes from the cache.
+func (c *NodeCache) Clear() {
+	if c == nil {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.lru.Init()
+	c.nodes = make(map[util.Uint256]*list.Element)
+}
+
+// Stats returns cache usage statistics.
+func (c *NodeCache) Stats() NodeCacheStats {
+	if c == nil {
+		return NodeCacheStats{}
+	}
+	c.lock.Lock()
+	l := c.lru.Len()
+	c.lock.Unlock()
+	return NodeCacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Len:    l,
+	}
+}
@@ -0,0 +1,120 @@
+package mpt
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeCache(t *testing.T) {
+	require.Nil(t, NewNodeCache(0))
+
+	t.Run("nil", func(t *testing.T) {
+		var c *NodeCache
+		c.add(util.Uint256{1}, NewLeafNode([]byte{1}), c.generation())
+		require.Nil(t, c.get(util.Uint256{1}))
+		c.Remove(util.Uint256{1})
+		c.BeginGC()
+		c.EndGC()
+		c.Clear()
+		require.Equal(t, NodeCacheStats{}, c.Stats())
+	})
+
+	c := NewNodeCache(2)
+	l1, l2, l3 := NewLeafNode([]byte{1}), NewLeafNode([]byte{2}), NewLeafNode([]byte{3})
+	c.add(l1.Hash(), l1, c.generation())
+	c.add(l2.Hash(), l2, c.generation())
+	require.Equal(t, l1, c.get(l1.Hash()))
+	c.add(l3.Hash(), l3, c.generation()) // l2 is the least recently used one.
+	require.Nil(t, c.get(l2.Hash()))
+	require.Equal(t, l1, c.get(l1.Hash()))
+	require.Equal(t, l3, c.get(l3.Hash()))
+	require.Equal(t, NodeCacheStats{Hits: 3, Misses: 1, Len: 2}, c.Stats())
+
+	t.Run("copy", func(t *testing.T) {
+		b := NewBranchNode()
+		b.Children[0] = NewHashNode(l1.Hash())
+		c.add(b.Hash(), b, c.generation())
+		b.Children[1] = l2
+
+		n := c.get(b.Hash()).(*BranchNode)
+		require.Equal(t, EmptyNode{}, n.Children[1])
+		n.Children[2] = l3
+		require.Equal(t, EmptyNode{}, c.get(b.Hash()).(*BranchNode).Children[2])
+	})
+
+	c.Remove(l3.Hash())
+	require.Nil(t, c.get(l3.Hash()))
+	c.Clear()
+	require.Equal(t, 0, c.Stats().Len)
+
+	t.Run("GC", func(t *testing.T) {
+		gen := c.generation()
+		c.BeginGC()
+		c.add(l1.Hash(), l1, c.generation())
+		require.Nil(t, c.get(l1.Hash()))
+		c.EndGC()
+		c.add(l1.Hash(), l1, gen) // Read before GC.
+		require.Nil(t, c.get(l1.Hash()))
+		c.add(l1.Hash(), l1, c.generation())
+		require.NotNil(t, c.get(l1.Hash()))
+	})
+}
+
+func testTrieNodeCache(t *testing.T, mode TrieMode) {
+	const count = 100
+
+	tr := NewTrie(nil, mode, newTestStore())
+	kv := make(map[string][]byte, count)
+	for i := 0; i < count; i++ {
+		k, v := random.Bytes(1+i%5), random.Bytes(10)
+		kv[string(k)] = v
+		require.NoError(t, tr.Put(k, v))
+	}
+	tr.Flush(0)
+	root := tr.StateRoot()
+
+	for _, capacity := range []int{1, 3, count * 10} {
+		c := NewNodeCache(capacity)
+		for i := 0; i < 3; i++ {
+			tr := NewTrie(NewHashNode(root), mode, tr.Store)
+			tr.SetNodeCache(c)
+			for k, v := range kv {
+				actual, err := tr.Get([]byte(k))
+				require.NoError(t, err, capacity)
+				require.Equal(t, v, actual, capacity)
+			}
+		}
+		st := c.Stats()
+		require.True(t, st.Len <= capacity)
+		if capacity > count {
+			require.NotZero(t, st.Hits)
+		}
+	}
+
+	t.Run("modification", func(t *testing.T) {
+		c := NewNodeCache(count * 10)
+		tr1 := NewTrie(NewHashNode(root), mode, tr.Store)
+		tr1.SetNodeCache(c)
+		for k, v := range kv {
+			require.NoError(t, tr1.Put([]byte(k), append(v, 1)))
+		}
+		require.NotEqual(t, root, tr1.StateRoot())
+
+		tr2 := NewTrie(NewHashNode(root), mode, tr.Store)
+		tr2.SetNodeCache(c)
+		for k, v := range kv {
+			actual, err := tr2.Get([]byte(k))
+			require.NoError(t, err)
+			require.Equal(t, v, actual)
+		}
+		require.NotZero(t, c.Stats().Hits)
+	})
+}
+
+func TestTrie_NodeCache(t *testing.T) {
+	t.Run("ModeAll", func(t *testing.T) { testTrieNodeCache(t, ModeAll) })
+	t.Run("ModeLatest", func(t *testing.T) { testTrieNodeCache(t, ModeLatest) })
+}
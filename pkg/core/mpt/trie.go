@@ -35,6 +35,7 @@ type Trie struct {
 	root     Node
 	mode     TrieMode
 	refcount map[util.Uint256]*cachedNode
+	nodes    *NodeCache
 }
 
 type cachedNode struct {
@@ -73,6 +74,12 @@ func NewTrie(root Node, mode TrieMode, store *storage.MemCachedStore) *Trie {
 	}
 }
 
+// SetNodeCache sets the cache of decoded nodes to be used by t, nil disables
+// caching. The cache can be shared between tries using the same storage.
+func (t *Trie) SetNodeCache(c *NodeCache) {
+	t.nodes = c
+}
+
 // Get returns value for the provided key in t.
 func (t *Trie) Get(key []byte) ([]byte, error) {
 	if len(key) > MaxKeyLength {
@@ -466,6 +473,7 @@ func (t *Trie) updateRefCount(h util.Uint256, key []byte, index uint32) int32 {
 		// BUG: negative reference count
 		panic(fmt.Sprintf("negative reference count: %s new %d, upd %d", h.StringBE(), cnt, t.refcount[h]))
 	case cnt == 0:
+		t.nodes.Remove(h)
 		if !t.mode.GC() {
 			t.Store.Delete(key)
 		} else {
@@ -511,6 +519,13 @@ func (t *Trie) removeRef(h util.Uint256, bs []byte) {
 }
 
 func (t *Trie) getFromStore(h util.Uint256) (Node, error) {
+	// Reference counter needs to be initialized from the storage.
+	if !t.mode.RC() || t.refcount[h] == nil {
+		if n := t.nodes.get(h); n != nil {
+			return n, nil
+		}
+	}
+	gen := t.nodes.generation()
 	data, err := getFromStore(makeStorageKey(h), t.mode, t.Store)
 	if err != nil {
 		return nil, err
@@ -523,8 +538,11 @@ func (t *Trie) getFromStore(h util.Uint256) (Node, error) {
 		return nil, r.Err
 	}
 
-	if t.mode.RC() {
+	if t.mode.RC() || t.mode.GC() {
+		// Strip activity flag and reference counter (or GC height).
 		data = data[:len(data)-5]
+	}
+	if t.mode.RC() {
 		node := t.refcount[h]
 		if node != nil {
 			node.bytes = data
@@ -533,6 +551,7 @@ func (t *Trie) getFromStore(h util.Uint256) (Node, error) {
 		}
 	}
 	n.Node.(flushedNode).setCache(data, h)
+	t.nodes.add(h, n.Node, gen)
 	return n.Node, nil
 }
 
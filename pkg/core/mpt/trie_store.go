@@ -38,6 +38,12 @@ func NewTrieStore(root util.Uint256, mode TrieMode, backed storage.Store) *TrieS
 	}
 }
 
+// SetNodeCache sets the cache of decoded MPT nodes to be used by the store, see
+// Trie.SetNodeCache.
+func (m *TrieStore) SetNodeCache(c *NodeCache) {
+	m.trie.SetNodeCache(c)
+}
+
 // Get implements the Store interface.
 func (m *TrieStore) Get(key []byte) ([]byte, error) {
 	if len(key) == 0 {
@@ -71,19 +71,23 @@ func NewContracts(cfg config.ProtocolConfiguration) *Contracts {
 	cs.Crypto = c
 	cs.Contracts = append(cs.Contracts, c)
 
-	ledger := newLedger()
+	ledger := newLedger(cfg.HeaderCommitment)
 	cs.Ledger = ledger
 	cs.Contracts = append(cs.Contracts, ledger)
 
 	gas := newGAS(int64(cfg.InitialGASSupply), cfg.P2PSigExtensions)
 	neo := newNEO(cfg)
-	policy := newPolicy()
+	policy := newPolicy(cfg)
 	neo.GAS = gas
 	neo.Policy = policy
 	gas.NEO = neo
 	mgmt.NEO = neo
 	policy.NEO = neo
 
+	gas.trackSupply = cfg.TrackSupplyDeltas
+	neo.trackSupply = cfg.TrackSupplyDeltas
+	mgmt.validateCallTokensHeight = cfg.ValidateCallTokensHeight
+
 	cs.GAS = gas
 	cs.NEO = neo
 	cs.Policy = policy
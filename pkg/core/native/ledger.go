@@ -26,12 +26,17 @@ type Ledger struct {
 	interop.ContractMD
 }
 
-const ledgerContractID = -4
+const (
+	ledgerContractID = -4
+	// ledgerMethodCount is the maximum number of Ledger methods.
+	ledgerMethodCount = 12
+)
 
-// newLedger creates new Ledger native contract.
-func newLedger() *Ledger {
+// newLedger creates new Ledger native contract, getHeaderCommitment method is
+// only available if headerCommitment is true.
+func newLedger(headerCommitment bool) *Ledger {
 	var l = &Ledger{
-		ContractMD: *interop.NewContractMD(nativenames.Ledger, ledgerContractID),
+		ContractMD: *interop.NewContractMDWithCapacity(nativenames.Ledger, ledgerContractID, ledgerMethodCount),
 	}
 	defer l.UpdateHash()
 
@@ -48,11 +53,28 @@ func newLedger() *Ledger {
 	md = newMethodAndPrice(l.getBlock, 1<<15, callflag.ReadStates)
 	l.AddMethod(md, desc)
 
+	desc = newDescriptor("getBlockHeader", smartcontract.ArrayType,
+		manifest.NewParameter("indexOrHash", smartcontract.ByteArrayType))
+	md = newMethodAndPrice(l.getBlockHeader, 1<<15, callflag.ReadStates)
+	l.AddMethod(md, desc)
+
+	if headerCommitment {
+		desc = newDescriptor("getHeaderCommitment", smartcontract.Hash256Type,
+			manifest.NewParameter("indexOrHash", smartcontract.ByteArrayType))
+		md = newMethodAndPrice(l.getHeaderCommitment, 1<<15, callflag.ReadStates)
+		l.AddMethod(md, desc)
+	}
+
 	desc = newDescriptor("getTransaction", smartcontract.ArrayType,
 		manifest.NewParameter("hash", smartcontract.Hash256Type))
 	md = newMethodAndPrice(l.getTransaction, 1<<15, callflag.ReadStates)
 	l.AddMethod(md, desc)
 
+	desc = newDescriptor("getTransactionData", smartcontract.ArrayType,
+		manifest.NewParameter("hash", smartcontract.Hash256Type))
+	md = newMethodAndPrice(l.getTransactionData, 1<<15, callflag.ReadStates)
+	l.AddMethod(md, desc)
+
 	desc = newDescriptor("getTransactionHeight", smartcontract.IntegerType,
 		manifest.NewParameter("hash", smartcontract.Hash256Type))
 	md = newMethodAndPrice(l.getTransactionHeight, 1<<15, callflag.ReadStates)
@@ -64,6 +86,13 @@ func newLedger() *Ledger {
 	md = newMethodAndPrice(l.getTransactionFromBlock, 1<<16, callflag.ReadStates)
 	l.AddMethod(md, desc)
 
+	desc = newDescriptor("getTransactionsFromBlock", smartcontract.ArrayType,
+		manifest.NewParameter("blockIndexOrHash", smartcontract.ByteArrayType),
+		manifest.NewParameter("startIndex", smartcontract.IntegerType),
+		manifest.NewParameter("count", smartcontract.IntegerType))
+	md = newMethodAndPrice(l.getTransactionsFromBlock, 1<<16, callflag.ReadStates)
+	l.AddMethod(md, desc)
+
 	desc = newDescriptor("getTransactionSigners", smartcontract.ArrayType,
 		manifest.NewParameter("hash", smartcontract.Hash256Type))
 	md = newMethodAndPrice(l.getTransactionSigners, 1<<15, callflag.ReadStates)
@@ -121,6 +150,27 @@ func (l *Ledger) getBlock(ic *interop.Context, params []stackitem.Item) stackite
 	return BlockToStackItem(block)
 }
 
+// getBlockHeader implements getBlockHeader SC method.
+func (l *Ledger) getBlockHeader(ic *interop.Context, params []stackitem.Item) stackitem.Item {
+	hash := getBlockHashFromItem(ic, params[0])
+	// Transactions are not needed, so trimmed block is enough.
+	b, err := ic.DAO.GetBlock(hash)
+	if err != nil || !isTraceableBlock(ic, b.Index) {
+		return stackitem.Null{}
+	}
+	return HeaderToStackItem(&b.Header)
+}
+
+// getHeaderCommitment implements getHeaderCommitment SC method.
+func (l *Ledger) getHeaderCommitment(ic *interop.Context, params []stackitem.Item) stackitem.Item {
+	hash := getBlockHashFromItem(ic, params[0])
+	b, err := ic.DAO.GetBlock(hash)
+	if err != nil || !isTraceableBlock(ic, b.Index) {
+		return stackitem.Null{}
+	}
+	return stackitem.NewByteArray(b.Commitment.BytesBE())
+}
+
 // getTransaction returns transaction to the SC.
 func (l *Ledger) getTransaction(ic *interop.Context, params []stackitem.Item) stackitem.Item {
 	tx, h, err := getTransactionAndHeight(ic.DAO, params[0])
@@ -130,6 +180,15 @@ func (l *Ledger) getTransaction(ic *interop.Context, params []stackitem.Item) st
 	return TransactionToStackItem(tx)
 }
 
+// getTransactionData returns transaction along with its attributes to the SC.
+func (l *Ledger) getTransactionData(ic *interop.Context, params []stackitem.Item) stackitem.Item {
+	tx, h, err := getTransactionAndHeight(ic.DAO, params[0])
+	if err != nil || !isTraceableBlock(ic, h) {
+		return stackitem.Null{}
+	}
+	return TransactionDataToStackItem(tx)
+}
+
 // getTransactionHeight returns transaction height to the SC.
 func (l *Ledger) getTransactionHeight(ic *interop.Context, params []stackitem.Item) stackitem.Item {
 	_, h, err := getTransactionAndHeight(ic.DAO, params[0])
@@ -154,6 +213,34 @@ func (l *Ledger) getTransactionFromBlock(ic *interop.Context, params []stackitem
 	return TransactionToStackItem(block.Transactions[index])
 }
 
+// getTransactionsFromBlockTxPrice is the price (in execution fee factor
+// units) charged by getTransactionsFromBlock for every transaction requested
+// in addition to the method price.
+const getTransactionsFromBlockTxPrice = 1 << 12
+
+// getTransactionsFromBlock returns count transactions starting from the given
+// index from the block with the given hash or index to the SC.
+func (l *Ledger) getTransactionsFromBlock(ic *interop.Context, params []stackitem.Item) stackitem.Item {
+	hash := getBlockHashFromItem(ic, params[0])
+	start := toUint32(params[1])
+	count := toUint32(params[2])
+	if !ic.VM.AddGas(int64(count) * getTransactionsFromBlockTxPrice * ic.BaseExecFee()) {
+		panic("insufficient gas")
+	}
+	block, err := ic.GetBlock(hash)
+	if err != nil || !isTraceableBlock(ic, block.Index) {
+		return stackitem.Null{}
+	}
+	if uint64(start)+uint64(count) > uint64(len(block.Transactions)) {
+		panic("wrong transactions range")
+	}
+	txes := make([]stackitem.Item, count)
+	for i := range txes {
+		txes[i] = TransactionToStackItem(block.Transactions[start+uint32(i)])
+	}
+	return stackitem.NewArray(txes)
+}
+
 // getTransactionSigners returns transaction signers to the SC.
 func (l *Ledger) getTransactionSigners(ic *interop.Context, params []stackitem.Item) stackitem.Item {
 	tx, h, err := getTransactionAndHeight(ic.DAO, params[0])
@@ -177,7 +264,9 @@ func (l *Ledger) getTransactionVMState(ic *interop.Context, params []stackitem.I
 }
 
 // isTraceableBlock defines whether we're able to give information about
-// the block with index specified.
+// the block with index specified. It only depends on MaxTraceableBlocks, so
+// the answer is the same for all nodes, RemoveUntraceableBlocks GC never
+// removes blocks that are still traceable at the current or previous height.
 func isTraceableBlock(ic *interop.Context, index uint32) bool {
 	height := ic.BlockHeight()
 	MaxTraceableBlocks := ic.Chain.GetConfig().MaxTraceableBlocks
@@ -231,22 +320,61 @@ func getTransactionAndHeight(d *dao.Simple, item stackitem.Item) (*transaction.T
 
 // BlockToStackItem converts block.Block to stackitem.Item.
 func BlockToStackItem(b *block.Block) stackitem.Item {
-	return stackitem.NewArray([]stackitem.Item{
-		stackitem.NewByteArray(b.Hash().BytesBE()),
-		stackitem.NewBigInteger(big.NewInt(int64(b.Version))),
-		stackitem.NewByteArray(b.PrevHash.BytesBE()),
-		stackitem.NewByteArray(b.MerkleRoot.BytesBE()),
-		stackitem.NewBigInteger(big.NewInt(int64(b.Timestamp))),
-		stackitem.NewBigInteger(new(big.Int).SetUint64(b.Nonce)),
-		stackitem.NewBigInteger(big.NewInt(int64(b.Index))),
-		stackitem.NewByteArray(b.NextConsensus.BytesBE()),
-		stackitem.NewBigInteger(big.NewInt(int64(len(b.Transactions)))),
-	})
+	return stackitem.NewArray(append(headerToStackItems(&b.Header),
+		stackitem.NewBigInteger(big.NewInt(int64(len(b.Transactions))))))
+}
+
+// HeaderToStackItem converts block.Header to stackitem.Item, it's the same as
+// BlockToStackItem, but without the number of transactions.
+func HeaderToStackItem(h *block.Header) stackitem.Item {
+	return stackitem.NewArray(headerToStackItems(h))
+}
+
+func headerToStackItems(h *block.Header) []stackitem.Item {
+	return []stackitem.Item{
+		stackitem.NewByteArray(h.Hash().BytesBE()),
+		stackitem.NewBigInteger(big.NewInt(int64(h.Version))),
+		stackitem.NewByteArray(h.PrevHash.BytesBE()),
+		stackitem.NewByteArray(h.MerkleRoot.BytesBE()),
+		stackitem.NewBigInteger(big.NewInt(int64(h.Timestamp))),
+		stackitem.NewBigInteger(new(big.Int).SetUint64(h.Nonce)),
+		stackitem.NewBigInteger(big.NewInt(int64(h.Index))),
+		stackitem.NewByteArray(h.NextConsensus.BytesBE()),
+	}
 }
 
 // TransactionToStackItem converts transaction.Transaction to stackitem.Item.
 func TransactionToStackItem(t *transaction.Transaction) stackitem.Item {
-	return stackitem.NewArray([]stackitem.Item{
+	return stackitem.NewArray(transactionToStackItems(t))
+}
+
+// TransactionDataToStackItem converts transaction.Transaction to stackitem.Item
+// including its attributes. It's an array with the same 8 fields as returned
+// by TransactionToStackItem (hash, version, nonce, sender, system fee, network
+// fee, valid until block and script) followed by an array of attributes. Each
+// attribute is an array of two elements: attribute type as an integer and
+// attribute value serialized the same way it's serialized in transaction
+// (without the type byte), it's an empty byte string for HighPriority.
+func TransactionDataToStackItem(t *transaction.Transaction) stackitem.Item {
+	attrs := make([]stackitem.Item, len(t.Attributes))
+	for i := range t.Attributes {
+		bw := io.NewBufBinWriter()
+		if t.Attributes[i].Value != nil {
+			t.Attributes[i].Value.EncodeBinary(bw.BinWriter)
+			if bw.Err != nil {
+				panic(fmt.Errorf("failed to serialize attribute %d to stackitem: %w", i, bw.Err))
+			}
+		}
+		attrs[i] = stackitem.NewArray([]stackitem.Item{
+			stackitem.NewBigInteger(big.NewInt(int64(t.Attributes[i].Type))),
+			stackitem.NewByteArray(bw.Bytes()),
+		})
+	}
+	return stackitem.NewArray(append(transactionToStackItems(t), stackitem.NewArray(attrs)))
+}
+
+func transactionToStackItems(t *transaction.Transaction) []stackitem.Item {
+	return []stackitem.Item{
 		stackitem.NewByteArray(t.Hash().BytesBE()),
 		stackitem.NewBigInteger(big.NewInt(int64(t.Version))),
 		stackitem.NewBigInteger(big.NewInt(int64(t.Nonce))),
@@ -255,7 +383,7 @@ func TransactionToStackItem(t *transaction.Transaction) stackitem.Item {
 		stackitem.NewBigInteger(big.NewInt(int64(t.NetworkFee))),
 		stackitem.NewBigInteger(big.NewInt(int64(t.ValidUntilBlock))),
 		stackitem.NewByteArray(t.Script),
-	})
+	}
 }
 
 // SignersToStackItem converts transaction.Signers to stackitem.Item.
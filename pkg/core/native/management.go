@@ -29,6 +29,11 @@ import (
 type Management struct {
 	interop.ContractMD
 	NEO *NEO
+
+	// validateCallTokensHeight is the height starting from which NEF
+	// method tokens are checked against the contract manifest permissions
+	// on deploy and update, 0 disables the check.
+	validateCallTokensHeight uint32
 }
 
 type ManagementCache struct {
@@ -262,6 +267,10 @@ func (m *Management) deployWithData(ic *interop.Context, args []stackitem.Item)
 	if ic.Tx == nil {
 		panic(errors.New("no transaction provided"))
 	}
+	err = m.ValidateCallTokens(ic.DAO, ic.BlockHeight()+1, neff.Tokens, manif)
+	if err != nil {
+		panic(err)
+	}
 	newcontract, err := m.Deploy(ic.DAO, ic.Tx.Sender(), neff, manif)
 	if err != nil {
 		panic(err)
@@ -285,20 +294,11 @@ func (m *Management) markUpdated(d *dao.Simple, hash util.Uint160, cs *state.Con
 // Deploy creates contract's hash/ID and saves new contract into the given DAO.
 // It doesn't run _deploy method and doesn't emit notification.
 func (m *Management) Deploy(d *dao.Simple, sender util.Uint160, neff *nef.File, manif *manifest.Manifest) (*state.Contract, error) {
-	h := state.CreateContractHash(sender, neff.Checksum, manif.Name)
-	_, err := m.GetContract(d, h)
-	if err == nil {
-		return nil, errors.New("contract already exists")
-	}
-	id, err := m.getNextContractID(d)
+	h, err := m.ValidateDeploy(d, sender, neff, manif)
 	if err != nil {
 		return nil, err
 	}
-	err = manif.IsValid(h)
-	if err != nil {
-		return nil, fmt.Errorf("invalid manifest: %w", err)
-	}
-	err = checkScriptAndMethods(neff.Script, manif.ABI.Methods)
+	id, err := m.getNextContractID(d)
 	if err != nil {
 		return nil, err
 	}
@@ -317,6 +317,26 @@ func (m *Management) Deploy(d *dao.Simple, sender util.Uint160, neff *nef.File,
 	return newcontract, nil
 }
 
+// ValidateDeploy performs all the checks Deploy does for the contract deployed
+// by the given sender without changing the state. It returns the hash of the
+// contract to be deployed.
+func (m *Management) ValidateDeploy(d *dao.Simple, sender util.Uint160, neff *nef.File, manif *manifest.Manifest) (util.Uint160, error) {
+	h := state.CreateContractHash(sender, neff.Checksum, manif.Name)
+	_, err := m.GetContract(d, h)
+	if err == nil {
+		return h, errors.New("contract already exists")
+	}
+	err = manif.IsValid(h)
+	if err != nil {
+		return h, fmt.Errorf("invalid manifest: %w", err)
+	}
+	err = checkScriptAndMethods(neff.Script, manif.ABI.Methods)
+	if err != nil {
+		return h, err
+	}
+	return h, nil
+}
+
 func (m *Management) update(ic *interop.Context, args []stackitem.Item) stackitem.Item {
 	return m.updateWithData(ic, append(args, stackitem.Null{}))
 }
@@ -335,6 +355,10 @@ func (m *Management) updateWithData(ic *interop.Context, args []stackitem.Item)
 	if err != nil {
 		panic(err)
 	}
+	err = m.ValidateCallTokens(ic.DAO, ic.BlockHeight()+1, contract.NEF.Tokens, &contract.Manifest)
+	if err != nil {
+		panic(err)
+	}
 	m.callDeploy(ic, contract, args[2], true)
 	m.emitNotification(ic, contractUpdateNotificationName, contract.Hash)
 	return stackitem.Null{}
@@ -585,6 +609,11 @@ func (m *Management) putContractState(d *dao.Simple, cs *state.Contract, updateC
 	return nil
 }
 
+// GetNextContractID allocates and returns the next available contract ID.
+func (m *Management) GetNextContractID(d *dao.Simple) (int32, error) {
+	return m.getNextContractID(d)
+}
+
 func (m *Management) getNextContractID(d *dao.Simple) (int32, error) {
 	si := d.GetStorageItem(m.ID, keyNextAvailableID)
 	if si == nil {
@@ -618,3 +647,49 @@ func checkScriptAndMethods(script []byte, methods []manifest.Method) error {
 	}
 	return vm.IsScriptCorrect(script, offsets)
 }
+
+// ValidateCallTokens checks NEF method tokens of the contract with the given
+// manifest (see checkCallTokens) if it's enabled for the block with the given
+// index by ValidateCallTokensHeight setting.
+func (m *Management) ValidateCallTokens(d *dao.Simple, index uint32, tokens []nef.MethodToken, manif *manifest.Manifest) error {
+	if m.validateCallTokensHeight == 0 || index < m.validateCallTokensHeight {
+		return nil
+	}
+	return m.checkCallTokens(d, tokens, manif)
+}
+
+// checkCallTokens checks that every NEF method token can be used by the
+// contract with the given manifest. Tokens with invalid call flags and tokens
+// not allowed by any of the manifest permissions are rejected (group
+// permissions can always match, the target may have or get the group). Tokens
+// targeting native contracts must also match some of their methods.
+func (m *Management) checkCallTokens(d *dao.Simple, tokens []nef.MethodToken, manif *manifest.Manifest) error {
+	for i := range tokens {
+		t := &tokens[i]
+		if t.CallFlag&^callflag.All != 0 {
+			return fmt.Errorf("method token #%d (%s, %s): invalid call flags %d",
+				i, t.Hash.StringLE(), t.Method, t.CallFlag)
+		}
+		var allowed bool
+		for j := range manif.Permissions {
+			p := &manif.Permissions[j]
+			if p.Contract.Type == manifest.PermissionHash && !p.Contract.Hash().Equals(t.Hash) {
+				continue
+			}
+			if p.Methods.Contains(t.Method) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("method token #%d (%s, %s) is not allowed by manifest permissions",
+				i, t.Hash.StringLE(), t.Method)
+		}
+		target, err := m.GetContract(d, t.Hash)
+		if err == nil && target.ID < 0 && target.Manifest.ABI.GetMethod(t.Method, int(t.ParamCount)) == nil {
+			return fmt.Errorf("method token #%d (%s, %s): native contract has no method with %d parameters",
+				i, t.Hash.StringLE(), t.Method, t.ParamCount)
+		}
+	}
+	return nil
+}
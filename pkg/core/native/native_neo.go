@@ -13,6 +13,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/dao"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/runtime"
+	istorage "github.com/nspcc-dev/neo-go/pkg/core/interop/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
@@ -91,6 +92,11 @@ const (
 	committeeRewardRatio = 10
 	// neoHolderRewardRatio is a percent of generated GAS that is distributed to voters.
 	voterRewardRatio = 80
+
+	// ValidatorsChangedEventName is the name of an event emitted at the
+	// committee update block when the set of next block validators changes
+	// (if ValidatorsChangedEvents setting is enabled).
+	ValidatorsChangedEventName = "ValidatorsChanged"
 )
 
 var (
@@ -194,6 +200,10 @@ func newNEO(cfg config.ProtocolConfiguration) *NEO {
 	md = newMethodAndPrice(n.getCandidatesCall, 1<<22, callflag.ReadStates)
 	n.AddMethod(md, desc)
 
+	desc = newDescriptor("getAllCandidates", smartcontract.InteropInterfaceType)
+	md = newMethodAndPrice(n.getAllCandidatesCall, 1<<22, callflag.ReadStates)
+	n.AddMethod(md, desc)
+
 	desc = newDescriptor("getAccountState", smartcontract.ArrayType,
 		manifest.NewParameter("account", smartcontract.Hash160Type))
 	md = newMethodAndPrice(n.getAccountState, 1<<15, callflag.ReadStates)
@@ -225,6 +235,12 @@ func newNEO(cfg config.ProtocolConfiguration) *NEO {
 	md = newMethodAndPrice(n.setRegisterPrice, 1<<15, callflag.States)
 	n.AddMethod(md, desc)
 
+	if cfg.ValidatorsChangedEvents {
+		n.AddEvent(ValidatorsChangedEventName,
+			manifest.NewParameter("Old", smartcontract.ArrayType),
+			manifest.NewParameter("New", smartcontract.ArrayType))
+	}
+
 	return n
 }
 
@@ -357,10 +373,33 @@ func (n *NEO) OnPersist(ic *interop.Context) error {
 		if err := n.updateCommittee(cache, ic); err != nil {
 			return err
 		}
+		if n.cfg.ValidatorsChangedEvents && !equalPublicKeys(oldKeys, cache.nextValidators) {
+			ic.Notifications = append(ic.Notifications, state.NotificationEvent{
+				ScriptHash: n.Hash,
+				Name:       ValidatorsChangedEventName,
+				Item: stackitem.NewArray([]stackitem.Item{
+					pubsToArray(oldKeys),
+					pubsToArray(cache.nextValidators),
+				}),
+			})
+		}
 	}
 	return nil
 }
 
+// equalPublicKeys checks whether two sorted key lists are the same.
+func equalPublicKeys(a, b keys.PublicKeys) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
 // PostPersist implements Contract interface.
 func (n *NEO) PostPersist(ic *interop.Context) error {
 	gas := n.GetGASPerBlock(ic.DAO, ic.Block.Index)
@@ -368,32 +407,19 @@ func (n *NEO) PostPersist(ic *interop.Context) error {
 	pubs := getCommitteeMembers(cache)
 	committeeSize := n.cfg.GetCommitteeSize(ic.Block.Index)
 	index := int(ic.Block.Index) % committeeSize
-	committeeReward := new(big.Int).Mul(gas, bigCommitteeRewardRatio)
-	n.GAS.mint(ic, pubs[index].GetScriptHash(), committeeReward.Div(committeeReward, big100), false)
+	n.GAS.mint(ic, pubs[index].GetScriptHash(), committeeReward(gas), false)
 
 	if n.cfg.ShouldUpdateCommitteeAt(ic.Block.Index) {
-		var voterReward = new(big.Int).Set(bigVoterRewardRatio)
-		voterReward.Mul(voterReward, gas)
-		voterReward.Mul(voterReward, big.NewInt(voterRewardFactor*int64(committeeSize)))
-		var validatorsCount = n.cfg.GetNumOfCNs(ic.Block.Index)
-		voterReward.Div(voterReward, big.NewInt(int64(committeeSize+validatorsCount)))
-		voterReward.Div(voterReward, big100)
-
 		var (
-			cs        = cache.committee
-			isCacheRW bool
-			key       = make([]byte, 38)
+			voterReward     = n.calculateVoterReward(gas, ic.Block.Index)
+			validatorsCount = n.cfg.GetNumOfCNs(ic.Block.Index)
+			cs              = cache.committee
+			isCacheRW       bool
+			key             = make([]byte, 38)
 		)
 		for i := range cs {
 			if cs[i].Votes.Sign() > 0 {
-				var tmp = new(big.Int)
-				if i < validatorsCount {
-					tmp.Set(intTwo)
-				} else {
-					tmp.Set(intOne)
-				}
-				tmp.Mul(tmp, voterReward)
-				tmp.Div(tmp, cs[i].Votes)
+				var tmp = rewardPerVote(voterReward, i < validatorsCount, cs[i].Votes)
 
 				key = makeVoterKey([]byte(cs[i].Key), key)
 
@@ -420,6 +446,32 @@ func (n *NEO) PostPersist(ic *interop.Context) error {
 	return nil
 }
 
+// calculateVoterReward returns the total reward (multiplied by
+// voterRewardFactor) distributed to voters at the committee update block with
+// the given index.
+func (n *NEO) calculateVoterReward(gas *big.Int, index uint32) *big.Int {
+	var committeeSize = n.cfg.GetCommitteeSize(index)
+	var voterReward = new(big.Int).Set(bigVoterRewardRatio)
+	voterReward.Mul(voterReward, gas)
+	voterReward.Mul(voterReward, big.NewInt(voterRewardFactor*int64(committeeSize)))
+	var validatorsCount = n.cfg.GetNumOfCNs(index)
+	voterReward.Div(voterReward, big.NewInt(int64(committeeSize+validatorsCount)))
+	return voterReward.Div(voterReward, big100)
+}
+
+// rewardPerVote returns the part of the voter reward given for a single vote
+// for the committee member with the given number of votes.
+func rewardPerVote(voterReward *big.Int, isValidator bool, votes *big.Int) *big.Int {
+	var tmp = new(big.Int)
+	if isValidator {
+		tmp.Set(intTwo)
+	} else {
+		tmp.Set(intOne)
+	}
+	tmp.Mul(tmp, voterReward)
+	return tmp.Div(tmp, votes)
+}
+
 func (n *NEO) getGASPerVote(d *dao.Simple, key []byte, indexes []uint32) []big.Int {
 	sort.Slice(indexes, func(i, j int) bool {
 		return indexes[i] < indexes[j]
@@ -549,6 +601,34 @@ func (n *NEO) GetGASPerBlock(d *dao.Simple, index uint32) *big.Int {
 	panic("NEO cache not initialized")
 }
 
+// GetGASPerBlockHistory returns GASPerBlock schedule for blocks from `from` to
+// `to` (inclusive), that's the same piecewise schedule CalculateNEOHolderReward
+// uses. The first record always starts at `from` (even if the value was set
+// earlier), every subsequent one is a GASPerBlock change in the range.
+func (n *NEO) GetGASPerBlockHistory(d *dao.Simple, from, to uint32) ([]GASPerBlockRecord, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid range: %d > %d", from, to)
+	}
+	cache := d.GetROCache(n.ID).(*NeoCache)
+	gr := cache.gasPerBlock
+	var res []GASPerBlockRecord
+	for i := range gr {
+		if gr[i].Index > to {
+			break
+		}
+		rec := GASPerBlockRecord{
+			Index:       gr[i].Index,
+			GASPerBlock: new(big.Int).Set(&gr[i].GASPerBlock),
+		}
+		if rec.Index <= from {
+			rec.Index = from
+			res = res[:0] // Only the latest value before the range matters.
+		}
+		res = append(res, rec)
+	}
+	return res, nil
+}
+
 // GetCommitteeAddress returns address of the committee.
 func (n *NEO) GetCommitteeAddress(d *dao.Simple) util.Uint160 {
 	cache := d.GetROCache(n.ID).(*NeoCache)
@@ -656,10 +736,37 @@ func (n *NEO) CalculateBonus(d *dao.Simple, acc util.Uint160, end uint32) (*big.
 	return n.calculateBonus(d, st.VoteTo, &st.Balance, st.BalanceHeight, end)
 }
 
+// CalculateBonusBreakdown returns the same amount of GAS as CalculateBonus
+// does, but split into NEO holder reward and voter reward parts.
+func (n *NEO) CalculateBonusBreakdown(d *dao.Simple, acc util.Uint160, end uint32) (*big.Int, *big.Int, error) {
+	si := d.GetStorageItem(n.ID, makeAccountKey(acc))
+	if si == nil {
+		return nil, nil, storage.ErrKeyNotFound
+	}
+	st, err := state.NEOBalanceFromBytes(si)
+	if err != nil {
+		return nil, nil, err
+	}
+	return n.calculateBonusParts(d, st.VoteTo, &st.Balance, st.BalanceHeight, end)
+}
+
 func (n *NEO) calculateBonus(d *dao.Simple, vote *keys.PublicKey, value *big.Int, start, end uint32) (*big.Int, error) {
+	holder, voter, err := n.calculateBonusParts(d, vote, value, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return holder.Add(holder, voter), nil
+}
+
+// calculateBonusParts returns NEO holder and voter rewards for the given
+// balance held from start to end block.
+func (n *NEO) calculateBonusParts(d *dao.Simple, vote *keys.PublicKey, value *big.Int, start, end uint32) (*big.Int, *big.Int, error) {
 	r, err := n.CalculateNEOHolderReward(d, value, start, end)
-	if err != nil || vote == nil {
-		return r, err
+	if err != nil {
+		return nil, nil, err
+	}
+	if vote == nil {
+		return r, big.NewInt(0), nil
 	}
 
 	var key = makeVoterKey(vote.Bytes())
@@ -667,8 +774,52 @@ func (n *NEO) calculateBonus(d *dao.Simple, vote *keys.PublicKey, value *big.Int
 	var tmp = (&reward[1]).Sub(&reward[1], &reward[0])
 	tmp.Mul(tmp, value)
 	tmp.Div(tmp, bigVoterRewardFactor)
-	tmp.Add(tmp, r)
-	return tmp, nil
+	return r, tmp, nil
+}
+
+// CalculateVoterRewardProjection returns the amount of GAS the account gets
+// for voting (NEO holder reward is not included) from its balance height up to
+// the end block. Rewards for the blocks after the current height are estimated
+// assuming that the account state, candidate votes and committee remain the
+// same.
+func (n *NEO) CalculateVoterRewardProjection(d *dao.Simple, acc util.Uint160, height, end uint32) (*big.Int, error) {
+	si := d.GetStorageItem(n.ID, makeAccountKey(acc))
+	if si == nil {
+		return nil, storage.ErrKeyNotFound
+	}
+	st, err := state.NEOBalanceFromBytes(si)
+	if err != nil {
+		return nil, err
+	}
+	if st.VoteTo == nil || st.Balance.Sign() == 0 || st.BalanceHeight >= end {
+		return big.NewInt(0), nil
+	}
+	var (
+		pub    = st.VoteTo.Bytes()
+		reward = n.getGASPerVote(d, makeVoterKey(pub), []uint32{st.BalanceHeight, end})
+		res    = new(big.Int).Sub(&reward[1], &reward[0])
+		cs     = d.GetROCache(n.ID).(*NeoCache).committee
+	)
+	for i := range cs {
+		if cs[i].Key != string(pub) {
+			continue
+		}
+		if cs[i].Votes.Sign() <= 0 {
+			break
+		}
+		// Reward for the committee update block h is stored for h+1, so
+		// it's the first not yet persisted block that should be counted.
+		for h := height + 1; h < end; h++ {
+			if !n.cfg.ShouldUpdateCommitteeAt(h) {
+				continue
+			}
+			voterReward := n.calculateVoterReward(n.GetGASPerBlock(d, h), h)
+			res.Add(res, rewardPerVote(voterReward, i < n.cfg.GetNumOfCNs(h), cs[i].Votes))
+		}
+		break
+	}
+	res.Mul(res, &st.Balance)
+	return res.Div(res, bigVoterRewardFactor), nil
 }
 
 // CalculateNEOHolderReward return GAS reward for holding `value` of NEO from start to end block.
@@ -923,6 +1074,25 @@ func (n *NEO) getCandidatesCall(ic *interop.Context, _ []stackitem.Item) stackit
 	return stackitem.NewArray(arr)
 }
 
+// getAllCandidatesCall returns an iterator over registered candidates, its
+// values are (public key, votes) structures ordered by key.
+func (n *NEO) getAllCandidatesCall(ic *interop.Context, _ []stackitem.Item) stackitem.Item {
+	kvs, err := n.getCandidates(ic.DAO, true)
+	if err != nil {
+		panic(err)
+	}
+	ch := make(chan storage.KeyValue, len(kvs))
+	for i := range kvs {
+		votes, err := stackitem.Serialize(stackitem.NewBigInteger(kvs[i].Votes))
+		if err != nil {
+			panic(err)
+		}
+		ch <- storage.KeyValue{Key: []byte(kvs[i].Key), Value: votes}
+	}
+	close(ch)
+	return stackitem.NewInterop(istorage.NewIterator(ch, nil, istorage.FindRemovePrefix|istorage.FindDeserialize))
+}
+
 func (n *NEO) getAccountState(ic *interop.Context, args []stackitem.Item) stackitem.Item {
 	key := makeAccountKey(toUint160(args[0]))
 	si := ic.DAO.GetStorageItem(n.ID, key)
@@ -976,6 +1146,34 @@ func (n *NEO) modifyVoterTurnout(d *dao.Simple, amount *big.Int) error {
 	return nil
 }
 
+// GetCommitteeBounty returns the amount of GAS minted to one of the committee
+// members in PostPersist of the block with the given index.
+func (n *NEO) GetCommitteeBounty(d *dao.Simple, index uint32) *big.Int {
+	return committeeReward(n.GetGASPerBlock(d, index))
+}
+
+// committeeReward returns the part of GAS generated per block that goes to
+// committee.
+func committeeReward(gasPerBlock *big.Int) *big.Int {
+	r := new(big.Int).Mul(gasPerBlock, bigCommitteeRewardRatio)
+	return r.Div(r, big100)
+}
+
+// GetStoredCommittee returns public keys of nodes in committee read directly
+// from the storage bypassing the cache, which makes it usable with DAO backed
+// by historic state.
+func (n *NEO) GetStoredCommittee(d *dao.Simple) (keys.PublicKeys, error) {
+	var cvs keysWithVotes
+	si := d.GetStorageItem(n.ID, prefixCommittee)
+	if si == nil {
+		return nil, errors.New("committee not found")
+	}
+	if err := cvs.DecodeBytes(si); err != nil {
+		return nil, fmt.Errorf("failed to decode committee: %w", err)
+	}
+	return getCommitteeMembers(&NeoCache{committee: cvs}), nil
+}
+
 // GetCommitteeMembers returns public keys of nodes in committee using cached value.
 func (n *NEO) GetCommitteeMembers(d *dao.Simple) keys.PublicKeys {
 	cache := d.GetROCache(n.ID).(*NeoCache)
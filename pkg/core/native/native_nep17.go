@@ -11,6 +11,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/contract"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/runtime"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
@@ -35,6 +36,8 @@ type nep17TokenNative struct {
 	factor       int64
 	incBalance   func(*interop.Context, util.Uint160, *state.StorageItem, *big.Int, *big.Int) error
 	balFromBytes func(item *state.StorageItem) (*big.Int, error)
+	// trackSupply enables per-block total supply change records.
+	trackSupply bool
 }
 
 // totalSupplyKey is the key used to store totalSupply value.
@@ -290,6 +293,49 @@ func (c *nep17TokenNative) addTokens(ic *interop.Context, h util.Uint160, amount
 	buf, supply := c.getTotalSupply(ic.DAO)
 	supply.Add(supply, amount)
 	c.saveTotalSupply(ic.DAO, buf, supply)
+	if c.trackSupply && ic.Block != nil {
+		c.addSupplyDelta(ic.DAO, ic.Block.Index, amount, supply)
+	}
+}
+
+// addSupplyDelta adds amount to the supply change record of the block with
+// the given index. Records are stored in the same DAO as the supply itself, so
+// changes made by failed transactions are dropped along with the record ones.
+func (c *nep17TokenNative) addSupplyDelta(d *dao.Simple, index uint32, amount *big.Int, supply *big.Int) {
+	delta, err := d.GetSupplyDelta(c.ID, index)
+	if errors.Is(err, storage.ErrKeyNotFound) {
+		delta = new(state.TokenSupplyDelta)
+	} else if err != nil {
+		panic(fmt.Errorf("failed to get supply delta: %w", err))
+	}
+	delta.Delta.Add(&delta.Delta, amount)
+	delta.Supply.Set(supply)
+	if err := d.PutSupplyDelta(c.ID, index, delta); err != nil {
+		panic(err)
+	}
+}
+
+// CommitSupplyDelta ensures that the supply change record for the block with
+// the given index exists, blocks not changing the supply get a record with zero
+// delta. It's a no-op if supply tracking is disabled.
+func (c *nep17TokenNative) CommitSupplyDelta(d *dao.Simple, index uint32) error {
+	if !c.trackSupply {
+		return nil
+	}
+	_, err := d.GetSupplyDelta(c.ID, index)
+	if err == nil || !errors.Is(err, storage.ErrKeyNotFound) {
+		return err
+	}
+	delta := new(state.TokenSupplyDelta)
+	_, supply := c.getTotalSupply(d)
+	delta.Supply.Set(supply)
+	return d.PutSupplyDelta(c.ID, index, delta)
+}
+
+// GetTotalSupply returns the current total supply of the token.
+func (c *nep17TokenNative) GetTotalSupply(d *dao.Simple) *big.Int {
+	_, supply := c.getTotalSupply(d)
+	return supply
 }
 
 func newDescriptor(name string, ret smartcontract.ParamType, ps ...manifest.Parameter) *manifest.Method {
@@ -106,6 +106,77 @@ func TestLedger_GetTransaction(t *testing.T) {
 	})
 }
 
+func TestLedger_GetTransactionData(t *testing.T) {
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(cfg *config.ProtocolConfiguration) {
+		cfg.MaxTraceableBlocks = 10
+		cfg.P2PSigExtensions = true
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	c := e.CommitteeInvoker(e.NativeHash(t, nativenames.Ledger))
+
+	conflict := util.Uint256{1, 2, 3}
+	tx := e.NewUnsignedTx(t, c.Hash, "currentIndex")
+	tx.Attributes = []transaction.Attribute{
+		{Type: transaction.HighPriority},
+		{Type: transaction.NotValidBeforeT, Value: &transaction.NotValidBefore{Height: 0}},
+		{Type: transaction.ConflictsT, Value: &transaction.Conflicts{Hash: conflict}},
+	}
+	e.SignTx(t, tx, -1, c.Committee)
+	e.AddNewBlock(t, tx)
+	e.CheckHalt(t, tx.Hash())
+
+	t.Run("success", func(t *testing.T) {
+		c.Invoke(t, []stackitem.Item{
+			stackitem.NewByteArray(tx.Hash().BytesBE()),
+			stackitem.NewBigInteger(big.NewInt(int64(tx.Version))),
+			stackitem.NewBigInteger(big.NewInt(int64(tx.Nonce))),
+			stackitem.NewByteArray(tx.Sender().BytesBE()),
+			stackitem.NewBigInteger(big.NewInt(tx.SystemFee)),
+			stackitem.NewBigInteger(big.NewInt(tx.NetworkFee)),
+			stackitem.NewBigInteger(big.NewInt(int64(tx.ValidUntilBlock))),
+			stackitem.NewByteArray(tx.Script),
+			stackitem.NewArray([]stackitem.Item{
+				stackitem.NewArray([]stackitem.Item{stackitem.Make(int(transaction.HighPriority)), stackitem.NewByteArray([]byte{})}),
+				stackitem.NewArray([]stackitem.Item{stackitem.Make(int(transaction.NotValidBeforeT)), stackitem.NewByteArray([]byte{4, 0, 0, 0, 0})}),
+				stackitem.NewArray([]stackitem.Item{stackitem.Make(int(transaction.ConflictsT)), stackitem.NewByteArray(append([]byte{util.Uint256Size}, conflict.BytesBE()...))}),
+			}),
+		}, "getTransactionData", tx.Hash())
+	})
+	t.Run("interop API", func(t *testing.T) {
+		src := `package calltxdata
+		import (
+			"github.com/nspcc-dev/neo-go/pkg/interop"
+			"github.com/nspcc-dev/neo-go/pkg/interop/native/ledger"
+		)
+		func GetConflict(h interop.Hash256) []byte {
+			tx := ledger.GetTransactionData(h)
+			for _, attr := range tx.Attributes {
+				if attr.Type == 0xe1 {
+					return attr.Value[1:]
+				}
+			}
+			return nil
+		}`
+		ctr := neotest.CompileSource(t, c.Committee.ScriptHash(), strings.NewReader(src), &compiler.Options{
+			Name: "txdata_contract",
+		})
+		e.DeployContract(t, ctr, nil)
+		e.CommitteeInvoker(ctr.Hash).InvokeAndCheck(t, func(t testing.TB, stack []stackitem.Item) {
+			require.Equal(t, 1, len(stack))
+			b, err := stack[0].TryBytes()
+			require.NoError(t, err)
+			require.Equal(t, conflict.BytesBE(), b)
+		}, "getConflict", tx.Hash())
+	})
+	t.Run("isn't traceable", func(t *testing.T) {
+		e.GenerateNewBlocks(t, int(e.Chain.GetConfig().MaxTraceableBlocks))
+		c.Invoke(t, stackitem.Null{}, "getTransactionData", tx.Hash())
+	})
+	t.Run("bad hash", func(t *testing.T) {
+		c.Invoke(t, stackitem.Null{}, "getTransactionData", util.Uint256{})
+	})
+}
+
 func TestLedger_GetTransactionFromBlock(t *testing.T) {
 	c := newLedgerClient(t)
 	e := c.Executor
@@ -144,6 +215,60 @@ func TestLedger_GetTransactionFromBlock(t *testing.T) {
 	})
 }
 
+func TestLedger_GetTransactionsFromBlock(t *testing.T) {
+	c := newLedgerClient(t)
+	e := c.Executor
+	ledgerInvoker := c.WithSigners(c.Committee)
+
+	txes := make([]*transaction.Transaction, 3)
+	for i := range txes {
+		txes[i] = ledgerInvoker.PrepareInvoke(t, "currentIndex")
+	}
+	e.AddNewBlock(t, txes...)
+	b := e.GetBlockByIndex(t, int(e.Chain.BlockHeight()))
+
+	check := func(hashes ...util.Uint256) func(t testing.TB, stack []stackitem.Item) {
+		return func(t testing.TB, stack []stackitem.Item) {
+			require.Equal(t, 1, len(stack))
+			actual, ok := stack[0].Value().([]stackitem.Item)
+			require.True(t, ok)
+			require.Equal(t, len(hashes), len(actual))
+			for i, h := range hashes {
+				require.Equal(t, h.BytesBE(), actual[i].Value().([]stackitem.Item)[0].Value().([]byte))
+			}
+		}
+	}
+	t.Run("good, by hash", func(t *testing.T) {
+		ledgerInvoker.InvokeAndCheck(t, check(txes[1].Hash(), txes[2].Hash()), "getTransactionsFromBlock", b.Hash(), 1, 2)
+	})
+	t.Run("good, by index", func(t *testing.T) {
+		ledgerInvoker.InvokeAndCheck(t, check(txes[0].Hash(), txes[1].Hash(), txes[2].Hash()), "getTransactionsFromBlock", int64(b.Index), 0, 3)
+	})
+	t.Run("good, empty", func(t *testing.T) {
+		ledgerInvoker.InvokeAndCheck(t, check(), "getTransactionsFromBlock", b.Hash(), 3, 0)
+	})
+	t.Run("charged per transaction", func(t *testing.T) {
+		h1 := ledgerInvoker.InvokeAndCheck(t, check(txes[0].Hash()), "getTransactionsFromBlock", b.Hash(), 0, 1)
+		h3 := ledgerInvoker.InvokeAndCheck(t, check(txes[0].Hash(), txes[1].Hash(), txes[2].Hash()), "getTransactionsFromBlock", b.Hash(), 0, 3)
+		gas1 := e.GetTxExecResult(t, h1).GasConsumed
+		gas3 := e.GetTxExecResult(t, h3).GasConsumed
+		require.GreaterOrEqual(t, gas3-gas1, 2*int64(1<<12)*e.Chain.GetBaseExecFee())
+	})
+	t.Run("bad range", func(t *testing.T) {
+		ledgerInvoker.InvokeFail(t, "wrong transactions range", "getTransactionsFromBlock", b.Hash(), 2, 2)
+		ledgerInvoker.InvokeFail(t, "wrong transactions range", "getTransactionsFromBlock", b.Hash(), 4, 0)
+		ledgerInvoker.InvokeFail(t, "", "getTransactionsFromBlock", b.Hash(), -1, 2)
+		ledgerInvoker.InvokeFail(t, "", "getTransactionsFromBlock", b.Hash(), 0, -1)
+	})
+	t.Run("unknown block hash", func(t *testing.T) {
+		ledgerInvoker.Invoke(t, stackitem.Null{}, "getTransactionsFromBlock", b.Hash().BytesLE(), 0, 1)
+	})
+	t.Run("isn't traceable", func(t *testing.T) {
+		e.GenerateNewBlocks(t, int(e.Chain.GetConfig().MaxTraceableBlocks))
+		ledgerInvoker.Invoke(t, stackitem.Null{}, "getTransactionsFromBlock", b.Hash(), 0, 1)
+	})
+}
+
 func TestLedger_GetBlock(t *testing.T) {
 	c := newLedgerClient(t)
 	e := c.Executor
@@ -178,6 +303,43 @@ func TestLedger_GetBlock(t *testing.T) {
 	})
 }
 
+func TestLedger_GetBlockHeader(t *testing.T) {
+	c := newLedgerClient(t)
+	e := c.Executor
+	ledgerInvoker := c.WithSigners(c.Committee)
+
+	ledgerInvoker.Invoke(t, e.Chain.GetHeaderHash(int(e.Chain.BlockHeight())).BytesBE(), "currentHash") // Adds a block.
+	b := e.GetBlockByIndex(t, int(e.Chain.BlockHeight()))
+	require.NotEqual(t, 0, len(b.Transactions))
+
+	expected := []stackitem.Item{
+		stackitem.NewByteArray(b.Hash().BytesBE()),
+		stackitem.NewBigInteger(big.NewInt(int64(b.Version))),
+		stackitem.NewByteArray(b.PrevHash.BytesBE()),
+		stackitem.NewByteArray(b.MerkleRoot.BytesBE()),
+		stackitem.NewBigInteger(big.NewInt(int64(b.Timestamp))),
+		stackitem.NewBigInteger(big.NewInt(int64(b.Nonce))),
+		stackitem.NewBigInteger(big.NewInt(int64(b.Index))),
+		stackitem.NewByteArray(b.NextConsensus.BytesBE()),
+	}
+	t.Run("good, by hash", func(t *testing.T) {
+		ledgerInvoker.Invoke(t, expected, "getBlockHeader", b.Hash())
+	})
+	t.Run("good, by index", func(t *testing.T) {
+		ledgerInvoker.Invoke(t, expected, "getBlockHeader", int64(b.Index))
+	})
+	t.Run("bad hash", func(t *testing.T) {
+		ledgerInvoker.Invoke(t, stackitem.Null{}, "getBlockHeader", b.Hash().BytesLE())
+	})
+	t.Run("future block", func(t *testing.T) {
+		ledgerInvoker.InvokeFail(t, "no block with index", "getBlockHeader", int64(e.Chain.BlockHeight()+1))
+	})
+	t.Run("isn't traceable", func(t *testing.T) {
+		e.GenerateNewBlocks(t, int(e.Chain.GetConfig().MaxTraceableBlocks))
+		ledgerInvoker.Invoke(t, stackitem.Null{}, "getBlockHeader", b.Hash())
+	})
+}
+
 func TestLedger_GetTransactionSigners(t *testing.T) {
 	c := newLedgerClient(t)
 	e := c.Executor
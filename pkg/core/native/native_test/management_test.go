@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/internal/contracts"
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/chaindump"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
@@ -21,6 +22,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
@@ -508,6 +510,95 @@ func TestManagement_ContractUpdate(t *testing.T) {
 	})
 }
 
+func TestManagement_DeployCallTokens(t *testing.T) {
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		c.ValidateCallTokensHeight = 1
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	managementInvoker := e.CommitteeInvoker(e.NativeHash(t, nativenames.Management))
+	neoHash := e.NativeHash(t, nativenames.Neo)
+	target := util.Uint160{1, 2, 3}
+
+	pkey, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	getBytes := func(t *testing.T, name string, tokens []nef.MethodToken, perms ...manifest.Permission) ([]byte, []byte) {
+		ne, err := nef.NewFile([]byte{byte(opcode.PUSH1), byte(opcode.RET)})
+		require.NoError(t, err)
+		ne.Tokens = tokens
+		ne.Checksum = ne.CalculateChecksum()
+		nefBytes, err := ne.Bytes()
+		require.NoError(t, err)
+
+		m := manifest.NewManifest(name)
+		m.ABI.Methods = []manifest.Method{{
+			Name:       "main",
+			ReturnType: smartcontract.IntegerType,
+		}}
+		m.Permissions = perms
+		manifBytes, err := json.Marshal(m)
+		require.NoError(t, err)
+		return nefBytes, manifBytes
+	}
+	token := nef.MethodToken{Hash: target, Method: "do", ParamCount: 1, CallFlag: callflag.All}
+	checkDeployed := func(t testing.TB, stack []stackitem.Item) {
+		require.Equal(t, 1, len(stack))
+		_, ok := stack[0].(*stackitem.Array)
+		require.True(t, ok)
+	}
+
+	t.Run("not in permissions", func(t *testing.T) {
+		nefBytes, manifBytes := getBytes(t, "absent", []nef.MethodToken{token},
+			*manifest.NewPermission(manifest.PermissionHash, neoHash))
+		managementInvoker.InvokeFail(t, "method token #0 ("+target.StringLE()+", do) is not allowed by manifest permissions",
+			"deploy", nefBytes, manifBytes)
+	})
+	t.Run("method not in permissions", func(t *testing.T) {
+		p := manifest.NewPermission(manifest.PermissionHash, target)
+		p.Methods.Add("other")
+		nefBytes, manifBytes := getBytes(t, "method", []nef.MethodToken{token}, *p)
+		managementInvoker.InvokeFail(t, "is not allowed by manifest permissions", "deploy", nefBytes, manifBytes)
+	})
+	t.Run("native parameters mismatch", func(t *testing.T) {
+		nefBytes, manifBytes := getBytes(t, "native", []nef.MethodToken{{
+			Hash: neoHash, Method: "balanceOf", ParamCount: 2, CallFlag: callflag.ReadStates,
+		}}, *manifest.NewPermission(manifest.PermissionWildcard))
+		managementInvoker.InvokeFail(t, "native contract has no method with 2 parameters", "deploy", nefBytes, manifBytes)
+	})
+	t.Run("wildcard", func(t *testing.T) {
+		nefBytes, manifBytes := getBytes(t, "wildcard", []nef.MethodToken{token, {
+			Hash: neoHash, Method: "balanceOf", ParamCount: 1, CallFlag: callflag.ReadStates,
+		}}, *manifest.NewPermission(manifest.PermissionWildcard))
+		managementInvoker.InvokeAndCheck(t, checkDeployed, "deploy", nefBytes, manifBytes)
+	})
+	t.Run("group", func(t *testing.T) {
+		nefBytes, manifBytes := getBytes(t, "group", []nef.MethodToken{token},
+			*manifest.NewPermission(manifest.PermissionGroup, pkey.PublicKey()))
+		managementInvoker.InvokeAndCheck(t, checkDeployed, "deploy", nefBytes, manifBytes)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		c := newManagementClient(t)
+		nefBytes, manifBytes := getBytes(t, "absent", []nef.MethodToken{token},
+			*manifest.NewPermission(manifest.PermissionHash, neoHash))
+		c.InvokeAndCheck(t, checkDeployed, "deploy", nefBytes, manifBytes)
+	})
+	t.Run("not yet enabled", func(t *testing.T) {
+		bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+			c.ValidateCallTokensHeight = 5
+		})
+		e := neotest.NewExecutor(t, bc, acc, acc)
+		c := e.CommitteeInvoker(e.NativeHash(t, nativenames.Management))
+		nefBytes, manifBytes := getBytes(t, "early", []nef.MethodToken{token},
+			*manifest.NewPermission(manifest.PermissionHash, neoHash))
+		c.InvokeAndCheck(t, checkDeployed, "deploy", nefBytes, manifBytes)
+
+		e.GenerateNewBlocks(t, 3)
+		nefBytes, manifBytes = getBytes(t, "late", []nef.MethodToken{token},
+			*manifest.NewPermission(manifest.PermissionHash, neoHash))
+		c.InvokeFail(t, "is not allowed by manifest permissions", "deploy", nefBytes, manifBytes)
+	})
+}
+
 func TestManagement_GetContract(t *testing.T) {
 	c := newManagementClient(t)
 	managementInvoker := c.WithSigners(c.Committee)
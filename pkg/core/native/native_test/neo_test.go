@@ -1,6 +1,7 @@
 package native_test
 
 import (
+	"bytes"
 	"encoding/json"
 	"math"
 	"math/big"
@@ -9,6 +10,9 @@ import (
 
 	"github.com/nspcc-dev/neo-go/internal/contracts"
 	"github.com/nspcc-dev/neo-go/internal/random"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
 	"github.com/nspcc-dev/neo-go/pkg/core/native"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
@@ -23,7 +27,11 @@ import (
 )
 
 func newNeoCommitteeClient(t *testing.T, expectedGASBalance int) *neotest.ContractInvoker {
-	bc, validators, committee := chain.NewMulti(t)
+	return newNeoCommitteeClientWithConfig(t, expectedGASBalance, nil)
+}
+
+func newNeoCommitteeClientWithConfig(t *testing.T, expectedGASBalance int, f func(*config.ProtocolConfiguration)) *neotest.ContractInvoker {
+	bc, validators, committee := chain.NewMultiWithCustomConfig(t, f)
 	e := neotest.NewExecutor(t, bc, validators, committee)
 
 	if expectedGASBalance > 0 {
@@ -54,6 +62,85 @@ func TestNEO_RegisterPriceCache(t *testing.T) {
 	testGetSetCache(t, newNeoCommitteeClient(t, 100_0000_0000), "RegisterPrice", native.DefaultRegisterPrice)
 }
 
+func TestNEO_RegisterPriceCharged(t *testing.T) {
+	neoCommitteeInvoker := newNeoCommitteeClient(t, 100_0000_0000)
+	neoValidatorsInvoker := neoCommitteeInvoker.WithSigners(neoCommitteeInvoker.Validator)
+	e := neoCommitteeInvoker.Executor
+
+	register := func(t *testing.T) int64 {
+		candidate := e.NewAccount(t, 2000_0000_0000)
+		pub := candidate.(neotest.SingleSigner).Account().PrivateKey().PublicKey()
+		h := neoValidatorsInvoker.WithSigners(candidate).Invoke(t, true, "registerCandidate", pub.Bytes())
+		return e.GetTxExecResult(t, h).GasConsumed
+	}
+
+	defaultConsumed := register(t)
+	require.Greater(t, defaultConsumed, int64(native.DefaultRegisterPrice))
+
+	newPrice := int64(10 * native.GASFactor)
+	neoCommitteeInvoker.Invoke(t, stackitem.Null{}, "setRegisterPrice", newPrice)
+	require.Equal(t, defaultConsumed-native.DefaultRegisterPrice+newPrice, register(t))
+}
+
+func TestNEO_GetAllCandidates(t *testing.T) {
+	neoCommitteeInvoker := newNeoCommitteeClient(t, 100_0000_0000)
+	neoValidatorsInvoker := neoCommitteeInvoker.WithSigners(neoCommitteeInvoker.Validator)
+	e := neoCommitteeInvoker.Executor
+
+	getAll := func(t *testing.T) []stackitem.Item {
+		s, err := neoCommitteeInvoker.TestInvoke(t, "getAllCandidates")
+		require.NoError(t, err)
+		require.Equal(t, 1, s.Len())
+		iter := s.Pop().Interop().Value().(interface {
+			Next() bool
+			Value() stackitem.Item
+		})
+		var res []stackitem.Item
+		for iter.Next() {
+			res = append(res, iter.Value())
+		}
+		return res
+	}
+	require.Equal(t, 0, len(getAll(t)))
+
+	pubs := make(keys.PublicKeys, 3)
+	for i := range pubs {
+		candidate := e.NewAccount(t, 2000_0000_0000)
+		pubs[i] = candidate.(neotest.SingleSigner).Account().PrivateKey().PublicKey()
+		neoValidatorsInvoker.WithSigners(candidate).Invoke(t, true, "registerCandidate", pubs[i].Bytes())
+	}
+	voted := pubs[1]
+	neoValidatorsInvoker.Invoke(t, true, "vote", e.Validator.ScriptHash(), voted.Bytes())
+	votes, _ := e.Chain.GetGoverningTokenBalance(e.Validator.ScriptHash())
+	require.Equal(t, 1, votes.Sign())
+	sort.Slice(pubs, func(i, j int) bool { // Storage order.
+		return bytes.Compare(pubs[i].Bytes(), pubs[j].Bytes()) < 0
+	})
+
+	// Unregistered candidates are not returned.
+	unregistered := e.NewAccount(t, 2000_0000_0000)
+	unregisteredPub := unregistered.(neotest.SingleSigner).Account().PrivateKey().PublicKey()
+	neoValidatorsInvoker.WithSigners(unregistered).Invoke(t, true, "registerCandidate", unregisteredPub.Bytes())
+	neoValidatorsInvoker.WithSigners(unregistered).Invoke(t, true, "unregisterCandidate", unregisteredPub.Bytes())
+
+	all := getAll(t)
+	require.Equal(t, len(pubs), len(all))
+	for i, item := range all {
+		fields := item.Value().([]stackitem.Item)
+		require.Equal(t, 2, len(fields))
+		key, err := fields[0].TryBytes()
+		require.NoError(t, err)
+		require.Equal(t, pubs[i].Bytes(), key)
+		v, err := fields[1].TryInteger()
+		require.NoError(t, err)
+		expected := big.NewInt(0)
+		if pubs[i].Equal(voted) {
+			expected = votes
+		}
+		require.Equal(t, 0, expected.Cmp(v), i)
+	}
+}
+
 func TestNEO_Vote(t *testing.T) {
 	neoCommitteeInvoker := newNeoCommitteeClient(t, 100_0000_0000)
 	neoValidatorsInvoker := neoCommitteeInvoker.WithSigners(neoCommitteeInvoker.Validator)
@@ -146,6 +233,28 @@ func TestNEO_Vote(t *testing.T) {
 	require.NoError(t, err)
 	require.EqualValues(t, sortedCandidates, pubs)
 
+	t.Run("claimable breakdown", func(t *testing.T) {
+		bc := e.Chain.(*core.Blockchain)
+		end := bc.BlockHeight() + 1
+		h := voters[1].ScriptHash()
+		holder, voter, err := bc.CalculateClaimableBreakdown(h, end)
+		require.NoError(t, err)
+		require.True(t, holder.Sign() > 0)
+		require.True(t, voter.Sign() > 0)
+		total, err := bc.CalculateClaimable(h, end)
+		require.NoError(t, err)
+		require.Equal(t, total, new(big.Int).Add(holder, voter))
+
+		// Reference account doesn't vote.
+		holder, voter, err = bc.CalculateClaimableBreakdown(referenceAccounts[1].ScriptHash(), end)
+		require.NoError(t, err)
+		require.True(t, holder.Sign() > 0)
+		require.Equal(t, 0, voter.Sign())
+
+		_, _, err = bc.CalculateClaimableBreakdown(random.Uint160(), end)
+		require.Error(t, err)
+	})
+
 	t.Run("check voter rewards", func(t *testing.T) {
 		gasBalance := make([]*big.Int, len(voters)-1)
 		referenceGASBalance := make([]*big.Int, len(referenceAccounts)-1)
@@ -198,6 +307,40 @@ func TestNEO_Vote(t *testing.T) {
 		}
 	})
 
+	t.Run("voter reward projection", func(t *testing.T) {
+		// Voters for a validator and for a non-validator committee member.
+		bc := e.Chain.(*core.Blockchain)
+		accs := []util.Uint160{voters[1].ScriptHash(), voters[validatorsCount].ScriptHash()}
+		end := bc.BlockHeight() + uint32(2*committeeSize) + 1
+		projections := make([]*big.Int, len(accs))
+		for i, h := range accs {
+			p, err := bc.GetVoterRewardProjection(h, end)
+			require.NoError(t, err)
+			require.True(t, p.Sign() > 0)
+			projections[i] = p
+		}
+		// Validator voter gets more.
+		require.Equal(t, 1, projections[0].Cmp(projections[1]))
+
+		// Projections match the actual rewards stored for the persisted blocks.
+		for bc.BlockHeight() < end-1 {
+			neoCommitteeInvoker.AddNewBlock(t)
+		}
+		for i, h := range accs {
+			p, err := bc.GetVoterRewardProjection(h, end)
+			require.NoError(t, err)
+			require.Equal(t, projections[i], p)
+		}
+
+		_, err := bc.GetVoterRewardProjection(accs[0], bc.BlockHeight())
+		require.Error(t, err)
+		p, err := bc.GetVoterRewardProjection(referenceAccounts[0].ScriptHash(), end+uint32(committeeSize))
+		require.NoError(t, err)
+		require.Equal(t, 0, p.Sign())
+		_, err = bc.GetVoterRewardProjection(random.Uint160(), end)
+		require.Error(t, err)
+	})
+
 	neoCommitteeInvoker.WithSigners(candidates[0]).Invoke(t, true, "unregisterCandidate", candidates[0].(neotest.SingleSigner).Account().PrivateKey().PublicKey().Bytes())
 	neoCommitteeInvoker.WithSigners(voters[0]).Invoke(t, false, "vote", voters[0].(neotest.SingleSigner).Account().PrivateKey().GetScriptHash(), candidates[0].(neotest.SingleSigner).Account().PrivateKey().PublicKey().Bytes())
 
@@ -211,6 +354,89 @@ func TestNEO_Vote(t *testing.T) {
 	}
 }
 
+func TestNEO_ValidatorsChangedEvent(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) { testValidatorsChangedEvent(t, true) })
+	t.Run("disabled", func(t *testing.T) { testValidatorsChangedEvent(t, false) })
+}
+
+func testValidatorsChangedEvent(t *testing.T, enabled bool) {
+	neoCommitteeInvoker := newNeoCommitteeClientWithConfig(t, 100_0000_0000, func(c *config.ProtocolConfiguration) {
+		c.ValidatorsChangedEvents = enabled
+	})
+	neoValidatorsInvoker := neoCommitteeInvoker.WithSigners(neoCommitteeInvoker.Validator)
+	e := neoCommitteeInvoker.Executor
+
+	cfg := e.Chain.GetConfig()
+	committeeSize := cfg.GetCommitteeSize(0)
+
+	standBy, err := e.Chain.GetNextBlockValidators()
+	require.NoError(t, err)
+
+	// Enough NEO is voted and enough candidates are registered to replace
+	// the standby validators.
+	voter := e.NewAccount(t, 10_0000_0000)
+	candidates := make([]neotest.Signer, committeeSize)
+	txes := make([]*transaction.Transaction, 0, committeeSize+2)
+	for i := range candidates {
+		candidates[i] = e.NewAccount(t, 2000_0000_0000)
+		pub := candidates[i].(neotest.SingleSigner).Account().PrivateKey().PublicKey().Bytes()
+		txes = append(txes, neoValidatorsInvoker.WithSigners(candidates[i]).PrepareInvoke(t, "registerCandidate", pub))
+	}
+	txes = append(txes, neoValidatorsInvoker.PrepareInvoke(t, "transfer", e.Validator.ScriptHash(), voter.ScriptHash(), native.NEOTotalSupply/4, nil))
+	txes = append(txes, neoValidatorsInvoker.WithSigners(voter).PrepareInvoke(t, "vote", voter.ScriptHash(),
+		candidates[0].(neotest.SingleSigner).Account().PrivateKey().PublicKey().Bytes()))
+
+	var (
+		events   []state.NotificationEvent
+		eventIdx []uint32
+	)
+	checkBlock := func(t *testing.T, b *block.Block) {
+		aers, err := e.Chain.GetAppExecResults(b.Hash(), trigger.OnPersist)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(aers))
+		for _, ev := range aers[0].Events {
+			if ev.Name == native.ValidatorsChangedEventName {
+				events = append(events, ev)
+				eventIdx = append(eventIdx, b.Index)
+			}
+		}
+	}
+	checkBlock(t, neoValidatorsInvoker.AddNewBlock(t, txes...))
+	for _, tx := range txes {
+		e.CheckHalt(t, tx.Hash(), stackitem.Make(true))
+	}
+	// Two committee update periods: the set changes at the first one only.
+	for i := 0; i < 2*committeeSize; i++ {
+		checkBlock(t, neoCommitteeInvoker.AddNewBlock(t))
+	}
+
+	newVals, err := e.Chain.GetNextBlockValidators()
+	require.NoError(t, err)
+	require.NotEqual(t, standBy, newVals)
+
+	ev := e.Chain.GetContractState(neoCommitteeInvoker.Hash).Manifest.ABI.GetEvent(native.ValidatorsChangedEventName)
+	if !enabled {
+		require.Nil(t, ev)
+		require.Equal(t, 0, len(events))
+		return
+	}
+	require.NotNil(t, ev)
+	require.Equal(t, 1, len(events))
+	require.True(t, cfg.ShouldUpdateCommitteeAt(eventIdx[0]))
+	require.Equal(t, e.NativeHash(t, nativenames.Neo), events[0].ScriptHash)
+	arr := events[0].Item.Value().([]stackitem.Item)
+	require.Equal(t, 2, len(arr))
+	checkKeys := func(t *testing.T, expected []*keys.PublicKey, item stackitem.Item) {
+		items := item.Value().([]stackitem.Item)
+		require.Equal(t, len(expected), len(items))
+		for i := range items {
+			require.Equal(t, expected[i].Bytes(), items[i].Value().([]byte))
+		}
+	}
+	checkKeys(t, standBy, arr[0])
+	checkKeys(t, newVals, arr[1])
+}
+
 // TestNEO_RecursiveDistribution is a test for https://github.com/nspcc-dev/neo-go/pull/2181.
 func TestNEO_RecursiveGASMint(t *testing.T) {
 	neoCommitteeInvoker := newNeoCommitteeClient(t, 100_0000_0000)
@@ -259,6 +485,49 @@ func TestNEO_GetAccountState(t *testing.T) {
 			stackitem.Null{},
 		}), "getAccountState", acc.ScriptHash())
 	})
+
+	t.Run("vote and transfer", func(t *testing.T) {
+		neoID := e.Chain.GetContractState(neoValidatorInvoker.Hash).ID
+		// checkState checks the method result against the stored account state.
+		checkState := func(t *testing.T, acc util.Uint160, amount int64, height uint32, vote *keys.PublicKey) {
+			voteItem := stackitem.Item(stackitem.Null{})
+			if vote != nil {
+				voteItem = stackitem.NewByteArray(vote.Bytes())
+			}
+			neoValidatorInvoker.Invoke(t, stackitem.NewStruct([]stackitem.Item{
+				stackitem.Make(amount),
+				stackitem.Make(height),
+				voteItem,
+			}), "getAccountState", acc)
+
+			si := e.Chain.GetStorageItem(neoID, append([]byte{20}, acc.BytesBE()...))
+			st, err := state.NEOBalanceFromBytes(si)
+			require.NoError(t, err)
+			require.Equal(t, big.NewInt(amount), &st.Balance)
+			require.Equal(t, height, st.BalanceHeight)
+			require.Equal(t, vote, st.VoteTo)
+		}
+
+		candidate := e.NewAccount(t, 2000_0000_0000)
+		candidatePub := candidate.(neotest.SingleSigner).Account().PrivateKey().PublicKey()
+		neoValidatorInvoker.WithSigners(candidate).Invoke(t, true, "registerCandidate", candidatePub.Bytes())
+
+		acc := e.NewAccount(t)
+		neoValidatorInvoker.Invoke(t, true, "transfer", e.Validator.ScriptHash(), acc.ScriptHash(), 10, nil)
+		transferHeight := e.Chain.BlockHeight()
+		checkState(t, acc.ScriptHash(), 10, transferHeight, nil)
+
+		// Voting distributes GAS, so the balance height is updated too.
+		neoValidatorInvoker.WithSigners(acc).Invoke(t, true, "vote", acc.ScriptHash(), candidatePub.Bytes())
+		voteHeight := e.Chain.BlockHeight()
+		checkState(t, acc.ScriptHash(), 10, voteHeight, candidatePub)
+
+		e.AddNewBlock(t)
+		neoValidatorInvoker.WithSigners(acc).Invoke(t, true, "transfer", acc.ScriptHash(), e.Validator.ScriptHash(), 3, nil)
+		transferHeight = e.Chain.BlockHeight()
+		require.Less(t, voteHeight, transferHeight)
+		checkState(t, acc.ScriptHash(), 7, transferHeight, candidatePub)
+	})
 }
 
 func TestNEO_CommitteeBountyOnPersist(t *testing.T) {
@@ -482,3 +751,54 @@ func TestNEO_CalculateBonus(t *testing.T) {
 			claimTx.SystemFee-claimTx.NetworkFee + +firstPart + secondPart))
 	})
 }
+
+func TestNEO_GetGASPerBlockHistory(t *testing.T) {
+	neoCommitteeInvoker := newNeoCommitteeClient(t, 10_0000_0000)
+	e := neoCommitteeInvoker.Executor
+	bc := e.Chain.(*core.Blockchain)
+	rewardDistance := 10
+	defaultGASPerBlock := big.NewInt(5 * native.GASFactor)
+	newGASPerBlock := big.NewInt(1 * native.GASFactor)
+
+	start := bc.BlockHeight()
+	for i := 0; i < rewardDistance/2-2; i++ {
+		e.AddNewBlock(t)
+	}
+	neoCommitteeInvoker.Invoke(t, stackitem.Null{}, "setGasPerBlock", newGASPerBlock)
+	changed := bc.BlockHeight() + 1 // New value is used starting from the next block.
+	for i := 0; i < rewardDistance/2; i++ {
+		e.AddNewBlock(t)
+	}
+	end := bc.BlockHeight()
+
+	t.Run("before change", func(t *testing.T) {
+		h, err := bc.GetGASPerBlockHistory(start, changed-1)
+		require.NoError(t, err)
+		require.Equal(t, []native.GASPerBlockRecord{{Index: start, GASPerBlock: defaultGASPerBlock}}, h)
+	})
+	t.Run("after change", func(t *testing.T) {
+		h, err := bc.GetGASPerBlockHistory(changed+1, end)
+		require.NoError(t, err)
+		require.Equal(t, []native.GASPerBlockRecord{{Index: changed + 1, GASPerBlock: newGASPerBlock}}, h)
+	})
+	t.Run("many blocks", func(t *testing.T) {
+		h, err := bc.GetGASPerBlockHistory(start, end)
+		require.NoError(t, err)
+		require.Equal(t, []native.GASPerBlockRecord{
+			{Index: start, GASPerBlock: defaultGASPerBlock},
+			{Index: changed, GASPerBlock: newGASPerBlock},
+		}, h)
+	})
+	t.Run("from genesis", func(t *testing.T) {
+		h, err := bc.GetGASPerBlockHistory(0, changed)
+		require.NoError(t, err)
+		require.Equal(t, []native.GASPerBlockRecord{
+			{Index: 0, GASPerBlock: defaultGASPerBlock},
+			{Index: changed, GASPerBlock: newGASPerBlock},
+		}, h)
+	})
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := bc.GetGASPerBlockHistory(end, start)
+		require.Error(t, err)
+	})
+}
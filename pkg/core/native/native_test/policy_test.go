@@ -1,18 +1,37 @@
 package native_test
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/core/native"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
 )
 
 func newPolicyClient(t *testing.T) *neotest.ContractInvoker {
-	return newNativeClient(t, nativenames.Policy)
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		c.PolicyExtensions = true
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	return e.CommitteeInvoker(e.NativeHash(t, nativenames.Policy))
 }
 
 func TestPolicy_FeePerByte(t *testing.T) {
@@ -39,6 +58,359 @@ func TestPolicy_StoragePriceCache(t *testing.T) {
 	testGetSetCache(t, newPolicyClient(t), "StoragePrice", native.DefaultStoragePrice)
 }
 
+func TestPolicy_MaxBlockSystemFee(t *testing.T) {
+	testGetSet(t, newPolicyClient(t), "MaxBlockSystemFee", 9000_00000000, 1, 100000_00000000)
+}
+
+func TestPolicy_MaxBlockSystemFeeCache(t *testing.T) {
+	testGetSetCache(t, newPolicyClient(t), "MaxBlockSystemFee", 9000_00000000)
+}
+
+func TestPolicy_ExtensionsDisabled(t *testing.T) {
+	c := newNativeClient(t, nativenames.Policy)
+	for _, m := range []struct {
+		name string
+		args []interface{}
+	}{
+		{"getMaxBlockSystemFee", nil},
+		{"setMaxBlockSystemFee", []interface{}{1}},
+		{"isBlockedMulti", []interface{}{[]interface{}{}}},
+		{"getBlockedAccounts", []interface{}{0, 1}},
+		{"getAttributeFee", []interface{}{int64(transaction.HighPriority)}},
+		{"setAttributeFee", []interface{}{int64(transaction.HighPriority), 1}},
+		{"getMaintenanceMode", nil},
+		{"setMaintenanceMode", []interface{}{true}},
+		{"isMaintenanceExempt", []interface{}{util.Uint160{}}},
+		{"addMaintenanceExempt", []interface{}{util.Uint160{}}},
+		{"removeMaintenanceExempt", []interface{}{util.Uint160{}}},
+		{"enableAttribute", []interface{}{int64(transaction.HighPriority)}},
+		{"disableAttribute", []interface{}{int64(transaction.HighPriority)}},
+	} {
+		c.InvokeFail(t, "method not found", m.name, m.args...)
+	}
+	bc := c.Chain.(*core.Blockchain)
+	require.Equal(t, bc.GetConfig().MaxBlockSystemFee, bc.GetMaxBlockSystemFee())
+}
+
+func TestPolicy_MaxBlockSystemFeeCheck(t *testing.T) {
+	c := newPolicyClient(t)
+	e := c.Executor
+	committeeInvoker := c.WithSigners(c.Committee)
+
+	const limit = 1_0000_0000
+	committeeInvoker.Invoke(t, stackitem.Null{}, "setMaxBlockSystemFee", limit)
+
+	t.Run("transaction", func(t *testing.T) {
+		// It's a block-level limit, a single transaction exceeding it
+		// is valid, but it's never included into a block.
+		tx := e.NewUnsignedTx(t, c.Hash, "getMaxBlockSystemFee")
+		e.SignTx(t, tx, limit+1, c.Committee)
+		require.NoError(t, e.Chain.VerifyTx(tx))
+		require.Equal(t, 0, len(e.Chain.ApplyPolicyToTxSet([]*transaction.Transaction{tx})))
+	})
+	t.Run("block", func(t *testing.T) {
+		tx1 := e.NewUnsignedTx(t, c.Hash, "getMaxBlockSystemFee")
+		e.SignTx(t, tx1, limit/2+1, c.Committee)
+		tx2 := e.NewUnsignedTx(t, c.Hash, "getMaxBlockSystemFee")
+		e.SignTx(t, tx2, limit/2+1, c.Committee)
+		require.NoError(t, e.Chain.VerifyTx(tx1))
+		require.NoError(t, e.Chain.VerifyTx(tx2))
+
+		b := e.SignBlock(e.NewUnsignedBlock(t, tx1, tx2))
+		err := e.Chain.AddBlock(b)
+		require.Error(t, err)
+		require.True(t, strings.Contains(err.Error(), "exceeds MaxBlockSystemFee"), "got: %v", err)
+	})
+}
+
+func TestPolicy_AttributeFee(t *testing.T) {
+	c := newPolicyClient(t)
+	e := c.Executor
+	committeeInvoker := c.WithSigners(c.Committee)
+	randomInvoker := c.WithSigners(c.NewAccount(t))
+	const attr = int64(transaction.HighPriority)
+
+	t.Run("get", func(t *testing.T) {
+		randomInvoker.Invoke(t, 0, "getAttributeFee", attr)
+		randomInvoker.InvokeFail(t, "unknown attribute type", "getAttributeFee", 0x12)
+		randomInvoker.InvokeFail(t, "unknown attribute type", "getAttributeFee", 0x101)
+	})
+	t.Run("set, bad value", func(t *testing.T) {
+		committeeInvoker.InvokeFail(t, "shouldn't be negative", "setAttributeFee", attr, -1)
+		committeeInvoker.InvokeFail(t, "shouldn't be negative", "setAttributeFee", attr, 10_00000001)
+		committeeInvoker.InvokeFail(t, "unknown attribute type", "setAttributeFee", 0x12, 1)
+		committeeInvoker.InvokeFail(t, "OracleResponse attribute fee can't be set", "setAttributeFee", int64(transaction.OracleResponseT), 1)
+	})
+	t.Run("set, not signed by committee", func(t *testing.T) {
+		randomInvoker.InvokeFail(t, "invalid committee signature", "setAttributeFee", attr, 1)
+	})
+
+	const fee = 1_0000_0000
+	h := committeeInvoker.Invoke(t, stackitem.Null{}, "setAttributeFee", attr, fee)
+	c.CheckTxNotificationEvent(t, h, 0, state.NotificationEvent{
+		ScriptHash: c.Hash,
+		Name:       native.AttributeFeeChangedEventName,
+		Item: stackitem.NewArray([]stackitem.Item{
+			stackitem.Make(attr), stackitem.Make(0), stackitem.Make(fee),
+		}),
+	})
+	h = committeeInvoker.Invoke(t, stackitem.Null{}, "setAttributeFee", attr, fee)
+	require.Equal(t, 0, len(c.GetTxExecResult(t, h).Events))
+	randomInvoker.Invoke(t, fee, "getAttributeFee", attr)
+	randomInvoker.Invoke(t, 0, "getAttributeFee", int64(transaction.ConflictsT))
+
+	newTx := func(t *testing.T, netFeeDelta int64) *transaction.Transaction {
+		tx := e.NewUnsignedTx(t, c.Hash, "getFeePerByte")
+		tx.Attributes = []transaction.Attribute{{Type: transaction.HighPriority}}
+		tx.Signers = []transaction.Signer{{Account: c.Committee.ScriptHash()}}
+		neotest.AddNetworkFee(e.Chain, tx, c.Committee)
+		neotest.AddSystemFee(e.Chain, tx, -1)
+		tx.NetworkFee += netFeeDelta
+		require.NoError(t, c.Committee.SignTx(e.Chain.GetConfig().Magic, tx))
+		return tx
+	}
+	t.Run("transaction, insufficient fee", func(t *testing.T) {
+		// Attribute fee is not left for witness verification.
+		tx := newTx(t, -1)
+		require.Error(t, e.Chain.VerifyTx(tx))
+
+		tx = newTx(t, -tx.NetworkFee+1)
+		err := e.Chain.VerifyTx(tx)
+		require.True(t, errors.Is(err, core.ErrTxSmallNetworkFee), "got: %v", err)
+	})
+	t.Run("transaction, good", func(t *testing.T) {
+		tx := newTx(t, 0)
+		require.Equal(t, int64(fee), e.Chain.GetAttributesFee(tx))
+		e.AddNewBlock(t, tx)
+		e.CheckHalt(t, tx.Hash())
+	})
+}
+
+func TestPolicy_MaintenanceMode(t *testing.T) {
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		c.PolicyExtensions = true
+		c.MaintenanceModeEvents = true
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	c := e.CommitteeInvoker(e.NativeHash(t, nativenames.Policy))
+	ordinary := e.NewAccount(t)
+	exempt := e.NewAccount(t)
+	randomInvoker := c.WithSigners(ordinary)
+
+	newTx := func(t *testing.T, signer neotest.Signer) *transaction.Transaction {
+		tx := e.NewUnsignedTx(t, c.Hash, "getFeePerByte")
+		tx.ValidUntilBlock += 10
+		return e.SignTx(t, tx, -1, signer)
+	}
+	checkEvent := func(t *testing.T, h util.Uint256, enabled bool) {
+		c.CheckTxNotificationEvent(t, h, 0, state.NotificationEvent{
+			ScriptHash: c.Hash,
+			Name:       native.MaintenanceModeChangedEventName,
+			Item:       stackitem.NewArray([]stackitem.Item{stackitem.NewBool(enabled)}),
+		})
+	}
+
+	t.Run("not signed by committee", func(t *testing.T) {
+		randomInvoker.InvokeFail(t, "invalid committee signature", "setMaintenanceMode", true)
+		randomInvoker.InvokeFail(t, "invalid committee signature", "addMaintenanceExempt", exempt.ScriptHash())
+		randomInvoker.InvokeFail(t, "invalid committee signature", "removeMaintenanceExempt", exempt.ScriptHash())
+	})
+
+	c.Invoke(t, true, "addMaintenanceExempt", exempt.ScriptHash())
+	c.Invoke(t, false, "addMaintenanceExempt", exempt.ScriptHash())
+	randomInvoker.Invoke(t, true, "isMaintenanceExempt", exempt.ScriptHash())
+	randomInvoker.Invoke(t, false, "isMaintenanceExempt", ordinary.ScriptHash())
+	randomInvoker.Invoke(t, false, "getMaintenanceMode")
+
+	pooled := newTx(t, ordinary)
+	require.NoError(t, bc.PoolTx(pooled))
+
+	h := c.Invoke(t, stackitem.Null{}, "setMaintenanceMode", true)
+	checkEvent(t, h, true)
+	h = c.Invoke(t, stackitem.Null{}, "setMaintenanceMode", true)
+	require.Equal(t, 0, len(c.GetTxExecResult(t, h).Events))
+	randomInvoker.Invoke(t, true, "getMaintenanceMode")
+
+	t.Run("enabled", func(t *testing.T) {
+		require.False(t, bc.GetMemPool().ContainsKey(pooled.Hash()))
+
+		tx := newTx(t, ordinary)
+		err := bc.PoolTx(tx)
+		require.True(t, errors.Is(err, core.ErrMaintenanceMode), "got: %v", err)
+		require.True(t, errors.Is(err, core.ErrPolicy), "got: %v", err)
+		require.True(t, errors.Is(bc.VerifyTx(tx), core.ErrMaintenanceMode))
+
+		require.NoError(t, bc.PoolTx(newTx(t, exempt)))
+		require.NoError(t, bc.PoolTx(newTx(t, c.Committee)))
+
+		// Blocks are not affected.
+		e.AddNewBlock(t, tx)
+		e.CheckHalt(t, tx.Hash())
+	})
+
+	h = c.Invoke(t, stackitem.Null{}, "setMaintenanceMode", false)
+	checkEvent(t, h, false)
+
+	t.Run("disabled", func(t *testing.T) {
+		require.NoError(t, bc.PoolTx(newTx(t, ordinary)))
+
+		c.Invoke(t, true, "removeMaintenanceExempt", exempt.ScriptHash())
+		c.Invoke(t, false, "removeMaintenanceExempt", exempt.ScriptHash())
+		randomInvoker.Invoke(t, false, "isMaintenanceExempt", exempt.ScriptHash())
+	})
+	t.Run("no events", func(t *testing.T) {
+		c := newPolicyClient(t)
+		h := c.Invoke(t, stackitem.Null{}, "setMaintenanceMode", true)
+		require.Equal(t, 0, len(c.GetTxExecResult(t, h).Events))
+	})
+}
+
+func TestPolicy_AttributeToggle(t *testing.T) {
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		c.P2PSigExtensions = true
+		c.PolicyExtensions = true
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	c := e.CommitteeInvoker(e.NativeHash(t, nativenames.Policy))
+	randomInvoker := c.WithSigners(e.NewAccount(t))
+
+	newTx := func(t *testing.T, attr transaction.Attribute) *transaction.Transaction {
+		tx := e.NewUnsignedTx(t, c.Hash, "getFeePerByte")
+		tx.Attributes = append(tx.Attributes, attr)
+		tx.ValidUntilBlock += 10
+		return e.SignTx(t, tx, -1, acc)
+	}
+	newConflictsTx := func(t *testing.T) *transaction.Transaction {
+		return newTx(t, transaction.Attribute{
+			Type:  transaction.ConflictsT,
+			Value: &transaction.Conflicts{Hash: util.Uint256{1, 2, 3}},
+		})
+	}
+	reserved := transaction.AttrType(transaction.ReservedLowerBound + 3)
+	newReservedTx := func(t *testing.T) *transaction.Transaction {
+		return newTx(t, transaction.Attribute{
+			Type:  reserved,
+			Value: &transaction.Reserved{Value: []byte{1, 2, 3}},
+		})
+	}
+
+	t.Run("not signed by committee", func(t *testing.T) {
+		randomInvoker.InvokeFail(t, "invalid committee signature", "disableAttribute", int64(transaction.ConflictsT))
+		randomInvoker.InvokeFail(t, "invalid committee signature", "enableAttribute", int64(transaction.ConflictsT))
+	})
+	t.Run("unknown type", func(t *testing.T) {
+		c.InvokeFail(t, "unknown attribute type", "disableAttribute", 0x55)
+		c.InvokeFail(t, "unknown attribute type", "enableAttribute", 0x100+int64(transaction.ConflictsT))
+	})
+	t.Run("P2PSigExtensions off", func(t *testing.T) {
+		c := newPolicyClient(t)
+		c.InvokeFail(t, "Conflicts attribute can't be enabled without P2PSigExtensions", "enableAttribute", int64(transaction.ConflictsT))
+		c.Invoke(t, stackitem.Null{}, "disableAttribute", int64(transaction.ConflictsT))
+	})
+
+	pooled := newConflictsTx(t)
+	require.NoError(t, bc.PoolTx(pooled))
+
+	c.Invoke(t, stackitem.Null{}, "disableAttribute", int64(transaction.ConflictsT))
+	require.False(t, bc.GetMemPool().ContainsKey(pooled.Hash()))
+	err := bc.PoolTx(newConflictsTx(t))
+	require.True(t, errors.Is(err, core.ErrInvalidAttribute), "got: %v", err)
+	require.True(t, strings.Contains(err.Error(), "Conflicts attribute is disabled by Policy"), "got: %v", err)
+	require.NoError(t, bc.PoolTx(newTx(t, transaction.Attribute{
+		Type:  transaction.NotValidBeforeT,
+		Value: &transaction.NotValidBefore{Height: 0},
+	})))
+
+	c.Invoke(t, stackitem.Null{}, "enableAttribute", int64(transaction.ConflictsT))
+	require.NoError(t, bc.PoolTx(newConflictsTx(t)))
+
+	t.Run("reserved", func(t *testing.T) {
+		err := bc.PoolTx(newReservedTx(t))
+		require.True(t, errors.Is(err, core.ErrInvalidAttribute), "got: %v", err)
+
+		c.Invoke(t, stackitem.Null{}, "enableAttribute", int64(reserved))
+		require.NoError(t, bc.PoolTx(newReservedTx(t)))
+
+		c.Invoke(t, stackitem.Null{}, "disableAttribute", int64(reserved))
+		err = bc.PoolTx(newReservedTx(t))
+		require.True(t, errors.Is(err, core.ErrInvalidAttribute), "got: %v", err)
+	})
+}
+
+func TestPolicy_Events(t *testing.T) {
+	c := newPolicyClient(t)
+	committeeInvoker := c.WithSigners(c.Committee)
+
+	checkEvent := func(t *testing.T, h util.Uint256, name string, args ...interface{}) {
+		items := make([]stackitem.Item, len(args))
+		for i := range args {
+			items[i] = stackitem.Make(args[i])
+		}
+		c.CheckTxNotificationEvent(t, h, 0, state.NotificationEvent{
+			ScriptHash: c.Hash,
+			Name:       name,
+			Item:       stackitem.NewArray(items),
+		})
+	}
+	checkNoEvents := func(t *testing.T, h util.Uint256) {
+		require.Equal(t, 0, len(c.GetTxExecResult(t, h).Events))
+	}
+
+	testCases := []struct {
+		name     string
+		event    string
+		oldValue int64
+	}{
+		{"ExecFeeFactor", native.ExecFeeFactorChangedEventName, interop.DefaultBaseExecFee},
+		{"StoragePrice", native.StoragePriceChangedEventName, native.DefaultStoragePrice},
+		{"FeePerByte", native.FeePerByteChangedEventName, 1000},
+		{"MaxBlockSystemFee", native.MaxBlockSystemFeeChangedEventName, 9000_00000000},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := committeeInvoker.Invoke(t, stackitem.Null{}, "set"+tc.name, tc.oldValue+1)
+			checkEvent(t, h, tc.event, tc.oldValue, tc.oldValue+1)
+
+			h = committeeInvoker.Invoke(t, stackitem.Null{}, "set"+tc.name, tc.oldValue+1)
+			checkNoEvents(t, h)
+		})
+	}
+
+	t.Run("block-unblock account", func(t *testing.T) {
+		unlucky := util.Uint160{1, 2, 3}
+
+		h := committeeInvoker.Invoke(t, true, "blockAccount", unlucky)
+		checkEvent(t, h, native.AccountBlockedEventName, unlucky.BytesBE())
+		h = committeeInvoker.Invoke(t, false, "blockAccount", unlucky)
+		checkNoEvents(t, h)
+
+		h = committeeInvoker.Invoke(t, true, "unblockAccount", unlucky)
+		checkEvent(t, h, native.AccountUnblockedEventName, unlucky.BytesBE())
+		h = committeeInvoker.Invoke(t, false, "unblockAccount", unlucky)
+		checkNoEvents(t, h)
+	})
+}
+
+func TestPolicy_EventsDisabled(t *testing.T) {
+	c := newNativeClient(t, nativenames.Policy)
+	committeeInvoker := c.WithSigners(c.Committee)
+
+	require.Equal(t, 0, len(c.Chain.GetContractState(c.Hash).Manifest.ABI.Events))
+	md, ok := native.NewContracts(c.Chain.GetConfig()).Policy.Metadata().GetMethod("setFeePerByte", 1)
+	require.True(t, ok)
+	require.Equal(t, callflag.States, md.RequiredFlags)
+
+	for _, h := range []util.Uint256{
+		committeeInvoker.Invoke(t, stackitem.Null{}, "setFeePerByte", 1001),
+		committeeInvoker.Invoke(t, stackitem.Null{}, "setExecFeeFactor", interop.DefaultBaseExecFee+1),
+		committeeInvoker.Invoke(t, stackitem.Null{}, "setStoragePrice", native.DefaultStoragePrice+1),
+		committeeInvoker.Invoke(t, true, "blockAccount", util.Uint160{1, 2, 3}),
+		committeeInvoker.Invoke(t, true, "unblockAccount", util.Uint160{1, 2, 3}),
+	} {
+		require.Equal(t, 0, len(c.GetTxExecResult(t, h).Events))
+	}
+}
+
 func TestPolicy_BlockedAccounts(t *testing.T) {
 	c := newPolicyClient(t)
 	e := c.Executor
@@ -57,6 +429,69 @@ func TestPolicy_BlockedAccounts(t *testing.T) {
 		randomInvoker.Invoke(t, false, "isBlocked", unlucky)
 	})
 
+	t.Run("isBlockedMulti", func(t *testing.T) {
+		others := []util.Uint160{{3, 2, 1}, {1, 2, 4}, {1, 2, 2}, {0xff}}
+		committeeInvoker.Invoke(t, true, "blockAccount", others[0])
+		committeeInvoker.Invoke(t, true, "blockAccount", unlucky)
+		committeeInvoker.Invoke(t, true, "blockAccount", others[3])
+
+		randomInvoker.Invoke(t, stackitem.NewArray([]stackitem.Item{}), "isBlockedMulti", []interface{}{})
+		randomInvoker.Invoke(t, stackitem.NewArray([]stackitem.Item{
+			stackitem.NewBool(false),
+			stackitem.NewBool(true),
+			stackitem.NewBool(true),
+			stackitem.NewBool(false),
+			stackitem.NewBool(true),
+			stackitem.NewBool(false),
+		}), "isBlockedMulti", []interface{}{others[1], unlucky, others[3], others[2], others[0], e.CommitteeHash})
+		randomInvoker.InvokeFail(t, "not an array", "isBlockedMulti", unlucky)
+
+		// Every account checked is charged for separately.
+		h1 := randomInvoker.Invoke(t, stackitem.NewArray([]stackitem.Item{stackitem.NewBool(false)}),
+			"isBlockedMulti", []interface{}{others[1]})
+		h2 := randomInvoker.Invoke(t, stackitem.NewArray([]stackitem.Item{stackitem.NewBool(false), stackitem.NewBool(false)}),
+			"isBlockedMulti", []interface{}{others[1], others[2]})
+		gas1 := e.GetTxExecResult(t, h1).GasConsumed
+		gas2 := e.GetTxExecResult(t, h2).GasConsumed
+		require.GreaterOrEqual(t, gas2-gas1, int64(1<<10)*e.Chain.GetBaseExecFee())
+
+		for _, h := range []util.Uint160{others[0], unlucky, others[3]} {
+			committeeInvoker.Invoke(t, true, "unblockAccount", h)
+		}
+		randomInvoker.Invoke(t, stackitem.NewArray([]stackitem.Item{stackitem.NewBool(false)}),
+			"isBlockedMulti", []interface{}{unlucky})
+	})
+
+	t.Run("getBlockedAccounts", func(t *testing.T) {
+		randomInvoker.Invoke(t, stackitem.NewArray([]stackitem.Item{}), "getBlockedAccounts", 0, 10)
+
+		accs := []util.Uint160{{3, 2, 1}, {1, 2, 4}, unlucky, {0xff}, {1, 2, 2}}
+		for _, h := range accs {
+			committeeInvoker.Invoke(t, true, "blockAccount", h)
+		}
+		sort.Slice(accs, func(i, j int) bool { return accs[i].Less(accs[j]) })
+		page := func(from, to int) stackitem.Item {
+			res := make([]stackitem.Item, 0, to-from)
+			for _, h := range accs[from:to] {
+				res = append(res, stackitem.NewByteArray(h.BytesBE()))
+			}
+			return stackitem.NewArray(res)
+		}
+		randomInvoker.Invoke(t, page(0, len(accs)), "getBlockedAccounts", 0, 10)
+		randomInvoker.Invoke(t, page(0, 2), "getBlockedAccounts", 0, 2)
+		randomInvoker.Invoke(t, page(2, 4), "getBlockedAccounts", 2, 2)
+		randomInvoker.Invoke(t, page(4, 5), "getBlockedAccounts", 4, 2)
+		randomInvoker.Invoke(t, page(5, 5), "getBlockedAccounts", 5, 2)
+		randomInvoker.Invoke(t, page(0, 0), "getBlockedAccounts", 1, 0)
+		randomInvoker.InvokeFail(t, "invalid start", "getBlockedAccounts", -1, 2)
+		randomInvoker.InvokeFail(t, "count should be in", "getBlockedAccounts", 0, native.MaxBlockedAccountsPage+1)
+
+		for _, h := range accs {
+			committeeInvoker.Invoke(t, true, "unblockAccount", h)
+		}
+		randomInvoker.Invoke(t, stackitem.NewArray([]stackitem.Item{}), "getBlockedAccounts", 0, 10)
+	})
+
 	t.Run("double-block", func(t *testing.T) {
 		// block
 		committeeInvoker.Invoke(t, true, "blockAccount", unlucky)
@@ -85,7 +520,39 @@ func TestPolicy_BlockedAccounts(t *testing.T) {
 
 		helperInvoker.Invoke(t, true, "do")
 		committeeInvoker.Invoke(t, true, "blockAccount", helper.Hash)
-		helperInvoker.InvokeFail(t, fmt.Sprintf("contract %s is blocked", helper.Hash.StringLE()), "do")
+		blockedErr := fmt.Sprintf("contract %s is blocked", helper.Hash.StringLE())
+
+		// Direct calls are rejected by the policy check.
+		bc := e.Chain.(*core.Blockchain)
+		err := bc.VerifyTx(helperInvoker.PrepareInvoke(t, "do"))
+		require.True(t, errors.Is(err, core.ErrPolicy), err)
+		require.Contains(t, err.Error(), blockedErr)
+
+		// So are transactions allowing to use the signature in it.
+		tx := transaction.New([]byte{byte(opcode.PUSH1)}, 0)
+		tx.Nonce = neotest.Nonce()
+		tx.ValidUntilBlock = bc.BlockHeight() + 1
+		tx.Signers = []transaction.Signer{{
+			Account:          e.Committee.ScriptHash(),
+			Scopes:           transaction.CustomContracts,
+			AllowedContracts: []util.Uint160{helper.Hash},
+		}}
+		neotest.AddNetworkFee(bc, tx, e.Committee)
+		require.NoError(t, e.Committee.SignTx(bc.GetConfig().Magic, tx))
+		err = bc.VerifyTx(tx)
+		require.True(t, errors.Is(err, core.ErrPolicy), err)
+		require.Contains(t, err.Error(), blockedErr)
+
+		// Calls that can't be detected statically fail at runtime.
+		w := io.NewBufBinWriter()
+		emit.Opcodes(w.BinWriter, opcode.NEWARRAY0)
+		emit.Int(w.BinWriter, int64(callflag.All))
+		emit.String(w.BinWriter, "do")
+		emit.Bytes(w.BinWriter, helper.Hash.BytesBE())
+		emit.Opcodes(w.BinWriter, opcode.NOP)
+		emit.Syscall(w.BinWriter, interopnames.SystemContractCall)
+		require.NoError(t, w.Err)
+		e.InvokeScriptCheckFAULT(t, w.Bytes(), []neotest.Signer{e.Committee}, blockedErr)
 
 		committeeInvoker.Invoke(t, true, "unblockAccount", helper.Hash)
 		helperInvoker.Invoke(t, true, "do")
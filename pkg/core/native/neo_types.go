@@ -19,6 +19,14 @@ type gasIndexPair struct {
 // gasRecord contains history of gas per block changes. It is used only by NEO cache.
 type gasRecord []gasIndexPair
 
+// GASPerBlockRecord is a GASPerBlock schedule breakpoint, GASPerBlock amount
+// of GAS is generated for every block starting from Index up to the next
+// breakpoint.
+type GASPerBlockRecord struct {
+	Index       uint32
+	GASPerBlock *big.Int
+}
+
 type (
 	// keyWithVotes is a serialized key with votes balance. It's not deserialized
 	// because some uses of it imply serialized-only usage and converting to
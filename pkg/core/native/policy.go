@@ -1,29 +1,43 @@
 package native
 
 import (
+	"encoding/binary"
 	"fmt"
+	"math"
 	"math/big"
 	"sort"
 
+	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/core/dao"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/stateroot"
 	"github.com/nspcc-dev/neo-go/pkg/core/storage"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 )
 
 const (
 	policyContractID = -7
+	// policyMethodCount is the maximum number of Policy methods.
+	policyMethodCount = 22
+	// MaxBlockedAccountsPage is the maximum number of accounts returned by
+	// a single getBlockedAccounts call.
+	MaxBlockedAccountsPage = 256
 
 	defaultExecFeeFactor      = interop.DefaultBaseExecFee
 	defaultFeePerByte         = 1000
 	defaultMaxVerificationGas = 1_50000000
+	defaultMaxBlockSystemFee  = 9000_00000000
 	// DefaultStoragePrice is the price to pay for 1 byte of storage.
 	DefaultStoragePrice = 100000
 
@@ -33,9 +47,47 @@ const (
 	maxFeePerByte = 100_000_000
 	// maxStoragePrice is the maximum allowed price for a byte of storage.
 	maxStoragePrice = 10000000
+	// maxMaxBlockSystemFee is the maximum allowed block system fee limit.
+	maxMaxBlockSystemFee = 100000_00000000
+	// maxAttributeFee is the maximum allowed fee for a transaction attribute.
+	maxAttributeFee = 10_00000000
 
 	// blockedAccountPrefix is a prefix used to store blocked account.
 	blockedAccountPrefix = 15
+	// attributeFeePrefix is a prefix used to store attribute fees.
+	attributeFeePrefix = 20
+	// maintenanceExemptPrefix is a prefix used to store accounts allowed to
+	// send transactions in maintenance mode.
+	maintenanceExemptPrefix = 22
+	// attributeStatePrefix is a prefix used to store attribute types
+	// enabled or disabled by the committee.
+	attributeStatePrefix = 23
+
+	// ExecFeeFactorChangedEventName is the name of an event emitted when
+	// execution fee factor is changed.
+	ExecFeeFactorChangedEventName = "ExecFeeFactorChanged"
+	// StoragePriceChangedEventName is the name of an event emitted when
+	// storage price is changed.
+	StoragePriceChangedEventName = "StoragePriceChanged"
+	// FeePerByteChangedEventName is the name of an event emitted when
+	// transaction fee per byte is changed.
+	FeePerByteChangedEventName = "FeePerByteChanged"
+	// MaxBlockSystemFeeChangedEventName is the name of an event emitted when
+	// the maximum block system fee is changed.
+	MaxBlockSystemFeeChangedEventName = "MaxBlockSystemFeeChanged"
+	// AccountBlockedEventName is the name of an event emitted when an account
+	// is blocked.
+	AccountBlockedEventName = "AccountBlocked"
+	// AccountUnblockedEventName is the name of an event emitted when an
+	// account is unblocked.
+	AccountUnblockedEventName = "AccountUnblocked"
+	// AttributeFeeChangedEventName is the name of an event emitted when
+	// transaction attribute fee is changed.
+	AttributeFeeChangedEventName = "AttributeFeeChanged"
+	// MaintenanceModeChangedEventName is the name of an event emitted when
+	// maintenance mode is enabled or disabled (if PolicyExtensions and
+	// MaintenanceModeEvents settings are on).
+	MaintenanceModeChangedEventName = "MaintenanceModeChanged"
 )
 
 var (
@@ -46,12 +98,30 @@ var (
 	feePerByteKey = []byte{10}
 	// storagePriceKey is a key used to store storage price.
 	storagePriceKey = []byte{19}
+	// maxBlockSystemFeeKey is a key used to store the maximum overall system
+	// fee of transactions in a block.
+	maxBlockSystemFeeKey = []byte{17}
+	// maintenanceModeKey is a key used to store maintenance mode flag.
+	maintenanceModeKey = []byte{21}
 )
 
 // Policy represents Policy native contract.
 type Policy struct {
 	interop.ContractMD
 	NEO *NEO
+
+	// extensions specifies whether additional Policy methods and events
+	// are available (see PolicyExtensions setting).
+	extensions bool
+	// defaultMaxBlockSystemFee is the maximum overall system fee of
+	// transactions in a block used until it's changed by the committee.
+	defaultMaxBlockSystemFee int64
+	// maintenanceEvents specifies whether maintenance mode changes
+	// produce notifications (it requires extensions).
+	maintenanceEvents bool
+	// p2pSigExtensionsEnabled defines whether the P2P signature extensions
+	// attributes can be enabled.
+	p2pSigExtensionsEnabled bool
 }
 
 type PolicyCache struct {
@@ -59,7 +129,12 @@ type PolicyCache struct {
 	feePerByte         int64
 	maxVerificationGas int64
 	storagePrice       uint32
+	maxBlockSystemFee  int64
 	blockedAccounts    []util.Uint160
+	attributeFees      map[transaction.AttrType]int64
+	maintenanceMode    bool
+	maintenanceExempt  []util.Uint160
+	attributeStates    map[transaction.AttrType]bool
 }
 
 var (
@@ -78,13 +153,40 @@ func copyPolicyCache(src, dst *PolicyCache) {
 	*dst = *src
 	dst.blockedAccounts = make([]util.Uint160, len(src.blockedAccounts))
 	copy(dst.blockedAccounts, src.blockedAccounts)
+	dst.maintenanceExempt = make([]util.Uint160, len(src.maintenanceExempt))
+	copy(dst.maintenanceExempt, src.maintenanceExempt)
+	dst.attributeFees = make(map[transaction.AttrType]int64, len(src.attributeFees))
+	for t, fee := range src.attributeFees {
+		dst.attributeFees[t] = fee
+	}
+	dst.attributeStates = make(map[transaction.AttrType]bool, len(src.attributeStates))
+	for t, enabled := range src.attributeStates {
+		dst.attributeStates[t] = enabled
+	}
 }
 
 // newPolicy returns Policy native contract.
-func newPolicy() *Policy {
-	p := &Policy{ContractMD: *interop.NewContractMD(nativenames.Policy, policyContractID)}
+func newPolicy(cfg config.ProtocolConfiguration) *Policy {
+	p := &Policy{
+		ContractMD:               *interop.NewContractMDWithCapacity(nativenames.Policy, policyContractID, policyMethodCount),
+		extensions:               cfg.PolicyExtensions,
+		defaultMaxBlockSystemFee: cfg.MaxBlockSystemFee,
+		maintenanceEvents:        cfg.MaintenanceModeEvents,
+		p2pSigExtensionsEnabled:  cfg.P2PSigExtensions,
+	}
+	if p.defaultMaxBlockSystemFee <= 0 {
+		p.defaultMaxBlockSystemFee = defaultMaxBlockSystemFee
+	}
 	defer p.UpdateHash()
 
+	// Setters only emit notifications (and require AllowNotify flag for
+	// that) with extensions enabled to keep the manifest and execution
+	// results compatible with the existing networks.
+	notifyFlags := callflag.States
+	if p.extensions {
+		notifyFlags |= callflag.AllowNotify
+	}
+
 	desc := newDescriptor("getFeePerByte", smartcontract.IntegerType)
 	md := newMethodAndPrice(p.getFeePerByte, 1<<15, callflag.ReadStates)
 	p.AddMethod(md, desc)
@@ -94,13 +196,26 @@ func newPolicy() *Policy {
 	md = newMethodAndPrice(p.isBlocked, 1<<15, callflag.ReadStates)
 	p.AddMethod(md, desc)
 
+	if p.extensions {
+		desc = newDescriptor("isBlockedMulti", smartcontract.ArrayType,
+			manifest.NewParameter("accounts", smartcontract.ArrayType))
+		md = newMethodAndPrice(p.isBlockedMulti, 1<<15, callflag.ReadStates)
+		p.AddMethod(md, desc)
+
+		desc = newDescriptor("getBlockedAccounts", smartcontract.ArrayType,
+			manifest.NewParameter("start", smartcontract.IntegerType),
+			manifest.NewParameter("count", smartcontract.IntegerType))
+		md = newMethodAndPrice(p.getBlockedAccounts, 1<<15, callflag.ReadStates)
+		p.AddMethod(md, desc)
+	}
+
 	desc = newDescriptor("getExecFeeFactor", smartcontract.IntegerType)
 	md = newMethodAndPrice(p.getExecFeeFactor, 1<<15, callflag.ReadStates)
 	p.AddMethod(md, desc)
 
 	desc = newDescriptor("setExecFeeFactor", smartcontract.VoidType,
 		manifest.NewParameter("value", smartcontract.IntegerType))
-	md = newMethodAndPrice(p.setExecFeeFactor, 1<<15, callflag.States)
+	md = newMethodAndPrice(p.setExecFeeFactor, 1<<15, notifyFlags)
 	p.AddMethod(md, desc)
 
 	desc = newDescriptor("getStoragePrice", smartcontract.IntegerType)
@@ -109,24 +224,108 @@ func newPolicy() *Policy {
 
 	desc = newDescriptor("setStoragePrice", smartcontract.VoidType,
 		manifest.NewParameter("value", smartcontract.IntegerType))
-	md = newMethodAndPrice(p.setStoragePrice, 1<<15, callflag.States)
+	md = newMethodAndPrice(p.setStoragePrice, 1<<15, notifyFlags)
 	p.AddMethod(md, desc)
 
 	desc = newDescriptor("setFeePerByte", smartcontract.VoidType,
 		manifest.NewParameter("value", smartcontract.IntegerType))
-	md = newMethodAndPrice(p.setFeePerByte, 1<<15, callflag.States)
+	md = newMethodAndPrice(p.setFeePerByte, 1<<15, notifyFlags)
 	p.AddMethod(md, desc)
 
+	if p.extensions {
+		desc = newDescriptor("getMaxBlockSystemFee", smartcontract.IntegerType)
+		md = newMethodAndPrice(p.getMaxBlockSystemFee, 1<<15, callflag.ReadStates)
+		p.AddMethod(md, desc)
+
+		desc = newDescriptor("setMaxBlockSystemFee", smartcontract.VoidType,
+			manifest.NewParameter("value", smartcontract.IntegerType))
+		md = newMethodAndPrice(p.setMaxBlockSystemFee, 1<<15, notifyFlags)
+		p.AddMethod(md, desc)
+	}
+
 	desc = newDescriptor("blockAccount", smartcontract.BoolType,
 		manifest.NewParameter("account", smartcontract.Hash160Type))
-	md = newMethodAndPrice(p.blockAccount, 1<<15, callflag.States)
+	md = newMethodAndPrice(p.blockAccount, 1<<15, notifyFlags)
 	p.AddMethod(md, desc)
 
 	desc = newDescriptor("unblockAccount", smartcontract.BoolType,
 		manifest.NewParameter("account", smartcontract.Hash160Type))
-	md = newMethodAndPrice(p.unblockAccount, 1<<15, callflag.States)
+	md = newMethodAndPrice(p.unblockAccount, 1<<15, notifyFlags)
 	p.AddMethod(md, desc)
 
+	if p.extensions {
+		desc = newDescriptor("getAttributeFee", smartcontract.IntegerType,
+			manifest.NewParameter("attributeType", smartcontract.IntegerType))
+		md = newMethodAndPrice(p.getAttributeFee, 1<<15, callflag.ReadStates)
+		p.AddMethod(md, desc)
+
+		desc = newDescriptor("setAttributeFee", smartcontract.VoidType,
+			manifest.NewParameter("attributeType", smartcontract.IntegerType),
+			manifest.NewParameter("value", smartcontract.IntegerType))
+		md = newMethodAndPrice(p.setAttributeFee, 1<<15, notifyFlags)
+		p.AddMethod(md, desc)
+	}
+
+	if p.extensions {
+		desc = newDescriptor("getMaintenanceMode", smartcontract.BoolType)
+		md = newMethodAndPrice(p.getMaintenanceMode, 1<<15, callflag.ReadStates)
+		p.AddMethod(md, desc)
+
+		desc = newDescriptor("setMaintenanceMode", smartcontract.VoidType,
+			manifest.NewParameter("enabled", smartcontract.BoolType))
+		md = newMethodAndPrice(p.setMaintenanceMode, 1<<15, notifyFlags)
+		p.AddMethod(md, desc)
+
+		desc = newDescriptor("isMaintenanceExempt", smartcontract.BoolType,
+			manifest.NewParameter("account", smartcontract.Hash160Type))
+		md = newMethodAndPrice(p.isMaintenanceExempt, 1<<15, callflag.ReadStates)
+		p.AddMethod(md, desc)
+
+		desc = newDescriptor("addMaintenanceExempt", smartcontract.BoolType,
+			manifest.NewParameter("account", smartcontract.Hash160Type))
+		md = newMethodAndPrice(p.addMaintenanceExempt, 1<<15, callflag.States)
+		p.AddMethod(md, desc)
+
+		desc = newDescriptor("removeMaintenanceExempt", smartcontract.BoolType,
+			manifest.NewParameter("account", smartcontract.Hash160Type))
+		md = newMethodAndPrice(p.removeMaintenanceExempt, 1<<15, callflag.States)
+		p.AddMethod(md, desc)
+	}
+
+	if p.extensions {
+		desc = newDescriptor("enableAttribute", smartcontract.VoidType,
+			manifest.NewParameter("attributeType", smartcontract.IntegerType))
+		md = newMethodAndPrice(p.enableAttribute, 1<<15, callflag.States)
+		p.AddMethod(md, desc)
+
+		desc = newDescriptor("disableAttribute", smartcontract.VoidType,
+			manifest.NewParameter("attributeType", smartcontract.IntegerType))
+		md = newMethodAndPrice(p.disableAttribute, 1<<15, callflag.States)
+		p.AddMethod(md, desc)
+	}
+
+	if p.extensions {
+		changeParams := []manifest.Parameter{
+			manifest.NewParameter("Old", smartcontract.IntegerType),
+			manifest.NewParameter("New", smartcontract.IntegerType),
+		}
+		p.AddEvent(ExecFeeFactorChangedEventName, changeParams...)
+		p.AddEvent(StoragePriceChangedEventName, changeParams...)
+		p.AddEvent(FeePerByteChangedEventName, changeParams...)
+		p.AddEvent(MaxBlockSystemFeeChangedEventName, changeParams...)
+
+		accountParam := manifest.NewParameter("Account", smartcontract.Hash160Type)
+		p.AddEvent(AccountBlockedEventName, accountParam)
+		p.AddEvent(AccountUnblockedEventName, accountParam)
+
+		p.AddEvent(AttributeFeeChangedEventName, append([]manifest.Parameter{
+			manifest.NewParameter("Type", smartcontract.IntegerType)}, changeParams...)...)
+	}
+
+	if p.extensions && p.maintenanceEvents {
+		p.AddEvent(MaintenanceModeChangedEventName, manifest.NewParameter("Enabled", smartcontract.BoolType))
+	}
+
 	return p
 }
 
@@ -140,13 +339,20 @@ func (p *Policy) Initialize(ic *interop.Context) error {
 	setIntWithKey(p.ID, ic.DAO, feePerByteKey, defaultFeePerByte)
 	setIntWithKey(p.ID, ic.DAO, execFeeFactorKey, defaultExecFeeFactor)
 	setIntWithKey(p.ID, ic.DAO, storagePriceKey, DefaultStoragePrice)
+	// MaxBlockSystemFee is only stored when it's changed, genesis state
+	// doesn't contain it to stay compatible with the existing networks,
+	// configured value is used until then.
 
 	cache := &PolicyCache{
 		execFeeFactor:      defaultExecFeeFactor,
 		feePerByte:         defaultFeePerByte,
 		maxVerificationGas: defaultMaxVerificationGas,
 		storagePrice:       DefaultStoragePrice,
+		maxBlockSystemFee:  p.defaultMaxBlockSystemFee,
 		blockedAccounts:    make([]util.Uint160, 0),
+		attributeFees:      make(map[transaction.AttrType]int64),
+		maintenanceExempt:  make([]util.Uint160, 0),
+		attributeStates:    make(map[transaction.AttrType]bool),
 	}
 	ic.DAO.SetCache(p.ID, cache)
 
@@ -168,6 +374,10 @@ func (p *Policy) fillCacheFromDAO(cache *PolicyCache, d *dao.Simple) error {
 	cache.feePerByte = getIntWithKey(p.ID, d, feePerByteKey)
 	cache.maxVerificationGas = defaultMaxVerificationGas
 	cache.storagePrice = uint32(getIntWithKey(p.ID, d, storagePriceKey))
+	cache.maxBlockSystemFee = p.defaultMaxBlockSystemFee
+	if si := d.GetStorageItem(p.ID, maxBlockSystemFeeKey); si != nil {
+		cache.maxBlockSystemFee = bigint.FromBytes(si).Int64()
+	}
 
 	cache.blockedAccounts = make([]util.Uint160, 0)
 	var fErr error
@@ -183,6 +393,50 @@ func (p *Policy) fillCacheFromDAO(cache *PolicyCache, d *dao.Simple) error {
 	if fErr != nil {
 		return fmt.Errorf("failed to initialize blocked accounts: %w", fErr)
 	}
+
+	cache.maintenanceMode = false
+	if si := d.GetStorageItem(p.ID, maintenanceModeKey); si != nil {
+		cache.maintenanceMode = bigint.FromBytes(si).Sign() != 0
+	}
+	cache.maintenanceExempt = make([]util.Uint160, 0)
+	d.Seek(p.ID, storage.SeekRange{Prefix: []byte{maintenanceExemptPrefix}}, func(k, _ []byte) bool {
+		hash, err := util.Uint160DecodeBytesBE(k)
+		if err != nil {
+			fErr = fmt.Errorf("failed to decode maintenance exempt account hash: %w", err)
+			return false
+		}
+		cache.maintenanceExempt = append(cache.maintenanceExempt, hash)
+		return true
+	})
+	if fErr != nil {
+		return fmt.Errorf("failed to initialize maintenance exempt accounts: %w", fErr)
+	}
+
+	cache.attributeFees = make(map[transaction.AttrType]int64)
+	d.Seek(p.ID, storage.SeekRange{Prefix: []byte{attributeFeePrefix}}, func(k, v []byte) bool {
+		if len(k) != 1 {
+			fErr = fmt.Errorf("invalid attribute fee key length %d", len(k))
+			return false
+		}
+		cache.attributeFees[transaction.AttrType(k[0])] = bigint.FromBytes(v).Int64()
+		return true
+	})
+	if fErr != nil {
+		return fmt.Errorf("failed to initialize attribute fees: %w", fErr)
+	}
+
+	cache.attributeStates = make(map[transaction.AttrType]bool)
+	d.Seek(p.ID, storage.SeekRange{Prefix: []byte{attributeStatePrefix}}, func(k, v []byte) bool {
+		if len(k) != 1 {
+			fErr = fmt.Errorf("invalid attribute state key length %d", len(k))
+			return false
+		}
+		cache.attributeStates[transaction.AttrType(k[0])] = bigint.FromBytes(v).Sign() != 0
+		return true
+	})
+	if fErr != nil {
+		return fmt.Errorf("failed to initialize attribute states: %w", fErr)
+	}
 	return nil
 }
 
@@ -232,8 +486,12 @@ func (p *Policy) setExecFeeFactor(ic *interop.Context, args []stackitem.Item) st
 	if !p.NEO.checkCommittee(ic) {
 		panic("invalid committee signature")
 	}
-	setIntWithKey(p.ID, ic.DAO, execFeeFactorKey, int64(value))
 	cache := ic.DAO.GetRWCache(p.ID).(*PolicyCache)
+	if cache.execFeeFactor == value {
+		return stackitem.Null{}
+	}
+	setIntWithKey(p.ID, ic.DAO, execFeeFactorKey, int64(value))
+	p.notifyChange(ic, ExecFeeFactorChangedEventName, int64(cache.execFeeFactor), int64(value))
 	cache.execFeeFactor = value
 	return stackitem.Null{}
 }
@@ -251,16 +509,117 @@ func (p *Policy) IsBlocked(dao *dao.Simple, hash util.Uint160) bool {
 	return isBlocked
 }
 
+// isBlockedMultiAccountPrice is the price (in execution fee factor units)
+// charged by isBlockedMulti for every account checked in addition to the
+// method price.
+const isBlockedMultiAccountPrice = 1 << 10
+
+// isBlockedMulti is Policy contract method and checks whether provided accounts
+// are blocked, it returns an array of booleans in the same order.
+func (p *Policy) isBlockedMulti(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	arr, ok := args[0].Value().([]stackitem.Item)
+	if !ok {
+		panic("not an array")
+	}
+	if !ic.VM.AddGas(int64(len(arr)) * isBlockedMultiAccountPrice * ic.BaseExecFee()) {
+		panic("insufficient gas")
+	}
+	hashes := make([]util.Uint160, len(arr))
+	for i := range arr {
+		hashes[i] = toUint160(arr[i])
+	}
+	blocked := p.IsBlockedMultiInternal(ic.DAO, hashes)
+	res := make([]stackitem.Item, len(blocked))
+	for i := range blocked {
+		res[i] = stackitem.NewBool(blocked[i])
+	}
+	return stackitem.NewArray(res)
+}
+
+// IsBlockedMultiInternal checks whether provided accounts are blocked. The
+// result has the same length and order as hashes. Blocked accounts list is
+// traversed only once, so it's cheaper than calling IsBlocked for every
+// account.
+func (p *Policy) IsBlockedMultiInternal(d *dao.Simple, hashes []util.Uint160) []bool {
+	var (
+		cache   = d.GetROCache(p.ID).(*PolicyCache)
+		res     = make([]bool, len(hashes))
+		indexes = make([]int, len(hashes))
+	)
+	if len(cache.blockedAccounts) == 0 {
+		return res
+	}
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.Slice(indexes, func(i, j int) bool {
+		return hashes[indexes[i]].Less(hashes[indexes[j]])
+	})
+	var j int
+	for _, i := range indexes {
+		for j < len(cache.blockedAccounts) && cache.blockedAccounts[j].Less(hashes[i]) {
+			j++
+		}
+		if j == len(cache.blockedAccounts) {
+			break
+		}
+		res[i] = cache.blockedAccounts[j].Equals(hashes[i])
+	}
+	return res
+}
+
+// getBlockedAccounts is Policy contract method and returns at most count
+// blocked accounts starting from the given position in the sorted blocked
+// accounts list.
+func (p *Policy) getBlockedAccounts(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	start := toBigInt(args[0])
+	count := toBigInt(args[1])
+	if !start.IsInt64() || start.Sign() < 0 {
+		panic("invalid start")
+	}
+	if !count.IsInt64() || count.Sign() < 0 || count.Int64() > MaxBlockedAccountsPage {
+		panic(fmt.Errorf("count should be in [0, %d] range", MaxBlockedAccountsPage))
+	}
+	accs := p.GetBlockedAccounts(ic.DAO, int(start.Int64()), int(count.Int64()))
+	res := make([]stackitem.Item, len(accs))
+	for i := range accs {
+		res[i] = stackitem.NewByteArray(accs[i].BytesBE())
+	}
+	return stackitem.NewArray(res)
+}
+
+// GetBlockedAccounts returns at most count blocked accounts starting from the
+// given position in the blocked accounts list sorted by hash.
+func (p *Policy) GetBlockedAccounts(d *dao.Simple, start, count int) []util.Uint160 {
+	cache := d.GetROCache(p.ID).(*PolicyCache)
+	if start >= len(cache.blockedAccounts) {
+		return []util.Uint160{}
+	}
+	if end := len(cache.blockedAccounts) - start; count > end {
+		count = end
+	}
+	res := make([]util.Uint160, count)
+	copy(res, cache.blockedAccounts[start:])
+	return res
+}
+
 // isBlockedInternal checks whether provided account is blocked. It returns position
 // of the blocked account in the blocked accounts list (or the position it should be
 // put at).
 func (p *Policy) isBlockedInternal(dao *dao.Simple, hash util.Uint160) (int, bool) {
 	cache := dao.GetROCache(p.ID).(*PolicyCache)
-	length := len(cache.blockedAccounts)
+	return searchAccount(cache.blockedAccounts, hash)
+}
+
+// searchAccount looks for the hash in the sorted list of accounts. It returns
+// position of the account in the list (or the position it should be put at)
+// and whether it's found.
+func searchAccount(accs []util.Uint160, hash util.Uint160) (int, bool) {
+	length := len(accs)
 	i := sort.Search(length, func(i int) bool {
-		return !cache.blockedAccounts[i].Less(hash)
+		return !accs[i].Less(hash)
 	})
-	if length != 0 && i != length && cache.blockedAccounts[i].Equals(hash) {
+	if length != 0 && i != length && accs[i].Equals(hash) {
 		return i, true
 	}
 	return i, false
@@ -276,6 +635,19 @@ func (p *Policy) GetStoragePriceInternal(d *dao.Simple) int64 {
 	return int64(cache.storagePrice)
 }
 
+// GetStoragePriceFromState returns storage price from the state with the given
+// root, it allows to get storage price values set in the past.
+func (p *Policy) GetStoragePriceFromState(mod *stateroot.Module, root util.Uint256) (int64, error) {
+	key := make([]byte, 4+len(storagePriceKey))
+	binary.LittleEndian.PutUint32(key, uint32(p.ID))
+	copy(key[4:], storagePriceKey)
+	si, err := mod.GetState(root, key)
+	if err != nil {
+		return 0, err
+	}
+	return bigint.FromBytes(si).Int64(), nil
+}
+
 func (p *Policy) setStoragePrice(ic *interop.Context, args []stackitem.Item) stackitem.Item {
 	value := toUint32(args[0])
 	if value <= 0 || maxStoragePrice < value {
@@ -284,12 +656,50 @@ func (p *Policy) setStoragePrice(ic *interop.Context, args []stackitem.Item) sta
 	if !p.NEO.checkCommittee(ic) {
 		panic("invalid committee signature")
 	}
-	setIntWithKey(p.ID, ic.DAO, storagePriceKey, int64(value))
 	cache := ic.DAO.GetRWCache(p.ID).(*PolicyCache)
+	if cache.storagePrice == value {
+		return stackitem.Null{}
+	}
+	setIntWithKey(p.ID, ic.DAO, storagePriceKey, int64(value))
+	p.notifyChange(ic, StoragePriceChangedEventName, int64(cache.storagePrice), int64(value))
 	cache.storagePrice = value
 	return stackitem.Null{}
 }
 
+// getMaxBlockSystemFee is Policy contract method and returns the maximum overall
+// system fee of transactions in a block.
+func (p *Policy) getMaxBlockSystemFee(ic *interop.Context, _ []stackitem.Item) stackitem.Item {
+	return stackitem.NewBigInteger(big.NewInt(p.GetMaxBlockSystemFeeInternal(ic.DAO)))
+}
+
+// GetMaxBlockSystemFeeInternal returns the maximum overall system fee of
+// transactions in a block. It's the MaxBlockSystemFee configuration value
+// unless it's changed by the committee.
+func (p *Policy) GetMaxBlockSystemFeeInternal(d *dao.Simple) int64 {
+	cache := d.GetROCache(p.ID).(*PolicyCache)
+	return cache.maxBlockSystemFee
+}
+
+// setMaxBlockSystemFee is Policy contract method and sets the maximum overall
+// system fee of transactions in a block.
+func (p *Policy) setMaxBlockSystemFee(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	value := toBigInt(args[0]).Int64()
+	if value <= 0 || value > maxMaxBlockSystemFee {
+		panic(fmt.Errorf("MaxBlockSystemFee must be between 0 and %d", maxMaxBlockSystemFee))
+	}
+	if !p.NEO.checkCommittee(ic) {
+		panic("invalid committee signature")
+	}
+	cache := ic.DAO.GetRWCache(p.ID).(*PolicyCache)
+	if cache.maxBlockSystemFee == value {
+		return stackitem.Null{}
+	}
+	setIntWithKey(p.ID, ic.DAO, maxBlockSystemFeeKey, value)
+	p.notifyChange(ic, MaxBlockSystemFeeChangedEventName, cache.maxBlockSystemFee, value)
+	cache.maxBlockSystemFee = value
+	return stackitem.Null{}
+}
+
 // setFeePerByte is Policy contract method and sets transaction's fee per byte.
 func (p *Policy) setFeePerByte(ic *interop.Context, args []stackitem.Item) stackitem.Item {
 	value := toBigInt(args[0]).Int64()
@@ -299,8 +709,12 @@ func (p *Policy) setFeePerByte(ic *interop.Context, args []stackitem.Item) stack
 	if !p.NEO.checkCommittee(ic) {
 		panic("invalid committee signature")
 	}
-	setIntWithKey(p.ID, ic.DAO, feePerByteKey, value)
 	cache := ic.DAO.GetRWCache(p.ID).(*PolicyCache)
+	if cache.feePerByte == value {
+		return stackitem.Null{}
+	}
+	setIntWithKey(p.ID, ic.DAO, feePerByteKey, value)
+	p.notifyChange(ic, FeePerByteChangedEventName, cache.feePerByte, value)
 	cache.feePerByte = value
 	return stackitem.Null{}
 }
@@ -330,6 +744,7 @@ func (p *Policy) blockAccount(ic *interop.Context, args []stackitem.Item) stacki
 		cache.blockedAccounts = append(cache.blockedAccounts[:i+1], cache.blockedAccounts[i:]...)
 		cache.blockedAccounts[i] = hash
 	}
+	p.notifyAccount(ic, AccountBlockedEventName, hash)
 	return stackitem.NewBool(true)
 }
 
@@ -348,17 +763,346 @@ func (p *Policy) unblockAccount(ic *interop.Context, args []stackitem.Item) stac
 	ic.DAO.DeleteStorageItem(p.ID, key)
 	cache := ic.DAO.GetRWCache(p.ID).(*PolicyCache)
 	cache.blockedAccounts = append(cache.blockedAccounts[:i], cache.blockedAccounts[i+1:]...)
+	p.notifyAccount(ic, AccountUnblockedEventName, hash)
+	return stackitem.NewBool(true)
+}
+
+// getAttributeFee is Policy contract method and returns the fee for a
+// transaction attribute of the given type.
+func (p *Policy) getAttributeFee(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	t := toAttrType(args[0])
+	return stackitem.NewBigInteger(big.NewInt(p.GetAttributeFeeInternal(ic.DAO, t)))
+}
+
+// GetAttributeFeeInternal returns the fee for a transaction attribute of the
+// given type, it's zero by default.
+func (p *Policy) GetAttributeFeeInternal(d *dao.Simple, t transaction.AttrType) int64 {
+	cache := d.GetROCache(p.ID).(*PolicyCache)
+	return cache.attributeFees[t]
+}
+
+// setAttributeFee is Policy contract method and sets the fee for a transaction
+// attribute of the given type. OracleResponse attribute fee can't be set,
+// oracle response transactions are built by oracle nodes (and paid for by the
+// Oracle contract) without it.
+func (p *Policy) setAttributeFee(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	t := toAttrType(args[0])
+	if t == transaction.OracleResponseT {
+		panic("OracleResponse attribute fee can't be set")
+	}
+	value := toBigInt(args[1]).Int64()
+	if value < 0 || value > maxAttributeFee {
+		panic(fmt.Errorf("AttributeFee shouldn't be negative or greater than %d", maxAttributeFee))
+	}
+	if !p.NEO.checkCommittee(ic) {
+		panic("invalid committee signature")
+	}
+	cache := ic.DAO.GetRWCache(p.ID).(*PolicyCache)
+	old := cache.attributeFees[t]
+	if old == value {
+		return stackitem.Null{}
+	}
+	setIntWithKey(p.ID, ic.DAO, []byte{attributeFeePrefix, byte(t)}, value)
+	ic.Notifications = append(ic.Notifications, state.NotificationEvent{
+		ScriptHash: p.Hash,
+		Name:       AttributeFeeChangedEventName,
+		Item: stackitem.NewArray([]stackitem.Item{
+			stackitem.NewBigInteger(big.NewInt(int64(t))),
+			stackitem.NewBigInteger(big.NewInt(old)),
+			stackitem.NewBigInteger(big.NewInt(value)),
+		}),
+	})
+	cache.attributeFees[t] = value
+	return stackitem.Null{}
+}
+
+// GetAttributesFeeInternal returns the sum of fees for all attributes of the
+// given transaction. It's a part of the network fee the transaction should pay,
+// fees for NotaryAssisted attribute defined by Notary contract are not included.
+func (p *Policy) GetAttributesFeeInternal(d *dao.Simple, tx *transaction.Transaction) int64 {
+	cache := d.GetROCache(p.ID).(*PolicyCache)
+	if len(cache.attributeFees) == 0 {
+		return 0
+	}
+	var fee int64
+	for i := range tx.Attributes {
+		fee += cache.attributeFees[tx.Attributes[i].Type]
+	}
+	return fee
+}
+
+// toAttrType converts the stack item to a known transaction attribute type and
+// panics if it's not possible.
+func toAttrType(s stackitem.Item) transaction.AttrType {
+	v := toUint32(s)
+	if v <= math.MaxUint8 {
+		switch t := transaction.AttrType(v); t {
+		case transaction.HighPriority, transaction.OracleResponseT,
+			transaction.NotValidBeforeT, transaction.ConflictsT, transaction.NotaryAssistedT:
+			return t
+		}
+	}
+	panic(fmt.Errorf("unknown attribute type %d", v))
+}
+
+// enableAttribute is Policy contract method and allows transaction attributes
+// of the given type irrespective of node configuration defaults. P2P signature
+// extensions attributes can't be enabled if the extensions are disabled.
+func (p *Policy) enableAttribute(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	t := toToggledAttrType(args[0])
+	if !p.p2pSigExtensionsEnabled {
+		switch t {
+		case transaction.NotValidBeforeT, transaction.ConflictsT, transaction.NotaryAssistedT:
+			panic(fmt.Errorf("%s attribute can't be enabled without P2PSigExtensions", t))
+		}
+	}
+	p.setAttributeState(ic, t, true)
+	return stackitem.Null{}
+}
+
+// disableAttribute is Policy contract method and forbids transaction
+// attributes of the given type.
+func (p *Policy) disableAttribute(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	p.setAttributeState(ic, toToggledAttrType(args[0]), false)
+	return stackitem.Null{}
+}
+
+func (p *Policy) setAttributeState(ic *interop.Context, t transaction.AttrType, enabled bool) {
+	if !p.NEO.checkCommittee(ic) {
+		panic("invalid committee signature")
+	}
+	cache := ic.DAO.GetRWCache(p.ID).(*PolicyCache)
+	if old, ok := cache.attributeStates[t]; ok && old == enabled {
+		return
+	}
+	var value int64
+	if enabled {
+		value = 1
+	}
+	setIntWithKey(p.ID, ic.DAO, []byte{attributeStatePrefix, byte(t)}, value)
+	cache.attributeStates[t] = enabled
+}
+
+// IsAttributeEnabledInternal returns whether transaction attributes of the
+// given type are enabled by the committee, def is returned if they were never
+// enabled or disabled.
+func (p *Policy) IsAttributeEnabledInternal(d *dao.Simple, t transaction.AttrType, def bool) bool {
+	cache := d.GetROCache(p.ID).(*PolicyCache)
+	if enabled, ok := cache.attributeStates[t]; ok {
+		return enabled
+	}
+	return def
+}
+
+// toToggledAttrType converts the stack item to a transaction attribute type
+// that can be enabled or disabled, it's either a known or a reserved one.
+func toToggledAttrType(s stackitem.Item) transaction.AttrType {
+	v := toUint32(s)
+	if v >= uint32(transaction.ReservedLowerBound) && v <= uint32(transaction.ReservedUpperBound) {
+		return transaction.AttrType(v)
+	}
+	return toAttrType(s)
+}
+
+// getMaintenanceMode is Policy contract method and returns whether maintenance
+// mode is enabled.
+func (p *Policy) getMaintenanceMode(ic *interop.Context, _ []stackitem.Item) stackitem.Item {
+	return stackitem.NewBool(p.IsMaintenanceMode(ic.DAO))
+}
+
+// IsMaintenanceMode returns whether maintenance mode is enabled.
+func (p *Policy) IsMaintenanceMode(d *dao.Simple) bool {
+	cache := d.GetROCache(p.ID).(*PolicyCache)
+	return cache.maintenanceMode
+}
+
+// setMaintenanceMode is Policy contract method and enables or disables
+// maintenance mode.
+func (p *Policy) setMaintenanceMode(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	enabled, err := args[0].TryBool()
+	if err != nil {
+		panic(err)
+	}
+	if !p.NEO.checkCommittee(ic) {
+		panic("invalid committee signature")
+	}
+	cache := ic.DAO.GetRWCache(p.ID).(*PolicyCache)
+	if cache.maintenanceMode == enabled {
+		return stackitem.Null{}
+	}
+	var value int64
+	if enabled {
+		value = 1
+	}
+	setIntWithKey(p.ID, ic.DAO, maintenanceModeKey, value)
+	cache.maintenanceMode = enabled
+	if p.maintenanceEvents {
+		ic.Notifications = append(ic.Notifications, state.NotificationEvent{
+			ScriptHash: p.Hash,
+			Name:       MaintenanceModeChangedEventName,
+			Item:       stackitem.NewArray([]stackitem.Item{stackitem.NewBool(enabled)}),
+		})
+	}
+	return stackitem.Null{}
+}
+
+// isMaintenanceExempt is Policy contract method and checks whether provided
+// account is allowed to send transactions in maintenance mode.
+func (p *Policy) isMaintenanceExempt(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	cache := ic.DAO.GetROCache(p.ID).(*PolicyCache)
+	_, exempt := searchAccount(cache.maintenanceExempt, toUint160(args[0]))
+	return stackitem.NewBool(exempt)
+}
+
+// addMaintenanceExempt is Policy contract method and adds provided account to
+// the list of accounts allowed to send transactions in maintenance mode.
+func (p *Policy) addMaintenanceExempt(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	if !p.NEO.checkCommittee(ic) {
+		panic("invalid committee signature")
+	}
+	hash := toUint160(args[0])
+	cache := ic.DAO.GetRWCache(p.ID).(*PolicyCache)
+	i, exempt := searchAccount(cache.maintenanceExempt, hash)
+	if exempt {
+		return stackitem.NewBool(false)
+	}
+	key := append([]byte{maintenanceExemptPrefix}, hash.BytesBE()...)
+	ic.DAO.PutStorageItem(p.ID, key, state.StorageItem{})
+	cache.maintenanceExempt = append(cache.maintenanceExempt, util.Uint160{})
+	copy(cache.maintenanceExempt[i+1:], cache.maintenanceExempt[i:])
+	cache.maintenanceExempt[i] = hash
+	return stackitem.NewBool(true)
+}
+
+// removeMaintenanceExempt is Policy contract method and removes provided
+// account from the list of accounts allowed to send transactions in
+// maintenance mode.
+func (p *Policy) removeMaintenanceExempt(ic *interop.Context, args []stackitem.Item) stackitem.Item {
+	if !p.NEO.checkCommittee(ic) {
+		panic("invalid committee signature")
+	}
+	hash := toUint160(args[0])
+	cache := ic.DAO.GetRWCache(p.ID).(*PolicyCache)
+	i, exempt := searchAccount(cache.maintenanceExempt, hash)
+	if !exempt {
+		return stackitem.NewBool(false)
+	}
+	key := append([]byte{maintenanceExemptPrefix}, hash.BytesBE()...)
+	ic.DAO.DeleteStorageItem(p.ID, key)
+	cache.maintenanceExempt = append(cache.maintenanceExempt[:i], cache.maintenanceExempt[i+1:]...)
 	return stackitem.NewBool(true)
 }
 
+// IsAllowedInMaintenance checks whether the transaction can be accepted with
+// the current maintenance mode setting. It's always true if maintenance mode
+// is disabled, otherwise at least one of transaction signers must be either
+// the committee or an exempt account.
+func (p *Policy) IsAllowedInMaintenance(d *dao.Simple, tx *transaction.Transaction) bool {
+	cache := d.GetROCache(p.ID).(*PolicyCache)
+	if !cache.maintenanceMode {
+		return true
+	}
+	committee := p.NEO.GetCommitteeAddress(d)
+	for i := range tx.Signers {
+		if tx.Signers[i].Account.Equals(committee) {
+			return true
+		}
+		if _, exempt := searchAccount(cache.maintenanceExempt, tx.Signers[i].Account); exempt {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyChange emits an event with the given name about parameter change from
+// old to new value if extensions are enabled.
+func (p *Policy) notifyChange(ic *interop.Context, name string, old, new int64) {
+	if !p.extensions {
+		return
+	}
+	ic.Notifications = append(ic.Notifications, state.NotificationEvent{
+		ScriptHash: p.Hash,
+		Name:       name,
+		Item: stackitem.NewArray([]stackitem.Item{
+			stackitem.NewBigInteger(big.NewInt(old)),
+			stackitem.NewBigInteger(big.NewInt(new)),
+		}),
+	})
+}
+
+// notifyAccount emits an event with the given name about the account
+// (un)blocking if extensions are enabled.
+func (p *Policy) notifyAccount(ic *interop.Context, name string, acc util.Uint160) {
+	if !p.extensions {
+		return
+	}
+	ic.Notifications = append(ic.Notifications, state.NotificationEvent{
+		ScriptHash: p.Hash,
+		Name:       name,
+		Item: stackitem.NewArray([]stackitem.Item{
+			stackitem.NewByteArray(acc.BytesBE()),
+		}),
+	})
+}
+
 // CheckPolicy checks whether transaction conforms to current policy restrictions
-// like not being signed by blocked account or not exceeding block-level system
-// fee limit.
+// like not being signed by blocked account or not calling blocked contracts (if
+// it can be determined statically from signer scopes or the script).
 func (p *Policy) CheckPolicy(d *dao.Simple, tx *transaction.Transaction) error {
-	for _, signer := range tx.Signers {
-		if _, isBlocked := p.isBlockedInternal(d, signer.Account); isBlocked {
-			return fmt.Errorf("account %s is blocked", signer.Account.StringLE())
+	hashes := make([]util.Uint160, len(tx.Signers))
+	for i := range tx.Signers {
+		hashes[i] = tx.Signers[i].Account
+	}
+	accountsCount := len(hashes)
+	for i := range tx.Signers {
+		if tx.Signers[i].Scopes&transaction.CustomContracts != 0 {
+			hashes = append(hashes, tx.Signers[i].AllowedContracts...)
+		}
+	}
+	hashes = append(hashes, getCalledContracts(tx.Script)...)
+	if len(hashes) == 1 {
+		if _, isBlocked := p.isBlockedInternal(d, hashes[0]); isBlocked {
+			return fmt.Errorf("account %s is blocked", hashes[0].StringLE())
+		}
+	} else {
+		for i, isBlocked := range p.IsBlockedMultiInternal(d, hashes) {
+			if !isBlocked {
+				continue
+			}
+			if i < accountsCount {
+				return fmt.Errorf("account %s is blocked", hashes[i].StringLE())
+			}
+			return fmt.Errorf("contract %s is blocked", hashes[i].StringLE())
 		}
 	}
 	return nil
 }
+
+// getCalledContracts returns hashes of contracts called by the script via
+// System.Contract.Call with the hash pushed right before the syscall (that's
+// what emit.AppCall produces). Scanning stops at the first invalid
+// instruction.
+func getCalledContracts(script []byte) []util.Uint160 {
+	var (
+		res    []util.Uint160
+		prev   []byte
+		callID = interopnames.ToID([]byte(interopnames.SystemContractCall))
+		ctx    = vm.NewContext(script)
+	)
+	for ctx.NextIP() < len(script) {
+		instr, param, err := ctx.Next()
+		if err != nil {
+			break
+		}
+		if instr == opcode.SYSCALL && len(prev) == util.Uint160Size &&
+			binary.LittleEndian.Uint32(param) == callID {
+			h, _ := util.Uint160DecodeBytesBE(prev)
+			res = append(res, h)
+		}
+		prev = nil
+		if instr == opcode.PUSHDATA1 {
+			prev = param
+		}
+	}
+	return res
+}
@@ -0,0 +1,312 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"go.uber.org/zap"
+)
+
+// ErrNativeDrift is returned from NewBlockchain (wrapped into NativeDriftError)
+// when stored native contract states don't match the ones generated by the
+// current node version for the current configuration.
+var ErrNativeDrift = errors.New("native contracts drift")
+
+// NativeDrift describes the difference between stored and autogenerated
+// states of a single native contract.
+type NativeDrift struct {
+	// Name is the native contract name.
+	Name string
+	// Activation is a non-empty description of the problem if the contract is
+	// stored, but inactive according to NativeActivations setting or vice
+	// versa. Other fields are empty in this case.
+	Activation string
+
+	AddedMethods   []string
+	RemovedMethods []string
+	// ChangedMethods contains methods with the same name and number of
+	// parameters, but different parameter names/types, return type or
+	// safe flag.
+	ChangedMethods []string
+
+	AddedEvents   []string
+	RemovedEvents []string
+	// ChangedEvents contains events with different parameters.
+	ChangedEvents []string
+
+	// Other lists differences not related to methods and events (ID, hash,
+	// NEF, other manifest fields).
+	Other []string
+
+	// ConfigSetting is the name of the configuration setting explaining the
+	// drift, if it's set the autogenerated state matches the stored one with
+	// different setting value (or different activation height).
+	ConfigSetting string
+}
+
+// NativeDriftError contains all native contract drifts found.
+type NativeDriftError struct {
+	Drifts []NativeDrift
+}
+
+// nativeDriftSettings are the configuration settings affecting native
+// contract states, they're used to check whether drift can be explained by
+// a configuration change.
+var nativeDriftSettings = []struct {
+	name   string
+	toggle func(c *config.ProtocolConfiguration)
+}{
+	{"HeaderCommitment", func(c *config.ProtocolConfiguration) { c.HeaderCommitment = !c.HeaderCommitment }},
+	{"PolicyExtensions", func(c *config.ProtocolConfiguration) { c.PolicyExtensions = !c.PolicyExtensions }},
+	{"MaintenanceModeEvents", func(c *config.ProtocolConfiguration) { c.MaintenanceModeEvents = !c.MaintenanceModeEvents }},
+	{"ValidatorsChangedEvents", func(c *config.ProtocolConfiguration) { c.ValidatorsChangedEvents = !c.ValidatorsChangedEvents }},
+}
+
+// IsAdditive returns true if the drift consists of new methods and events
+// only, so that it's a compatible contract extension.
+func (d *NativeDrift) IsAdditive() bool {
+	return d.Activation == "" && len(d.RemovedMethods) == 0 && len(d.ChangedMethods) == 0 &&
+		len(d.RemovedEvents) == 0 && len(d.ChangedEvents) == 0 && len(d.Other) == 0 &&
+		len(d.AddedMethods)+len(d.AddedEvents) != 0
+}
+
+// String implements fmt.Stringer interface.
+func (d *NativeDrift) String() string {
+	var sb strings.Builder
+	if d.Activation != "" {
+		sb.WriteString("native contract " + d.Name + " " + d.Activation)
+	} else {
+		sb.WriteString("native " + d.Name + ": version mismatch (stored contract state differs from autogenerated one)")
+		for _, l := range []struct {
+			name string
+			list []string
+		}{
+			{"added methods", d.AddedMethods},
+			{"removed methods", d.RemovedMethods},
+			{"changed methods", d.ChangedMethods},
+			{"added events", d.AddedEvents},
+			{"removed events", d.RemovedEvents},
+			{"changed events", d.ChangedEvents},
+			{"other", d.Other},
+		} {
+			if len(l.list) != 0 {
+				sb.WriteString("; " + l.name + ": " + strings.Join(l.list, ", "))
+			}
+		}
+	}
+	switch {
+	case d.ConfigSetting != "":
+		sb.WriteString(" (explained by " + d.ConfigSetting + " setting, check the configuration)")
+	case d.IsAdditive():
+		sb.WriteString(" (additive, resynchronize the node from the genesis to apply it)")
+	default:
+		sb.WriteString(" (incompatible, try to resynchronize the node from the genesis or roll back)")
+	}
+	return sb.String()
+}
+
+// Error implements error interface.
+func (e *NativeDriftError) Error() string {
+	s := make([]string, len(e.Drifts))
+	for i := range e.Drifts {
+		s[i] = e.Drifts[i].String()
+	}
+	return ErrNativeDrift.Error() + ": " + strings.Join(s, "; ")
+}
+
+// Unwrap allows to check for ErrNativeDrift with errors.Is.
+func (e *NativeDriftError) Unwrap() error {
+	return ErrNativeDrift
+}
+
+// IsAdditive returns true if all drifts are additive.
+func (e *NativeDriftError) IsAdditive() bool {
+	for i := range e.Drifts {
+		if !e.Drifts[i].IsAdditive() {
+			return false
+		}
+	}
+	return true
+}
+
+// checkNativeDrift compares autogenerated native contracts' states with the
+// stored ones and returns NativeDriftError if they differ. Stored states are
+// never changed here, they're a part of the MPT and can only be updated via
+// block processing (see NativeActivations setting).
+// Need to be done after native Management cache initialisation to be able to
+// get contract state from DAO via high-level bc API.
+func (bc *Blockchain) checkNativeDrift(height uint32) error {
+	var drifts []NativeDrift
+	for _, c := range bc.contracts.Contracts {
+		md := c.Metadata()
+		storedCS := bc.GetContractState(md.Hash)
+		history := md.UpdateHistory
+		if len(history) == 0 || history[0] > height {
+			if storedCS != nil {
+				drifts = append(drifts, NativeDrift{
+					Name:          md.Name,
+					Activation:    fmt.Sprintf("is already stored, but marked as inactive for height %d in config", height),
+					ConfigSetting: "NativeActivations",
+				})
+			}
+			continue
+		}
+		if storedCS == nil {
+			drifts = append(drifts, NativeDrift{
+				Name:          md.Name,
+				Activation:    fmt.Sprintf("is not stored, but should be active at height %d according to config", height),
+				ConfigSetting: "NativeActivations",
+			})
+			continue
+		}
+		autogenCS := &state.Contract{
+			ContractBase:  md.ContractBase,
+			UpdateCounter: storedCS.UpdateCounter, // it can be restored only from the DB, so use the stored value.
+		}
+		eq, err := equalContractStates(storedCS, autogenCS)
+		if err != nil {
+			return fmt.Errorf("failed to check native %s state against autogenerated one: %w", md.Name, err)
+		}
+		if eq {
+			continue
+		}
+		d := diffNativeStates(storedCS, autogenCS)
+		d.ConfigSetting, err = bc.explainNativeDrift(storedCS)
+		if err != nil {
+			return fmt.Errorf("failed to check native %s state against autogenerated one: %w", md.Name, err)
+		}
+		drifts = append(drifts, d)
+	}
+	if len(drifts) == 0 {
+		return nil
+	}
+	dErr := &NativeDriftError{Drifts: drifts}
+	bc.log.Error("native contracts drift detected", zap.Error(dErr))
+	return dErr
+}
+
+// explainNativeDrift returns the name of the configuration setting which
+// makes autogenerated state of the native contract the same as the stored one
+// if there is any.
+func (bc *Blockchain) explainNativeDrift(storedCS *state.Contract) (string, error) {
+	for _, s := range nativeDriftSettings {
+		cfg := bc.config
+		s.toggle(&cfg)
+		var c interop.Contract
+		for _, n := range native.NewContracts(cfg).Contracts {
+			if n.Metadata().Hash.Equals(storedCS.Hash) {
+				c = n
+				break
+			}
+		}
+		if c == nil {
+			continue
+		}
+		eq, err := equalContractStates(storedCS, &state.Contract{
+			ContractBase:  c.Metadata().ContractBase,
+			UpdateCounter: storedCS.UpdateCounter,
+		})
+		if err != nil {
+			return "", err
+		}
+		if eq {
+			return s.name, nil
+		}
+	}
+	return "", nil
+}
+
+func equalContractStates(a, b *state.Contract) (bool, error) {
+	aBytes, err := stackitem.SerializeConvertible(a)
+	if err != nil {
+		return false, err
+	}
+	bBytes, err := stackitem.SerializeConvertible(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(aBytes, bBytes), nil
+}
+
+// diffNativeStates returns the list of differences between stored and
+// autogenerated native contract states.
+func diffNativeStates(stored, autogen *state.Contract) NativeDrift {
+	var (
+		d     = NativeDrift{Name: autogen.Manifest.Name}
+		sABI  = &stored.Manifest.ABI
+		aABI  = &autogen.Manifest.ABI
+		sMeth = make(map[string]*manifest.Method, len(sABI.Methods))
+	)
+	methodKey := func(m *manifest.Method) string {
+		return m.Name + "/" + strconv.Itoa(len(m.Parameters))
+	}
+	for i := range sABI.Methods {
+		sMeth[methodKey(&sABI.Methods[i])] = &sABI.Methods[i]
+	}
+	for i := range aABI.Methods {
+		m := &aABI.Methods[i]
+		k := methodKey(m)
+		sm, ok := sMeth[k]
+		switch {
+		case !ok:
+			d.AddedMethods = append(d.AddedMethods, k)
+		case !reflect.DeepEqual(sm.Parameters, m.Parameters) || sm.ReturnType != m.ReturnType || sm.Safe != m.Safe:
+			// Offsets are not compared, they're defined by NEF script.
+			d.ChangedMethods = append(d.ChangedMethods, k)
+		}
+		delete(sMeth, k)
+	}
+	for i := range sABI.Methods {
+		if k := methodKey(&sABI.Methods[i]); sMeth[k] != nil {
+			d.RemovedMethods = append(d.RemovedMethods, k)
+		}
+	}
+
+	for i := range aABI.Events {
+		e := &aABI.Events[i]
+		se := sABI.GetEvent(e.Name)
+		switch {
+		case se == nil:
+			d.AddedEvents = append(d.AddedEvents, e.Name)
+		case !reflect.DeepEqual(se.Parameters, e.Parameters):
+			d.ChangedEvents = append(d.ChangedEvents, e.Name)
+		}
+	}
+	for i := range sABI.Events {
+		if aABI.GetEvent(sABI.Events[i].Name) == nil {
+			d.RemovedEvents = append(d.RemovedEvents, sABI.Events[i].Name)
+		}
+	}
+
+	if stored.ID != autogen.ID {
+		d.Other = append(d.Other, fmt.Sprintf("ID (%d vs %d)", stored.ID, autogen.ID))
+	}
+	if !stored.Hash.Equals(autogen.Hash) {
+		d.Other = append(d.Other, "hash")
+	}
+	// NEF script contains method stubs, so it's expected to change along
+	// with the method set.
+	methodsChanged := len(d.AddedMethods)+len(d.RemovedMethods) != 0
+	if !methodsChanged && !reflect.DeepEqual(stored.NEF, autogen.NEF) {
+		d.Other = append(d.Other, "NEF")
+	}
+	sm, am := stored.Manifest, autogen.Manifest
+	sm.ABI, am.ABI = manifest.ABI{}, manifest.ABI{}
+	sj, sErr := json.Marshal(sm)
+	aj, aErr := json.Marshal(am)
+	if sErr != nil || aErr != nil || !bytes.Equal(sj, aj) {
+		d.Other = append(d.Other, "manifest")
+	}
+	return d
+}
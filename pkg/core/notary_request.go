@@ -0,0 +1,209 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/fee"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativeprices"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/network/payload"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+)
+
+// NotaryMainTxParams contains parameters of the main transaction of notary
+// request built by BuildNotaryRequest.
+type NotaryMainTxParams struct {
+	// Script is the main transaction script.
+	Script []byte
+	// SystemFee is the main transaction system fee.
+	SystemFee int64
+	// Signers are the main transaction signers excluding Notary contract
+	// (it's added by BuildNotaryRequest as the last signer). The first one
+	// pays the fees.
+	Signers []transaction.Signer
+	// VerificationScripts are the verification scripts of Signers, only
+	// standard signature and multisignature contracts are supported.
+	VerificationScripts [][]byte
+	// Attributes are additional main transaction attributes,
+	// NotaryAssisted attribute is added by BuildNotaryRequest.
+	Attributes []transaction.Attribute
+	// ValidUntilBlock is the ValidUntilBlock of both main and fallback
+	// transactions.
+	ValidUntilBlock uint32
+}
+
+// NotaryFallbackTxParams contains parameters of the fallback transaction of
+// notary request built by BuildNotaryRequest.
+type NotaryFallbackTxParams struct {
+	// Script is the fallback transaction script.
+	Script []byte
+	// SystemFee is the fallback transaction system fee.
+	SystemFee int64
+	// ValidFor is the number of blocks (up to MaxNotValidBeforeDelta) before
+	// the main transaction expiration fallback transaction is valid for.
+	ValidFor uint32
+}
+
+// BuildNotaryRequest creates P2PNotaryRequest payload with the main and
+// fallback transactions for the given parameters. Fallback transaction fees
+// are paid from the Notary deposit of the account with depositor verification
+// script which also signs the request, nKeys is the number of keys main
+// transaction signatures are to be collected from by the Notary service (it
+// must match the main transaction signers). Network fees of both transactions
+// are calculated exactly for the current chain state and all witnesses are
+// set, but only the dummy Notary ones are filled. So the request is to be
+// signed in the following order: main transaction witnesses (except the
+// Notary one), fallback transaction witness of the depositor (the second one)
+// and then the request witness.
+func (bc *Blockchain) BuildNotaryRequest(main NotaryMainTxParams, fallback NotaryFallbackTxParams, depositor []byte, nKeys uint8) (*payload.P2PNotaryRequest, error) {
+	if !bc.P2PSigExtensionsEnabled() {
+		return nil, errors.New("P2PSigExtensions are disabled")
+	}
+	if nKeys == 0 {
+		return nil, errors.New("main transaction should have NKeys > 0")
+	}
+	if len(main.Signers) != len(main.VerificationScripts) {
+		return nil, fmt.Errorf("main transaction signers and verification scripts mismatch: %d vs %d",
+			len(main.Signers), len(main.VerificationScripts))
+	}
+	notaryHash := bc.contracts.Notary.Hash
+	var nKeysActual int
+	for i, s := range main.VerificationScripts {
+		if !main.Signers[i].Account.Equals(hash.Hash160(s)) {
+			return nil, fmt.Errorf("invalid verification script for signer #%d", i)
+		}
+		if vm.IsSignatureContract(s) {
+			nKeysActual++
+		} else if _, pubs, ok := vm.ParseMultiSigContract(s); ok {
+			nKeysActual += len(pubs)
+		} else {
+			return nil, fmt.Errorf("signer #%d: only signature and multisignature contracts are supported", i)
+		}
+	}
+	if nKeysActual != int(nKeys) {
+		return nil, fmt.Errorf("expected and actual NKeys mismatch: %d vs %d", nKeys, nKeysActual)
+	}
+
+	var (
+		height      = bc.BlockHeight()
+		maxNVBDelta = bc.GetMaxNotValidBeforeDelta()
+	)
+	if main.ValidUntilBlock <= height {
+		return nil, fmt.Errorf("ValidUntilBlock %d is not above current height %d", main.ValidUntilBlock, height)
+	}
+	if fallback.ValidFor == 0 || fallback.ValidFor > maxNVBDelta || fallback.ValidFor > main.ValidUntilBlock {
+		return nil, fmt.Errorf("fallback transaction should be valid for 1 to %d blocks", maxNVBDelta)
+	}
+	nvb := main.ValidUntilBlock - fallback.ValidFor + 1
+	if nvb > height+maxNVBDelta {
+		return nil, fmt.Errorf("fallback transaction should become valid not later than %d blocks after current height %d", maxNVBDelta, height)
+	}
+
+	mainTx := transaction.New(main.Script, main.SystemFee)
+	mainTx.ValidUntilBlock = main.ValidUntilBlock
+	mainTx.Signers = append(append(mainTx.Signers, main.Signers...), transaction.Signer{Account: notaryHash})
+	mainTx.Attributes = append(append(mainTx.Attributes, main.Attributes...), transaction.Attribute{
+		Type:  transaction.NotaryAssistedT,
+		Value: &transaction.NotaryAssisted{NKeys: nKeys},
+	})
+	scripts := append(make([][]byte, 0, len(main.VerificationScripts)+1), main.VerificationScripts...)
+	netFee, err := bc.notaryAssistedNetworkFee(mainTx, append(scripts, nil))
+	if err != nil {
+		return nil, err
+	}
+	mainTx.NetworkFee = netFee
+	for _, s := range main.VerificationScripts {
+		mainTx.Scripts = append(mainTx.Scripts, transaction.Witness{VerificationScript: s})
+	}
+	mainTx.Scripts = append(mainTx.Scripts, dummyNotaryWitness())
+
+	payer := hash.Hash160(depositor)
+	fallbackTx := transaction.New(fallback.Script, fallback.SystemFee)
+	fallbackTx.ValidUntilBlock = main.ValidUntilBlock
+	fallbackTx.Signers = []transaction.Signer{{Account: notaryHash}, {Account: payer}}
+	fallbackTx.Attributes = []transaction.Attribute{
+		{
+			Type:  transaction.NotaryAssistedT,
+			Value: &transaction.NotaryAssisted{NKeys: 0},
+		},
+		{
+			Type:  transaction.NotValidBeforeT,
+			Value: &transaction.NotValidBefore{Height: nvb},
+		},
+		{
+			Type:  transaction.ConflictsT,
+			Value: &transaction.Conflicts{Hash: mainTx.Hash()},
+		},
+	}
+	netFee, err = bc.notaryAssistedNetworkFee(fallbackTx, [][]byte{nil, depositor})
+	if err != nil {
+		return nil, fmt.Errorf("depositor: %w", err)
+	}
+	fallbackTx.NetworkFee = netFee
+	fallbackTx.Scripts = []transaction.Witness{dummyNotaryWitness(), {VerificationScript: depositor}}
+
+	// Fallback transaction fees are paid from the deposit if main transaction
+	// is not completed, the deposit also must not be withdrawn before fallback
+	// transaction expiration.
+	need := big.NewInt(fallbackTx.SystemFee + fallbackTx.NetworkFee)
+	if balance := bc.GetNotaryBalance(payer); balance.Cmp(need) < 0 {
+		return nil, fmt.Errorf("insufficient Notary deposit: %s, need %s", balance, need)
+	}
+	if till := bc.GetNotaryDepositExpiration(payer); till <= fallbackTx.ValidUntilBlock {
+		return nil, fmt.Errorf("fallback transaction is valid after deposit is unlocked: ValidUntilBlock is %d, deposit lock expires at %d",
+			fallbackTx.ValidUntilBlock, till)
+	}
+
+	return &payload.P2PNotaryRequest{
+		MainTransaction:     mainTx,
+		FallbackTransaction: fallbackTx,
+		Witness:             transaction.Witness{VerificationScript: depositor},
+	}, nil
+}
+
+// notaryAssistedNetworkFee calculates network fee for the given NotaryAssisted
+// transaction without witnesses, verification scripts are the scripts of its
+// signers with nil for the Notary contract.
+func (bc *Blockchain) notaryAssistedNetworkFee(tx *transaction.Transaction, verificationScripts [][]byte) (int64, error) {
+	var (
+		baseFee = bc.GetBaseExecFee()
+		size    = io.GetVarSize(tx)
+		netFee  int64
+	)
+	for i, s := range verificationScripts {
+		if s == nil {
+			w := dummyNotaryWitness()
+			size += io.GetVarSize(w.InvocationScript) + io.GetVarSize(w.VerificationScript)
+			netFee += fee.Opcode(baseFee,
+				opcode.PUSHDATA1, opcode.RET, // Invocation script.
+				opcode.PUSH0, opcode.SYSCALL, opcode.RET) + // System.Contract.CallNative.
+				nativeprices.NotaryVerificationPrice*baseFee
+			continue
+		}
+		sigFee, sizeDelta := fee.Calculate(baseFee, s)
+		if sizeDelta == 0 {
+			return 0, fmt.Errorf("signer #%d: only signature and multisignature contracts are supported", i)
+		}
+		netFee += sigFee
+		size += sizeDelta
+	}
+	na := tx.GetAttributes(transaction.NotaryAssistedT)[0].Value.(*transaction.NotaryAssisted)
+	netFee += int64(size)*bc.FeePerByte() +
+		(int64(na.NKeys)+1)*bc.GetNotaryServiceFeePerKey() +
+		bc.GetAttributesFee(tx)
+	return netFee, nil
+}
+
+// dummyNotaryWitness returns Notary contract witness placeholder which is
+// replaced by the Notary service.
+func dummyNotaryWitness() transaction.Witness {
+	return transaction.Witness{
+		InvocationScript:   append([]byte{byte(opcode.PUSHDATA1), 64}, make([]byte, 64)...),
+		VerificationScript: []byte{},
+	}
+}
@@ -749,3 +749,142 @@ func TestNotary(t *testing.T) {
 	}, 3*time.Second, 100*time.Millisecond)
 	checkFallbackTxs(t, requests, false)
 }
+
+func TestBlockchain_BuildNotaryRequest(t *testing.T) {
+	bc, acc := chain.NewSingleWithCustomConfig(t, func(c *config.ProtocolConfiguration) {
+		c.P2PSigExtensions = true
+	})
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	notaryHash := bc.GetNotaryContractScriptHash()
+	gasInvoker := e.CommitteeInvoker(e.NativeHash(t, nativenames.Gas))
+	designationInvoker := e.CommitteeInvoker(e.NativeHash(t, nativenames.Designation))
+
+	notaryNode, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+	designationInvoker.Invoke(t, stackitem.Null{}, "designateAsRole",
+		int64(roles.P2PNotary), []interface{}{notaryNode.PublicKey().Bytes()})
+
+	requester := e.NewAccount(t).(neotest.SingleSigner)
+	depositor := e.NewAccount(t).(neotest.SingleSigner)
+	gasInvoker.Invoke(t, true, "transfer", acc.ScriptHash(), notaryHash, 10_0000_0000,
+		[]interface{}{depositor.ScriptHash(), int64(bc.BlockHeight() + 100)})
+
+	mainParams := func() core.NotaryMainTxParams {
+		return core.NotaryMainTxParams{
+			Script:              []byte{byte(opcode.PUSH1)},
+			SystemFee:           1000_0000,
+			Signers:             []transaction.Signer{{Account: requester.ScriptHash(), Scopes: transaction.CalledByEntry}},
+			VerificationScripts: [][]byte{requester.Script()},
+			ValidUntilBlock:     bc.BlockHeight() + 10,
+		}
+	}
+	fallbackParams := core.NotaryFallbackTxParams{
+		Script:   []byte{byte(opcode.RET)},
+		ValidFor: 5,
+	}
+	build := func(t *testing.T) *payload.P2PNotaryRequest {
+		r, err := bc.BuildNotaryRequest(mainParams(), fallbackParams, depositor.Script(), 1)
+		require.NoError(t, err)
+		return r
+	}
+	// reload drops cached transaction hash and size after modification.
+	reload := func(t *testing.T, tx *transaction.Transaction) *transaction.Transaction {
+		tx, err := transaction.NewTransactionFromBytes(tx.Bytes())
+		require.NoError(t, err)
+		return tx
+	}
+	// sign signs the request in the proper order: main transaction, fallback
+	// transaction and the request itself.
+	sign := func(r *payload.P2PNotaryRequest) {
+		r.MainTransaction.Scripts[0].InvocationScript = requester.SignHashable(uint32(netmode.UnitTestNet), r.MainTransaction)
+		r.FallbackTransaction.Scripts[1].InvocationScript = depositor.SignHashable(uint32(netmode.UnitTestNet), r.FallbackTransaction)
+		r.Witness.InvocationScript = depositor.SignHashable(uint32(netmode.UnitTestNet), r)
+	}
+	// complete adds Notary node witness to the main transaction.
+	complete := func(tx *transaction.Transaction) {
+		tx.Scripts[1].InvocationScript = append([]byte{byte(opcode.PUSHDATA1), 64}, notaryNode.SignHashable(uint32(netmode.UnitTestNet), tx)...)
+	}
+	verificationF := func(_ *transaction.Transaction, data interface{}) error {
+		r := data.(*payload.P2PNotaryRequest)
+		payer := r.FallbackTransaction.Signers[1].Account
+		if _, err := bc.VerifyWitness(payer, r, &r.Witness, bc.GetMaxVerificationGAS()); err != nil {
+			return err
+		}
+		if r.FallbackTransaction.ValidUntilBlock >= bc.GetNotaryDepositExpiration(payer) {
+			return errors.New("fallback transaction is valid after deposit is unlocked")
+		}
+		return nil
+	}
+	pool := func(r *payload.P2PNotaryRequest) error {
+		mp := mempool.New(10, 1, true)
+		return bc.PoolTxWithData(r.FallbackTransaction, r, mp, network.NewNotaryFeer(bc), verificationF)
+	}
+
+	t.Run("good", func(t *testing.T) {
+		r := build(t)
+		sign(r)
+		require.NoError(t, pool(dupNotaryRequest(t, r)))
+
+		complete(r.MainTransaction)
+		require.NoError(t, bc.VerifyTx(r.MainTransaction))
+		e.AddNewBlock(t, r.MainTransaction)
+		e.CheckHalt(t, r.MainTransaction.Hash(), stackitem.Make(1))
+	})
+	t.Run("main network fee", func(t *testing.T) {
+		r := build(t)
+		r.MainTransaction.NetworkFee--
+		r.MainTransaction = reload(t, r.MainTransaction)
+		sign(r)
+		complete(r.MainTransaction)
+		require.Error(t, bc.VerifyTx(reload(t, r.MainTransaction)))
+	})
+	t.Run("fallback network fee", func(t *testing.T) {
+		r := build(t)
+		r.FallbackTransaction.NetworkFee--
+		sign(r)
+		require.Error(t, pool(r))
+	})
+	t.Run("fallback NotValidBefore", func(t *testing.T) {
+		r := build(t)
+		nvb := r.FallbackTransaction.GetAttributes(transaction.NotValidBeforeT)[0].Value.(*transaction.NotValidBefore)
+		nvb.Height = bc.BlockHeight() + bc.GetMaxNotValidBeforeDelta() + 1
+		sign(r)
+		require.True(t, errors.Is(pool(r), core.ErrInvalidAttribute))
+	})
+	t.Run("invalid parameters", func(t *testing.T) {
+		_, err := bc.BuildNotaryRequest(mainParams(), fallbackParams, depositor.Script(), 2)
+		require.Error(t, err) // NKeys mismatch.
+
+		p := mainParams()
+		p.VerificationScripts = [][]byte{depositor.Script()}
+		_, err = bc.BuildNotaryRequest(p, fallbackParams, depositor.Script(), 1)
+		require.Error(t, err)
+
+		p = mainParams()
+		p.ValidUntilBlock = bc.BlockHeight()
+		_, err = bc.BuildNotaryRequest(p, fallbackParams, depositor.Script(), 1)
+		require.Error(t, err)
+
+		fp := fallbackParams
+		fp.ValidFor = bc.GetMaxNotValidBeforeDelta() + 1
+		_, err = bc.BuildNotaryRequest(mainParams(), fp, depositor.Script(), 1)
+		require.Error(t, err)
+	})
+	t.Run("insufficient deposit", func(t *testing.T) {
+		_, err := bc.BuildNotaryRequest(mainParams(), fallbackParams, requester.Script(), 1)
+		require.Error(t, err)
+
+		fp := fallbackParams
+		fp.SystemFee = 10_0000_0000
+		_, err = bc.BuildNotaryRequest(mainParams(), fp, depositor.Script(), 1)
+		require.Error(t, err)
+	})
+	t.Run("deposit expiration", func(t *testing.T) {
+		p := mainParams()
+		p.ValidUntilBlock = bc.GetNotaryDepositExpiration(depositor.ScriptHash())
+		fp := fallbackParams
+		fp.ValidFor = p.ValidUntilBlock - bc.BlockHeight()
+		_, err := bc.BuildNotaryRequest(p, fp, depositor.Script(), 1)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,182 @@
+package core
+
+import (
+	"sync"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/mempool"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// TxRebroadcastState is the state of locally submitted transaction tracked
+// for rebroadcasting.
+type TxRebroadcastState byte
+
+const (
+	// TxRebroadcastPending means that transaction is in the mempool and will
+	// be rebroadcasted if it stays there long enough.
+	TxRebroadcastPending TxRebroadcastState = iota
+	// TxRebroadcastExhausted means that transaction is still in the mempool,
+	// but the maximum number of rebroadcasts is reached.
+	TxRebroadcastExhausted
+	// TxRebroadcastConfirmed means that transaction is included into a block.
+	TxRebroadcastConfirmed
+	// TxRebroadcastExpired means that transaction is removed from the mempool
+	// because of its ValidUntilBlock.
+	TxRebroadcastExpired
+	// TxRebroadcastDropped means that transaction is removed from the mempool
+	// without being included into a block before its expiration (because of
+	// a conflicting transaction accepted, policy change, mempool eviction,
+	// etc.).
+	TxRebroadcastDropped
+)
+
+// TxRebroadcastStatus is the rebroadcast status of locally submitted
+// transaction.
+type TxRebroadcastStatus struct {
+	State TxRebroadcastState
+	// PooledAt is the chain height the transaction was pooled at.
+	PooledAt uint32
+	// Retries is the number of rebroadcasts made.
+	Retries int
+	// NextAt is the height of the block the next rebroadcast is scheduled
+	// after, it's only relevant for pending transactions.
+	NextAt uint32
+}
+
+// txRebroadcaster tracks locally submitted transactions and schedules them for
+// rebroadcasting if they stay in the mempool for too long. The first
+// rebroadcast happens threshold blocks after pooling, the interval is then
+// doubled after every rebroadcast.
+type txRebroadcaster struct {
+	lock       sync.RWMutex
+	threshold  uint32
+	maxRetries int
+	announce   func(*transaction.Transaction)
+	txs        map[util.Uint256]*rebroadcastItem
+}
+
+type rebroadcastItem struct {
+	tx     *transaction.Transaction
+	status TxRebroadcastStatus
+}
+
+// String implements fmt.Stringer interface.
+func (s TxRebroadcastState) String() string {
+	switch s {
+	case TxRebroadcastPending:
+		return "pending"
+	case TxRebroadcastExhausted:
+		return "exhausted"
+	case TxRebroadcastConfirmed:
+		return "confirmed"
+	case TxRebroadcastExpired:
+		return "expired"
+	case TxRebroadcastDropped:
+		return "dropped"
+	default:
+		return "unknown"
+	}
+}
+
+// isFinal returns true if the transaction is no longer in the mempool.
+func (s TxRebroadcastState) isFinal() bool {
+	return s >= TxRebroadcastConfirmed
+}
+
+func newTxRebroadcaster() *txRebroadcaster {
+	return &txRebroadcaster{txs: make(map[util.Uint256]*rebroadcastItem)}
+}
+
+// set changes rebroadcasting settings, zero threshold or nil announce disable
+// rebroadcasting and drop all tracked transactions.
+func (r *txRebroadcaster) set(threshold uint32, maxRetries int, announce func(*transaction.Transaction)) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if threshold == 0 || announce == nil {
+		threshold, announce = 0, nil
+		r.txs = make(map[util.Uint256]*rebroadcastItem)
+	}
+	r.threshold = threshold
+	r.maxRetries = maxRetries
+	r.announce = announce
+}
+
+// track starts tracking of the transaction pooled at the given height.
+func (r *txRebroadcaster) track(tx *transaction.Transaction, height uint32) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.announce == nil {
+		return
+	}
+	r.txs[tx.Hash()] = &rebroadcastItem{
+		tx: tx,
+		status: TxRebroadcastStatus{
+			State:    TxRebroadcastPending,
+			PooledAt: height,
+			NextAt:   height + r.threshold,
+		},
+	}
+}
+
+// getStatus returns the status of the tracked transaction.
+func (r *txRebroadcaster) getStatus(h util.Uint256) (TxRebroadcastStatus, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	it, ok := r.txs[h]
+	if !ok {
+		return TxRebroadcastStatus{}, false
+	}
+	return it.status, true
+}
+
+// onBlock updates transaction states after the new block addition and
+// rebroadcasts transactions that stay in the mempool for too long. Statuses
+// of transactions that are no longer in the mempool are kept until their
+// ValidUntilBlock.
+func (r *txRebroadcaster) onBlock(b *block.Block, pool *mempool.Pool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if len(r.txs) == 0 {
+		return
+	}
+	var (
+		included = make(map[util.Uint256]bool, len(b.Transactions))
+		due      []*transaction.Transaction
+	)
+	for _, tx := range b.Transactions {
+		included[tx.Hash()] = true
+	}
+	for h, it := range r.txs {
+		st := &it.status
+		switch {
+		case st.State.isFinal():
+			if b.Index > it.tx.ValidUntilBlock {
+				delete(r.txs, h)
+			}
+		case included[h]:
+			st.State = TxRebroadcastConfirmed
+		case !pool.ContainsKey(h):
+			if it.tx.ValidUntilBlock <= b.Index {
+				st.State = TxRebroadcastExpired
+			} else {
+				st.State = TxRebroadcastDropped
+			}
+		case st.State == TxRebroadcastPending && b.Index >= st.NextAt:
+			due = append(due, it.tx)
+			st.Retries++
+			st.NextAt = b.Index + r.threshold<<st.Retries
+			if r.maxRetries != 0 && st.Retries >= r.maxRetries {
+				st.State = TxRebroadcastExhausted
+			}
+		}
+	}
+	if len(due) != 0 {
+		go func(announce func(*transaction.Transaction)) {
+			for _, tx := range due {
+				announce(tx)
+			}
+		}(r.announce)
+	}
+}
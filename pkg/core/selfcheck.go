@@ -0,0 +1,268 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/block"
+	"github.com/nspcc-dev/neo-go/pkg/core/state"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+)
+
+// MaxSelfCheckIssues is the maximum number of issues SelfCheck keeps in its
+// result, the rest of them is only counted.
+const MaxSelfCheckIssues = 1000
+
+// SelfCheckKind is a classification of inconsistencies found by SelfCheck.
+type SelfCheckKind byte
+
+// Various kinds of SelfCheck issues.
+const (
+	// SelfCheckHeaderHashList means that the header hash list doesn't match
+	// headers stored in the DB.
+	SelfCheckHeaderHashList SelfCheckKind = iota
+	// SelfCheckHeaderLink means that the header doesn't reference the
+	// previous one properly.
+	SelfCheckHeaderLink
+	// SelfCheckWitness means that the header witness is invalid.
+	SelfCheckWitness
+	// SelfCheckTransaction means that some block transaction is missing or
+	// can't be decoded.
+	SelfCheckTransaction
+	// SelfCheckMerkleRoot means that the block merkle root doesn't match
+	// stored transactions.
+	SelfCheckMerkleRoot
+	// SelfCheckAppExecResult means that execution results are missing for
+	// the block or some of its transactions.
+	SelfCheckAppExecResult
+	// SelfCheckStateRoot means that local state roots are missing or don't
+	// form a proper chain.
+	SelfCheckStateRoot
+)
+
+// SelfCheckIssue is a single inconsistency found by SelfCheck.
+type SelfCheckIssue struct {
+	Index uint32
+	Kind  SelfCheckKind
+	Err   error
+}
+
+// SelfCheckResult contains the results of SelfCheck.
+type SelfCheckResult struct {
+	// From and To are the first and the last checked block indexes.
+	From uint32
+	To   uint32
+	// Issues contains found inconsistencies, at most MaxSelfCheckIssues of
+	// them.
+	Issues []SelfCheckIssue
+	// Skipped is the number of issues that didn't fit into Issues.
+	Skipped int
+}
+
+// String implements fmt.Stringer interface.
+func (k SelfCheckKind) String() string {
+	switch k {
+	case SelfCheckHeaderHashList:
+		return "header hash list"
+	case SelfCheckHeaderLink:
+		return "header link"
+	case SelfCheckWitness:
+		return "witness"
+	case SelfCheckTransaction:
+		return "transaction"
+	case SelfCheckMerkleRoot:
+		return "merkle root"
+	case SelfCheckAppExecResult:
+		return "application execution result"
+	case SelfCheckStateRoot:
+		return "state root"
+	default:
+		return fmt.Sprintf("unknown (%d)", byte(k))
+	}
+}
+
+// Error implements error interface.
+func (i SelfCheckIssue) Error() string {
+	return fmt.Sprintf("block %d: %s: %v", i.Index, i.Kind, i.Err)
+}
+
+// OK returns true if no issues were found.
+func (r *SelfCheckResult) OK() bool {
+	return len(r.Issues) == 0 && r.Skipped == 0
+}
+
+func (r *SelfCheckResult) add(index uint32, kind SelfCheckKind, err error) {
+	if len(r.Issues) >= MaxSelfCheckIssues {
+		r.Skipped++
+		return
+	}
+	r.Issues = append(r.Issues, SelfCheckIssue{Index: index, Kind: kind, Err: err})
+}
+
+// SelfCheck verifies internal consistency of the last depth blocks stored in
+// the DB (or all of them if depth is 0 or exceeds the chain height). If
+// RemoveUntraceableBlocks is enabled, only the last MaxTraceableBlocks blocks
+// are checked since the data for older ones is removed. It checks
+// header hash list against stored headers, header linkage and witnesses,
+// transactions and merkle roots, presence of execution results and local
+// state roots continuity. It doesn't change anything in the DB and reports
+// all issues found instead of stopping at the first one, so it's suitable to
+// be run after unclean shutdown before Run.
+func (bc *Blockchain) SelfCheck(depth uint32) *SelfCheckResult {
+	bc.addLock.Lock()
+	defer bc.addLock.Unlock()
+
+	var (
+		res = &SelfCheckResult{To: bc.BlockHeight()}
+		err error
+
+		prev     *block.Header
+		prevRoot *state.MPTRoot
+		syncP    uint32
+	)
+	if depth != 0 && depth <= res.To {
+		res.From = res.To - depth + 1
+	}
+	if mtb := bc.config.MaxTraceableBlocks; bc.config.RemoveUntraceableBlocks && res.To >= mtb &&
+		res.From < res.To-mtb+1 {
+		// Only headers are kept for older blocks, there is nothing to check.
+		res.From = res.To - mtb + 1
+	}
+	if res.From > 0 {
+		prev, err = bc.selfCheckHeader(res.From - 1)
+		if err != nil {
+			res.add(res.From-1, SelfCheckHeaderHashList, err)
+		}
+		prevRoot, _ = bc.stateRoot.GetStateRoot(res.From - 1)
+	}
+	if bc.config.P2PStateExchangeExtensions {
+		syncP, _ = bc.dao.GetStateSyncPoint()
+	}
+	for i := res.From; i <= res.To; i++ {
+		var cur *block.Header
+		cur, err = bc.selfCheckHeader(i)
+		if err != nil {
+			res.add(i, SelfCheckHeaderHashList, err)
+		} else {
+			if prev != nil || i == 0 {
+				bc.selfCheckLink(res, cur, prev)
+			}
+			bc.selfCheckBody(res, cur)
+		}
+		if i >= syncP {
+			prevRoot = bc.selfCheckStateRoot(res, i, cur, prevRoot)
+		}
+		prev = cur
+	}
+	return res
+}
+
+// selfCheckHeader returns the header with the given index from the header
+// hash list making sure it's stored properly.
+func (bc *Blockchain) selfCheckHeader(index uint32) (*block.Header, error) {
+	h := bc.GetHeaderHash(int(index))
+	b, err := bc.dao.GetBlock(h)
+	if err != nil {
+		return nil, fmt.Errorf("can't get header %s: %w", h.StringLE(), err)
+	}
+	if b.Index != index {
+		return nil, fmt.Errorf("header %s has index %d", h.StringLE(), b.Index)
+	}
+	if !b.Hash().Equals(h) {
+		return nil, fmt.Errorf("header %s is stored with %s key", b.Hash().StringLE(), h.StringLE())
+	}
+	return &b.Header, nil
+}
+
+func (bc *Blockchain) selfCheckLink(res *SelfCheckResult, cur, prev *block.Header) {
+	if prev != nil {
+		if !cur.PrevHash.Equals(prev.Hash()) {
+			res.add(cur.Index, SelfCheckHeaderLink, fmt.Errorf("%w: %s != %s",
+				ErrHdrHashMismatch, cur.PrevHash.StringLE(), prev.Hash().StringLE()))
+			return
+		}
+		if cur.Timestamp <= prev.Timestamp {
+			res.add(cur.Index, SelfCheckHeaderLink, ErrHdrInvalidTimestamp)
+		}
+	}
+	if err := bc.verifyHeaderWitnesses(cur, prev); err != nil {
+		res.add(cur.Index, SelfCheckWitness, err)
+	}
+}
+
+// selfCheckBody checks block transactions, merkle root and execution results.
+func (bc *Blockchain) selfCheckBody(res *SelfCheckResult, h *block.Header) {
+	index := h.Index
+	d := bc.getBlockDAO(index)
+	b, err := d.GetBlock(h.Hash())
+	if err != nil {
+		res.add(index, SelfCheckTransaction, fmt.Errorf("can't get block: %w", err))
+		return
+	}
+	aers, err := d.GetAppExecResults(h.Hash(), trigger.OnPersist|trigger.PostPersist)
+	if err != nil || len(aers) != 2 {
+		res.add(index, SelfCheckAppExecResult, fmt.Errorf("block execution results: %d found, err: %v", len(aers), err))
+	}
+
+	var (
+		hashes = make([]util.Uint256, len(b.Transactions))
+		valid  = true
+	)
+	for i := range b.Transactions {
+		txHash := b.Transactions[i].Hash()
+		tx, height, err := d.GetTransaction(txHash)
+		if err != nil {
+			res.add(index, SelfCheckTransaction, fmt.Errorf("can't get transaction %s: %w", txHash.StringLE(), err))
+			valid = false
+			continue
+		}
+		hashes[i] = tx.Hash()
+		if !hashes[i].Equals(txHash) || height != index {
+			res.add(index, SelfCheckTransaction, fmt.Errorf("transaction %s is stored as %s at height %d",
+				hashes[i].StringLE(), txHash.StringLE(), height))
+			valid = false
+			continue
+		}
+		aers, err := d.GetAppExecResults(txHash, trigger.Application)
+		if err != nil || len(aers) != 1 {
+			res.add(index, SelfCheckAppExecResult, fmt.Errorf("transaction %s: %d execution results found, err: %v",
+				txHash.StringLE(), len(aers), err))
+		}
+	}
+	if valid {
+		if root := hash.CalcMerkleRoot(hashes); !root.Equals(h.MerkleRoot) {
+			res.add(index, SelfCheckMerkleRoot, fmt.Errorf("%s != %s", root.StringLE(), h.MerkleRoot.StringLE()))
+		}
+	}
+}
+
+// selfCheckStateRoot checks local state root for the given height and returns
+// it (if it exists).
+func (bc *Blockchain) selfCheckStateRoot(res *SelfCheckResult, index uint32, h *block.Header, prev *state.MPTRoot) *state.MPTRoot {
+	sr, err := bc.stateRoot.GetStateRoot(index)
+	if err != nil {
+		res.add(index, SelfCheckStateRoot, fmt.Errorf("can't get state root: %w", err))
+		return nil
+	}
+	if sr.Index != index {
+		res.add(index, SelfCheckStateRoot, fmt.Errorf("state root is stored with %d index", sr.Index))
+	}
+	if bc.config.StateRootInHeader && h != nil && prev != nil && !h.PrevStateRoot.Equals(prev.Root) {
+		res.add(index, SelfCheckStateRoot, fmt.Errorf("previous state root mismatch: %s != %s",
+			h.PrevStateRoot.StringLE(), prev.Root.StringLE()))
+	}
+	if !bc.config.KeepOnlyLatestState || index == res.To {
+		if !sr.Root.Equals(util.Uint256{}) {
+			if _, err := bc.dao.Store.Get(append([]byte{byte(storage.DataMPT)}, sr.Root.BytesBE()...)); err != nil {
+				res.add(index, SelfCheckStateRoot, fmt.Errorf("state root %s node: %w", sr.Root.StringLE(), err))
+			}
+		}
+	}
+	if index == res.To && !sr.Root.Equals(bc.stateRoot.CurrentLocalStateRoot()) {
+		res.add(index, SelfCheckStateRoot, fmt.Errorf("current state root mismatch: %s != %s",
+			sr.Root.StringLE(), bc.stateRoot.CurrentLocalStateRoot().StringLE()))
+	}
+	return sr
+}
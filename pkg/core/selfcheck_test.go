@@ -0,0 +1,110 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/internal/testchain"
+	"github.com/nspcc-dev/neo-go/pkg/config"
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockchain_SelfCheck(t *testing.T) {
+	st := storage.NewMemoryStore()
+	bc := initTestChain(t, st, nil)
+	// Blocks are added manually, so the chain is not run, but someone needs
+	// to consume events.
+	go bc.notificationDispatcher()
+	t.Cleanup(func() { close(bc.stopCh) })
+
+	txes := make([]*transaction.Transaction, 2)
+	for i := range txes {
+		var err error
+		txes[i], err = testchain.NewTransferFromOwner(bc, bc.contracts.NEO.Hash, util.Uint160{1, 2, 3}, 1, uint32(i), 100)
+		require.NoError(t, err)
+	}
+	require.NoError(t, bc.AddBlock(bc.newBlock(txes...)))
+	// Make sure the first batch of header hashes is stored.
+	_, err := bc.genBlocks(headerBatchCount + 4)
+	require.NoError(t, err)
+	_, err = bc.persist(true)
+	require.NoError(t, err)
+	height := bc.BlockHeight()
+
+	// check opens the chain over a copy of the DB modified by f and runs
+	// SelfCheck against it.
+	check := func(t *testing.T, depth uint32, f func(d *dao.Simple)) *SelfCheckResult {
+		cache := storage.NewMemCachedStore(st) // Extra wrapper to avoid good DB corruption.
+		if f != nil {
+			d := dao.NewSimple(cache, bc.config.StateRootInHeader, bc.config.P2PSigExtensions)
+			f(d)
+			_, err := d.Persist()
+			require.NoError(t, err)
+		}
+		chain := initTestChain(t, cache, nil)
+		return chain.SelfCheck(depth)
+	}
+	kinds := func(res *SelfCheckResult) []SelfCheckKind {
+		var ks []SelfCheckKind
+		for _, iss := range res.Issues {
+			ks = append(ks, iss.Kind)
+		}
+		return ks
+	}
+
+	t.Run("good", func(t *testing.T) {
+		res := check(t, 0, nil)
+		require.True(t, res.OK(), res.Issues)
+		require.Equal(t, uint32(0), res.From)
+		require.Equal(t, height, res.To)
+
+		res = check(t, 10, nil)
+		require.True(t, res.OK(), res.Issues)
+		require.Equal(t, height-9, res.From)
+		require.Equal(t, height, res.To)
+	})
+	t.Run("untraceable blocks removed", func(t *testing.T) {
+		chain := initTestChain(t, storage.NewMemCachedStore(st), func(c *config.Config) {
+			c.ProtocolConfiguration.RemoveUntraceableBlocks = true
+			c.ProtocolConfiguration.MaxTraceableBlocks = 10
+		})
+		res := chain.SelfCheck(0)
+		require.True(t, res.OK(), res.Issues)
+		require.Equal(t, height-9, res.From)
+		require.Equal(t, height, res.To)
+	})
+	t.Run("missing execution result", func(t *testing.T) {
+		res := check(t, 0, func(d *dao.Simple) {
+			require.NoError(t, d.StoreAsTransaction(txes[0], 1, nil))
+		})
+		require.Equal(t, []SelfCheckKind{SelfCheckAppExecResult}, kinds(res))
+		require.Equal(t, uint32(1), res.Issues[0].Index)
+	})
+	t.Run("corrupted transaction", func(t *testing.T) {
+		res := check(t, 0, func(d *dao.Simple) {
+			key := append([]byte{byte(storage.DataExecutable)}, txes[1].Hash().BytesBE()...)
+			val, err := d.Store.Get(key)
+			require.NoError(t, err)
+			d.Store.Put(key, val[:10])
+		})
+		require.Equal(t, []SelfCheckKind{SelfCheckTransaction}, kinds(res))
+		require.Equal(t, uint32(1), res.Issues[0].Index)
+	})
+	t.Run("corrupted header hash list", func(t *testing.T) {
+		const broken = headerBatchCount - 5
+		res := check(t, height-broken+1, func(d *dao.Simple) {
+			hashes := make([]util.Uint256, headerBatchCount)
+			for i := range hashes {
+				hashes[i] = bc.GetHeaderHash(i)
+			}
+			hashes[broken] = util.Uint256{1, 2, 3}
+			require.NoError(t, d.StoreHeaderHashes(hashes, 0))
+		})
+		require.Equal(t, uint32(broken), res.From)
+		require.Equal(t, []SelfCheckKind{SelfCheckHeaderHashList}, kinds(res))
+		require.Equal(t, uint32(broken), res.Issues[0].Index)
+	})
+}
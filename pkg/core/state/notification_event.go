@@ -20,6 +20,15 @@ type NotificationEvent struct {
 	Item       *stackitem.Array `json:"state"`
 }
 
+// NotificationWithContext is a notification event accompanied by the index of
+// the block it was emitted in and the hash of its script container (either a
+// block or a transaction).
+type NotificationWithContext struct {
+	NotificationEvent
+	BlockIndex uint32
+	Container  util.Uint256
+}
+
 // AppExecResult represent the result of the script execution, gathering together
 // all resulting notifications, state, stack and other metadata.
 type AppExecResult struct {
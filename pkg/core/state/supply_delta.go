@@ -0,0 +1,38 @@
+package state
+
+import (
+	"math/big"
+
+	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+)
+
+// TokenSupplyDelta is a net change of some native token total supply made in
+// a block (or a range of blocks) along with the total supply value after it.
+type TokenSupplyDelta struct {
+	Delta  big.Int
+	Supply big.Int
+}
+
+// SupplyDelta contains GAS and NEO total supply changes.
+type SupplyDelta struct {
+	GAS TokenSupplyDelta
+	NEO TokenSupplyDelta
+}
+
+// EncodeBinary implements io.Serializable interface.
+func (d *TokenSupplyDelta) EncodeBinary(w *io.BinWriter) {
+	w.WriteVarBytes(bigint.ToBytes(&d.Delta))
+	w.WriteVarBytes(bigint.ToBytes(&d.Supply))
+}
+
+// DecodeBinary implements io.Serializable interface.
+func (d *TokenSupplyDelta) DecodeBinary(r *io.BinReader) {
+	delta := r.ReadVarBytes(bigint.MaxBytesLen)
+	supply := r.ReadVarBytes(bigint.MaxBytesLen)
+	if r.Err != nil {
+		return
+	}
+	d.Delta = *bigint.FromBytes(delta)
+	d.Supply = *bigint.FromBytes(supply)
+}
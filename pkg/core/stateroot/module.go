@@ -16,6 +16,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
@@ -32,6 +33,7 @@ type (
 		srInHead bool
 		mode     mpt.TrieMode
 		mpt      *mpt.Trie
+		nodes    *mpt.NodeCache
 		verifier VerifierFunc
 		log      *zap.Logger
 
@@ -72,10 +74,34 @@ func NewModule(cfg config.ProtocolConfiguration, verif VerifierFunc, log *zap.Lo
 	}
 }
 
+// SetNodeCacheSize sets the capacity of MPT node cache used for trie
+// traversal, zero disables caching. It's not safe to call this method
+// concurrently with other module methods, it's supposed to be called before
+// the blockchain is started.
+func (s *Module) SetNodeCacheSize(capacity int) {
+	s.nodes = mpt.NewNodeCache(capacity)
+	if s.mpt != nil {
+		s.mpt.SetNodeCache(s.nodes)
+	}
+}
+
+// NodeCache returns MPT node cache used by the module (nil if caching is
+// disabled). It can be used for other tries working over the module's storage.
+func (s *Module) NodeCache() *mpt.NodeCache {
+	return s.nodes
+}
+
+// newTrie creates a new trie using the module's node cache.
+func (s *Module) newTrie(root mpt.Node, mode mpt.TrieMode, store *storage.MemCachedStore) *mpt.Trie {
+	tr := mpt.NewTrie(root, mode, store)
+	tr.SetNodeCache(s.nodes)
+	return tr
+}
+
 // GetState returns value at the specified key fom the MPT with the specified root.
 func (s *Module) GetState(root util.Uint256, key []byte) ([]byte, error) {
 	// Allow accessing old values, it's RO thing.
-	tr := mpt.NewTrie(mpt.NewHashNode(root), s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
+	tr := s.newTrie(mpt.NewHashNode(root), s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
 	return tr.Get(key)
 }
 
@@ -86,14 +112,14 @@ func (s *Module) GetState(root util.Uint256, key []byte) ([]byte, error) {
 // then item with key equals to prefix is not included into result.
 func (s *Module) FindStates(root util.Uint256, prefix, start []byte, max int) ([]storage.KeyValue, error) {
 	// Allow accessing old values, it's RO thing.
-	tr := mpt.NewTrie(mpt.NewHashNode(root), s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
+	tr := s.newTrie(mpt.NewHashNode(root), s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
 	return tr.Find(prefix, start, max)
 }
 
 // GetStateProof returns proof of having key in the MPT with the specified root.
 func (s *Module) GetStateProof(root util.Uint256, key []byte) ([][]byte, error) {
 	// Allow accessing old values, it's RO thing.
-	tr := mpt.NewTrie(mpt.NewHashNode(root), s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
+	tr := s.newTrie(mpt.NewHashNode(root), s.mode&^mpt.ModeGCFlag, storage.NewMemCachedStore(s.Store))
 	return tr.GetProof(key)
 }
 
@@ -102,6 +128,54 @@ func (s *Module) GetStateRoot(height uint32) (*state.MPTRoot, error) {
 	return s.getStateRoot(makeStateRootKey(height))
 }
 
+// GetStateRootRange returns state roots for heights from start to end
+// (inclusive) reading them in a single storage pass. An error is returned if
+// state root for any height of the range is missing (roots can be removed by
+// GC).
+func (s *Module) GetStateRootRange(start, end uint32) ([]*state.MPTRoot, error) {
+	if start > end {
+		return nil, fmt.Errorf("invalid range: start %d is bigger than end %d", start, end)
+	}
+	var (
+		res      []*state.MPTRoot
+		next     = start
+		err      error
+		startKey = makeStateRootKey(start)
+	)
+	s.Store.Seek(storage.SeekRange{
+		Prefix: startKey[:1], // DataMPTAux
+		Start:  startKey[1:],
+	}, func(k, v []byte) bool {
+		if len(k) != 5 {
+			return true
+		}
+		index := binary.BigEndian.Uint32(k[1:])
+		if index != next {
+			return false
+		}
+		sr := new(state.MPTRoot)
+		r := io.NewBinReaderFromBuf(v)
+		sr.DecodeBinary(r)
+		if r.Err != nil {
+			err = fmt.Errorf("failed to decode state root for height %d: %w", index, r.Err)
+			return false
+		}
+		res = append(res, sr)
+		if index == end {
+			return false
+		}
+		next++
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(res) == 0 || res[len(res)-1].Index != end {
+		return nil, fmt.Errorf("%w: no state root for height %d", storage.ErrKeyNotFound, next)
+	}
+	return res, nil
+}
+
 // GetLatestStateHeight returns the latest blockchain height by the given stateroot.
 func (s *Module) GetLatestStateHeight(root util.Uint256) (uint32, error) {
 	rootBytes := root.BytesBE()
@@ -153,7 +227,7 @@ func (s *Module) Init(height uint32) error {
 	}
 
 	if height == 0 {
-		s.mpt = mpt.NewTrie(nil, s.mode, s.Store)
+		s.mpt = s.newTrie(nil, s.mode, s.Store)
 		s.currentLocal.Store(util.Uint256{})
 		return nil
 	}
@@ -163,7 +237,7 @@ func (s *Module) Init(height uint32) error {
 	}
 	s.currentLocal.Store(r.Root)
 	s.localHeight.Store(r.Index)
-	s.mpt = mpt.NewTrie(mpt.NewHashNode(r.Root), s.mode, s.Store)
+	s.mpt = s.newTrie(mpt.NewHashNode(r.Root), s.mode, s.Store)
 	return nil
 }
 
@@ -182,6 +256,7 @@ func (s *Module) CleanStorage() error {
 		return true
 	})
 	_, err := b.Persist()
+	s.nodes.Clear()
 	if err != nil {
 		return fmt.Errorf("failed to remove outdated MPT-reated items: %w", err)
 	}
@@ -199,7 +274,7 @@ func (s *Module) JumpToState(sr *state.MPTRoot) {
 
 	s.currentLocal.Store(sr.Root)
 	s.localHeight.Store(sr.Index)
-	s.mpt = mpt.NewTrie(mpt.NewHashNode(sr.Root), s.mode, s.Store)
+	s.mpt = s.newTrie(mpt.NewHashNode(sr.Root), s.mode, s.Store)
 }
 
 // GC performs garbage collection.
@@ -211,6 +286,8 @@ func (s *Module) GC(index uint32, store storage.Store) time.Duration {
 	var stored int64
 	s.log.Info("starting MPT garbage collection", zap.Uint32("index", index))
 	start := time.Now()
+	s.nodes.BeginGC()
+	defer s.nodes.EndGC()
 	err := store.SeekGC(storage.SeekRange{
 		Prefix: []byte{byte(storage.DataMPT)},
 	}, func(k, v []byte) bool {
@@ -220,6 +297,9 @@ func (s *Module) GC(index uint32, store storage.Store) time.Duration {
 			if h <= index {
 				removed++
 				stored--
+				var nodeHash util.Uint256
+				copy(nodeHash[:], k[1:])
+				s.nodes.Remove(nodeHash)
 				return false
 			}
 		}
@@ -262,6 +342,9 @@ func (s *Module) UpdateCurrentLocal(mpt *mpt.Trie, sr *state.MPTRoot) {
 		s.validatedHeight.Store(sr.Index)
 		updateStateHeightMetric(sr.Index)
 	}
+	if s.nodes != nil {
+		updateNodeCacheMetrics(s.nodes.Stats())
+	}
 }
 
 // VerifyStateRoot checks if state root is valid.
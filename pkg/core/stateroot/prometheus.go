@@ -1,20 +1,50 @@
 package stateroot
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/prometheus/client_golang/prometheus"
+)
 
-// stateHeight prometheus metric.
-var stateHeight = prometheus.NewGauge(
-	prometheus.GaugeOpts{
-		Help:      "Current verified state height",
-		Name:      "current_state_height",
-		Namespace: "neogo",
-	},
+var (
+	// stateHeight prometheus metric.
+	stateHeight = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Current verified state height",
+			Name:      "current_state_height",
+			Namespace: "neogo",
+		},
+	)
+	// nodeCacheHits prometheus metric.
+	nodeCacheHits = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Number of MPT nodes retrieved from the node cache",
+			Name:      "mpt_node_cache_hits",
+			Namespace: "neogo",
+		},
+	)
+	// nodeCacheMisses prometheus metric.
+	nodeCacheMisses = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Help:      "Number of MPT nodes not found in the node cache",
+			Name:      "mpt_node_cache_misses",
+			Namespace: "neogo",
+		},
+	)
 )
 
 func init() {
-	prometheus.MustRegister(stateHeight)
+	prometheus.MustRegister(
+		stateHeight,
+		nodeCacheHits,
+		nodeCacheMisses,
+	)
 }
 
 func updateStateHeightMetric(sHeight uint32) {
 	stateHeight.Set(float64(sHeight))
 }
+
+func updateNodeCacheMetrics(st mpt.NodeCacheStats) {
+	nodeCacheHits.Set(float64(st.Hits))
+	nodeCacheMisses.Set(float64(st.Misses))
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/config"
 	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
 	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/chaindump"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/native/noderoles"
 	"github.com/nspcc-dev/neo-go/pkg/core/state"
@@ -313,3 +314,45 @@ func TestStateroot_GetLatestStateHeight(t *testing.T) {
 		require.Equal(t, i, h)
 	}
 }
+
+func TestStateroot_GetStateRootRange(t *testing.T) {
+	bc, acc := chain.NewSingle(t)
+	e := neotest.NewExecutor(t, bc, acc, acc)
+	e.GenerateNewBlocks(t, 5)
+
+	w := io.NewBufBinWriter()
+	require.NoError(t, chaindump.Dump(bc, w.BinWriter, 0, bc.BlockHeight()+1))
+	require.NoError(t, w.Err)
+
+	bc2, _ := chain.NewSingle(t)
+	require.NoError(t, chaindump.Restore(bc2, io.NewBinReaderFromBuf(w.Bytes()), 1, bc.BlockHeight(), nil))
+	require.Equal(t, bc.BlockHeight(), bc2.BlockHeight())
+
+	m, m2 := bc.GetStateModule(), bc2.GetStateModule()
+	roots, err := m.GetStateRootRange(1, 4)
+	require.NoError(t, err)
+	require.Equal(t, 4, len(roots))
+	for i, r := range roots {
+		expected, err := m.GetStateRoot(uint32(i + 1))
+		require.NoError(t, err)
+		require.Equal(t, expected, r)
+	}
+	restored, err := m2.GetStateRootRange(1, 4)
+	require.NoError(t, err)
+	require.Equal(t, roots, restored)
+
+	t.Run("single", func(t *testing.T) {
+		rs, err := m.GetStateRootRange(bc.BlockHeight(), bc.BlockHeight())
+		require.NoError(t, err)
+		require.Equal(t, 1, len(rs))
+		require.Equal(t, bc.BlockHeight(), rs[0].Index)
+	})
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := m.GetStateRootRange(3, 2)
+		require.Error(t, err)
+	})
+	t.Run("missing", func(t *testing.T) {
+		_, err := m.GetStateRootRange(bc.BlockHeight(), bc.BlockHeight()+2)
+		require.ErrorIs(t, err, storage.ErrKeyNotFound)
+	})
+}
@@ -0,0 +1,157 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/contract"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+)
+
+// ErrStateTransitionMismatch is returned from VerifyBlockStateTransition when
+// the state root computed by block re-execution differs from the stored one.
+var ErrStateTransitionMismatch = errors.New("state root mismatch")
+
+// historicStore is a read-only Store that returns contract storage items from
+// the MPT and everything else (blocks, transactions, etc.) from the backend
+// store.
+type historicStore struct {
+	storage.Store
+	trie *mpt.TrieStore
+}
+
+func isStorageKey(key []byte) bool {
+	return len(key) != 0 && (key[0] == byte(storage.STStorage) || key[0] == byte(storage.STTempStorage))
+}
+
+// Get implements storage.Store interface.
+func (s *historicStore) Get(key []byte) ([]byte, error) {
+	if isStorageKey(key) {
+		return s.trie.Get(key)
+	}
+	return s.Store.Get(key)
+}
+
+// Seek implements storage.Store interface.
+func (s *historicStore) Seek(rng storage.SeekRange, f func(k, v []byte) bool) {
+	if isStorageKey(rng.Prefix) {
+		s.trie.Seek(rng, f)
+		return
+	}
+	s.Store.Seek(rng, f)
+}
+
+// PutChangeSet implements storage.Store interface.
+func (s *historicStore) PutChangeSet(puts map[string][]byte, stor map[string][]byte) error {
+	return fmt.Errorf("%w: PutChangeSet is not supported", mpt.ErrForbiddenTrieStoreOperation)
+}
+
+// SeekGC implements storage.Store interface.
+func (s *historicStore) SeekGC(rng storage.SeekRange, keep func(k, v []byte) bool) error {
+	return fmt.Errorf("%w: SeekGC is not supported", mpt.ErrForbiddenTrieStoreOperation)
+}
+
+// Close implements storage.Store interface, it doesn't close the backend store.
+func (s *historicStore) Close() error {
+	return s.trie.Close()
+}
+
+// VerifyBlockStateTransition re-executes the block with the given index over
+// the state at index-1 and checks that the resulting state root matches the
+// local one stored for index (and PrevStateRoot of the next header if
+// StateRootInHeader is enabled). It doesn't change anything in the DB and
+// requires historic states to be available, so it can't be used with
+// KeepOnlyLatestState.
+func (bc *Blockchain) VerifyBlockStateTransition(index uint32) error {
+	if bc.config.KeepOnlyLatestState {
+		return errors.New("only latest state is supported")
+	}
+	if index == 0 {
+		return errors.New("genesis block has no previous state")
+	}
+	height := bc.BlockHeight()
+	if index > height {
+		return fmt.Errorf("block %d is not yet persisted, current height is %d", index, height)
+	}
+	var mode = mpt.ModeAll
+	if bc.config.RemoveUntraceableBlocks {
+		if index+bc.config.MaxTraceableBlocks <= height {
+			return fmt.Errorf("state for height %d is outdated and removed from the storage", index-1)
+		}
+		mode |= mpt.ModeGCFlag
+	}
+	b, err := bc.GetBlock(bc.GetHeaderHash(int(index)))
+	if err != nil {
+		return fmt.Errorf("failed to get block %d: %w", index, err)
+	}
+	prev, err := bc.stateRoot.GetStateRoot(index - 1)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve stateroot for height %d: %w", index-1, err)
+	}
+	expected, err := bc.stateRoot.GetStateRoot(index)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve stateroot for height %d: %w", index, err)
+	}
+
+	backend := storage.NewPrivateMemCachedStore(bc.dao.Store)
+	trieStore := mpt.NewTrieStore(prev.Root, mode, backend)
+	trieStore.SetNodeCache(bc.stateRoot.NodeCache())
+	d := dao.NewSimple(&historicStore{
+		Store: backend,
+		trie:  trieStore,
+	}, bc.config.StateRootInHeader, bc.config.P2PSigExtensions)
+	d.Version = bc.dao.Version
+	err = bc.initializeNativeCache(index-1, d)
+	if err != nil {
+		return fmt.Errorf("failed to initialize native cache backed by historic DAO: %w", err)
+	}
+
+	cache := d.GetPrivate()
+	if _, err := bc.runPersist(bc.contracts.GetPersistScript(), b, cache, trigger.OnPersist); err != nil {
+		return fmt.Errorf("onPersist failed: %w", err)
+	}
+	for _, tx := range b.Transactions {
+		systemInterop := bc.newInteropContext(trigger.Application, cache, b, tx)
+		v := systemInterop.SpawnVM()
+		v.LoadScriptWithFlags(tx.Script, callflag.All)
+		v.SetPriceGetter(systemInterop.GetPrice)
+		v.LoadToken = contract.LoadToken(systemInterop)
+		v.GasLimit = tx.SystemFee
+
+		_ = systemInterop.Exec()
+		if !v.HasFailed() {
+			if _, err := systemInterop.DAO.Persist(); err != nil {
+				return fmt.Errorf("failed to persist invocation results: %w", err)
+			}
+		}
+	}
+	if _, err := bc.runPersist(bc.contracts.GetPostPersistScript(), b, cache, trigger.PostPersist); err != nil {
+		return fmt.Errorf("postPersist failed: %w", err)
+	}
+
+	tr := mpt.NewTrie(mpt.NewHashNode(prev.Root), mode, storage.NewMemCachedStore(backend))
+	if _, err := tr.PutBatch(mpt.MapToMPTBatch(cache.Store.GetStorageChanges())); err != nil {
+		return fmt.Errorf("failed to apply MPT changes: %w", err)
+	}
+	tr.Flush(index)
+	root := tr.StateRoot()
+	if !root.Equals(expected.Root) {
+		return fmt.Errorf("%w: block %d, computed %s, stored %s", ErrStateTransitionMismatch,
+			index, root.StringLE(), expected.Root.StringLE())
+	}
+	if bc.config.StateRootInHeader && index < bc.HeaderHeight() {
+		h, err := bc.GetHeader(bc.GetHeaderHash(int(index) + 1))
+		if err != nil {
+			return fmt.Errorf("failed to get next header: %w", err)
+		}
+		if !h.PrevStateRoot.Equals(root) {
+			return fmt.Errorf("%w: block %d, computed %s, next header has %s", ErrStateTransitionMismatch,
+				index, root.StringLE(), h.PrevStateRoot.StringLE())
+		}
+	}
+	return nil
+}
@@ -21,16 +21,33 @@ const (
 	// in order not to mess up the previous state which has its own items stored by
 	// STStorage prefix. Once state exchange process is completed, all items with
 	// STStorage prefix will be replaced with STTempStorage-prefixed ones.
-	STTempStorage                  KeyPrefix = 0x71
-	STNEP11Transfers               KeyPrefix = 0x72
-	STNEP17Transfers               KeyPrefix = 0x73
-	STTokenTransferInfo            KeyPrefix = 0x74
-	IXHeaderHashList               KeyPrefix = 0x80
+	STTempStorage       KeyPrefix = 0x71
+	STNEP11Transfers    KeyPrefix = 0x72
+	STNEP17Transfers    KeyPrefix = 0x73
+	STTokenTransferInfo KeyPrefix = 0x74
+	STSupplyDelta       KeyPrefix = 0x75
+	IXHeaderHashList    KeyPrefix = 0x80
+	// IXConflicts is used for the node-local index of transactions declaring
+	// conflicts with the given one.
+	IXConflicts KeyPrefix = 0x81
+	// IXOracleResponses is used for the node-local index of oracle response
+	// transactions by request ID.
+	IXOracleResponses KeyPrefix = 0x82
+	// IXEventJournal is used for the node-local journal of block events
+	// delivered to durable subscribers.
+	IXEventJournal KeyPrefix = 0x83
+	// IXJournalCursor is used to store durable subscribers positions in
+	// the events journal.
+	IXJournalCursor KeyPrefix = 0x84
+	// IXEventJournalCount is used to store the number of journaled events
+	// produced by every block.
+	IXEventJournalCount            KeyPrefix = 0x85
 	SYSCurrentBlock                KeyPrefix = 0xc0
 	SYSCurrentHeader               KeyPrefix = 0xc1
 	SYSStateSyncCurrentBlockHeight KeyPrefix = 0xc2
 	SYSStateSyncPoint              KeyPrefix = 0xc3
 	SYSStateJumpStage              KeyPrefix = 0xc4
+	SYSColdStorageHeight           KeyPrefix = 0xc5
 	SYSVersion                     KeyPrefix = 0xf0
 )
 
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+)
+
+// StorageKV is a contract storage item returned from DiffContractStorage.
+type StorageKV struct {
+	// Key is the item key (without contract ID).
+	Key []byte
+	// Value is the item value at the second height, nil for deleted items.
+	Value []byte
+	// PrevValue is the item value at the first height, nil for added items.
+	PrevValue []byte
+}
+
+// DiffContractStorage compares the storage of the contract with the given ID
+// at two heights using MPT state of these heights. It returns items that exist
+// at heightB, but not at heightA (added), items that exist at both heights with
+// different values (modified) and items that exist at heightA, but not at
+// heightB (deleted), every list is sorted by key. An error is returned if the
+// state for any of the heights is not available (not yet processed or already
+// removed by KeepOnlyLatestState or RemoveUntraceableBlocks).
+func (bc *Blockchain) DiffContractStorage(id int32, heightA, heightB uint32) (added, modified, deleted []StorageKV, err error) {
+	a, err := bc.getContractStorageAt(id, heightA)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	b, err := bc.getContractStorageAt(id, heightB)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	var i, j int
+	for i < len(a) || j < len(b) {
+		var cmp int
+		switch {
+		case i == len(a):
+			cmp = 1
+		case j == len(b):
+			cmp = -1
+		default:
+			cmp = bytes.Compare(a[i].Key, b[j].Key)
+		}
+		switch {
+		case cmp < 0:
+			deleted = append(deleted, StorageKV{Key: a[i].Key, PrevValue: a[i].Value})
+			i++
+		case cmp > 0:
+			added = append(added, StorageKV{Key: b[j].Key, Value: b[j].Value})
+			j++
+		default:
+			if !bytes.Equal(a[i].Value, b[j].Value) {
+				modified = append(modified, StorageKV{Key: b[j].Key, Value: b[j].Value, PrevValue: a[i].Value})
+			}
+			i++
+			j++
+		}
+	}
+	return added, modified, deleted, nil
+}
+
+// getContractStorageAt returns all storage items of the contract with the given
+// ID at the given height from the MPT, keys are returned without contract ID.
+func (bc *Blockchain) getContractStorageAt(id int32, height uint32) ([]storage.KeyValue, error) {
+	local := bc.stateRoot.CurrentLocalHeight()
+	if height > local {
+		return nil, fmt.Errorf("no state for height %d, current local state height is %d", height, local)
+	}
+	if bc.config.KeepOnlyLatestState && height != local {
+		return nil, fmt.Errorf("state for height %d is not available, only latest state is supported", height)
+	}
+	if bc.config.RemoveUntraceableBlocks && height+bc.config.MaxTraceableBlocks <= local {
+		return nil, fmt.Errorf("state for height %d is outdated and removed from the storage", height)
+	}
+	sr, err := bc.stateRoot.GetStateRoot(height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve state root for height %d: %w", height, err)
+	}
+	prefix := make([]byte, 4)
+	binary.LittleEndian.PutUint32(prefix, uint32(id))
+	kvs, err := bc.stateRoot.FindStates(sr.Root, prefix, nil, math.MaxInt32)
+	if err != nil {
+		if errors.Is(err, mpt.ErrNotFound) {
+			return nil, nil // No storage items.
+		}
+		return nil, fmt.Errorf("failed to retrieve storage at height %d: %w", height, err)
+	}
+	for i := range kvs {
+		kvs[i].Key = kvs[i].Key[len(prefix):]
+	}
+	return kvs, nil
+}
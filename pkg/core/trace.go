@@ -0,0 +1,155 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/nspcc-dev/neo-go/pkg/core/dao"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop/contract"
+	"github.com/nspcc-dev/neo-go/pkg/core/mpt"
+	"github.com/nspcc-dev/neo-go/pkg/core/storage"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// ExecutionTrace is an opcode-level trace of transaction execution.
+type ExecutionTrace struct {
+	// Container is the hash of the traced transaction.
+	Container util.Uint256
+	// Block is the index of the block the transaction is included in.
+	Block          uint32
+	VMState        vm.State
+	GasConsumed    int64
+	FaultException string
+	Steps          []TraceStep
+}
+
+// TraceStep describes a single executed instruction. ScriptHash and IP can be
+// mapped to the source code position using DebugInfo of the contract.
+type TraceStep struct {
+	ScriptHash util.Uint160
+	IP         int
+	Opcode     opcode.Opcode
+	// GasConsumed is the amount of GAS consumed by this instruction including
+	// interop calls made by it.
+	GasConsumed int64
+	// Stack is a copy of the evaluation stack after the instruction execution
+	// with the top item being the last.
+	Stack []stackitem.Item
+}
+
+// TraceTransaction re-executes the transaction with the given hash over the
+// state it was originally executed with (OnPersist and all the preceding
+// transactions of the same block are executed first) and returns the trace
+// of every instruction executed. It doesn't change anything in the DB and
+// requires historic states to be available, so it can't be used with
+// KeepOnlyLatestState.
+func (bc *Blockchain) TraceTransaction(txHash util.Uint256) (*ExecutionTrace, error) {
+	if bc.config.KeepOnlyLatestState {
+		return nil, errors.New("only latest state is supported")
+	}
+	_, index, err := bc.GetTransaction(txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+	if index == math.MaxUint32 {
+		return nil, errors.New("transaction is not yet persisted")
+	}
+	var mode = mpt.ModeAll
+	if bc.config.RemoveUntraceableBlocks {
+		if index+bc.config.MaxTraceableBlocks <= bc.BlockHeight() {
+			return nil, fmt.Errorf("state for height %d is outdated and removed from the storage", index-1)
+		}
+		mode |= mpt.ModeGCFlag
+	}
+	b, err := bc.GetBlock(bc.GetHeaderHash(int(index)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", index, err)
+	}
+	prev, err := bc.stateRoot.GetStateRoot(index - 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve stateroot for height %d: %w", index-1, err)
+	}
+
+	backend := storage.NewPrivateMemCachedStore(bc.dao.Store)
+	trieStore := mpt.NewTrieStore(prev.Root, mode, backend)
+	trieStore.SetNodeCache(bc.stateRoot.NodeCache())
+	d := dao.NewSimple(&historicStore{
+		Store: backend,
+		trie:  trieStore,
+	}, bc.config.StateRootInHeader, bc.config.P2PSigExtensions)
+	d.Version = bc.dao.Version
+	err = bc.initializeNativeCache(index-1, d)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize native cache backed by historic DAO: %w", err)
+	}
+
+	cache := d.GetPrivate()
+	if _, err := bc.runPersist(bc.contracts.GetPersistScript(), b, cache, trigger.OnPersist); err != nil {
+		return nil, fmt.Errorf("onPersist failed: %w", err)
+	}
+	for _, tx := range b.Transactions {
+		systemInterop := bc.newInteropContext(trigger.Application, cache, b, tx)
+		v := systemInterop.SpawnVM()
+		v.LoadScriptWithFlags(tx.Script, callflag.All)
+		v.SetPriceGetter(systemInterop.GetPrice)
+		v.LoadToken = contract.LoadToken(systemInterop)
+		v.GasLimit = tx.SystemFee
+
+		if !tx.Hash().Equals(txHash) {
+			_ = systemInterop.Exec()
+			if !v.HasFailed() {
+				if _, err := systemInterop.DAO.Persist(); err != nil {
+					return nil, fmt.Errorf("failed to persist invocation results: %w", err)
+				}
+			}
+			continue
+		}
+
+		trace := &ExecutionTrace{
+			Container: txHash,
+			Block:     index,
+		}
+		err := traceVM(v, trace)
+		systemInterop.Finalize()
+		if err != nil {
+			trace.FaultException = err.Error()
+		}
+		trace.VMState = v.State()
+		trace.GasConsumed = v.GasConsumed()
+		return trace, nil
+	}
+	return nil, fmt.Errorf("transaction %s is missing from block %d", txHash.StringLE(), index)
+}
+
+// traceVM executes the script loaded into v step by step and records every
+// instruction into trace.
+func traceVM(v *vm.VM, trace *ExecutionTrace) error {
+	for !v.HasStopped() {
+		ctx := v.Context()
+		ip, op := ctx.NextInstr()
+		gas := v.GasConsumed()
+		err := v.Step()
+		step := TraceStep{
+			ScriptHash:  ctx.ScriptHash(),
+			IP:          ip,
+			Opcode:      op,
+			GasConsumed: v.GasConsumed() - gas,
+		}
+		items := v.Estack().ToArray()
+		step.Stack = make([]stackitem.Item, len(items))
+		for i := range items {
+			step.Stack[i] = stackitem.DeepCopy(items[i])
+		}
+		trace.Steps = append(trace.Steps, step)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package transaction
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
@@ -38,6 +39,9 @@ const (
 
 	// MaxConditionNesting limits the maximum allowed level of condition nesting.
 	MaxConditionNesting = 2
+	// MaxConditionSubitems limits the number of sub-conditions in a single
+	// And/Or condition.
+	MaxConditionSubitems = maxSubitems
 )
 
 // WitnessCondition is a condition of WitnessRule.
@@ -54,6 +58,9 @@ type WitnessCondition interface {
 	DecodeBinarySpecific(*io.BinReader, int)
 	// ToStackItem converts WitnessCondition to stackitem.Item.
 	ToStackItem() stackitem.Item
+	// Validate checks that condition doesn't exceed MaxConditionNesting and
+	// MaxConditionSubitems limits.
+	Validate() error
 
 	json.Marshaler
 }
@@ -133,6 +140,22 @@ func (c *ConditionBoolean) MarshalJSON() ([]byte, error) {
 	return json.Marshal(aux)
 }
 
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *ConditionBoolean) UnmarshalJSON(data []byte) error {
+	cond, err := unmarshalTypedConditionJSON(data, WitnessBoolean)
+	if err != nil {
+		return err
+	}
+	*c = *cond.(*ConditionBoolean)
+	return nil
+}
+
+// Validate implements WitnessCondition interface, simple conditions are
+// always valid.
+func (c *ConditionBoolean) Validate() error {
+	return nil
+}
+
 // ToStackItem implements WitnessCondition interface allowing to convert
 // to stackitem.Item.
 func (c *ConditionBoolean) ToStackItem() stackitem.Item {
@@ -176,6 +199,22 @@ func (c *ConditionNot) MarshalJSON() ([]byte, error) {
 	return json.Marshal(aux)
 }
 
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *ConditionNot) UnmarshalJSON(data []byte) error {
+	cond, err := unmarshalTypedConditionJSON(data, WitnessNot)
+	if err != nil {
+		return err
+	}
+	*c = *cond.(*ConditionNot)
+	return nil
+}
+
+// Validate implements WitnessCondition interface checking nesting depth and
+// the number of sub-conditions.
+func (c *ConditionNot) Validate() error {
+	return validateCondition(c, MaxConditionNesting)
+}
+
 // ToStackItem implements WitnessCondition interface allowing to convert
 // to stackitem.Item.
 func (c *ConditionNot) ToStackItem() stackitem.Item {
@@ -258,6 +297,22 @@ func (c *ConditionAnd) MarshalJSON() ([]byte, error) {
 	return arrayToJSON(c, []WitnessCondition(*c))
 }
 
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *ConditionAnd) UnmarshalJSON(data []byte) error {
+	cond, err := unmarshalTypedConditionJSON(data, WitnessAnd)
+	if err != nil {
+		return err
+	}
+	*c = *cond.(*ConditionAnd)
+	return nil
+}
+
+// Validate implements WitnessCondition interface checking nesting depth and
+// the number of sub-conditions.
+func (c *ConditionAnd) Validate() error {
+	return validateCondition(c, MaxConditionNesting)
+}
+
 // ToStackItem implements WitnessCondition interface allowing to convert
 // to stackitem.Item.
 func (c *ConditionAnd) ToStackItem() stackitem.Item {
@@ -304,6 +359,22 @@ func (c *ConditionOr) MarshalJSON() ([]byte, error) {
 	return arrayToJSON(c, []WitnessCondition(*c))
 }
 
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *ConditionOr) UnmarshalJSON(data []byte) error {
+	cond, err := unmarshalTypedConditionJSON(data, WitnessOr)
+	if err != nil {
+		return err
+	}
+	*c = *cond.(*ConditionOr)
+	return nil
+}
+
+// Validate implements WitnessCondition interface checking nesting depth and
+// the number of sub-conditions.
+func (c *ConditionOr) Validate() error {
+	return validateCondition(c, MaxConditionNesting)
+}
+
 // ToStackItem implements WitnessCondition interface allowing to convert
 // to stackitem.Item.
 func (c *ConditionOr) ToStackItem() stackitem.Item {
@@ -342,6 +413,22 @@ func (c *ConditionScriptHash) MarshalJSON() ([]byte, error) {
 	return json.Marshal(aux)
 }
 
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *ConditionScriptHash) UnmarshalJSON(data []byte) error {
+	cond, err := unmarshalTypedConditionJSON(data, WitnessScriptHash)
+	if err != nil {
+		return err
+	}
+	*c = *cond.(*ConditionScriptHash)
+	return nil
+}
+
+// Validate implements WitnessCondition interface, simple conditions are
+// always valid.
+func (c *ConditionScriptHash) Validate() error {
+	return nil
+}
+
 // ToStackItem implements WitnessCondition interface allowing to convert
 // to stackitem.Item.
 func (c *ConditionScriptHash) ToStackItem() stackitem.Item {
@@ -380,6 +467,22 @@ func (c *ConditionGroup) MarshalJSON() ([]byte, error) {
 	return json.Marshal(aux)
 }
 
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *ConditionGroup) UnmarshalJSON(data []byte) error {
+	cond, err := unmarshalTypedConditionJSON(data, WitnessGroup)
+	if err != nil {
+		return err
+	}
+	*c = *cond.(*ConditionGroup)
+	return nil
+}
+
+// Validate implements WitnessCondition interface, simple conditions are
+// always valid.
+func (c *ConditionGroup) Validate() error {
+	return nil
+}
+
 // ToStackItem implements WitnessCondition interface allowing to convert
 // to stackitem.Item.
 func (c *ConditionGroup) ToStackItem() stackitem.Item {
@@ -416,6 +519,18 @@ func (c ConditionCalledByEntry) MarshalJSON() ([]byte, error) {
 	return json.Marshal(aux)
 }
 
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *ConditionCalledByEntry) UnmarshalJSON(data []byte) error {
+	_, err := unmarshalTypedConditionJSON(data, WitnessCalledByEntry)
+	return err
+}
+
+// Validate implements WitnessCondition interface, simple conditions are
+// always valid.
+func (c ConditionCalledByEntry) Validate() error {
+	return nil
+}
+
 // ToStackItem implements WitnessCondition interface allowing to convert
 // to stackitem.Item.
 func (c ConditionCalledByEntry) ToStackItem() stackitem.Item {
@@ -454,6 +569,22 @@ func (c *ConditionCalledByContract) MarshalJSON() ([]byte, error) {
 	return json.Marshal(aux)
 }
 
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *ConditionCalledByContract) UnmarshalJSON(data []byte) error {
+	cond, err := unmarshalTypedConditionJSON(data, WitnessCalledByContract)
+	if err != nil {
+		return err
+	}
+	*c = *cond.(*ConditionCalledByContract)
+	return nil
+}
+
+// Validate implements WitnessCondition interface, simple conditions are
+// always valid.
+func (c *ConditionCalledByContract) Validate() error {
+	return nil
+}
+
 // ToStackItem implements WitnessCondition interface allowing to convert
 // to stackitem.Item.
 func (c *ConditionCalledByContract) ToStackItem() stackitem.Item {
@@ -492,6 +623,22 @@ func (c *ConditionCalledByGroup) MarshalJSON() ([]byte, error) {
 	return json.Marshal(aux)
 }
 
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *ConditionCalledByGroup) UnmarshalJSON(data []byte) error {
+	cond, err := unmarshalTypedConditionJSON(data, WitnessCalledByGroup)
+	if err != nil {
+		return err
+	}
+	*c = *cond.(*ConditionCalledByGroup)
+	return nil
+}
+
+// Validate implements WitnessCondition interface, simple conditions are
+// always valid.
+func (c *ConditionCalledByGroup) Validate() error {
+	return nil
+}
+
 // ToStackItem implements WitnessCondition interface allowing to convert
 // to stackitem.Item.
 func (c *ConditionCalledByGroup) ToStackItem() stackitem.Item {
@@ -549,7 +696,7 @@ func unmarshalArrayOfConditionJSONs(arr []json.RawMessage, maxDepth int) ([]Witn
 	if l == 0 {
 		return nil, errors.New("empty array of conditions")
 	}
-	if l >= maxSubitems {
+	if l > maxSubitems {
 		return nil, errors.New("too many elements")
 	}
 	res := make([]WitnessCondition, l)
@@ -627,11 +774,59 @@ func unmarshalConditionJSON(data []byte, maxDepth int) (WitnessCondition, error)
 		}
 		res = (*ConditionCalledByGroup)(aux.Group)
 	default:
-		return nil, errors.New("invalid condition type")
+		return nil, fmt.Errorf("invalid condition type %q", aux.Type)
+	}
+	return res, nil
+}
+
+// unmarshalTypedConditionJSON unmarshals condition from the given JSON data
+// and checks that it's of the expected type.
+func unmarshalTypedConditionJSON(data []byte, typ WitnessConditionType) (WitnessCondition, error) {
+	res, err := UnmarshalConditionJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	if res.Type() != typ {
+		return nil, fmt.Errorf("unexpected condition type %s (expected %s)", res.Type(), typ)
 	}
 	return res, nil
 }
 
+// validateCondition checks that the given condition fits into maxDepth levels
+// and has no more than MaxConditionSubitems elements in each And/Or array,
+// these are exactly the limits applied by the binary and JSON decoders.
+func validateCondition(c WitnessCondition, maxDepth int) error {
+	if maxDepth <= 0 {
+		return fmt.Errorf("too many nesting levels (max %d)", MaxConditionNesting)
+	}
+	var subs []WitnessCondition
+	switch c := c.(type) {
+	case *ConditionNot:
+		if c.Condition == nil {
+			return errors.New("no condition in Not")
+		}
+		subs = []WitnessCondition{c.Condition}
+	case *ConditionAnd:
+		subs = *c
+	case *ConditionOr:
+		subs = *c
+	default:
+		return nil
+	}
+	if len(subs) == 0 {
+		return fmt.Errorf("empty array of conditions in %s", c.Type())
+	}
+	if len(subs) > MaxConditionSubitems {
+		return fmt.Errorf("too many sub-conditions in %s: %d (max %d)", c.Type(), len(subs), MaxConditionSubitems)
+	}
+	for _, sub := range subs {
+		if err := validateCondition(sub, maxDepth-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func condToStackItem(typ WitnessConditionType, c interface{}) stackitem.Item {
 	res := make([]stackitem.Item, 0, 2)
 	res = append(res, stackitem.NewBigInteger(big.NewInt(int64(typ))))
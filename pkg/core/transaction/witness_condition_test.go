@@ -35,6 +35,9 @@ func (c InvalidCondition) MarshalJSON() ([]byte, error) {
 func (c InvalidCondition) ToStackItem() stackitem.Item {
 	panic("invalid")
 }
+func (c InvalidCondition) Validate() error {
+	return nil
+}
 
 type condCase struct {
 	condition         WitnessCondition
@@ -153,6 +156,154 @@ func TestWitnessConditionJSONErrors(t *testing.T) {
 	}
 }
 
+func TestWitnessConditionTypedJSON(t *testing.T) {
+	var someBool = true
+	pk, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	var cases = []struct {
+		cond WitnessCondition
+		dst  json.Unmarshaler
+	}{
+		{(*ConditionBoolean)(&someBool), new(ConditionBoolean)},
+		{&ConditionNot{(*ConditionBoolean)(&someBool)}, new(ConditionNot)},
+		{&ConditionAnd{(*ConditionBoolean)(&someBool), &ConditionScriptHash{1}}, new(ConditionAnd)},
+		{&ConditionOr{(*ConditionBoolean)(&someBool), &ConditionCalledByContract{2}}, new(ConditionOr)},
+		{&ConditionScriptHash{1, 2, 3}, new(ConditionScriptHash)},
+		{(*ConditionGroup)(pk.PublicKey()), new(ConditionGroup)},
+		{&ConditionCalledByEntry{}, new(ConditionCalledByEntry)},
+		{&ConditionCalledByContract{1, 2, 3}, new(ConditionCalledByContract)},
+		{(*ConditionCalledByGroup)(pk.PublicKey()), new(ConditionCalledByGroup)},
+	}
+	for _, c := range cases {
+		t.Run(c.cond.Type().String(), func(t *testing.T) {
+			data, err := json.Marshal(c.cond)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(data, c.dst))
+			require.Equal(t, c.cond, c.dst)
+		})
+	}
+
+	t.Run("wrong type", func(t *testing.T) {
+		data, err := json.Marshal(&ConditionScriptHash{1, 2, 3})
+		require.NoError(t, err)
+		err = json.Unmarshal(data, new(ConditionCalledByContract))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unexpected condition type ScriptHash")
+	})
+	t.Run("unknown type", func(t *testing.T) {
+		_, err := UnmarshalConditionJSON([]byte(`{"type":"CalledByNobody"}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `"CalledByNobody"`)
+	})
+}
+
+func TestWitnessConditionJSONNesting(t *testing.T) {
+	var (
+		someBool = true
+		subs     = make([]WitnessCondition, maxSubitems)
+	)
+	for i := range subs {
+		subs[i] = (*ConditionBoolean)(&someBool)
+	}
+
+	t.Run("max subitems", func(t *testing.T) {
+		for _, cond := range []WitnessCondition{(*ConditionAnd)(&subs), (*ConditionOr)(&subs)} {
+			data, err := json.Marshal(cond)
+			require.NoError(t, err)
+			res, err := UnmarshalConditionJSON(data)
+			require.NoError(t, err)
+			require.Equal(t, cond, res)
+		}
+	})
+	t.Run("nested", func(t *testing.T) {
+		// And/Or/Not take one nesting level, so only simple conditions can
+		// be used inside of them.
+		for _, cond := range []WitnessCondition{
+			&ConditionAnd{&ConditionOr{(*ConditionBoolean)(&someBool)}},
+			&ConditionOr{&ConditionAnd{(*ConditionBoolean)(&someBool)}},
+			&ConditionOr{&ConditionNot{(*ConditionBoolean)(&someBool)}},
+			&ConditionNot{&ConditionAnd{(*ConditionBoolean)(&someBool)}},
+		} {
+			data, err := json.Marshal(cond)
+			require.NoError(t, err)
+			_, err = UnmarshalConditionJSON(data)
+			require.Error(t, err, string(data))
+			require.Contains(t, err.Error(), "too many nesting levels")
+			require.Error(t, json.Unmarshal(data, new(ConditionAnd)))
+		}
+	})
+	t.Run("deep", func(t *testing.T) {
+		var cond WitnessCondition = (*ConditionBoolean)(&someBool)
+		for i := 0; i < 64; i++ {
+			if i%2 == 0 {
+				cond = &ConditionAnd{cond, (*ConditionBoolean)(&someBool)}
+			} else {
+				cond = &ConditionOr{(*ConditionBoolean)(&someBool), cond}
+			}
+		}
+		data, err := json.Marshal(cond)
+		require.NoError(t, err)
+		_, err = UnmarshalConditionJSON(data)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "too many nesting levels")
+	})
+}
+
+func TestWitnessConditionValidate(t *testing.T) {
+	var (
+		someBool = true
+		b        = (*ConditionBoolean)(&someBool)
+		subs     = make(ConditionAnd, MaxConditionSubitems)
+	)
+	for i := range subs {
+		subs[i] = b
+	}
+	pk, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	for _, c := range []WitnessCondition{
+		b,
+		&ConditionScriptHash{1, 2, 3},
+		(*ConditionGroup)(pk.PublicKey()),
+		ConditionCalledByEntry{},
+		&ConditionCalledByContract{1, 2, 3},
+		(*ConditionCalledByGroup)(pk.PublicKey()),
+		&ConditionNot{b},
+		&ConditionOr{b, &ConditionScriptHash{1}},
+		&subs,
+	} {
+		require.NoError(t, c.Validate(), c.Type().String())
+		require.NoError(t, decodeCondition(c), c.Type().String())
+	}
+
+	tooMany := append(ConditionOr{b}, subs...)
+	for name, c := range map[string]WitnessCondition{
+		"empty And":         &ConditionAnd{},
+		"empty Or":          &ConditionOr{},
+		"nested Not":        &ConditionNot{&ConditionNot{b}},
+		"nested And":        &ConditionOr{&ConditionAnd{b}},
+		"too many subitems": &tooMany,
+	} {
+		require.Error(t, c.Validate(), name)
+		// Validate applies the same limits as the decoder does.
+		require.Error(t, decodeCondition(c), name)
+	}
+	require.Error(t, (&ConditionNot{}).Validate())
+}
+
+// decodeCondition serializes the given condition and decodes it back.
+func decodeCondition(c WitnessCondition) error {
+	w := io.NewBufBinWriter()
+	c.EncodeBinary(w.BinWriter)
+	if w.Err != nil {
+		return w.Err
+	}
+	r := io.NewBinReaderFromBuf(w.Bytes())
+	DecodeBinaryCondition(r)
+	return r.Err
+}
+
 type TestMC struct {
 	calling util.Uint160
 	current util.Uint160
@@ -49,6 +49,17 @@ func (w *WitnessRule) DecodeBinary(br *io.BinReader) {
 	w.Condition = DecodeBinaryCondition(br)
 }
 
+// Validate checks rule action and condition (see WitnessCondition.Validate).
+func (w *WitnessRule) Validate() error {
+	if w.Action != WitnessDeny && w.Action != WitnessAllow {
+		return errors.New("unknown witness rule action")
+	}
+	if w.Condition == nil {
+		return errors.New("no condition")
+	}
+	return w.Condition.Validate()
+}
+
 // UnmarshalJSON implements json.Unmarshaler interface.
 func (w *WitnessRule) MarshalJSON() ([]byte, error) {
 	cond, err := w.Condition.MarshalJSON()
@@ -56,6 +56,17 @@ func TestWitnessRuleBadJSON(t *testing.T) {
 	}
 }
 
+func TestWitnessRule_Validate(t *testing.T) {
+	var b bool
+	require.NoError(t, (&WitnessRule{Action: WitnessAllow, Condition: (*ConditionBoolean)(&b)}).Validate())
+	require.Error(t, (&WitnessRule{Action: 0xff, Condition: (*ConditionBoolean)(&b)}).Validate())
+	require.Error(t, (&WitnessRule{Action: WitnessDeny}).Validate())
+	require.Error(t, (&WitnessRule{
+		Action:    WitnessDeny,
+		Condition: &ConditionNot{&ConditionNot{(*ConditionBoolean)(&b)}},
+	}).Validate())
+}
+
 func TestWitnessRule_ToStackItem(t *testing.T) {
 	var b bool
 	for _, act := range []WitnessAction{WitnessDeny, WitnessAllow} {
@@ -36,7 +36,8 @@ func createGenesisBlock(cfg config.ProtocolConfiguration) (*block.Block, error)
 			InvocationScript:   []byte{},
 			VerificationScript: []byte{byte(opcode.PUSH1)},
 		},
-		StateRootEnabled: cfg.StateRootInHeader,
+		StateRootEnabled:  cfg.StateRootInHeader,
+		CommitmentEnabled: cfg.HeaderCommitment,
 	}
 
 	b := &block.Block{
@@ -48,6 +49,17 @@ func createGenesisBlock(cfg config.ProtocolConfiguration) (*block.Block, error)
 	return b, nil
 }
 
+// ComputeGenesisHash returns the hash of the genesis block for the given
+// configuration. It doesn't need a running chain and can be used to check
+// that some node belongs to the expected network.
+func ComputeGenesisHash(cfg config.ProtocolConfiguration) (util.Uint256, error) {
+	b, err := createGenesisBlock(cfg)
+	if err != nil {
+		return util.Uint256{}, err
+	}
+	return b.Hash(), nil
+}
+
 func validatorsFromConfig(cfg config.ProtocolConfiguration) ([]*keys.PublicKey, error) {
 	vs, err := keys.NewPublicKeysFromStrings(cfg.StandbyCommittee)
 	if err != nil {
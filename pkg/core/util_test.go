@@ -21,6 +21,23 @@ func TestGenesisBlockMainNet(t *testing.T) {
 	assert.Equal(t, expect, block.Hash().StringLE())
 }
 
+func TestComputeGenesisHash(t *testing.T) {
+	cfg, err := config.Load("../../config", netmode.MainNet)
+	require.NoError(t, err)
+
+	h, err := ComputeGenesisHash(cfg.ProtocolConfiguration)
+	require.NoError(t, err)
+	require.Equal(t, "1f4d1defa46faa5e7b9b8d3f79a06bec777d7c26c4aa5f6f5899a291daa87c15", h.StringLE())
+
+	cfg.ProtocolConfiguration.StateRootInHeader = true
+	h2, err := ComputeGenesisHash(cfg.ProtocolConfiguration)
+	require.NoError(t, err)
+	require.NotEqual(t, h, h2)
+
+	_, err = ComputeGenesisHash(config.ProtocolConfiguration{StandbyCommittee: []string{"bad"}})
+	require.Error(t, err)
+}
+
 func TestGetConsensusAddressMainNet(t *testing.T) {
 	var (
 		consensusAddr   = "NVg7LjGcUSrgxgjX3zEgqaksfMaiS8Z6e1"
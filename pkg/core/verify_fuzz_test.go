@@ -0,0 +1,66 @@
+//go:build go1.18
+// +build go1.18
+
+package core_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/core"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/neotest"
+	"github.com/nspcc-dev/neo-go/pkg/neotest/chain"
+	"github.com/stretchr/testify/require"
+)
+
+// newFuzzChain returns a chain with the profile generating transactions
+// sent by the funded validator account.
+func newFuzzChain(f *testing.F) (*core.Blockchain, neotest.GenProfile) {
+	bc, acc := chain.NewSingle(f)
+	p := neotest.DefaultGenProfile(bc.GetConfig().Magic, bc.BlockHeight())
+	p.Sender = acc
+	p.MaxValidUntilBlockIncrement = bc.GetConfig().MaxValidUntilBlockIncrement
+	p.FeePerByte = bc.FeePerByte()
+	p.BaseExecFee = bc.GetBaseExecFee()
+	p.AttributesFee = bc.GetAttributesFee
+	if !bc.P2PSigExtensionsEnabled() {
+		p.NotaryServiceFeePerKey = 0
+	}
+	return bc, p
+}
+
+// checkVerifyTx ensures that transaction verification doesn't panic and that
+// accepted transaction is accepted again.
+func checkVerifyTx(t *testing.T, bc *core.Blockchain, tx *transaction.Transaction) {
+	var err error
+	require.NotPanics(t, func() { err = bc.VerifyTx(tx) })
+	if err == nil {
+		require.NoError(t, bc.VerifyTx(tx), "accepted transaction is rejected on resubmission")
+	}
+}
+
+func FuzzVerifyTx(f *testing.F) {
+	bc, p := newFuzzChain(f)
+	rnd := rand.New(rand.NewSource(0))
+	for i := 0; i < 50; i++ {
+		f.Add(neotest.NewRandomTransaction(rnd, p).Bytes())
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tx, err := transaction.NewTransactionFromBytes(data)
+		if err != nil {
+			return
+		}
+		checkVerifyTx(t, bc, tx)
+	})
+}
+
+func FuzzVerifyRandomTx(f *testing.F) {
+	bc, p := newFuzzChain(f)
+	for i := int64(0); i < 50; i++ {
+		f.Add(i)
+	}
+	f.Fuzz(func(t *testing.T, seed int64) {
+		checkVerifyTx(t, bc, neotest.NewRandomTransaction(rand.New(rand.NewSource(seed)), p))
+	})
+}
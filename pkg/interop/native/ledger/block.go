@@ -29,3 +29,29 @@ type Block struct {
 	// TransactionsLength represents the length of block's transactions array.
 	TransactionsLength int
 }
+
+// BlockHeader represents a NEO block header. It has the same fields as Block
+// except for TransactionsLength. To use it you need to get it via
+// GetBlockHeader function call.
+type BlockHeader struct {
+	// Hash represents the hash (256 bit BE value in a 32 byte slice) of the
+	// given block.
+	Hash interop.Hash256
+	// Version of the block.
+	Version int
+	// PrevHash represents the hash (256 bit BE value in a 32 byte slice) of the
+	// previous block.
+	PrevHash interop.Hash256
+	// MerkleRoot represents the root hash (256 bit BE value in a 32 byte slice)
+	// of a transaction list.
+	MerkleRoot interop.Hash256
+	// Timestamp represents millisecond-precision block timestamp.
+	Timestamp int
+	// Nonce represents block nonce.
+	Nonce int
+	// Index represents the height of the block.
+	Index int
+	// NextConsensus represents contract address of the next miner (160 bit BE
+	// value in a 20 byte slice).
+	NextConsensus interop.Hash160
+}
@@ -43,11 +43,28 @@ func GetBlock(indexOrHash interface{}) *Block {
 	return neogointernal.CallWithToken(Hash, "getBlock", int(contract.ReadStates), indexOrHash).(*Block)
 }
 
+// GetBlockHeader represents `getBlockHeader` method of Ledger native contract.
+func GetBlockHeader(indexOrHash interface{}) *BlockHeader {
+	return neogointernal.CallWithToken(Hash, "getBlockHeader", int(contract.ReadStates), indexOrHash).(*BlockHeader)
+}
+
+// GetHeaderCommitment represents `getHeaderCommitment` method of Ledger native
+// contract. It's only available on networks with HeaderCommitment protocol
+// extension enabled.
+func GetHeaderCommitment(indexOrHash interface{}) interop.Hash256 {
+	return neogointernal.CallWithToken(Hash, "getHeaderCommitment", int(contract.ReadStates), indexOrHash).(interop.Hash256)
+}
+
 // GetTransaction represents `getTransaction` method of Ledger native contract.
 func GetTransaction(hash interop.Hash256) *Transaction {
 	return neogointernal.CallWithToken(Hash, "getTransaction", int(contract.ReadStates), hash).(*Transaction)
 }
 
+// GetTransactionData represents `getTransactionData` method of Ledger native contract.
+func GetTransactionData(hash interop.Hash256) *TransactionData {
+	return neogointernal.CallWithToken(Hash, "getTransactionData", int(contract.ReadStates), hash).(*TransactionData)
+}
+
 // GetTransactionHeight represents `getTransactionHeight` method of Ledger native contract.
 func GetTransactionHeight(hash interop.Hash256) int {
 	return neogointernal.CallWithToken(Hash, "getTransactionHeight", int(contract.ReadStates), hash).(int)
@@ -59,6 +76,12 @@ func GetTransactionFromBlock(indexOrHash interface{}, txIndex int) *Transaction
 		indexOrHash, txIndex).(*Transaction)
 }
 
+// GetTransactionsFromBlock represents `getTransactionsFromBlock` method of Ledger native contract.
+func GetTransactionsFromBlock(indexOrHash interface{}, startIndex, count int) []*Transaction {
+	return neogointernal.CallWithToken(Hash, "getTransactionsFromBlock", int(contract.ReadStates),
+		indexOrHash, startIndex, count).([]*Transaction)
+}
+
 // GetTransactionSigners represents `getTransactionSigners` method of Ledger native contract.
 func GetTransactionSigners(hash interop.Hash256) []TransactionSigner {
 	return neogointernal.CallWithToken(Hash, "getTransactionSigners", int(contract.ReadStates),
@@ -25,3 +25,43 @@ type Transaction struct {
 	// Script represents code to run in NeoVM for this transaction.
 	Script []byte
 }
+
+// TransactionData represents a NEO transaction along with its attributes. It
+// contains the same fields as Transaction followed by Attributes.
+type TransactionData struct {
+	// Hash represents the hash (256 bit BE value in a 32 byte slice) of the
+	// given transaction (which also is its ID).
+	Hash interop.Hash256
+	// Version represents the transaction version.
+	Version int
+	// Nonce is a random number to avoid hash collision.
+	Nonce int
+	// Sender represents the sender (160 bit BE value in a 20 byte slice) of the
+	// given Transaction.
+	Sender interop.Hash160
+	// SysFee represents fee to be burned.
+	SysFee int
+	// NetFee represents fee to be distributed to consensus nodes.
+	NetFee int
+	// ValidUntilBlock is the maximum blockchain height exceeding which
+	// transaction should fail verification.
+	ValidUntilBlock int
+	// Script represents code to run in NeoVM for this transaction.
+	Script []byte
+	// Attributes represents transaction attributes in the same order they
+	// appear in the transaction.
+	Attributes []TransactionAttribute
+}
+
+// TransactionAttribute represents a transaction attribute.
+type TransactionAttribute struct {
+	// Type is the attribute type (like 0x01 for HighPriority or 0xe1 for
+	// Conflicts).
+	Type int
+	// Value is the attribute value serialized the same way it's serialized
+	// in transaction (without the type byte), it's empty for HighPriority.
+	// For example, it's a 32-byte conflicting transaction hash prefixed by
+	// its length (0x20) for Conflicts and a 4-byte LE height prefixed by
+	// its length (0x04) for NotValidBefore.
+	Value []byte
+}
@@ -9,6 +9,7 @@ package neo
 import (
 	"github.com/nspcc-dev/neo-go/pkg/interop"
 	"github.com/nspcc-dev/neo-go/pkg/interop/contract"
+	"github.com/nspcc-dev/neo-go/pkg/interop/iterator"
 	"github.com/nspcc-dev/neo-go/pkg/interop/neogointernal"
 )
 
@@ -19,6 +20,12 @@ type AccountState struct {
 	VoteTo  interop.PublicKey
 }
 
+// Candidate represents a single native NEO candidate.
+type Candidate struct {
+	Key   interop.PublicKey
+	Votes int
+}
+
 // Hash represents NEO contract hash.
 const Hash = "\xf5\x63\xea\x40\xbc\x28\x3d\x4d\x0e\x05\xc4\x8e\xa3\x05\xb3\xf2\xa0\x73\x40\xef"
 
@@ -58,6 +65,14 @@ func GetCandidates() []interop.PublicKey {
 	return neogointernal.CallWithToken(Hash, "getCandidates", int(contract.ReadStates)).([]interop.PublicKey)
 }
 
+// GetAllCandidates represents `getAllCandidates` method of NEO native contract.
+// It returns an iterator over registered candidates, its values are
+// (interop.PublicKey, int) structures (see Candidate), use
+// iterator.Next and iterator.Value to traverse them.
+func GetAllCandidates() iterator.Iterator {
+	return neogointernal.CallWithToken(Hash, "getAllCandidates", int(contract.ReadStates)).(iterator.Iterator)
+}
+
 // GetNextBlockValidators represents `getNextBlockValidators` method of NEO native contract.
 func GetNextBlockValidators() []interop.PublicKey {
 	return neogointernal.CallWithToken(Hash, "getNextBlockValidators", int(contract.ReadStates)).([]interop.PublicKey)
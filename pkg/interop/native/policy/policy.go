@@ -20,7 +20,7 @@ func GetFeePerByte() int {
 
 // SetFeePerByte represents `setFeePerByte` method of Policy native contract.
 func SetFeePerByte(value int) {
-	neogointernal.CallWithTokenNoRet(Hash, "setFeePerByte", int(contract.States), value)
+	neogointernal.CallWithTokenNoRet(Hash, "setFeePerByte", int(contract.States|contract.AllowNotify), value)
 }
 
 // GetExecFeeFactor represents `getExecFeeFactor` method of Policy native contract.
@@ -30,7 +30,7 @@ func GetExecFeeFactor() int {
 
 // SetExecFeeFactor represents `setExecFeeFactor` method of Policy native contract.
 func SetExecFeeFactor(value int) {
-	neogointernal.CallWithTokenNoRet(Hash, "setExecFeeFactor", int(contract.States), value)
+	neogointernal.CallWithTokenNoRet(Hash, "setExecFeeFactor", int(contract.States|contract.AllowNotify), value)
 }
 
 // GetStoragePrice represents `getStoragePrice` method of Policy native contract.
@@ -40,7 +40,52 @@ func GetStoragePrice() int {
 
 // SetStoragePrice represents `setStoragePrice` method of Policy native contract.
 func SetStoragePrice(value int) {
-	neogointernal.CallWithTokenNoRet(Hash, "setStoragePrice", int(contract.States), value)
+	neogointernal.CallWithTokenNoRet(Hash, "setStoragePrice", int(contract.States|contract.AllowNotify), value)
+}
+
+// GetMaxBlockSystemFee represents `getMaxBlockSystemFee` method of Policy native contract.
+func GetMaxBlockSystemFee() int {
+	return neogointernal.CallWithToken(Hash, "getMaxBlockSystemFee", int(contract.ReadStates)).(int)
+}
+
+// SetMaxBlockSystemFee represents `setMaxBlockSystemFee` method of Policy native contract.
+func SetMaxBlockSystemFee(value int) {
+	neogointernal.CallWithTokenNoRet(Hash, "setMaxBlockSystemFee", int(contract.States|contract.AllowNotify), value)
+}
+
+// GetAttributeFee represents `getAttributeFee` method of Policy native contract.
+func GetAttributeFee(t byte) int {
+	return neogointernal.CallWithToken(Hash, "getAttributeFee", int(contract.ReadStates), t).(int)
+}
+
+// SetAttributeFee represents `setAttributeFee` method of Policy native contract.
+func SetAttributeFee(t byte, value int) {
+	neogointernal.CallWithTokenNoRet(Hash, "setAttributeFee", int(contract.States|contract.AllowNotify), t, value)
+}
+
+// GetMaintenanceMode represents `getMaintenanceMode` method of Policy native contract.
+func GetMaintenanceMode() bool {
+	return neogointernal.CallWithToken(Hash, "getMaintenanceMode", int(contract.ReadStates)).(bool)
+}
+
+// SetMaintenanceMode represents `setMaintenanceMode` method of Policy native contract.
+func SetMaintenanceMode(enabled bool) {
+	neogointernal.CallWithTokenNoRet(Hash, "setMaintenanceMode", int(contract.States|contract.AllowNotify), enabled)
+}
+
+// IsMaintenanceExempt represents `isMaintenanceExempt` method of Policy native contract.
+func IsMaintenanceExempt(addr interop.Hash160) bool {
+	return neogointernal.CallWithToken(Hash, "isMaintenanceExempt", int(contract.ReadStates), addr).(bool)
+}
+
+// AddMaintenanceExempt represents `addMaintenanceExempt` method of Policy native contract.
+func AddMaintenanceExempt(addr interop.Hash160) bool {
+	return neogointernal.CallWithToken(Hash, "addMaintenanceExempt", int(contract.States), addr).(bool)
+}
+
+// RemoveMaintenanceExempt represents `removeMaintenanceExempt` method of Policy native contract.
+func RemoveMaintenanceExempt(addr interop.Hash160) bool {
+	return neogointernal.CallWithToken(Hash, "removeMaintenanceExempt", int(contract.States), addr).(bool)
 }
 
 // IsBlocked represents `isBlocked` method of Policy native contract.
@@ -48,12 +93,32 @@ func IsBlocked(addr interop.Hash160) bool {
 	return neogointernal.CallWithToken(Hash, "isBlocked", int(contract.ReadStates), addr).(bool)
 }
 
+// IsBlockedMulti represents `isBlockedMulti` method of Policy native contract.
+func IsBlockedMulti(addrs []interop.Hash160) []bool {
+	return neogointernal.CallWithToken(Hash, "isBlockedMulti", int(contract.ReadStates), addrs).([]bool)
+}
+
+// GetBlockedAccounts represents `getBlockedAccounts` method of Policy native contract.
+func GetBlockedAccounts(start, count int) []interop.Hash160 {
+	return neogointernal.CallWithToken(Hash, "getBlockedAccounts", int(contract.ReadStates), start, count).([]interop.Hash160)
+}
+
 // BlockAccount represents `blockAccount` method of Policy native contract.
 func BlockAccount(addr interop.Hash160) bool {
-	return neogointernal.CallWithToken(Hash, "blockAccount", int(contract.States), addr).(bool)
+	return neogointernal.CallWithToken(Hash, "blockAccount", int(contract.States|contract.AllowNotify), addr).(bool)
 }
 
 // UnblockAccount represents `unblockAccount` method of Policy native contract.
 func UnblockAccount(addr interop.Hash160) bool {
-	return neogointernal.CallWithToken(Hash, "unblockAccount", int(contract.States), addr).(bool)
+	return neogointernal.CallWithToken(Hash, "unblockAccount", int(contract.States|contract.AllowNotify), addr).(bool)
+}
+
+// EnableAttribute represents `enableAttribute` method of Policy native contract.
+func EnableAttribute(t byte) {
+	neogointernal.CallWithTokenNoRet(Hash, "enableAttribute", int(contract.States), t)
+}
+
+// DisableAttribute represents `disableAttribute` method of Policy native contract.
+func DisableAttribute(t byte) {
+	neogointernal.CallWithTokenNoRet(Hash, "disableAttribute", int(contract.States), t)
 }
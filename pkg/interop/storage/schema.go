@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"github.com/nspcc-dev/neo-go/pkg/interop/iterator"
+	"github.com/nspcc-dev/neo-go/pkg/interop/native/std"
+)
+
+// FieldType defines the type of values stored in a schema collection (see
+// DefineMap and DefineValue) and thus the way they're serialized.
+type FieldType byte
+
+// This block defines all supported field types.
+const (
+	// IntegerField values are stored as little-endian two's complement
+	// byte representation of an integer (like Put does it for int).
+	IntegerField FieldType = iota + 1
+	// BoolField values are stored as a single 0 or 1 byte.
+	BoolField
+	// BytesField values are stored as is.
+	BytesField
+	// StringField values are stored as is.
+	StringField
+	// StructField values (structures, slices and maps) are stored
+	// serialized with std.Serialize.
+	StructField
+)
+
+// Map is a storage collection of values of the same type with arbitrary keys.
+// It can only be created with DefineMap.
+type Map struct {
+	prefix []byte
+	typ    FieldType
+}
+
+// Value is a single storage value. It can only be created with DefineValue.
+type Value struct {
+	key []byte
+	typ FieldType
+}
+
+// DefineMap declares a named storage collection with values of the given type.
+// The key layout is generated by the compiler, so both parameters must be
+// constants and name must be unique (with respect to DefineValue also) in the
+// whole contract. Every key of the collection is a concatenation of a one-byte
+// name length, name itself and the key passed to MapGet/MapPut/MapDelete, so
+// prefixes of different collections never overlap and don't depend on the
+// order of definitions. Collections are usually defined as package-level
+// variables:
+//
+//	var balances = storage.DefineMap("b", storage.IntegerField)
+func DefineMap(name string, typ FieldType) Map {
+	return Map{}
+}
+
+// DefineValue declares a named storage value of the given type. It's similar
+// to DefineMap, but the value is stored using a single key consisting of
+// one-byte name length and name itself.
+func DefineValue(name string, typ FieldType) Value {
+	return Value{}
+}
+
+// encodeField returns the value of the given type to be passed to Put.
+func encodeField(typ FieldType, value interface{}) interface{} {
+	res := value
+	if typ == StructField {
+		res = std.Serialize(value)
+	}
+	return res
+}
+
+// decodeField converts the value returned from Get to the given type.
+func decodeField(typ FieldType, value interface{}) interface{} {
+	res := value
+	if res != nil {
+		switch typ {
+		case IntegerField:
+			res = value.(int)
+		case BoolField:
+			res = value.(bool)
+		case BytesField:
+			res = value.([]byte)
+		case StructField:
+			res = std.Deserialize(value.([]byte))
+		}
+	}
+	return res
+}
+
+// MapGet returns the value stored in m for the given key using given Context.
+// It's converted to the collection type, so it can be type asserted to the
+// appropriate Go type. If the value is not present in the database it returns
+// nil. This function uses `System.Storage.Get` syscall.
+func MapGet(ctx Context, m Map, key []byte) interface{} {
+	return decodeField(m.typ, Get(ctx, append(m.prefix, key...)))
+}
+
+// MapPut saves the given value in m with the given key using given Context.
+// The value must be of the collection type. This function uses
+// `System.Storage.Put` syscall.
+func MapPut(ctx Context, m Map, key []byte, value interface{}) {
+	Put(ctx, append(m.prefix, key...), encodeField(m.typ, value))
+}
+
+// MapDelete removes the value stored in m for the given key using given
+// Context. This function uses `System.Storage.Delete` syscall.
+func MapDelete(ctx Context, m Map, key []byte) {
+	Delete(ctx, append(m.prefix, key...))
+}
+
+// MapFind returns an iterator.Iterator over elements of m. Values are
+// returned in their serialized form, use DeserializeValues option for
+// StructField collections. This function uses `System.Storage.Find` syscall.
+func MapFind(ctx Context, m Map, options FindFlags) iterator.Iterator {
+	return Find(ctx, m.prefix, options)
+}
+
+// ValueGet returns the value stored for v using given Context. It works the
+// same way MapGet does. This function uses `System.Storage.Get` syscall.
+func ValueGet(ctx Context, v Value) interface{} {
+	return decodeField(v.typ, Get(ctx, v.key))
+}
+
+// ValuePut saves the given value for v using given Context. The value must be
+// of the v type. This function uses `System.Storage.Put` syscall.
+func ValuePut(ctx Context, v Value, value interface{}) {
+	Put(ctx, v.key, encodeField(v.typ, value))
+}
+
+// ValueDelete removes the value stored for v using given Context. This
+// function uses `System.Storage.Delete` syscall.
+func ValueDelete(ctx Context, v Value) {
+	Delete(ctx, v.key)
+}
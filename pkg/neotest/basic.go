@@ -301,7 +301,7 @@ func AddNetworkFee(bc blockchainer.Blockchainer, tx *transaction.Transaction, si
 		tx.NetworkFee += netFee
 		size += sizeDelta
 	}
-	tx.NetworkFee += int64(size) * bc.FeePerByte()
+	tx.NetworkFee += int64(size)*bc.FeePerByte() + bc.GetAttributesFee(tx)
 }
 
 // NewUnsignedBlock creates new unsigned block from txs.
@@ -323,6 +323,10 @@ func (e *Executor) NewUnsignedBlock(t testing.TB, txs ...*transaction.Transactio
 	}
 	b.PrevHash = lastBlock.Hash()
 	b.Index = e.Chain.BlockHeight() + 1
+	if e.Chain.GetConfig().HeaderCommitment {
+		b.CommitmentEnabled = true
+		b.Commitment = e.Chain.GetHeaderCommitment(b.Index)
+	}
 	b.RebuildMerkleRoot()
 	return b
 }
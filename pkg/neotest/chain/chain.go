@@ -12,6 +12,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/neotest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract"
+	"github.com/nspcc-dev/neo-go/pkg/util/clock"
 	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap/zaptest"
@@ -162,6 +163,18 @@ func NewSingleWithCustomConfigAndStore(t testing.TB, f func(cfg *config.Protocol
 	return bc, neotest.NewMultiSigner(committeeAcc)
 }
 
+// NewSingleWithCustomConfigAndClock is similar to NewSingleWithCustomConfig,
+// but also sets the time source of the Blockchain before running it. It allows
+// to drive time-dependent Blockchain behavior (like persisting and garbage
+// collection) with clock.Manual.
+func NewSingleWithCustomConfigAndClock(t testing.TB, f func(*config.ProtocolConfiguration), c clock.Clock) (*core.Blockchain, neotest.Signer) {
+	bc, acc := NewSingleWithCustomConfigAndStore(t, f, nil, false)
+	bc.SetClock(c)
+	go bc.Run()
+	t.Cleanup(bc.Close)
+	return bc, acc
+}
+
 // NewMulti creates new blockchain instance with four validators and six
 // committee members, otherwise not differring much from NewSingle. The
 // second value returned contains validators Signer, the third -- committee one.
@@ -58,6 +58,26 @@ func (c *ContractInvoker) TestInvoke(t testing.TB, method string, args ...interf
 	return ic.VM.Estack(), err
 }
 
+// TestInvokeUntil creates test VM and invokes method with args stepping
+// through it until stop returns true, at this point VM state is serialized
+// and returned, so that it can be restored later with vm.VM.LoadSnapshot.
+// It fails if execution ends before stop returns true.
+func (c *ContractInvoker) TestInvokeUntil(t testing.TB, stop func(v *vm.VM) bool, method string, args ...interface{}) []byte {
+	tx := c.PrepareInvokeNoSign(t, method, args...)
+	b := c.NewUnsignedBlock(t, tx)
+	ic := c.Chain.GetTestVM(trigger.Application, tx, b)
+	t.Cleanup(ic.Finalize)
+
+	ic.VM.LoadWithFlags(tx.Script, callflag.All)
+	for !stop(ic.VM) {
+		require.False(t, ic.VM.HasStopped(), "execution has ended")
+		require.NoError(t, ic.VM.Step())
+	}
+	data, err := ic.VM.Snapshot()
+	require.NoError(t, err)
+	return data
+}
+
 // WithSigners creates new client with the provided signer.
 func (c *ContractInvoker) WithSigners(signers ...Signer) *ContractInvoker {
 	newC := *c
@@ -0,0 +1,495 @@
+package neotest
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/fee"
+	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
+)
+
+// WitnessKind is a kind of witness generated by NewRandomTransaction.
+type WitnessKind byte
+
+// Witness kinds generated by NewRandomTransaction.
+const (
+	// WitnessSigned is a proper signer's witness.
+	WitnessSigned WitnessKind = iota
+	// WitnessEmpty has signer's verification script and empty invocation
+	// script.
+	WitnessEmpty
+	// WitnessRandom has random invocation and verification scripts.
+	WitnessRandom
+)
+
+// FeeStrategy defines how NewRandomTransaction sets network fee.
+type FeeStrategy byte
+
+// Fee strategies used by NewRandomTransaction.
+const (
+	// FeeExact is the minimum network fee required for the transaction.
+	FeeExact FeeStrategy = iota
+	// FeeBelowMinimum is the minimum network fee minus one.
+	FeeBelowMinimum
+	// FeeExcessive is a network fee exceeding the minimum one.
+	FeeExcessive
+)
+
+// GenProfile defines parameters of transactions generated by
+// NewRandomTransaction. Weights are relative, items with zero (or missing)
+// weight are never generated.
+type GenProfile struct {
+	// Network is the magic used to sign transactions.
+	Network netmode.Magic
+	// Height is the current chain height, ValidUntilBlock and NotValidBefore
+	// values are generated around it.
+	Height uint32
+	// MaxValidUntilBlockIncrement is the chain's MaxValidUntilBlockIncrement
+	// setting.
+	MaxValidUntilBlockIncrement uint32
+	// Sender is used as the first signer if set, other signers use throwaway
+	// keys.
+	Sender Signer
+	// MaxSigners is the maximum number of transaction signers.
+	MaxSigners int
+	// MaxAttributes is the maximum number of transaction attributes.
+	MaxAttributes int
+	// MaxScriptSize is the maximum transaction script size.
+	MaxScriptSize int
+	// MaxSystemFee is the maximum transaction system fee.
+	MaxSystemFee int64
+
+	// AttributeWeights are the weights of attribute types, any type from the
+	// reserved range can be used.
+	AttributeWeights map[transaction.AttrType]int
+	// ScopeWeights are the weights of signer scopes. Non-global scopes can be
+	// combined with each other.
+	ScopeWeights map[transaction.WitnessScope]int
+	// WitnessWeights are the weights of witness kinds.
+	WitnessWeights map[WitnessKind]int
+	// FeeWeights are the weights of network fee strategies.
+	FeeWeights map[FeeStrategy]int
+
+	// FeePerByte, BaseExecFee and NotaryServiceFeePerKey are used to
+	// calculate the minimum network fee.
+	FeePerByte             int64
+	BaseExecFee            int64
+	NotaryServiceFeePerKey int64
+	// AttributesFee returns Policy attribute fees for the transaction if set.
+	AttributesFee func(*transaction.Transaction) int64
+}
+
+// maxSubitems is the maximum number of allowed contracts, groups or rules
+// accepted by the transaction decoder.
+const maxSubitems = 16
+
+// DefaultGenProfile returns GenProfile generating every attribute type, signer
+// scope, witness kind and fee strategy with equal probability using default
+// native contract settings.
+func DefaultGenProfile(network netmode.Magic, height uint32) GenProfile {
+	attrs := map[transaction.AttrType]int{
+		transaction.HighPriority:           1,
+		transaction.OracleResponseT:        1,
+		transaction.NotValidBeforeT:        1,
+		transaction.ConflictsT:             1,
+		transaction.NotaryAssistedT:        1,
+		transaction.ReservedLowerBound + 3: 1,
+		transaction.ReservedUpperBound:     1,
+	}
+	return GenProfile{
+		Network:                     network,
+		Height:                      height,
+		MaxValidUntilBlockIncrement: 5760,
+		MaxSigners:                  4,
+		MaxAttributes:               4,
+		MaxScriptSize:               transaction.MaxScriptLength,
+		MaxSystemFee:                1_0000_0000,
+		AttributeWeights:            attrs,
+		ScopeWeights: map[transaction.WitnessScope]int{
+			transaction.None:            1,
+			transaction.CalledByEntry:   1,
+			transaction.CustomContracts: 1,
+			transaction.CustomGroups:    1,
+			transaction.Rules:           1,
+			transaction.Global:          1,
+		},
+		WitnessWeights: map[WitnessKind]int{
+			WitnessSigned: 1,
+			WitnessEmpty:  1,
+			WitnessRandom: 1,
+		},
+		FeeWeights: map[FeeStrategy]int{
+			FeeExact:        1,
+			FeeBelowMinimum: 1,
+			FeeExcessive:    1,
+		},
+		FeePerByte:             1000,
+		BaseExecFee:            interop.DefaultBaseExecFee,
+		NotaryServiceFeePerKey: 1000_0000,
+	}
+}
+
+// NewRandomTransaction generates a transaction that can be successfully
+// decoded, but has random (and likely invalid) contents according to the
+// profile. The result is deterministic for the same rnd state and profile.
+func NewRandomTransaction(rnd *rand.Rand, p GenProfile) *transaction.Transaction {
+	tx := transaction.New(randomScript(rnd, p.MaxScriptSize), 0)
+	tx.Nonce = rnd.Uint32()
+	tx.ValidUntilBlock = randomHeight(rnd, p)
+	if p.MaxSystemFee > 0 {
+		tx.SystemFee = rnd.Int63n(p.MaxSystemFee + 1)
+	}
+
+	signers := randomSigners(rnd, p)
+	tx.Signers = make([]transaction.Signer, len(signers))
+	for i, s := range signers {
+		tx.Signers[i] = randomSigner(rnd, p, s.ScriptHash())
+	}
+	nAttrs := 0
+	if p.MaxAttributes > 0 {
+		nAttrs = rnd.Intn(p.MaxAttributes + 1)
+	}
+	if max := transaction.MaxAttributes - len(signers); nAttrs > max {
+		nAttrs = max
+	}
+	attrWeights := make(map[int]int, len(p.AttributeWeights))
+	for t, w := range p.AttributeWeights {
+		attrWeights[int(t)] = w
+	}
+	used := make(map[transaction.AttrType]bool)
+	for i := 0; i < nAttrs; i++ {
+		typ, ok := pickWeighted(rnd, attrWeights)
+		if !ok {
+			break
+		}
+		t := transaction.AttrType(typ)
+		if used[t] && t != transaction.ConflictsT {
+			continue
+		}
+		used[t] = true
+		tx.Attributes = append(tx.Attributes, randomAttribute(rnd, p, t))
+	}
+
+	// Witnesses are signed after setting network fee, so their sizes are
+	// calculated in advance.
+	kinds := make([]WitnessKind, len(signers))
+	witnessWeights := make(map[int]int, len(p.WitnessWeights))
+	for k, w := range p.WitnessWeights {
+		witnessWeights[int(k)] = w
+	}
+	var (
+		size            = io.GetVarSize(tx) // No witnesses yet.
+		verificationFee int64
+	)
+	tx.Scripts = make([]transaction.Witness, len(signers))
+	for i, s := range signers {
+		k, _ := pickWeighted(rnd, witnessWeights)
+		kinds[i] = WitnessKind(k)
+		w := &tx.Scripts[i]
+		switch kinds[i] {
+		case WitnessSigned, WitnessEmpty:
+			w.InvocationScript = []byte{}
+			w.VerificationScript = s.Script()
+			netFee, sizeDelta := fee.Calculate(p.BaseExecFee, s.Script())
+			verificationFee += netFee
+			if kinds[i] == WitnessSigned && sizeDelta != 0 {
+				size += sizeDelta
+				continue
+			}
+		default:
+			w.InvocationScript = randomBytes(rnd, rnd.Intn(transaction.MaxInvocationScript+1))
+			w.VerificationScript = randomBytes(rnd, rnd.Intn(transaction.MaxVerificationScript+1))
+		}
+		size += io.GetVarSize(w.InvocationScript) + io.GetVarSize(w.VerificationScript)
+	}
+	minFee := int64(size)*p.FeePerByte + verificationFee
+	if attrs := tx.GetAttributes(transaction.NotaryAssistedT); len(attrs) != 0 {
+		minFee += (int64(attrs[0].Value.(*transaction.NotaryAssisted).NKeys) + 1) * p.NotaryServiceFeePerKey
+	}
+	if p.AttributesFee != nil {
+		minFee += p.AttributesFee(tx)
+	}
+	feeWeights := make(map[int]int, len(p.FeeWeights))
+	for s, w := range p.FeeWeights {
+		feeWeights[int(s)] = w
+	}
+	strategy, _ := pickWeighted(rnd, feeWeights)
+	switch FeeStrategy(strategy) {
+	case FeeBelowMinimum:
+		tx.NetworkFee = minFee - 1
+	case FeeExcessive:
+		tx.NetworkFee = minFee + 1 + rnd.Int63n(1_0000_0000)
+	default:
+		tx.NetworkFee = minFee
+	}
+	if tx.NetworkFee < 0 {
+		tx.NetworkFee = 0
+	}
+
+	for i, s := range signers {
+		if kinds[i] == WitnessSigned {
+			tx.Scripts[i].InvocationScript = s.SignHashable(uint32(p.Network), tx)
+		}
+	}
+	return tx
+}
+
+// WriteFuzzCorpus writes transactions to dir as seed corpus entries of Go
+// fuzzing engine, so that they can be used by fuzz targets accepting a single
+// []byte argument (dir is usually testdata/fuzz/FuzzName).
+func WriteFuzzCorpus(dir string, txes []*transaction.Transaction) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return err
+	}
+	for _, tx := range txes {
+		data := fmt.Sprintf("go test fuzz v1\n[]byte(%q)\n", tx.Bytes())
+		name := filepath.Join(dir, tx.Hash().StringLE())
+		if err := os.WriteFile(name, []byte(data), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pickWeighted returns a random key of weights with probability proportional
+// to its weight. Keys are iterated in ascending order for determinism.
+func pickWeighted(rnd *rand.Rand, weights map[int]int) (int, bool) {
+	keys := make([]int, 0, len(weights))
+	total := 0
+	for k, w := range weights {
+		if w > 0 {
+			keys = append(keys, k)
+			total += w
+		}
+	}
+	if total == 0 {
+		return 0, false
+	}
+	sort.Ints(keys)
+	n := rnd.Intn(total)
+	for _, k := range keys {
+		if n < weights[k] {
+			return k, true
+		}
+		n -= weights[k]
+	}
+	panic("unreachable")
+}
+
+func randomBytes(rnd *rand.Rand, n int) []byte {
+	b := make([]byte, n)
+	rnd.Read(b)
+	return b
+}
+
+// randomHeight returns height either near the current one or near the maximum
+// allowed ValidUntilBlock.
+func randomHeight(rnd *rand.Rand, p GenProfile) uint32 {
+	switch rnd.Intn(5) {
+	case 0:
+		return p.Height
+	case 1:
+		return p.Height + 1
+	case 2:
+		return p.Height + p.MaxValidUntilBlockIncrement
+	case 3:
+		return p.Height + p.MaxValidUntilBlockIncrement + 1
+	default:
+		return p.Height + 1 + uint32(rnd.Int63n(int64(p.MaxValidUntilBlockIncrement)+1))
+	}
+}
+
+// randomScript returns either a correct script or random bytes of size either
+// small, random or equal to the maximum one.
+func randomScript(rnd *rand.Rand, max int) []byte {
+	if max < 1 {
+		max = 1
+	}
+	var size int
+	switch rnd.Intn(3) {
+	case 0:
+		size = 1 + rnd.Intn(16)
+	case 1:
+		size = 1 + rnd.Intn(max)
+	default:
+		size = max
+	}
+	if size > max {
+		size = max
+	}
+	if rnd.Intn(4) == 0 {
+		return randomBytes(rnd, size)
+	}
+	script := make([]byte, size)
+	if size < 4 {
+		for i := range script {
+			script[i] = byte(opcode.PUSH1)
+		}
+		return script
+	}
+	// PUSHDATA2 <data> DROP
+	script[0] = byte(opcode.PUSHDATA2)
+	l := size - 4
+	script[1], script[2] = byte(l), byte(l>>8)
+	rnd.Read(script[3 : 3+l])
+	script[size-1] = byte(opcode.DROP)
+	return script
+}
+
+// randomKey returns a throwaway private key.
+func randomKey(rnd *rand.Rand) *keys.PrivateKey {
+	for {
+		k, err := keys.NewPrivateKeyFromBytes(randomBytes(rnd, 32))
+		if err == nil && k.D.Sign() != 0 && k.D.Cmp(k.Curve.Params().N) < 0 {
+			return k
+		}
+	}
+}
+
+func randomSigners(rnd *rand.Rand, p GenProfile) []Signer {
+	n := 1
+	if p.MaxSigners > 1 {
+		n += rnd.Intn(p.MaxSigners)
+	}
+	if n > transaction.MaxAttributes {
+		n = transaction.MaxAttributes
+	}
+	signers := make([]Signer, 0, n)
+	if p.Sender != nil {
+		signers = append(signers, p.Sender)
+	}
+	for len(signers) < n {
+		s := NewSingleSigner(wallet.NewAccountFromPrivateKey(randomKey(rnd)))
+		var dup bool
+		for _, other := range signers {
+			dup = dup || other.ScriptHash().Equals(s.ScriptHash())
+		}
+		if !dup {
+			signers = append(signers, s)
+		}
+	}
+	return signers
+}
+
+func randomSigner(rnd *rand.Rand, p GenProfile, acc util.Uint160) transaction.Signer {
+	s := transaction.Signer{Account: acc}
+	weights := make(map[int]int, len(p.ScopeWeights))
+	for sc, w := range p.ScopeWeights {
+		weights[int(sc)] = w
+	}
+	sc, _ := pickWeighted(rnd, weights)
+	s.Scopes = transaction.WitnessScope(sc)
+	if s.Scopes != transaction.Global && s.Scopes != transaction.None {
+		// Combine with other enabled scopes.
+		for _, other := range []transaction.WitnessScope{transaction.CalledByEntry,
+			transaction.CustomContracts, transaction.CustomGroups, transaction.Rules} {
+			if p.ScopeWeights[other] > 0 && rnd.Intn(4) == 0 {
+				s.Scopes |= other
+			}
+		}
+	}
+	if s.Scopes&transaction.CustomContracts != 0 {
+		s.AllowedContracts = make([]util.Uint160, 1+rnd.Intn(maxSubitems))
+		for i := range s.AllowedContracts {
+			rnd.Read(s.AllowedContracts[i][:])
+		}
+	}
+	if s.Scopes&transaction.CustomGroups != 0 {
+		s.AllowedGroups = make([]*keys.PublicKey, 1+rnd.Intn(4))
+		for i := range s.AllowedGroups {
+			s.AllowedGroups[i] = randomKey(rnd).PublicKey()
+		}
+	}
+	if s.Scopes&transaction.Rules != 0 {
+		s.Rules = make([]transaction.WitnessRule, 1+rnd.Intn(4))
+		for i := range s.Rules {
+			s.Rules[i].Action = transaction.WitnessAction(rnd.Intn(2))
+			s.Rules[i].Condition = randomCondition(rnd, transaction.MaxConditionNesting)
+		}
+	}
+	return s
+}
+
+func randomCondition(rnd *rand.Rand, depth int) transaction.WitnessCondition {
+	n := 6
+	if depth > 1 { // Composite conditions use one more level for their children.
+		n = 9
+	}
+	switch rnd.Intn(n) {
+	case 0:
+		c := transaction.ConditionBoolean(rnd.Intn(2) == 0)
+		return &c
+	case 1:
+		return &transaction.ConditionCalledByEntry{}
+	case 2:
+		var c transaction.ConditionScriptHash
+		rnd.Read(c[:])
+		return &c
+	case 3:
+		var c transaction.ConditionCalledByContract
+		rnd.Read(c[:])
+		return &c
+	case 4:
+		c := transaction.ConditionGroup(*randomKey(rnd).PublicKey())
+		return &c
+	case 5:
+		c := transaction.ConditionCalledByGroup(*randomKey(rnd).PublicKey())
+		return &c
+	case 6:
+		return &transaction.ConditionNot{Condition: randomCondition(rnd, depth-1)}
+	case 7:
+		c := make(transaction.ConditionAnd, 1+rnd.Intn(3))
+		for i := range c {
+			c[i] = randomCondition(rnd, depth-1)
+		}
+		return &c
+	default:
+		c := make(transaction.ConditionOr, 1+rnd.Intn(3))
+		for i := range c {
+			c[i] = randomCondition(rnd, depth-1)
+		}
+		return &c
+	}
+}
+
+var oracleCodes = []transaction.OracleResponseCode{transaction.Success, transaction.ProtocolNotSupported,
+	transaction.ConsensusUnreachable, transaction.NotFound, transaction.Timeout, transaction.Forbidden,
+	transaction.ResponseTooLarge, transaction.InsufficientFunds, transaction.ContentTypeNotSupported,
+	transaction.Error}
+
+func randomAttribute(rnd *rand.Rand, p GenProfile, t transaction.AttrType) transaction.Attribute {
+	attr := transaction.Attribute{Type: t}
+	switch t {
+	case transaction.HighPriority:
+	case transaction.OracleResponseT:
+		r := &transaction.OracleResponse{
+			ID:   uint64(rnd.Intn(4)),
+			Code: oracleCodes[rnd.Intn(len(oracleCodes))],
+		}
+		if r.Code == transaction.Success {
+			r.Result = randomBytes(rnd, rnd.Intn(64))
+		}
+		attr.Value = r
+	case transaction.NotValidBeforeT:
+		attr.Value = &transaction.NotValidBefore{Height: randomHeight(rnd, p)}
+	case transaction.ConflictsT:
+		c := &transaction.Conflicts{}
+		rnd.Read(c.Hash[:])
+		attr.Value = c
+	case transaction.NotaryAssistedT:
+		attr.Value = &transaction.NotaryAssisted{NKeys: uint8(rnd.Intn(256))}
+	default:
+		attr.Value = &transaction.Reserved{Value: randomBytes(rnd, rnd.Intn(64))}
+	}
+	return attr
+}
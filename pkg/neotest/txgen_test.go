@@ -0,0 +1,89 @@
+package neotest
+
+import (
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/config/netmode"
+	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRandomTransaction_Determinism(t *testing.T) {
+	p := DefaultGenProfile(netmode.UnitTestNet, 10)
+	r1, r2 := rand.New(rand.NewSource(42)), rand.New(rand.NewSource(42))
+	for i := 0; i < 20; i++ {
+		tx1, tx2 := NewRandomTransaction(r1, p), NewRandomTransaction(r2, p)
+		require.Equal(t, tx1.Hash(), tx2.Hash())
+		require.Equal(t, len(tx1.Scripts), len(tx2.Scripts))
+		for j := range tx1.Scripts {
+			require.Equal(t, tx1.Scripts[j].VerificationScript, tx2.Scripts[j].VerificationScript)
+			require.Equal(t, len(tx1.Scripts[j].InvocationScript), len(tx2.Scripts[j].InvocationScript))
+		}
+	}
+	other := NewRandomTransaction(rand.New(rand.NewSource(43)), p)
+	require.NotEqual(t, NewRandomTransaction(rand.New(rand.NewSource(42)), p).Hash(), other.Hash())
+}
+
+func TestNewRandomTransaction_Coverage(t *testing.T) {
+	const samples = 1000
+	var (
+		rnd    = rand.New(rand.NewSource(1))
+		p      = DefaultGenProfile(netmode.UnitTestNet, 10)
+		attrs  = make(map[transaction.AttrType]int)
+		scopes = make(map[transaction.WitnessScope]int)
+	)
+	p.MaxScriptSize = 1024 // Keep the test fast.
+	for i := 0; i < samples; i++ {
+		tx := NewRandomTransaction(rnd, p)
+		actual, err := transaction.NewTransactionFromBytes(tx.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, tx.Hash(), actual.Hash())
+		for _, a := range tx.Attributes {
+			attrs[a.Type]++
+		}
+		for _, s := range tx.Signers {
+			for _, sc := range []transaction.WitnessScope{transaction.CalledByEntry, transaction.CustomContracts,
+				transaction.CustomGroups, transaction.Rules, transaction.Global} {
+				if s.Scopes&sc != 0 {
+					scopes[sc]++
+				}
+			}
+			if s.Scopes == transaction.None {
+				scopes[transaction.None]++
+			}
+		}
+	}
+	for typ := range p.AttributeWeights {
+		require.NotZero(t, attrs[typ], typ)
+	}
+	require.Equal(t, len(p.AttributeWeights), len(attrs))
+	for sc := range p.ScopeWeights {
+		require.NotZero(t, scopes[sc], sc)
+	}
+
+	t.Run("zero weight", func(t *testing.T) {
+		p.AttributeWeights = map[transaction.AttrType]int{transaction.ConflictsT: 1, transaction.HighPriority: 0}
+		p.MaxAttributes = 10
+		p.MaxSigners = 1
+		for i := 0; i < 20; i++ {
+			tx := NewRandomTransaction(rnd, p)
+			for _, a := range tx.Attributes {
+				require.Equal(t, transaction.ConflictsT, a.Type)
+			}
+		}
+	})
+}
+
+func TestWriteFuzzCorpus(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "FuzzTest")
+	p := DefaultGenProfile(netmode.UnitTestNet, 10)
+	tx := NewRandomTransaction(rand.New(rand.NewSource(1)), p)
+	require.NoError(t, WriteFuzzCorpus(dir, []*transaction.Transaction{tx}))
+
+	data, err := os.ReadFile(filepath.Join(dir, tx.Hash().StringLE()))
+	require.NoError(t, err)
+	require.Contains(t, string(data), "go test fuzz v1\n[]byte(\"")
+}
@@ -6,18 +6,32 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/io"
 )
 
-// MaxCapabilities is the maximum number of capabilities per payload.
-const MaxCapabilities = 32
+// MaxCapabilitiesLimit is the upper limit for MaxCapabilities, payloads with
+// more capabilities are never accepted.
+const MaxCapabilitiesLimit = 255
+
+// MaxCapabilities is the maximum number of capabilities per payload. It can be
+// adjusted, but values higher than MaxCapabilitiesLimit are not respected.
+var MaxCapabilities = 32
 
 // Capabilities is a list of Capability.
 type Capabilities []Capability
 
 // DecodeBinary implements Serializable interface.
 func (cs *Capabilities) DecodeBinary(br *io.BinReader) {
-	br.ReadArray(cs, MaxCapabilities)
+	br.ReadArray(cs, maxCapabilities())
 	br.Err = cs.checkUniqueCapabilities()
 }
 
+// maxCapabilities returns the effective maximum number of capabilities per
+// payload.
+func maxCapabilities() int {
+	if MaxCapabilities > MaxCapabilitiesLimit {
+		return MaxCapabilitiesLimit
+	}
+	return MaxCapabilities
+}
+
 // EncodeBinary implements Serializable interface.
 func (cs *Capabilities) EncodeBinary(br *io.BinWriter) {
 	br.WriteArray(*cs)
@@ -26,7 +40,7 @@ func (cs *Capabilities) EncodeBinary(br *io.BinWriter) {
 // checkUniqueCapabilities checks whether payload capabilities have unique type.
 func (cs Capabilities) checkUniqueCapabilities() error {
 	err := errors.New("capabilities with the same type are not allowed")
-	var isFullNode, isTCP, isWS bool
+	var isFullNode, isArchival, isTCP, isWS bool
 	for _, cap := range cs {
 		switch cap.Type {
 		case FullNode:
@@ -34,6 +48,11 @@ func (cs Capabilities) checkUniqueCapabilities() error {
 				return err
 			}
 			isFullNode = true
+		case ArchivalNode:
+			if isArchival {
+				return err
+			}
+			isArchival = true
 		case TCPServer:
 			if isTCP {
 				return err
@@ -61,11 +80,12 @@ func (c *Capability) DecodeBinary(br *io.BinReader) {
 	switch c.Type {
 	case FullNode:
 		c.Data = &Node{}
+	case ArchivalNode:
+		c.Data = &Archival{}
 	case TCPServer, WSServer:
 		c.Data = &Server{}
 	default:
-		br.Err = errors.New("unknown node capability type")
-		return
+		c.Data = &UnknownCapability{}
 	}
 	c.Data.DecodeBinary(br)
 }
@@ -95,6 +115,24 @@ func (n *Node) EncodeBinary(bw *io.BinWriter) {
 	bw.WriteU32LE(n.StartHeight)
 }
 
+// Archival represents archival node capability. It has no data, but it's
+// encoded as a single zero byte (reserved for future use) to be compatible
+// with UnknownCapability decoding.
+type Archival struct{}
+
+// DecodeBinary implements Serializable interface.
+func (a *Archival) DecodeBinary(br *io.BinReader) {
+	var zero = br.ReadB()
+	if br.Err == nil && zero != 0 {
+		br.Err = errors.New("archival capability: non-zero reserved byte")
+	}
+}
+
+// EncodeBinary implements Serializable interface.
+func (a *Archival) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteB(0)
+}
+
 // Server represents TCP or WS server capability with port.
 type Server struct {
 	// Port is the port this server is listening on.
@@ -110,3 +148,18 @@ func (s *Server) DecodeBinary(br *io.BinReader) {
 func (s *Server) EncodeBinary(bw *io.BinWriter) {
 	bw.WriteU16LE(s.Port)
 }
+
+// UnknownCapability represents capability of the type not known to this node.
+// Its data are kept as is (they're expected to be length-prefixed), so that
+// newer capabilities don't break payload decoding.
+type UnknownCapability []byte
+
+// DecodeBinary implements Serializable interface.
+func (u *UnknownCapability) DecodeBinary(br *io.BinReader) {
+	*u = br.ReadVarBytes()
+}
+
+// EncodeBinary implements Serializable interface.
+func (u *UnknownCapability) EncodeBinary(bw *io.BinWriter) {
+	bw.WriteVarBytes(*u)
+}
@@ -10,4 +10,7 @@ const (
 	WSServer Type = 0x02
 	// FullNode represents full node capability type.
 	FullNode Type = 0x10
+	// ArchivalNode represents archival node capability type, such nodes
+	// store the whole chain history (they don't remove untraceable blocks).
+	ArchivalNode Type = 0x11
 )
@@ -14,7 +14,7 @@ import (
 // Ledger is enough of Blockchain to satisfy Pool.
 type Ledger interface {
 	BlockHeight() uint32
-	IsExtensibleAllowed(util.Uint160) bool
+	VerifyExtensible(*payload.Extensible) error
 	VerifyWitness(util.Uint160, hash.Hashable, *transaction.Witness, int64) (int64, error)
 }
 
@@ -42,10 +42,7 @@ func New(bc Ledger, capacity int) *Pool {
 	}
 }
 
-var (
-	errDisallowedSender = errors.New("disallowed sender")
-	errInvalidHeight    = errors.New("invalid height")
-)
+var errInvalidHeight = errors.New("invalid height")
 
 // Add adds extensible payload to the pool.
 // First return value specifies if payload was new.
@@ -89,8 +86,8 @@ func (p *Pool) verify(e *payload.Extensible) (bool, error) {
 		}
 		return false, errInvalidHeight
 	}
-	if !p.chain.IsExtensibleAllowed(e.Sender) {
-		return false, errDisallowedSender
+	if err := p.chain.VerifyExtensible(e); err != nil {
+		return false, err
 	}
 	return true, nil
 }
@@ -121,7 +118,7 @@ func (p *Pool) RemoveStale(index uint32) {
 			old := elem
 			elem = elem.Next()
 
-			if e.ValidBlockEnd <= index || !p.chain.IsExtensibleAllowed(e.Sender) {
+			if e.ValidBlockEnd <= index || p.chain.VerifyExtensible(e) != nil {
 				delete(p.verified, h)
 				lst.Remove(old)
 				continue
@@ -121,7 +121,10 @@ type testChain struct {
 	isAllowed     func(util.Uint160) bool
 }
 
-var errVerification = errors.New("verification failed")
+var (
+	errVerification     = errors.New("verification failed")
+	errDisallowedSender = errors.New("disallowed sender")
+)
 
 func newTestChain() *testChain {
 	return &testChain{
@@ -139,7 +142,10 @@ func (c *testChain) VerifyWitness(u util.Uint160, _ hash.Hashable, _ *transactio
 	}
 	return 0, nil
 }
-func (c *testChain) IsExtensibleAllowed(u util.Uint160) bool {
-	return c.isAllowed(u)
+func (c *testChain) VerifyExtensible(e *payload.Extensible) error {
+	if !c.isAllowed(e.Sender) {
+		return errDisallowedSender
+	}
+	return nil
 }
 func (c *testChain) BlockHeight() uint32 { return c.height }
@@ -32,6 +32,9 @@ type Message struct {
 	// StateRootInHeader specifies if state root is included in block header.
 	// This is needed for correct decoding.
 	StateRootInHeader bool
+	// HeaderCommitment specifies if commitment is included in block header.
+	// This is needed for correct decoding.
+	HeaderCommitment bool
 }
 
 // MessageFlag represents compression level of message payload.
@@ -145,7 +148,9 @@ func (m *Message) decodePayload() error {
 	case CMDAddr:
 		p = &payload.AddressList{}
 	case CMDBlock:
-		p = block.New(m.StateRootInHeader)
+		b := block.New(m.StateRootInHeader)
+		b.CommitmentEnabled = m.HeaderCommitment
+		p = b
 	case CMDExtensible:
 		p = payload.NewExtensible()
 	case CMDP2PNotaryRequest:
@@ -157,7 +162,10 @@ func (m *Message) decodePayload() error {
 	case CMDGetBlockByIndex:
 		p = &payload.GetBlockByIndex{}
 	case CMDHeaders:
-		p = &payload.Headers{StateRootInHeader: m.StateRootInHeader}
+		p = &payload.Headers{
+			StateRootInHeader: m.StateRootInHeader,
+			HeaderCommitment:  m.HeaderCommitment,
+		}
 	case CMDTX:
 		p, err := transaction.NewTransactionFromBytes(buf)
 		if err != nil {
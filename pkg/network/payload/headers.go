@@ -13,6 +13,8 @@ type Headers struct {
 	Hdrs []*block.Header
 	// StateRootInHeader specifies whether header contains state root.
 	StateRootInHeader bool
+	// HeaderCommitment specifies whether header contains commitment.
+	HeaderCommitment bool
 }
 
 // Users can at most request 2k header.
@@ -47,6 +49,7 @@ func (p *Headers) DecodeBinary(br *io.BinReader) {
 	for i := 0; i < int(lenHeaders); i++ {
 		header := &block.Header{}
 		header.StateRootEnabled = p.StateRootInHeader
+		header.CommitmentEnabled = p.HeaderCommitment
 		header.DecodeBinary(br)
 		p.Hdrs[i] = header
 	}
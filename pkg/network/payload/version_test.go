@@ -35,6 +35,10 @@ func TestVersionEncodeDecode(t *testing.T) {
 				StartHeight: height,
 			},
 		},
+		{
+			Type: capability.ArchivalNode,
+			Data: &capability.Archival{},
+		},
 	}
 
 	version := NewVersion(magic, id, useragent, capabilities)
@@ -46,3 +50,74 @@ func TestVersionEncodeDecode(t *testing.T) {
 	assert.Equal(t, versionDecoded.UserAgent, []byte(useragent))
 	assert.Equal(t, version, versionDecoded)
 }
+
+func TestVersionUnknownCapability(t *testing.T) {
+	var capabilities = []capability.Capability{
+		{
+			Type: capability.FullNode,
+			Data: &capability.Node{
+				StartHeight: 100500,
+			},
+		},
+		{
+			Type: 0xf0,
+			Data: &capability.UnknownCapability{1, 2, 3},
+		},
+		{
+			Type: 0xf0,
+			Data: &capability.UnknownCapability{},
+		},
+	}
+
+	version := NewVersion(56753, 13337, "/NEO:0.0.1/", capabilities)
+	versionDecoded := &Version{}
+	testserdes.EncodeDecodeBinary(t, version, versionDecoded)
+	assert.Equal(t, version, versionDecoded)
+}
+
+func TestVersionDuplicateArchivalCapability(t *testing.T) {
+	archival := capability.Capability{
+		Type: capability.ArchivalNode,
+		Data: &capability.Archival{},
+	}
+	version := NewVersion(56753, 13337, "/NEO:0.0.1/", []capability.Capability{archival, archival})
+	data, err := testserdes.EncodeBinary(version)
+	assert.NoError(t, err)
+	assert.Error(t, testserdes.DecodeBinary(data, new(Version)))
+}
+
+func TestVersionMaxCapabilities(t *testing.T) {
+	check := func(t *testing.T, n int, ok bool) {
+		var capabilities = make([]capability.Capability, n)
+		for i := range capabilities {
+			capabilities[i] = capability.Capability{
+				Type: 0xf0,
+				Data: &capability.UnknownCapability{},
+			}
+		}
+		data, err := testserdes.EncodeBinary(NewVersion(56753, 13337, "/NEO:0.0.1/", capabilities))
+		assert.NoError(t, err)
+		err = testserdes.DecodeBinary(data, new(Version))
+		if ok {
+			assert.NoError(t, err)
+		} else {
+			assert.Error(t, err)
+		}
+	}
+	t.Run("default", func(t *testing.T) {
+		check(t, capability.MaxCapabilities, true)
+		check(t, capability.MaxCapabilities+1, false)
+	})
+	t.Run("adjusted", func(t *testing.T) {
+		old := capability.MaxCapabilities
+		t.Cleanup(func() { capability.MaxCapabilities = old })
+
+		capability.MaxCapabilities = 64
+		check(t, 64, true)
+		check(t, 65, false)
+
+		capability.MaxCapabilities = capability.MaxCapabilitiesLimit + 10
+		check(t, capability.MaxCapabilitiesLimit, true)
+		check(t, capability.MaxCapabilitiesLimit+1, false)
+	})
+}
@@ -67,8 +67,10 @@ type (
 		HeaderHeight() uint32
 		P2PSigExtensionsEnabled() bool
 		PoolTx(t *transaction.Transaction, pools ...*mempool.Pool) error
+		PoolTxWithSource(t *transaction.Transaction, src mempool.TxSource, pools ...*mempool.Pool) error
 		PoolTxWithData(t *transaction.Transaction, data interface{}, mp *mempool.Pool, feer mempool.Feer, verificationFunction func(t *transaction.Transaction, data interface{}) error) error
 		RegisterPostBlock(f func(func(*transaction.Transaction, *mempool.Pool, bool) bool, *mempool.Pool, *block.Block))
+		SetTxRebroadcast(threshold uint32, maxRetries int, announce func(*transaction.Transaction))
 		SubscribeForBlocks(ch chan<- *block.Block)
 		UnsubscribeFromBlocks(ch chan<- *block.Block)
 	}
@@ -191,6 +193,11 @@ func newServerFromConstructors(config ServerConfig, chain Ledger, stSync StateSy
 			}, s.notaryFeer)
 		})
 	}
+	if config.TxRebroadcastThreshold != 0 {
+		chain.SetTxRebroadcast(config.TxRebroadcastThreshold, config.TxRebroadcastMaxRetries, func(t *transaction.Transaction) {
+			s.broadcastTX(t, nil)
+		})
+	}
 	s.bQueue = newBlockQueue(maxBlockBatch, chain, log, func(b *block.Block) {
 		s.tryStartServices()
 	})
@@ -519,6 +526,12 @@ func (s *Server) getVersionMsg() (*Message, error) {
 				StartHeight: s.chain.BlockHeight(),
 			},
 		})
+		if !s.config.RemoveUntraceableBlocks {
+			capabilities = append(capabilities, capability.Capability{
+				Type: capability.ArchivalNode,
+				Data: &capability.Archival{},
+			})
+		}
 	}
 	payload := payload.NewVersion(
 		s.Net,
@@ -1367,9 +1380,10 @@ func (s *Server) verifyAndPoolTX(t *transaction.Transaction) error {
 }
 
 // RelayTxn a new transaction to the local node and the connected peers.
+// Transactions relayed this way are considered to be locally submitted.
 // Reference: the method OnRelay in C#: https://github.com/neo-project/neo/blob/master/neo/Network/P2P/LocalNode.cs#L159
 func (s *Server) RelayTxn(t *transaction.Transaction) error {
-	err := s.verifyAndPoolTX(t)
+	err := s.chain.PoolTxWithSource(t, mempool.TxSourceLocal)
 	if err == nil {
 		s.broadcastTX(t, nil)
 	}
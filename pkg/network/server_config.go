@@ -81,6 +81,14 @@ type (
 
 		// ExtensiblePoolSize is size of the pool for extensible payloads from a single sender.
 		ExtensiblePoolSize int
+
+		// TxRebroadcastThreshold is the number of blocks after which locally
+		// submitted transactions are rebroadcasted, 0 disables it.
+		TxRebroadcastThreshold uint32
+
+		// TxRebroadcastMaxRetries is the maximum number of rebroadcasts for
+		// a single transaction.
+		TxRebroadcastMaxRetries int
 	}
 )
 
@@ -116,5 +124,8 @@ func NewServerConfig(cfg config.Config) ServerConfig {
 		P2PNotaryCfg:       appConfig.P2PNotary,
 		StateRootCfg:       appConfig.StateRoot,
 		ExtensiblePoolSize: appConfig.ExtensiblePoolSize,
+
+		TxRebroadcastThreshold:  appConfig.TxRebroadcastThreshold,
+		TxRebroadcastMaxRetries: appConfig.TxRebroadcastMaxRetries,
 	}
 }
@@ -167,7 +167,10 @@ func (p *TCPPeer) handleConn() {
 	if err == nil {
 		r := io.NewBinReaderFromIO(p.conn)
 		for {
-			msg := &Message{StateRootInHeader: p.server.config.StateRootInHeader}
+			msg := &Message{
+				StateRootInHeader: p.server.config.StateRootInHeader,
+				HeaderCommitment:  p.server.config.HeaderCommitment,
+			}
 			err = msg.Decode(r)
 
 			if err == payload.ErrTooManyHeaders {
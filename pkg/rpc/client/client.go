@@ -70,6 +70,7 @@ type cache struct {
 	initDone                 bool
 	network                  netmode.Magic
 	stateRootInHeader        bool
+	headerCommitment         bool
 	calculateValidUntilBlock calculateValidUntilBlockCache
 	nativeHashes             map[string]util.Uint160
 }
@@ -137,8 +138,8 @@ func (c *Client) getRequestID() uint64 {
 	return c.latestReqID.Inc()
 }
 
-// Init sets magic of the network client connected to, stateRootInHeader option
-// and native NEO, GAS and Policy contracts scripthashes. This method should be
+// Init sets magic of the network client connected to, stateRootInHeader and
+// headerCommitment options and native NEO, GAS and Policy contracts scripthashes. This method should be
 // called before any header- or block-related requests in order to deserialize
 // responses properly.
 func (c *Client) Init() error {
@@ -152,6 +153,7 @@ func (c *Client) Init() error {
 
 	c.cache.network = version.Protocol.Network
 	c.cache.stateRootInHeader = version.Protocol.StateRootInHeader
+	c.cache.headerCommitment = version.Protocol.HeaderCommitment
 	if version.Protocol.MillisecondsPerBlock == 0 {
 		c.cache.network = version.Magic
 		c.cache.stateRootInHeader = version.StateRootInHeader
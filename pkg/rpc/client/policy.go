@@ -23,6 +23,11 @@ func (c *Client) GetStoragePrice() (int64, error) {
 	return c.invokeNativePolicyMethod("getStoragePrice")
 }
 
+// GetMaxBlockSystemFee invokes `getMaxBlockSystemFee` method on a native Policy contract.
+func (c *Client) GetMaxBlockSystemFee() (int64, error) {
+	return c.invokeNativePolicyMethod("getMaxBlockSystemFee")
+}
+
 // GetMaxNotValidBeforeDelta invokes `getMaxNotValidBeforeDelta` method on a native Notary contract.
 func (c *Client) GetMaxNotValidBeforeDelta() (int64, error) {
 	notaryHash, err := c.GetNativeContractHash(nativenames.Notary)
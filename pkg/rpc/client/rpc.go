@@ -102,7 +102,12 @@ func (c *Client) getBlock(params request.RawParams) (*block.Block, error) {
 	if err != nil {
 		return nil, err
 	}
+	hc, err := c.HeaderCommitment()
+	if err != nil {
+		return nil, err
+	}
 	b = block.New(sr)
+	b.CommitmentEnabled = hc
 	b.DecodeBinary(r)
 	if r.Err != nil {
 		return nil, r.Err
@@ -133,6 +138,10 @@ func (c *Client) getBlockVerbose(params request.RawParams) (*result.Block, error
 		return nil, err
 	}
 	resp.Header.StateRootEnabled = sr
+	resp.Header.CommitmentEnabled, err = c.HeaderCommitment()
+	if err != nil {
+		return nil, err
+	}
 	if err = c.performRequest("getblock", params, resp); err != nil {
 		return nil, err
 	}
@@ -167,9 +176,14 @@ func (c *Client) GetBlockHeader(hash util.Uint256) (*block.Header, error) {
 	if err != nil {
 		return nil, err
 	}
+	hc, err := c.HeaderCommitment()
+	if err != nil {
+		return nil, err
+	}
 	r := io.NewBinReaderFromBuf(resp)
 	h = new(block.Header)
 	h.StateRootEnabled = sr
+	h.CommitmentEnabled = hc
 	h.DecodeBinary(r)
 	if r.Err != nil {
 		return nil, r.Err
@@ -193,6 +207,12 @@ func (c *Client) GetBlockHeaderVerbose(hash util.Uint256) (*result.Header, error
 		params = request.NewRawParams(hash.StringLE(), 1)
 		resp   = &result.Header{}
 	)
+	// Client may be not initialized here, header settings are only used if
+	// they're known.
+	c.cacheLock.RLock()
+	resp.StateRootEnabled = c.cache.stateRootInHeader
+	resp.CommitmentEnabled = c.cache.headerCommitment
+	c.cacheLock.RUnlock()
 	if err := c.performRequest("getblockheader", params, resp); err != nil {
 		return nil, err
 	}
@@ -1112,6 +1132,19 @@ func (c *Client) StateRootInHeader() (bool, error) {
 	return c.cache.stateRootInHeader, nil
 }
 
+// HeaderCommitment returns true if application-defined commitment is contained
+// in block header. You should initialize Client cache with Init() before
+// calling HeaderCommitment.
+func (c *Client) HeaderCommitment() (bool, error) {
+	c.cacheLock.RLock()
+	defer c.cacheLock.RUnlock()
+
+	if !c.cache.initDone {
+		return false, errNetworkNotInitialized
+	}
+	return c.cache.headerCommitment, nil
+}
+
 // GetNativeContractHash returns native contract hash by its name.
 func (c *Client) GetNativeContractHash(name string) (util.Uint160, error) {
 	c.cacheLock.RLock()
@@ -439,6 +439,18 @@ var rpcClientTestCases = map[string][]rpcClientTestCase{
 			},
 		},
 	},
+	"getMaxBlockSystemFee": {
+		{
+			name: "positive",
+			invoke: func(c *Client) (interface{}, error) {
+				return c.GetMaxBlockSystemFee()
+			},
+			serverResponse: `{"id":1,"jsonrpc":"2.0","result":{"state":"HALT","gasconsumed":"2007390","script":"EMAMDWdldEZlZVBlckJ5dGUMFJphpG7sl7iTBtfOgfFbRiCR0AkyQWJ9W1I=","stack":[{"type":"Integer","value":"900000000000"}],"tx":null}}`,
+			result: func(c *Client) interface{} {
+				return int64(900000000000)
+			},
+		},
+	},
 	"getOraclePrice": {
 		{
 			name: "positive",
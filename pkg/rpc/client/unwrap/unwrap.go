@@ -0,0 +1,180 @@
+/*
+Package unwrap provides a set of functions to get simple values from invocation
+results. All of them accept the result of some invocation method (like
+Client.InvokeScript) as is, check the VM state and return an error if it's not
+HALT or if the result stack doesn't contain exactly one element. Then they
+convert the stack item into the requested type.
+*/
+package unwrap
+
+import (
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// Nothing checks the invocation result for correctness and returns an error
+// if something is wrong with it. It's used for methods that don't return anything.
+func Nothing(r *result.Invoke, err error) error {
+	if err != nil {
+		return err
+	}
+	return checkResOK(r)
+}
+
+// Item returns a stack item from the result if execution was successful (HALT
+// state) and if it's the only element on the result stack.
+func Item(r *result.Invoke, err error) (stackitem.Item, error) {
+	err = checkResAndErr(r, err)
+	if err != nil {
+		return nil, err
+	}
+	return r.Stack[0], nil
+}
+
+// BigInt expects correct execution (HALT state) with a single stack item
+// returned. A big.Int is extracted from this item and returned.
+func BigInt(r *result.Invoke, err error) (*big.Int, error) {
+	itm, err := Item(r, err)
+	if err != nil {
+		return nil, err
+	}
+	return itm.TryInteger()
+}
+
+// Bool expects correct execution (HALT state) with a single stack item
+// returned. A bool is extracted from this item and returned.
+func Bool(r *result.Invoke, err error) (bool, error) {
+	itm, err := Item(r, err)
+	if err != nil {
+		return false, err
+	}
+	return itm.TryBool()
+}
+
+// Int64 expects correct execution (HALT state) with a single stack item
+// returned. An int64 is extracted from this item and returned.
+func Int64(r *result.Invoke, err error) (int64, error) {
+	bi, err := BigInt(r, err)
+	if err != nil {
+		return 0, err
+	}
+	if !bi.IsInt64() {
+		return 0, errors.New("int64 overflow")
+	}
+	return bi.Int64(), nil
+}
+
+// Bytes expects correct execution (HALT state) with a single stack item
+// returned. A slice of bytes is extracted from this item and returned.
+func Bytes(r *result.Invoke, err error) ([]byte, error) {
+	itm, err := Item(r, err)
+	if err != nil {
+		return nil, err
+	}
+	return itm.TryBytes()
+}
+
+// String expects correct execution (HALT state) with a single stack item
+// returned. A string is extracted from this item and checked for UTF-8
+// correctness, valid strings are then returned.
+func String(r *result.Invoke, err error) (string, error) {
+	b, err := Bytes(r, err)
+	if err != nil {
+		return "", err
+	}
+	if !utf8.Valid(b) {
+		return "", errors.New("not a UTF-8 string")
+	}
+	return string(b), nil
+}
+
+// Uint160 expects correct execution (HALT state) with a single stack item
+// returned. A util.Uint160 is extracted from this item and returned.
+func Uint160(r *result.Invoke, err error) (util.Uint160, error) {
+	b, err := Bytes(r, err)
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	return util.Uint160DecodeBytesBE(b)
+}
+
+// Uint256 expects correct execution (HALT state) with a single stack item
+// returned. A util.Uint256 is extracted from this item and returned.
+func Uint256(r *result.Invoke, err error) (util.Uint256, error) {
+	b, err := Bytes(r, err)
+	if err != nil {
+		return util.Uint256{}, err
+	}
+	return util.Uint256DecodeBytesBE(b)
+}
+
+// PublicKey expects correct execution (HALT state) with a single stack item
+// returned. A public key is extracted from this item and returned.
+func PublicKey(r *result.Invoke, err error) (*keys.PublicKey, error) {
+	b, err := Bytes(r, err)
+	if err != nil {
+		return nil, err
+	}
+	return keys.NewPublicKeyFromBytes(b, elliptic.P256())
+}
+
+// Array expects correct execution (HALT state) with a single array stack item
+// returned. This item is returned to the caller. Notice that this function can
+// be used for structures as well since they're also represented as slices of
+// stack items (the number of them and their types are structure-specific).
+func Array(r *result.Invoke, err error) ([]stackitem.Item, error) {
+	itm, err := Item(r, err)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := itm.Value().([]stackitem.Item)
+	if !ok {
+		return nil, errors.New("not an array")
+	}
+	return arr, nil
+}
+
+// Map expects correct execution (HALT state) with a single stack item
+// returned. A stackitem.Map is extracted from this item and returned.
+func Map(r *result.Invoke, err error) (*stackitem.Map, error) {
+	itm, err := Item(r, err)
+	if err != nil {
+		return nil, err
+	}
+	if t := itm.Type(); t != stackitem.MapT {
+		return nil, fmt.Errorf("%s is not a map", t.String())
+	}
+	return itm.(*stackitem.Map), nil
+}
+
+func checkResOK(r *result.Invoke) error {
+	if r.State != "HALT" {
+		return fmt.Errorf("invocation failed: %s", r.FaultException)
+	}
+	if r.FaultException != "" {
+		return fmt.Errorf("inconsistent result, HALTed with exception: %s", r.FaultException)
+	}
+	return nil
+}
+
+func checkResAndErr(r *result.Invoke, err error) error {
+	if err != nil {
+		return err
+	}
+	err = checkResOK(r)
+	if err != nil {
+		return err
+	}
+	if len(r.Stack) != 1 {
+		return fmt.Errorf("result stack contains %d items", len(r.Stack))
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package unwrap
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/nspcc-dev/neo-go/pkg/rpc/response/result"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStdErrors(t *testing.T) {
+	funcs := []func(r *result.Invoke, err error) (interface{}, error){
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return BigInt(r, err)
+		},
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return Bool(r, err)
+		},
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return Int64(r, err)
+		},
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return Bytes(r, err)
+		},
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return String(r, err)
+		},
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return Uint160(r, err)
+		},
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return Uint256(r, err)
+		},
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return PublicKey(r, err)
+		},
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return Array(r, err)
+		},
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return Map(r, err)
+		},
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return Item(r, err)
+		},
+		func(r *result.Invoke, err error) (interface{}, error) {
+			return nil, Nothing(r, err)
+		},
+	}
+	t.Run("error on input", func(t *testing.T) {
+		for _, f := range funcs {
+			_, err := f(nil, errors.New("some"))
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("FAULT state", func(t *testing.T) {
+		for _, f := range funcs {
+			_, err := f(&result.Invoke{State: "FAULT"}, nil)
+			require.Error(t, err)
+		}
+	})
+	t.Run("HALT state with exception", func(t *testing.T) {
+		for _, f := range funcs {
+			_, err := f(&result.Invoke{State: "HALT", FaultException: "something happened"}, nil)
+			require.Error(t, err)
+		}
+	})
+	t.Run("multiple return values", func(t *testing.T) {
+		for _, f := range funcs[:len(funcs)-1] {
+			_, err := f(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(42), stackitem.Make(42)}}, nil)
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestBigInt(t *testing.T) {
+	_, err := BigInt(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make([]stackitem.Item{})}}, nil)
+	require.Error(t, err)
+
+	i, err := BigInt(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(42)}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(42), i)
+}
+
+func TestBool(t *testing.T) {
+	_, err := Bool(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make("0x03c564ed28ba3d50beb1a52dcb751b929e1d747281566bd510363470be186bc0")}}, nil)
+	require.Error(t, err)
+
+	b, err := Bool(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(true)}}, nil)
+	require.NoError(t, err)
+	require.True(t, b)
+}
+
+func TestInt64(t *testing.T) {
+	_, err := Int64(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make("0x03c564ed28ba3d50beb1a52dcb751b929e1d747281566bd510363470be186bc0")}}, nil)
+	require.Error(t, err)
+
+	_, err = Int64(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(new(big.Int).Lsh(big.NewInt(1), 64))}}, nil)
+	require.Error(t, err)
+
+	i, err := Int64(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(42)}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), i)
+}
+
+func TestBytes(t *testing.T) {
+	_, err := Bytes(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make([]stackitem.Item{})}}, nil)
+	require.Error(t, err)
+
+	b, err := Bytes(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make([]byte{1, 2, 3})}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, b)
+}
+
+func TestString(t *testing.T) {
+	_, err := String(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make([]stackitem.Item{})}}, nil)
+	require.Error(t, err)
+
+	_, err = String(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make([]byte{0, 0xff})}}, nil)
+	require.Error(t, err)
+
+	s, err := String(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make("value")}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, "value", s)
+}
+
+func TestUint160(t *testing.T) {
+	_, err := Uint160(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(util.Uint256{1, 2, 3}.BytesBE())}}, nil)
+	require.Error(t, err)
+
+	u, err := Uint160(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(util.Uint160{1, 2, 3}.BytesBE())}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, util.Uint160{1, 2, 3}, u)
+}
+
+func TestUint256(t *testing.T) {
+	_, err := Uint256(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(util.Uint160{1, 2, 3}.BytesBE())}}, nil)
+	require.Error(t, err)
+
+	u, err := Uint256(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(util.Uint256{1, 2, 3}.BytesBE())}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, util.Uint256{1, 2, 3}, u)
+}
+
+func TestPublicKey(t *testing.T) {
+	k, err := keys.NewPrivateKey()
+	require.NoError(t, err)
+
+	_, err = PublicKey(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(util.Uint160{1, 2, 3}.BytesBE())}}, nil)
+	require.Error(t, err)
+
+	pk, err := PublicKey(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(k.PublicKey().Bytes())}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, k.PublicKey(), pk)
+}
+
+func TestArray(t *testing.T) {
+	_, err := Array(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(42)}}, nil)
+	require.Error(t, err)
+
+	a, err := Array(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make([]stackitem.Item{stackitem.Make(42)})}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(a))
+	require.Equal(t, stackitem.Make(42), a[0])
+}
+
+func TestMap(t *testing.T) {
+	_, err := Map(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.Make(42)}}, nil)
+	require.Error(t, err)
+
+	m, err := Map(&result.Invoke{State: "HALT", Stack: []stackitem.Item{stackitem.NewMapWithValue([]stackitem.MapElement{{Key: stackitem.Make(42), Value: stackitem.Make("string")}})}}, nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, m.Len())
+	require.Equal(t, 0, m.Index(stackitem.Make(42)))
+}
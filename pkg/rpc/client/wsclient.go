@@ -159,7 +159,12 @@ readloop:
 					// Client is not initialised.
 					break
 				}
-				val = block.New(sr)
+				b := block.New(sr)
+				b.CommitmentEnabled, err = c.HeaderCommitment()
+				if err != nil {
+					break
+				}
+				val = b
 			case response.TransactionEventID:
 				val = &transaction.Transaction{}
 			case response.NotificationEventID:
@@ -41,6 +41,9 @@ type (
 		InitialGasDistribution      fixedn.Fixed8
 		// StateRootInHeader is true if state root is contained in block header.
 		StateRootInHeader bool
+		// HeaderCommitment is true if application-defined commitment is
+		// contained in block header.
+		HeaderCommitment bool
 	}
 )
 
@@ -68,6 +71,7 @@ type (
 		ValidatorsCount             byte          `json:"validatorscount"`
 		InitialGasDistribution      int64         `json:"initialgasdistribution"`
 		StateRootInHeader           bool          `json:"staterootinheader,omitempty"`
+		HeaderCommitment            bool          `json:"headercommitment,omitempty"`
 	}
 
 	// versionUnmarshallerAux is an auxiliary struct used for Version JSON unmarshalling.
@@ -93,6 +97,7 @@ type (
 		ValidatorsCount             byte            `json:"validatorscount"`
 		InitialGasDistribution      json.RawMessage `json:"initialgasdistribution"`
 		StateRootInHeader           bool            `json:"staterootinheader,omitempty"`
+		HeaderCommitment            bool            `json:"headercommitment,omitempty"`
 	}
 )
 
@@ -119,6 +124,7 @@ func (v *Version) MarshalJSON() ([]byte, error) {
 			ValidatorsCount:             v.Protocol.ValidatorsCount,
 			InitialGasDistribution:      int64(v.Protocol.InitialGasDistribution),
 			StateRootInHeader:           v.Protocol.StateRootInHeader,
+			HeaderCommitment:            v.Protocol.HeaderCommitment,
 		},
 		StateRootInHeader: v.StateRootInHeader,
 	}
@@ -146,6 +152,7 @@ func (v *Version) UnmarshalJSON(data []byte) error {
 	v.Protocol.MemoryPoolMaxTransactions = aux.Protocol.MemoryPoolMaxTransactions
 	v.Protocol.ValidatorsCount = aux.Protocol.ValidatorsCount
 	v.Protocol.StateRootInHeader = aux.Protocol.StateRootInHeader
+	v.Protocol.HeaderCommitment = aux.Protocol.HeaderCommitment
 	v.StateRootInHeader = aux.StateRootInHeader
 	if len(aux.Protocol.InitialGasDistribution) == 0 {
 		return nil
@@ -114,6 +114,17 @@ func TestVersion_MarshalUnmarshalJSON(t *testing.T) {
 			require.Equal(t, expected, actual)
 		})
 	})
+	t.Run("header commitment", func(t *testing.T) {
+		expected := new(Version)
+		*expected = *v
+		expected.Protocol.HeaderCommitment = true
+		data, err := json.Marshal(expected)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"headercommitment":true`)
+		actual := &Version{}
+		require.NoError(t, json.Unmarshal(data, actual))
+		require.Equal(t, expected, actual)
+	})
 }
 
 func TestVersionFromUserAgent(t *testing.T) {
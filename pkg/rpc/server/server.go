@@ -557,6 +557,7 @@ func (s *Server) getVersion(_ request.Params) (interface{}, *response.Error) {
 			ValidatorsCount:             byte(cfg.GetNumOfCNs(s.chain.BlockHeight())),
 			InitialGasDistribution:      cfg.InitialGASSupply,
 			StateRootInHeader:           cfg.StateRootInHeader,
+			HeaderCommitment:            cfg.HeaderCommitment,
 		},
 	}, nil
 }
@@ -648,7 +649,7 @@ func (s *Server) calculateNetworkFee(reqParams request.Params) (interface{}, *re
 		size += sizeDelta
 	}
 	fee := s.chain.FeePerByte()
-	netFee += int64(size) * fee
+	netFee += int64(size)*fee + s.chain.GetAttributesFee(tx)
 	return result.NetworkFee{Value: netFee}, nil
 }
 
@@ -1796,6 +1797,10 @@ func (s *Server) getFakeNextBlock(nextBlockHeight uint32) (*block.Block, error)
 	// This is why we provide block here.
 	b := block.New(s.stateRootEnabled)
 	b.Index = nextBlockHeight
+	if s.chain.GetConfig().HeaderCommitment {
+		b.CommitmentEnabled = true
+		b.Commitment = s.chain.GetHeaderCommitment(nextBlockHeight)
+	}
 	hdr, err := s.chain.GetHeader(s.chain.GetHeaderHash(int(nextBlockHeight - 1)))
 	if err != nil {
 		return nil, err
@@ -1860,6 +1865,7 @@ func (s *Server) submitBlock(reqParams request.Params) (interface{}, *response.E
 		return nil, response.NewInvalidParamsError("missing parameter or not base64", err)
 	}
 	b := block.New(s.stateRootEnabled)
+	b.CommitmentEnabled = s.chain.GetConfig().HeaderCommitment
 	r := io.NewBinReaderFromBuf(blockBytes)
 	b.DecodeBinary(r)
 	if r.Err != nil {
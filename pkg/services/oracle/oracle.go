@@ -32,6 +32,33 @@ type (
 		GetTransaction(util.Uint256) (*transaction.Transaction, uint32, error)
 	}
 
+	// ResponseLedger is the interface to Blockchain sufficient for
+	// BuildResponseTx.
+	ResponseLedger interface {
+		BlockHeight() uint32
+		FeePerByte() int64
+		GetBaseExecFee() int64
+		GetConfig() config.ProtocolConfiguration
+		GetContractState(util.Uint160) *state.Contract
+		GetMaxVerificationGAS() int64
+		GetNativeContractScriptHash(string) (util.Uint160, error)
+		GetOracleRequest(id uint64) (*state.OracleRequest, error)
+		GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) *interop.Context
+	}
+
+	// feeLedger is the part of Ledger used for response fee calculation.
+	feeLedger interface {
+		FeePerByte() int64
+		GetBaseExecFee() int64
+	}
+
+	// verifyLedger is the part of Ledger used for Oracle contract witness
+	// verification.
+	verifyLedger interface {
+		GetMaxVerificationGAS() int64
+		GetTestVM(t trigger.Type, tx *transaction.Transaction, b *block.Block) *interop.Context
+	}
+
 	// Oracle represents oracle module capable of talking
 	// with the external world.
 	Oracle struct {
@@ -3,16 +3,22 @@ package oracle
 import (
 	"encoding/hex"
 	"errors"
+	"fmt"
 	gio "io"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/fee"
 	"github.com/nspcc-dev/neo-go/pkg/core/interop"
+	"github.com/nspcc-dev/neo-go/pkg/core/native"
+	"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames"
 	"github.com/nspcc-dev/neo-go/pkg/core/transaction"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/wallet"
 	"go.uber.org/zap"
 )
 
@@ -82,7 +88,18 @@ func readResponse(rc gio.ReadCloser, limit int) ([]byte, error) {
 
 // CreateResponseTx creates unsigned oracle response transaction.
 func (o *Oracle) CreateResponseTx(gasForResponse int64, vub uint32, resp *transaction.OracleResponse) (*transaction.Transaction, error) {
-	tx := transaction.New(o.oracleResponse, 0)
+	return newResponseTx(o.Chain, o.oracleResponse, o.oracleHash, o.getOracleSignContract(),
+		gasForResponse, vub, resp, o.testVerify)
+}
+
+// newResponseTx creates unsigned oracle response transaction with Oracle
+// native contract as the first signer paying fees and oracleSignContract
+// as the second one. verify is used to calculate Oracle contract witness
+// verification cost.
+func newResponseTx(bc feeLedger, respScript []byte, oracleHash util.Uint160, oracleSignContract []byte,
+	gasForResponse int64, vub uint32, resp *transaction.OracleResponse,
+	verify func(*transaction.Transaction) (int64, bool)) (*transaction.Transaction, error) {
+	tx := transaction.New(respScript, 0)
 	tx.Nonce = uint32(resp.ID)
 	tx.ValidUntilBlock = vub
 	tx.Attributes = []transaction.Attribute{{
@@ -90,10 +107,9 @@ func (o *Oracle) CreateResponseTx(gasForResponse int64, vub uint32, resp *transa
 		Value: resp,
 	}}
 
-	oracleSignContract := o.getOracleSignContract()
 	tx.Signers = []transaction.Signer{
 		{
-			Account: o.oracleHash,
+			Account: oracleHash,
 			Scopes:  transaction.None,
 		},
 		{
@@ -109,39 +125,103 @@ func (o *Oracle) CreateResponseTx(gasForResponse int64, vub uint32, resp *transa
 	size := io.GetVarSize(tx)
 	tx.Scripts = append(tx.Scripts, transaction.Witness{VerificationScript: oracleSignContract})
 
-	gasConsumed, ok := o.testVerify(tx)
+	gasConsumed, ok := verify(tx)
 	if !ok {
 		return nil, errors.New("can't verify transaction")
 	}
 	tx.NetworkFee += gasConsumed
 
-	netFee, sizeDelta := fee.Calculate(o.Chain.GetBaseExecFee(), tx.Scripts[1].VerificationScript)
+	netFee, sizeDelta := fee.Calculate(bc.GetBaseExecFee(), tx.Scripts[1].VerificationScript)
 	tx.NetworkFee += netFee
 	size += sizeDelta
 
-	currNetFee := tx.NetworkFee + int64(size)*o.Chain.FeePerByte()
+	currNetFee := tx.NetworkFee + int64(size)*bc.FeePerByte()
 	if currNetFee > gasForResponse {
 		attrSize := io.GetVarSize(tx.Attributes)
 		resp.Code = transaction.InsufficientFunds
 		resp.Result = nil
 		size = size - attrSize + io.GetVarSize(tx.Attributes)
 	}
-	tx.NetworkFee += int64(size) * o.Chain.FeePerByte() // 233
+	tx.NetworkFee += int64(size) * bc.FeePerByte() // 233
 
 	// Calculate system fee.
 	tx.SystemFee = gasForResponse - tx.NetworkFee
 	return tx, nil
 }
 
+// BuildResponseTx creates unsigned oracle response transaction for the request
+// with the given ID the same way oracle service does it. Oracle native
+// contract is the first signer paying fees and oracleAcc (which is expected
+// to be a multisignature account of designated oracle nodes) is the second
+// one, its witness is to be added with oracleAcc.SignTx. The rest of the
+// request's GasForResponse left after paying network fee is used as a system
+// fee. If GasForResponse is not sufficient to pay for the response,
+// InsufficientFunds code is used and the result is dropped, an error is
+// returned if it can't pay even for that.
+func BuildResponseTx(bc ResponseLedger, requestID uint64, code transaction.OracleResponseCode,
+	result []byte, oracleAcc *wallet.Account) (*transaction.Transaction, error) {
+	if oracleAcc.Contract == nil || len(oracleAcc.Contract.Script) == 0 {
+		return nil, errors.New("oracle account has no verification script")
+	}
+	if len(result) > transaction.MaxOracleResultSize {
+		return nil, ErrResponseTooLarge
+	}
+	if code != transaction.Success && len(result) != 0 {
+		return nil, transaction.ErrInvalidResult
+	}
+	req, err := bc.GetOracleRequest(requestID)
+	if err != nil {
+		return nil, fmt.Errorf("can't get request %d: %w", requestID, err)
+	}
+	oracleHash, err := bc.GetNativeContractScriptHash(nativenames.Oracle)
+	if err != nil {
+		return nil, err
+	}
+	cs := bc.GetContractState(oracleHash)
+	if cs == nil {
+		return nil, errors.New("oracle contract not found")
+	}
+	md := cs.Manifest.ABI.GetMethod(manifest.MethodVerify, -1)
+	if md == nil {
+		return nil, fmt.Errorf("%s method not found", manifest.MethodVerify)
+	}
+
+	resp := &transaction.OracleResponse{
+		ID:     requestID,
+		Code:   code,
+		Result: result,
+	}
+	gasForResponse := int64(req.GasForResponse)
+	vub := bc.BlockHeight() + bc.GetConfig().MaxValidUntilBlockIncrement
+	tx, err := newResponseTx(bc, native.CreateOracleResponseScript(oracleHash), oracleHash, oracleAcc.Contract.Script,
+		gasForResponse, vub, resp, func(tx *transaction.Transaction) (int64, bool) {
+			return testVerify(bc, tx, cs.NEF.Script, oracleHash, md.Offset)
+		})
+	if err != nil {
+		return nil, err
+	}
+	if tx.SystemFee < 0 {
+		return nil, fmt.Errorf("GasForResponse of request %d (%d) doesn't cover network fee (%d)",
+			requestID, gasForResponse, tx.NetworkFee)
+	}
+	return tx, nil
+}
+
 func (o *Oracle) testVerify(tx *transaction.Transaction) (int64, bool) {
+	return testVerify(o.Chain, tx, o.oracleScript, o.oracleHash, o.verifyOffset)
+}
+
+// testVerify runs Oracle contract verify method (at verifyOffset of script)
+// for the given transaction and returns the GAS consumed.
+func testVerify(bc verifyLedger, tx *transaction.Transaction, script []byte, h util.Uint160, verifyOffset int) (int64, bool) {
 	// (*Blockchain).GetTestVM calls Hash() method of provided transaction; once being called, this
 	// method caches transaction hash, but tx building is not yet completed and hash will be changed.
 	// So make a copy of tx to avoid wrong hash caching.
 	cp := *tx
-	ic := o.Chain.GetTestVM(trigger.Verification, &cp, nil)
-	ic.VM.GasLimit = o.Chain.GetMaxVerificationGAS()
-	ic.VM.LoadScriptWithHash(o.oracleScript, o.oracleHash, callflag.ReadOnly)
-	ic.VM.Context().Jump(o.verifyOffset)
+	ic := bc.GetTestVM(trigger.Verification, &cp, nil)
+	ic.VM.GasLimit = bc.GetMaxVerificationGAS()
+	ic.VM.LoadScriptWithHash(script, h, callflag.ReadOnly)
+	ic.VM.Context().Jump(verifyOffset)
 
 	ok := isVerifyOk(ic)
 	return ic.VM.GasConsumed(), ok
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"path"
 	"sort"
 	"strconv"
 	"strings"
@@ -19,29 +20,382 @@ import (
 const srcTmpl = `
 {{- define "METHOD" -}}
 // {{.Name}} {{.Comment}}
+{{- range $d := .Details}}
+// {{$d}}
+{{- end}}
 func {{.Name}}({{range $index, $arg := .Arguments -}}
 	{{- if ne $index 0}}, {{end}}
 		{{- .Name}} {{.Type}}
 	{{- end}}) {{if .ReturnType }}{{ .ReturnType }} {
-	return neogointernal.CallWithToken(Hash, "{{ .NameABI }}", int(contract.{{ .CallFlag }})
-		{{- range $arg := .Arguments -}}, {{.Name}}{{end}}).({{ .ReturnType }})
+	return {{if .Decoder}}{{.Decoder}}({{end}}neogointernal.CallWithToken(Hash, "{{ .NameABI }}", int(contract.{{ .CallFlag }})
+		{{- range $arg := .Arguments -}}, {{.Name}}{{end}}){{if .Decoder}}){{else}}.({{ .ReturnType }}){{end}}
 	{{- else -}} {
 	neogointernal.CallWithTokenNoRet(Hash, "{{ .NameABI }}", int(contract.{{ .CallFlag }})
 		{{- range $arg := .Arguments -}}, {{.Name}}{{end}})
 	{{- end}}
 }
 {{- end -}}
+{{- define "DECODER" -}}
+// {{.Name}} converts contract invocation result to {{.Type}}.
+// Null is converted to the zero value.
+func {{.Name}}(item interface{}) {{.Type}} {
+	if item == nil {
+		return {{.Zero}}
+	}
+	arr := item.([]interface{})
+{{- if .Elem}}
+	res := make({{.Type}}, len(arr))
+	for i := range arr {
+		res[i] = {{.Elem}}(arr[i])
+	}
+	return res
+{{- else}}
+	return {{.Literal}}{
+{{- range $f := .Fields}}
+		{{$f.Key}} {{$f.Expr}},
+{{- end}}
+	}
+{{- end}}
+}
+{{- end -}}
 // Package {{.PackageName}} contains wrappers for {{.ContractName}} contract.
 package {{.PackageName}}
 
 import (
-{{range $m := .Imports}}	"{{ $m }}"
+{{range $m := .Imports}}	{{ $m }}
 {{end}})
 
 // Hash contains contract hash in big-endian form.
 const Hash = "{{ .Hash }}"
 {{range $m := .Methods}}
 {{template "METHOD" $m }}
+{{end}}
+{{- range $d := .Decoders}}
+{{template "DECODER" $d }}
+{{end}}`
+
+const rpcSrcTmpl = `
+{{- define "SAFEMETHOD" -}}
+// {{.Name}} {{.Comment}}
+{{- range $d := .Details}}
+// {{$d}}
+{{- end}}
+func (c *ContractReader) {{.Name}}({{range $index, $arg := .Arguments -}}
+	{{- if ne $index 0}}, {{end}}
+		{{- .Name}} {{.Type}}
+	{{- end}}) {{if .ReturnType }}({{ .ReturnType }}, error) {
+	return {{if .Decoder}}{{.Decoder}}({{end}}unwrap.{{.Unwrap}}(c.invoke("{{ .NameABI }}", callflag.{{ .CallFlag }}
+		{{- range $arg := .Arguments -}}, {{.Expr}}{{end}})){{if .Decoder}}){{end}}
+	{{- else -}} error {
+	return unwrap.Nothing(c.invoke("{{ .NameABI }}", callflag.{{ .CallFlag }}
+		{{- range $arg := .Arguments -}}, {{.Expr}}{{end}}))
+	{{- end}}
+}
+{{- end -}}
+{{- define "METHOD" -}}
+// {{.Name}} creates a transaction invoking ` + "`{{ .NameABI }}`" + ` method of contract
+// and sends it to the network returning its hash and ValidUntilBlock value.
+{{- range $d := .Details}}
+// {{$d}}
+{{- end}}
+func (c *Contract) {{.Name}}({{range $index, $arg := .Arguments -}}
+	{{- if ne $index 0}}, {{end}}
+		{{- .Name}} {{.Type}}
+	{{- end}}) (util.Uint256, uint32, error) {
+	return c.send("{{ .NameABI }}", callflag.{{ .CallFlag }}
+		{{- range $arg := .Arguments -}}, {{.Expr}}{{end}})
+}
+{{- end -}}
+{{- define "STRUCT" -}}
+// {{.Name}} is a contract-specific {{.Origin}} type used by its methods.
+{{- if .Skeleton}}
+// Its fields are not described in the binding configuration, so it only
+// keeps the original stack item.
+type {{.Name}} struct {
+	Item stackitem.Item
+}
+{{- else}}
+type {{.Name}} struct {
+{{- range $f := .Fields}}
+	{{$f.Key}} {{$f.Type}}
+{{- end}}
+}
+{{- end}}
+
+// itemTo{{.Name}} converts stack item into *{{.Name}}.
+func itemTo{{.Name}}(item stackitem.Item, err error) (*{{.Name}}, error) {
+	if err != nil {
+		return nil, err
+	}
+	var res = new({{.Name}})
+	err = res.FromStackItem(item)
+	return res, err
+}
+
+// FromStackItem retrieves fields of {{.Name}} from the given stack item
+// and returns an error if it's not compatible.
+func (res *{{.Name}}) FromStackItem(item stackitem.Item) error {
+{{- if .Skeleton}}
+	res.Item = item
+	return nil
+{{- else}}
+	arr, ok := item.Value().([]stackitem.Item)
+	if !ok {
+		return errors.New("not an array")
+	}
+	if len(arr) != {{len .Fields}} {
+		return errors.New("wrong number of structure elements")
+	}
+	var err error
+{{- range $f := .Fields}}
+	res.{{$f.Name}}, err = {{$f.From}}
+	if err != nil {
+		return fmt.Errorf("field {{$f.Name}}: %w", err)
+	}
+{{- end}}
+	return nil
+{{- end}}
+}
+
+// ToStackItem creates stack item from {{.Name}}.
+func (res *{{.Name}}) ToStackItem() (stackitem.Item, error) {
+	if res == nil {
+		return stackitem.Null{}, nil
+	}
+{{- if .Skeleton}}
+	return res.Item, nil
+{{- else}}
+{{- if .ToErr}}
+	var (
+		err   error
+		itm   stackitem.Item
+		items = make([]stackitem.Item, 0, {{len .Fields}})
+	)
+{{- else}}
+	var items = make([]stackitem.Item, 0, {{len .Fields}})
+{{- end}}
+{{- range $f := .Fields}}
+{{- if $f.ToErr}}
+	itm, err = {{$f.To}}
+	if err != nil {
+		return nil, fmt.Errorf("field {{$f.Name}}: %w", err)
+	}
+	items = append(items, itm)
+{{- else}}
+	items = append(items, {{$f.To}})
+{{- end}}
+{{- end}}
+	return stackitem.NewStruct(items), nil
+{{- end}}
+}
+{{- end -}}
+{{- define "SLICE" -}}
+// itemTo{{.Name}} converts stack item into {{.Type}}.
+func itemTo{{.Name}}(item stackitem.Item, err error) ({{.Type}}, error) {
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := item.Value().([]stackitem.Item)
+	if !ok {
+		return nil, errors.New("not an array")
+	}
+	res := make({{.Type}}, len(arr))
+	for i := range arr {
+		res[i], err = {{.From}}
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+	return res, nil
+}
+
+// {{lowerFirst .Name}}ToItem converts {{.Type}} into stack item.
+func {{lowerFirst .Name}}ToItem(s {{.Type}}) (stackitem.Item, error) {
+	items := make([]stackitem.Item, len(s))
+{{- if .ToErr}}
+	for i := range s {
+		var err error
+		items[i], err = {{.To}}
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+	}
+{{- else}}
+	for i := range s {
+		items[i] = {{.To}}
+	}
+{{- end}}
+	return stackitem.NewArray(items), nil
+}
+{{- end -}}
+// Package {{.PackageName}} contains RPC wrappers for {{.ContractName}} contract.
+package {{.PackageName}}
+
+import (
+{{range $m := .Imports}}	{{ $m }}
+{{end}})
+
+// Hash contains contract hash.
+var Hash = {{ .Hash }}
+
+// Invoker is used by ContractReader to call various safe methods.
+type Invoker interface {
+	InvokeScript(script []byte, signers []transaction.Signer) (*result.Invoke, error)
+}
+
+// Actor is used by Contract to create and send transactions.
+type Actor interface {
+	Invoker
+	CreateTxFromScript(script []byte, acc *wallet.Account, sysFee, netFee int64, cosigners []client.SignerAccount) (*transaction.Transaction, error)
+	SignAndPushTx(tx *transaction.Transaction, acc *wallet.Account, cosigners []client.SignerAccount) (util.Uint256, error)
+}
+
+// ContractReader implements safe contract methods.
+type ContractReader struct {
+	invoker Invoker
+}
+
+// Contract implements all contract methods.
+type Contract struct {
+	ContractReader
+	actor   Actor
+	account *wallet.Account
+}
+
+// NewReader creates an instance of ContractReader using Hash and the given Invoker.
+func NewReader(invoker Invoker) *ContractReader {
+	return &ContractReader{invoker}
+}
+
+// New creates an instance of Contract using Hash and the given Actor. Transactions
+// are sent on behalf of the given account, it must be unlocked.
+func New(actor Actor, acc *wallet.Account) *Contract {
+	return &Contract{ContractReader{actor}, actor, acc}
+}
+
+func script(method string, f callflag.CallFlag, args ...interface{}) ([]byte, error) {
+	w := io.NewBufBinWriter()
+	emit.AppCall(w.BinWriter, Hash, method, f, args...)
+	if w.Err != nil {
+		return nil, w.Err
+	}
+	return w.Bytes(), nil
+}
+
+func (c *ContractReader) invoke(method string, f callflag.CallFlag, args ...interface{}) (*result.Invoke, error) {
+	s, err := script(method, f, args...)
+	if err != nil {
+		return nil, err
+	}
+	return c.invoker.InvokeScript(s, nil)
+}
+
+func (c *Contract) send(method string, f callflag.CallFlag, args ...interface{}) (util.Uint256, uint32, error) {
+	s, err := script(method, f, args...)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	tx, err := c.actor.CreateTxFromScript(s, c.account, -1, 0, nil)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	h, err := c.actor.SignAndPushTx(tx, c.account, nil)
+	return h, tx.ValidUntilBlock, err
+}
+{{- if .Deploy}}
+
+// Deploy creates a transaction deploying the contract with the given NEF file,
+// manifest and data (passed to _deploy method) and sends it to the network on
+// behalf of the given account returning its hash and ValidUntilBlock value.
+// Notice that the hash of the deployed contract depends on the sender, so
+// it's equal to Hash only if the account is the same as the one used for the
+// original deployment.
+func Deploy(actor Actor, acc *wallet.Account, nefFile *nef.File, m *manifest.Manifest, data interface{}) (util.Uint256, uint32, error) {
+	rawNef, err := nefFile.Bytes()
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	rawManifest, err := json.Marshal(m)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	w := io.NewBufBinWriter()
+	mgmtHash := state.CreateContractHash(util.Uint160{}, 0, nativenames.Management)
+	emit.AppCall(w.BinWriter, mgmtHash, "deploy", callflag.All, rawNef, rawManifest, data)
+	if w.Err != nil {
+		return util.Uint256{}, 0, w.Err
+	}
+	tx, err := actor.CreateTxFromScript(w.Bytes(), acc, -1, 0, nil)
+	if err != nil {
+		return util.Uint256{}, 0, err
+	}
+	h, err := actor.SignAndPushTx(tx, acc, nil)
+	return h, tx.ValidUntilBlock, err
+}
+{{- end}}
+{{range $m := .SafeMethods}}
+{{template "SAFEMETHOD" $m }}
+{{end}}
+{{- range $m := .Methods}}
+{{template "METHOD" $m }}
+{{end}}
+{{- range $s := .Structs}}
+{{template "STRUCT" $s }}
+{{end}}
+{{- range $s := .Slices}}
+{{template "SLICE" $s }}
+{{end}}
+{{- if .Helpers.String}}
+// itemToString converts stack item into string.
+func itemToString(item stackitem.Item, err error) (string, error) {
+	if err != nil {
+		return "", err
+	}
+	b, err := item.TryBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+{{end}}
+{{- if .Helpers.Uint160}}
+// itemToUint160 converts stack item into util.Uint160.
+func itemToUint160(item stackitem.Item, err error) (util.Uint160, error) {
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	b, err := item.TryBytes()
+	if err != nil {
+		return util.Uint160{}, err
+	}
+	return util.Uint160DecodeBytesBE(b)
+}
+{{end}}
+{{- if .Helpers.Uint256}}
+// itemToUint256 converts stack item into util.Uint256.
+func itemToUint256(item stackitem.Item, err error) (util.Uint256, error) {
+	if err != nil {
+		return util.Uint256{}, err
+	}
+	b, err := item.TryBytes()
+	if err != nil {
+		return util.Uint256{}, err
+	}
+	return util.Uint256DecodeBytesBE(b)
+}
+{{end}}
+{{- if .Helpers.PublicKey}}
+// itemToPublicKey converts stack item into *keys.PublicKey.
+func itemToPublicKey(item stackitem.Item, err error) (*keys.PublicKey, error) {
+	if err != nil {
+		return nil, err
+	}
+	b, err := item.TryBytes()
+	if err != nil {
+		return nil, err
+	}
+	return keys.NewPublicKeyFromBytes(b, elliptic.P256())
+}
 {{end}}`
 
 type (
@@ -52,7 +406,36 @@ type (
 		Hash      util.Uint160                 `yaml:"hash,omitempty"`
 		Overrides map[string]Override          `yaml:"overrides,omitempty"`
 		CallFlags map[string]callflag.CallFlag `yaml:"callflags,omitempty"`
-		Output    io.Writer                    `yaml:"-"`
+		// Structs contains field lists for structure types used in
+		// overrides. The key is a fully-qualified type name (like
+		// "github.com/user/pkg.Type") and fields are to be listed in the
+		// order they're stored in the contract. Method results of these
+		// types are decoded field by field instead of being cast directly.
+		Structs map[string][]StructField `yaml:"structs,omitempty"`
+		// RPCBindings makes Generate produce client-side wrappers invoking
+		// contract methods via RPC instead of the on-chain ones. Overrides
+		// are not applied in this mode unless StructHelpers is set.
+		RPCBindings bool `yaml:"rpc,omitempty"`
+		// StructHelpers makes RPC bindings define types for structures
+		// returned by safe methods (as specified in Overrides) along with
+		// FromStackItem/ToStackItem conversion methods. Fields are taken
+		// from Structs, structures not described there only keep the
+		// original stack item. It's ignored for on-chain bindings.
+		StructHelpers bool `yaml:"structhelpers,omitempty"`
+		// Deploy makes RPC bindings include Deploy function sending contract
+		// deployment transaction. It's ignored for on-chain bindings.
+		Deploy bool `yaml:"deploy,omitempty"`
+		// DetailedComments adds method safety, call flags and parameter
+		// list from the manifest to the comments of generated methods.
+		DetailedComments bool      `yaml:"detailedcomments,omitempty"`
+		Output           io.Writer `yaml:"-"`
+	}
+
+	// StructField describes a single field of the structure type
+	// used in overrides.
+	StructField struct {
+		Field string   `yaml:"field"`
+		Type  Override `yaml:"type"`
 	}
 
 	contractTmpl struct {
@@ -60,7 +443,13 @@ type (
 		ContractName string
 		Imports      []string
 		Hash         string
+		Deploy       bool
+		SafeMethods  []methodTmpl
 		Methods      []methodTmpl
+		Decoders     []decoderTmpl
+		Structs      []structTmpl
+		Slices       []sliceTmpl
+		Helpers      map[string]bool
 	}
 
 	methodTmpl struct {
@@ -68,13 +457,31 @@ type (
 		NameABI    string
 		CallFlag   string
 		Comment    string
+		Details    []string
 		Arguments  []paramTmpl
 		ReturnType string
+		Unwrap     string
+		Decoder    string
+	}
+
+	decoderTmpl struct {
+		Name    string
+		Type    string
+		Zero    string
+		Literal string
+		Elem    string
+		Fields  []fieldTmpl
+	}
+
+	fieldTmpl struct {
+		Key  string
+		Expr string
 	}
 
 	paramTmpl struct {
 		Name string
 		Type string
+		Expr string
 	}
 )
 
@@ -83,6 +490,7 @@ func NewConfig() Config {
 	return Config{
 		Overrides: make(map[string]Override),
 		CallFlags: make(map[string]callflag.CallFlag),
+		Structs:   make(map[string][]StructField),
 	}
 }
 
@@ -93,10 +501,14 @@ func Generate(cfg Config) error {
 		return err
 	}
 
+	src := srcTmpl
+	if cfg.RPCBindings {
+		src = rpcSrcTmpl
+	}
 	tmp, err := template.New("generate").Funcs(template.FuncMap{
 		"lowerFirst": lowerFirst,
 		"scTypeToGo": scTypeToGo,
-	}).Parse(srcTmpl)
+	}).Parse(src)
 	if err != nil {
 		return err
 	}
@@ -137,10 +549,74 @@ func scTypeToGo(typ smartcontract.ParamType) string {
 	}
 }
 
+// scTypeToRPCGo returns Go type, unwrap function name and an import (if any)
+// used by RPC bindings for the given parameter type. Parameter types are
+// slightly different from the returned ones because of emit.Array limitations.
+func scTypeToRPCGo(typ smartcontract.ParamType, isParam bool) (string, string, string) {
+	switch typ {
+	case smartcontract.AnyType, smartcontract.InteropInterfaceType:
+		if isParam {
+			return "interface{}", "", ""
+		}
+		return "stackitem.Item", "Item", "github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	case smartcontract.BoolType:
+		return "bool", "Bool", ""
+	case smartcontract.IntegerType:
+		return "*big.Int", "BigInt", "math/big"
+	case smartcontract.ByteArrayType, smartcontract.SignatureType:
+		return "[]byte", "Bytes", ""
+	case smartcontract.StringType:
+		return "string", "String", ""
+	case smartcontract.Hash160Type:
+		return "util.Uint160", "Uint160", ""
+	case smartcontract.Hash256Type:
+		return "util.Uint256", "Uint256", ""
+	case smartcontract.PublicKeyType:
+		return "*keys.PublicKey", "PublicKey", "github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	case smartcontract.ArrayType:
+		if isParam {
+			return "[]interface{}", "", ""
+		}
+		return "[]stackitem.Item", "Array", "github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	case smartcontract.MapType:
+		if isParam {
+			return "interface{}", "", ""
+		}
+		return "*stackitem.Map", "Map", "github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	case smartcontract.VoidType:
+		return "", "", ""
+	default:
+		panic("unreachable")
+	}
+}
+
+// isSafe checks whether method m can be invoked without sending a transaction.
+func isSafe(cfg Config, m manifest.Method) bool {
+	if f, ok := cfg.CallFlags[m.Name]; ok {
+		return f&(callflag.WriteStates|callflag.AllowNotify) == 0
+	}
+	return m.Safe
+}
+
 func templateFromManifest(cfg Config) (contractTmpl, error) {
+	if err := validateStructs(cfg); err != nil {
+		return contractTmpl{}, err
+	}
+
 	hStr := ""
-	for _, b := range cfg.Hash.BytesBE() {
-		hStr += fmt.Sprintf("\\x%02x", b)
+	if cfg.RPCBindings {
+		hStr = "util.Uint160{"
+		for i, b := range cfg.Hash.BytesBE() {
+			if i != 0 {
+				hStr += ", "
+			}
+			hStr += fmt.Sprintf("0x%02x", b)
+		}
+		hStr += "}"
+	} else {
+		for _, b := range cfg.Hash.BytesBE() {
+			hStr += fmt.Sprintf("\\x%02x", b)
+		}
 	}
 
 	ctr := contractTmpl{
@@ -159,7 +635,42 @@ func templateFromManifest(cfg Config) (contractTmpl, error) {
 		ctr.PackageName = buf.String()
 	}
 
+	aliases := importAliases(cfg)
+	if len(aliases) != 0 {
+		cfg = applyAliases(cfg, aliases)
+	}
 	imports := make(map[string]struct{})
+	decoders := make(map[string]bool)
+	var structs *rpcStructs
+	if cfg.RPCBindings && cfg.StructHelpers {
+		structs = newRPCStructs(cfg, &ctr, imports)
+	}
+	if cfg.RPCBindings {
+		for _, imp := range []string{
+			"github.com/nspcc-dev/neo-go/pkg/core/transaction",
+			"github.com/nspcc-dev/neo-go/pkg/io",
+			"github.com/nspcc-dev/neo-go/pkg/rpc/client",
+			"github.com/nspcc-dev/neo-go/pkg/rpc/response/result",
+			"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag",
+			"github.com/nspcc-dev/neo-go/pkg/util",
+			"github.com/nspcc-dev/neo-go/pkg/vm/emit",
+			"github.com/nspcc-dev/neo-go/pkg/wallet",
+		} {
+			imports[imp] = struct{}{}
+		}
+		if cfg.Deploy {
+			ctr.Deploy = true
+			for _, imp := range []string{
+				"encoding/json",
+				"github.com/nspcc-dev/neo-go/pkg/core/native/nativenames",
+				"github.com/nspcc-dev/neo-go/pkg/core/state",
+				"github.com/nspcc-dev/neo-go/pkg/smartcontract/manifest",
+				"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef",
+			} {
+				imports[imp] = struct{}{}
+			}
+		}
+	}
 	seen := make(map[string]bool)
 	for _, m := range cfg.Manifest.ABI.Methods {
 		seen[m.Name] = false
@@ -169,8 +680,10 @@ func templateFromManifest(cfg Config) (contractTmpl, error) {
 			continue
 		}
 
-		imports["github.com/nspcc-dev/neo-go/pkg/interop/contract"] = struct{}{}
-		imports["github.com/nspcc-dev/neo-go/pkg/interop/neogointernal"] = struct{}{}
+		if !cfg.RPCBindings {
+			imports["github.com/nspcc-dev/neo-go/pkg/interop/contract"] = struct{}{}
+			imports["github.com/nspcc-dev/neo-go/pkg/interop/neogointernal"] = struct{}{}
+		}
 
 		// Consider `perform(a)` and `perform(a, b)` methods.
 		// First, try to export the second method with `Perform2` name.
@@ -198,6 +711,13 @@ func templateFromManifest(cfg Config) (contractTmpl, error) {
 		} else if m.Safe {
 			mtd.CallFlag = callflag.ReadOnly.String()
 		}
+		if cfg.DetailedComments {
+			mtd.Details = methodDetails(m, mtd.CallFlag)
+		}
+		if cfg.RPCBindings {
+			templateRPCMethod(&ctr, &mtd, cfg, m, isSafe(cfg, m), structs, imports)
+			continue
+		}
 		for i := range m.Parameters {
 			name := m.Parameters[i].Name
 			if name == "" {
@@ -225,6 +745,7 @@ func templateFromManifest(cfg Config) (contractTmpl, error) {
 			if over.Package != "" {
 				imports[over.Package] = struct{}{}
 			}
+			mtd.Decoder = templateDecoder(&ctr, cfg, over, decoders, imports)
 		} else {
 			mtd.ReturnType = scTypeToGo(m.ReturnType)
 			switch m.ReturnType {
@@ -241,10 +762,263 @@ func templateFromManifest(cfg Config) (contractTmpl, error) {
 		ctr.Imports = append(ctr.Imports, imp)
 	}
 	sort.Strings(ctr.Imports)
+	for i, imp := range ctr.Imports {
+		ctr.Imports[i] = strconv.Quote(imp)
+		if alias, ok := aliases[imp]; ok {
+			ctr.Imports[i] = alias + " " + ctr.Imports[i]
+		}
+	}
 
 	return ctr, nil
 }
 
+// standardImports contains packages imported by generated on-chain wrappers
+// irrespective of overrides, they're always imported under their own names.
+var standardImports = map[string]string{
+	"contract":      "github.com/nspcc-dev/neo-go/pkg/interop/contract",
+	"interop":       "github.com/nspcc-dev/neo-go/pkg/interop",
+	"neogointernal": "github.com/nspcc-dev/neo-go/pkg/interop/neogointernal",
+}
+
+// importAliases returns aliases for override packages having the same name as
+// some other imported package. Conflicting packages are named by appending
+// a number to the package name, like `types1` and `types2`.
+func importAliases(cfg Config) map[string]string {
+	if cfg.RPCBindings {
+		return nil
+	}
+	var (
+		pkgs   = make(map[string][]string)
+		seen   = make(map[string]bool)
+		addPkg = func(p string) {
+			if p == "" || seen[p] || standardImports[path.Base(p)] == p {
+				return
+			}
+			seen[p] = true
+			pkgs[path.Base(p)] = append(pkgs[path.Base(p)], p)
+		}
+	)
+	for _, over := range cfg.Overrides {
+		addPkg(over.Package)
+	}
+	for _, fields := range cfg.Structs {
+		for _, f := range fields {
+			addPkg(f.Type.Package)
+		}
+	}
+
+	var (
+		names = make([]string, 0, len(pkgs))
+		taken = make(map[string]bool)
+	)
+	for name := range standardImports {
+		taken[name] = true
+	}
+	for name := range pkgs {
+		names = append(names, name)
+		taken[name] = true
+	}
+	sort.Strings(names)
+	aliases := make(map[string]string)
+	for _, name := range names {
+		list := pkgs[name]
+		if _, ok := standardImports[name]; !ok && len(list) == 1 {
+			continue
+		}
+		sort.Strings(list)
+		n := 1
+		for _, p := range list {
+			alias := name + strconv.Itoa(n)
+			for taken[alias] {
+				n++
+				alias = name + strconv.Itoa(n)
+			}
+			taken[alias] = true
+			aliases[p] = alias
+		}
+	}
+	return aliases
+}
+
+// applyAliases returns a copy of cfg with package names in overrides replaced
+// by the given aliases.
+func applyAliases(cfg Config, aliases map[string]string) Config {
+	rename := func(over Override) Override {
+		alias, ok := aliases[over.Package]
+		if !ok {
+			return over
+		}
+		index := strings.LastIndexAny(over.TypeName, "]*") + 1
+		dot := strings.IndexByte(over.TypeName[index:], '.')
+		if dot < 0 {
+			return over
+		}
+		over.TypeName = over.TypeName[:index] + alias + over.TypeName[index+dot:]
+		return over
+	}
+
+	overrides := make(map[string]Override, len(cfg.Overrides))
+	for k, over := range cfg.Overrides {
+		overrides[k] = rename(over)
+	}
+	structs := make(map[string][]StructField, len(cfg.Structs))
+	for k, fields := range cfg.Structs {
+		renamed := make([]StructField, len(fields))
+		for i := range fields {
+			renamed[i] = StructField{Field: fields[i].Field, Type: rename(fields[i].Type)}
+		}
+		structs[k] = renamed
+	}
+	cfg.Overrides = overrides
+	cfg.Structs = structs
+	return cfg
+}
+
+// templateDecoder adds decoder functions for the structure type referenced by
+// over (either directly or via a pointer or a slice) to ctr and returns the
+// name of the function to use. An empty string is returned for types that
+// are not described in cfg.Structs.
+func templateDecoder(ctr *contractTmpl, cfg Config, over Override, decoders map[string]bool, imports map[string]struct{}) string {
+	typ := over.TypeName
+	if strings.HasPrefix(typ, "[]") {
+		elem := templateDecoder(ctr, cfg, Override{Package: over.Package, TypeName: typ[2:]}, decoders, imports)
+		if elem == "" {
+			return ""
+		}
+		name := elem + "Slice"
+		if !decoders[name] {
+			decoders[name] = true
+			ctr.Decoders = append(ctr.Decoders, decoderTmpl{Name: name, Type: typ, Zero: "nil", Elem: elem})
+		}
+		return name
+	}
+
+	base := strings.TrimPrefix(typ, "*")
+	index := strings.IndexByte(base, '.')
+	if over.Package == "" || index == -1 || strings.ContainsAny(base, "[]*") {
+		return ""
+	}
+	fields, ok := cfg.Structs[over.Package+base[index:]]
+	if !ok {
+		return ""
+	}
+	name := "decode" + upperFirst(base[:index]) + upperFirst(base[index+1:])
+	if base != typ {
+		name += "Ptr"
+	}
+	if decoders[name] {
+		return name
+	}
+	decoders[name] = true
+
+	// Reserve the place in the list before processing fields, so that
+	// recursive types are handled properly.
+	ctr.Decoders = append(ctr.Decoders, decoderTmpl{})
+	pos := len(ctr.Decoders) - 1
+	dec := decoderTmpl{
+		Name:    name,
+		Type:    typ,
+		Zero:    typ + "{}",
+		Literal: strings.Replace(typ, "*", "&", 1),
+	}
+	if base != typ {
+		dec.Zero = "nil"
+	}
+	var width int
+	for _, f := range fields {
+		if len(f.Field) > width {
+			width = len(f.Field)
+		}
+	}
+	for i, f := range fields {
+		if f.Type.Package != "" {
+			imports[f.Type.Package] = struct{}{}
+		}
+		item := fmt.Sprintf("arr[%d]", i)
+		expr := item + ".(" + f.Type.TypeName + ")"
+		if d := templateDecoder(ctr, cfg, f.Type, decoders, imports); d != "" {
+			expr = d + "(" + item + ")"
+		}
+		dec.Fields = append(dec.Fields, fieldTmpl{
+			Key:  fmt.Sprintf("%-*s", width+1, f.Field+":"),
+			Expr: expr,
+		})
+	}
+	ctr.Decoders[pos] = dec
+	return name
+}
+
+// methodDetails returns additional comment lines describing the given method.
+func methodDetails(m manifest.Method, callFlag string) []string {
+	var res = make([]string, 0, 2+len(m.Parameters))
+	if m.Safe {
+		res = append(res, fmt.Sprintf("It's a safe (read-only) method called with %s call flags.", callFlag))
+	} else {
+		res = append(res, fmt.Sprintf("It's not a safe method, it's called with %s call flags.", callFlag))
+	}
+	if len(m.Parameters) == 0 {
+		return res
+	}
+	res = append(res, "Parameters:")
+	for i := range m.Parameters {
+		name := m.Parameters[i].Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		res = append(res, fmt.Sprintf("  %s %s", name, m.Parameters[i].Type))
+	}
+	return res
+}
+
+// templateRPCMethod fills mtd for RPC bindings and adds it to the list of
+// safe or state-changing methods of ctr. Results of safe methods are converted
+// to structure types if structs is not nil and there is an override for them.
+func templateRPCMethod(ctr *contractTmpl, mtd *methodTmpl, cfg Config, m manifest.Method, safe bool,
+	structs *rpcStructs, imports map[string]struct{}) {
+	for i := range m.Parameters {
+		name := m.Parameters[i].Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+
+		typeStr, _, imp := scTypeToRPCGo(m.Parameters[i].Type, true)
+		if imp != "" {
+			imports[imp] = struct{}{}
+		}
+		expr := name
+		if m.Parameters[i].Type == smartcontract.PublicKeyType {
+			expr = name + ".Bytes()"
+		}
+		mtd.Arguments = append(mtd.Arguments, paramTmpl{
+			Name: name,
+			Type: typeStr,
+			Expr: expr,
+		})
+	}
+	if !safe {
+		ctr.Methods = append(ctr.Methods, *mtd)
+		return
+	}
+
+	imports["github.com/nspcc-dev/neo-go/pkg/rpc/client/unwrap"] = struct{}{}
+	if over, ok := cfg.Overrides[m.Name]; ok && structs != nil {
+		if t, ok := structs.returnType(over); ok {
+			mtd.ReturnType = t.Type
+			mtd.Unwrap = "Item"
+			mtd.Decoder = t.Helper
+			ctr.SafeMethods = append(ctr.SafeMethods, *mtd)
+			return
+		}
+	}
+	typeStr, unwrapName, imp := scTypeToRPCGo(m.ReturnType, false)
+	if imp != "" {
+		imports[imp] = struct{}{}
+	}
+	mtd.ReturnType = typeStr
+	mtd.Unwrap = unwrapName
+	ctr.SafeMethods = append(ctr.SafeMethods, *mtd)
+}
+
 func upperFirst(s string) string {
 	return strings.ToUpper(s[0:1]) + s[1:]
 }
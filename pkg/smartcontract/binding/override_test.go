@@ -30,3 +30,13 @@ func TestNewOverrideFromString(t *testing.T) {
 		require.Equal(t, tc.value, s)
 	}
 }
+
+func TestApplyAliases(t *testing.T) {
+	cfg := Config{Overrides: map[string]Override{
+		"a": {Package: "import.com/pkg", TypeName: "map[int]*pkg.Type"},
+		"b": {Package: "import.com/pkg", TypeName: "Type"},
+	}}
+	cfg = applyAliases(cfg, map[string]string{"import.com/pkg": "pkg1"})
+	require.Equal(t, "map[int]*pkg1.Type", cfg.Overrides["a"].TypeName)
+	require.Equal(t, "Type", cfg.Overrides["b"].TypeName)
+}
@@ -0,0 +1,307 @@
+package binding
+
+import (
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+const interopPackage = "github.com/nspcc-dev/neo-go/pkg/interop"
+
+type (
+	// rpcType describes conversion of values of some type used by RPC
+	// bindings to and from stack items.
+	rpcType struct {
+		// Name is used in helper function names.
+		Name string
+		// Type is Go type used in bindings.
+		Type string
+		// Helper is the name of a function converting stack item (along
+		// with an error) into Type, if any.
+		Helper string
+		// From is an expression converting %s stack item into Type and an error.
+		From string
+		// To is an expression converting %s value into stack item.
+		To string
+		// ToErr is true if To expression also returns an error.
+		ToErr bool
+	}
+
+	structTmpl struct {
+		Name     string
+		Origin   string
+		Skeleton bool
+		ToErr    bool
+		Fields   []structFieldTmpl
+	}
+
+	structFieldTmpl struct {
+		Name  string
+		Key   string
+		Type  string
+		From  string
+		To    string
+		ToErr bool
+	}
+
+	sliceTmpl struct {
+		Name  string
+		Type  string
+		Elem  string
+		From  string
+		To    string
+		ToErr bool
+	}
+
+	// rpcStructs generates types and helpers for structures used by RPC
+	// bindings.
+	rpcStructs struct {
+		cfg     Config
+		ctr     *contractTmpl
+		imports map[string]struct{}
+		types   map[string]rpcType
+		names   map[string]string
+	}
+)
+
+// rpcReservedNames contains identifiers declared by RPC bindings.
+var rpcReservedNames = []string{"Hash", "Invoker", "Actor", "ContractReader", "Contract", "NewReader", "New", "Deploy"}
+
+var basicRPCTypes = map[string]rpcType{
+	"BigInt": {Name: "BigInt", Type: "*big.Int", From: "%s.TryInteger()", To: "stackitem.NewBigInteger(%s)"},
+	"Bool":   {Name: "Bool", Type: "bool", From: "%s.TryBool()", To: "stackitem.NewBool(%s)"},
+	"Bytes":  {Name: "Bytes", Type: "[]byte", From: "%s.TryBytes()", To: "stackitem.NewByteArray(%s)"},
+	"Item":   {Name: "Item", Type: "stackitem.Item", From: "%s, nil", To: "%s"},
+	"String": {Name: "String", Type: "string", Helper: "itemToString", From: "itemToString(%s, nil)",
+		To: "stackitem.NewByteArray([]byte(%s))"},
+	"Uint160": {Name: "Uint160", Type: "util.Uint160", Helper: "itemToUint160", From: "itemToUint160(%s, nil)",
+		To: "stackitem.NewByteArray(%s.BytesBE())"},
+	"Uint256": {Name: "Uint256", Type: "util.Uint256", Helper: "itemToUint256", From: "itemToUint256(%s, nil)",
+		To: "stackitem.NewByteArray(%s.BytesBE())"},
+	"PublicKey": {Name: "PublicKey", Type: "*keys.PublicKey", Helper: "itemToPublicKey", From: "itemToPublicKey(%s, nil)",
+		To: "stackitem.NewByteArray(%s.Bytes())"},
+}
+
+// structKey returns the key of the structure referenced by the given override
+// in Config.Structs (even if it's wrapped into slices or pointers) and true
+// if it's a structure from some non-interop package.
+func structKey(over Override) (string, bool) {
+	base := strings.TrimLeft(over.TypeName, "[]*")
+	index := strings.IndexByte(base, '.')
+	if over.Package == "" || over.Package == interopPackage || strings.HasPrefix(over.Package, interopPackage+"/") ||
+		index == -1 || strings.ContainsAny(base, "[]*") {
+		return "", false
+	}
+	return over.Package + base[index:], true
+}
+
+// validateStructs checks that structure descriptions are well-formed and
+// each of them is used in overrides directly or via other structures.
+func validateStructs(cfg Config) error {
+	for key, fields := range cfg.Structs {
+		if len(fields) == 0 {
+			return fmt.Errorf("structure %s has no fields", key)
+		}
+		seen := make(map[string]bool, len(fields))
+		for i, f := range fields {
+			if !token.IsIdentifier(f.Field) || !token.IsExported(f.Field) {
+				return fmt.Errorf("structure %s: field %d has invalid name %q", key, i, f.Field)
+			}
+			if seen[f.Field] {
+				return fmt.Errorf("structure %s: duplicate field %s", key, f.Field)
+			}
+			seen[f.Field] = true
+			if f.Type.TypeName == "" {
+				return fmt.Errorf("structure %s: field %s has no type", key, f.Field)
+			}
+		}
+	}
+
+	used := make(map[string]bool)
+	var markUsed func(over Override)
+	markUsed = func(over Override) {
+		key, ok := structKey(over)
+		if !ok || used[key] {
+			return
+		}
+		used[key] = true
+		for _, f := range cfg.Structs[key] {
+			markUsed(f.Type)
+		}
+	}
+	for _, over := range cfg.Overrides {
+		markUsed(over)
+	}
+	for key := range cfg.Structs {
+		if !used[key] {
+			return fmt.Errorf("structure %s is declared, but not used in overrides", key)
+		}
+	}
+	return nil
+}
+
+func newRPCStructs(cfg Config, ctr *contractTmpl, imports map[string]struct{}) *rpcStructs {
+	g := &rpcStructs{
+		cfg:     cfg,
+		ctr:     ctr,
+		imports: imports,
+		types:   make(map[string]rpcType),
+		names:   make(map[string]string),
+	}
+	for _, name := range rpcReservedNames {
+		g.names[name] = ""
+	}
+	return g
+}
+
+// returnType returns conversion info for the method result with the given
+// override and true if it references some structure.
+func (g *rpcStructs) returnType(over Override) (rpcType, bool) {
+	if _, ok := structKey(over); !ok {
+		return rpcType{}, false
+	}
+	g.imports["github.com/nspcc-dev/neo-go/pkg/vm/stackitem"] = struct{}{}
+	return g.typeOf(over), true
+}
+
+// typeOf returns conversion info for the given type from the contract side
+// (as used in overrides and structure descriptions).
+func (g *rpcStructs) typeOf(over Override) rpcType {
+	typ := over.TypeName
+	if typ == "[]byte" {
+		return g.basic("Bytes")
+	}
+	if strings.HasPrefix(typ, "[]") {
+		return g.slice(g.typeOf(Override{Package: over.Package, TypeName: typ[2:]}))
+	}
+	if _, ok := structKey(over); ok {
+		return g.structure(over)
+	}
+	switch typ {
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return g.basic("BigInt")
+	case "bool":
+		return g.basic("Bool")
+	case "string":
+		return g.basic("String")
+	}
+	if over.Package == interopPackage {
+		switch typ[strings.IndexByte(typ, '.')+1:] {
+		case "Hash160":
+			return g.basic("Uint160")
+		case "Hash256":
+			return g.basic("Uint256")
+		case "PublicKey":
+			return g.basic("PublicKey")
+		case "Signature":
+			return g.basic("Bytes")
+		}
+	}
+	return g.basic("Item")
+}
+
+func (g *rpcStructs) basic(name string) rpcType {
+	t := basicRPCTypes[name]
+	switch name {
+	case "BigInt":
+		g.imports["math/big"] = struct{}{}
+	case "PublicKey":
+		g.imports["crypto/elliptic"] = struct{}{}
+		g.imports["github.com/nspcc-dev/neo-go/pkg/crypto/keys"] = struct{}{}
+	}
+	if t.Helper != "" {
+		if g.ctr.Helpers == nil {
+			g.ctr.Helpers = make(map[string]bool)
+		}
+		g.ctr.Helpers[name] = true
+	}
+	return t
+}
+
+func (g *rpcStructs) slice(elem rpcType) rpcType {
+	name := elem.Name + "Slice"
+	if t, ok := g.types[name]; ok {
+		return t
+	}
+	t := rpcType{
+		Name:   name,
+		Type:   "[]" + elem.Type,
+		Helper: "itemTo" + name,
+		From:   "itemTo" + name + "(%s, nil)",
+		To:     lowerFirst(name) + "ToItem(%s)",
+		ToErr:  true,
+	}
+	g.types[name] = t
+	g.imports["errors"] = struct{}{}
+	g.imports["fmt"] = struct{}{}
+	g.ctr.Slices = append(g.ctr.Slices, sliceTmpl{
+		Name:  name,
+		Type:  t.Type,
+		Elem:  elem.Type,
+		From:  fmt.Sprintf(elem.From, "arr[i]"),
+		To:    fmt.Sprintf(elem.To, "s[i]"),
+		ToErr: elem.ToErr,
+	})
+	return t
+}
+
+func (g *rpcStructs) structure(over Override) rpcType {
+	key, _ := structKey(over)
+	if t, ok := g.types[key]; ok {
+		return t
+	}
+
+	base := strings.TrimLeft(over.TypeName, "[]*")
+	index := strings.IndexByte(base, '.')
+	name := base[index+1:]
+	if k, ok := g.names[name]; ok && k != key {
+		name = upperFirst(base[:index]) + name
+	}
+	g.names[name] = key
+
+	t := rpcType{
+		Name:   name,
+		Type:   "*" + name,
+		Helper: "itemTo" + name,
+		From:   "itemTo" + name + "(%s, nil)",
+		To:     "%s.ToStackItem()",
+		ToErr:  true,
+	}
+	// Register the type before processing fields, so that recursive
+	// structures are handled properly.
+	g.types[key] = t
+
+	st := structTmpl{
+		Name:   name,
+		Origin: base,
+	}
+	fields, ok := g.cfg.Structs[key]
+	if !ok {
+		st.Skeleton = true
+	} else {
+		g.imports["errors"] = struct{}{}
+		g.imports["fmt"] = struct{}{}
+	}
+	var width int
+	for _, f := range fields {
+		if len(f.Field) > width {
+			width = len(f.Field)
+		}
+	}
+	g.ctr.Structs = append(g.ctr.Structs, structTmpl{})
+	pos := len(g.ctr.Structs) - 1
+	for _, f := range fields {
+		ft := g.typeOf(f.Type)
+		st.ToErr = st.ToErr || ft.ToErr
+		st.Fields = append(st.Fields, structFieldTmpl{
+			Name:  f.Field,
+			Key:   fmt.Sprintf("%-*s", width, f.Field),
+			Type:  ft.Type,
+			From:  fmt.Sprintf(ft.From, fmt.Sprintf("arr[%d]", len(st.Fields))),
+			To:    fmt.Sprintf(ft.To, "res."+f.Field),
+			ToErr: ft.ToErr,
+		})
+	}
+	g.ctr.Structs[pos] = st
+	return t
+}
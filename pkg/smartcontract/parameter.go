@@ -16,7 +16,10 @@ import (
 
 	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 )
 
@@ -399,3 +402,102 @@ func ExpandParameterToEmitable(param Parameter) (interface{}, error) {
 		return param.Value, nil
 	}
 }
+
+// EmitParameter emits the given parameter to the given buffer using an
+// appropriate emit primitive for its type. Array and Map parameters are emitted
+// recursively, parameters with nil Value are emitted as PUSHNULL. It can't be
+// a part of the emit package, because smartcontract depends on it.
+func EmitParameter(w *io.BinWriter, p Parameter) error {
+	if p.Value == nil {
+		switch p.Type {
+		case InteropInterfaceType, UnknownType, VoidType:
+		default:
+			emit.Opcodes(w, opcode.PUSHNULL)
+			return w.Err
+		}
+	}
+	var ok bool
+	switch p.Type {
+	case IntegerType:
+		var v *big.Int
+		if v, ok = p.Value.(*big.Int); ok {
+			emit.BigInt(w, v)
+		}
+	case ByteArrayType, SignatureType:
+		var v []byte
+		if v, ok = p.Value.([]byte); ok {
+			emit.Bytes(w, v)
+		}
+	case StringType:
+		var v string
+		if v, ok = p.Value.(string); ok {
+			emit.String(w, v)
+		}
+	case Hash160Type:
+		var v util.Uint160
+		if v, ok = p.Value.(util.Uint160); ok {
+			emit.Bytes(w, v.BytesBE())
+		}
+	case Hash256Type:
+		var v util.Uint256
+		if v, ok = p.Value.(util.Uint256); ok {
+			emit.Bytes(w, v.BytesBE())
+		}
+	case PublicKeyType:
+		switch v := p.Value.(type) {
+		case *keys.PublicKey:
+			emit.Bytes(w, v.Bytes())
+			ok = true
+		case []byte:
+			emit.Bytes(w, v)
+			ok = true
+		}
+	case BoolType:
+		var v bool
+		if v, ok = p.Value.(bool); ok {
+			emit.Bool(w, v)
+		}
+	case ArrayType:
+		var arr []Parameter
+		if arr, ok = p.Value.([]Parameter); ok {
+			if len(arr) == 0 {
+				emit.Opcodes(w, opcode.NEWARRAY0)
+				break
+			}
+			for i := len(arr) - 1; i >= 0; i-- {
+				if err := EmitParameter(w, arr[i]); err != nil {
+					return err
+				}
+			}
+			emit.Int(w, int64(len(arr)))
+			emit.Opcodes(w, opcode.PACK)
+		}
+	case MapType:
+		var pairs []ParameterPair
+		if pairs, ok = p.Value.([]ParameterPair); ok {
+			if len(pairs) == 0 {
+				emit.Opcodes(w, opcode.NEWMAP)
+				break
+			}
+			// PACKMAP takes the key first and then the value.
+			for i := len(pairs) - 1; i >= 0; i-- {
+				if err := EmitParameter(w, pairs[i].Value); err != nil {
+					return err
+				}
+				if err := EmitParameter(w, pairs[i].Key); err != nil {
+					return err
+				}
+			}
+			emit.Int(w, int64(len(pairs)))
+			emit.Opcodes(w, opcode.PACKMAP)
+		}
+	case AnyType:
+		// Only null values are supported and they're handled above.
+	default:
+		return fmt.Errorf("unsupported parameter type: %s", p.Type)
+	}
+	if !ok {
+		return fmt.Errorf("invalid %s parameter value of type %T", p.Type, p.Value)
+	}
+	return w.Err
+}
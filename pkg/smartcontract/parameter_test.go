@@ -14,6 +14,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -611,3 +612,104 @@ func TestExpandParameterToEmitable(t *testing.T) {
 		require.Error(t, err)
 	}
 }
+
+func TestEmitParameter(t *testing.T) {
+	pk, _ := keys.NewPrivateKey()
+	testCases := []struct {
+		In       Parameter
+		Expected func(w *io.BinWriter)
+	}{
+		{
+			In:       Parameter{Type: IntegerType, Value: big.NewInt(123)},
+			Expected: func(w *io.BinWriter) { emit.Int(w, 123) },
+		},
+		{
+			In:       Parameter{Type: ByteArrayType, Value: []byte{1, 2, 3}},
+			Expected: func(w *io.BinWriter) { emit.Bytes(w, []byte{1, 2, 3}) },
+		},
+		{
+			In:       Parameter{Type: StringType, Value: "str"},
+			Expected: func(w *io.BinWriter) { emit.String(w, "str") },
+		},
+		{
+			In:       Parameter{Type: Hash160Type, Value: util.Uint160{1, 2, 3}},
+			Expected: func(w *io.BinWriter) { emit.Bytes(w, util.Uint160{1, 2, 3}.BytesBE()) },
+		},
+		{
+			In:       Parameter{Type: Hash256Type, Value: util.Uint256{1, 2, 3}},
+			Expected: func(w *io.BinWriter) { emit.Bytes(w, util.Uint256{1, 2, 3}.BytesBE()) },
+		},
+		{
+			In:       Parameter{Type: PublicKeyType, Value: pk.PublicKey()},
+			Expected: func(w *io.BinWriter) { emit.Bytes(w, pk.PublicKey().Bytes()) },
+		},
+		{
+			In:       Parameter{Type: PublicKeyType, Value: pk.PublicKey().Bytes()},
+			Expected: func(w *io.BinWriter) { emit.Bytes(w, pk.PublicKey().Bytes()) },
+		},
+		{
+			In:       Parameter{Type: SignatureType, Value: []byte{4, 5, 6}},
+			Expected: func(w *io.BinWriter) { emit.Bytes(w, []byte{4, 5, 6}) },
+		},
+		{
+			In:       Parameter{Type: BoolType, Value: true},
+			Expected: func(w *io.BinWriter) { emit.Bool(w, true) },
+		},
+		{
+			In: Parameter{Type: ArrayType, Value: []Parameter{
+				{Type: IntegerType, Value: big.NewInt(1)},
+				{Type: ArrayType, Value: []Parameter{{Type: BoolType, Value: false}}},
+			}},
+			Expected: func(w *io.BinWriter) { emit.Array(w, big.NewInt(1), []interface{}{false}) },
+		},
+		{
+			In:       Parameter{Type: ArrayType, Value: []Parameter{}},
+			Expected: func(w *io.BinWriter) { emit.Array(w) },
+		},
+		{
+			In: Parameter{Type: MapType, Value: []ParameterPair{
+				{Key: Parameter{Type: StringType, Value: "a"}, Value: Parameter{Type: IntegerType, Value: big.NewInt(1)}},
+				{Key: Parameter{Type: StringType, Value: "b"}, Value: Parameter{Type: BoolType, Value: true}},
+			}},
+			Expected: func(w *io.BinWriter) { emit.Map(w, "a", big.NewInt(1), "b", true) },
+		},
+		{
+			In:       Parameter{Type: MapType, Value: []ParameterPair{}},
+			Expected: func(w *io.BinWriter) { emit.Map(w) },
+		},
+		{
+			In:       Parameter{Type: AnyType},
+			Expected: func(w *io.BinWriter) { emit.Opcodes(w, opcode.PUSHNULL) },
+		},
+		{
+			In:       Parameter{Type: IntegerType},
+			Expected: func(w *io.BinWriter) { emit.Opcodes(w, opcode.PUSHNULL) },
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.In.Type.String(), func(t *testing.T) {
+			actual := io.NewBufBinWriter()
+			require.NoError(t, EmitParameter(actual.BinWriter, tc.In))
+
+			expected := io.NewBufBinWriter()
+			tc.Expected(expected.BinWriter)
+			require.NoError(t, expected.Err)
+			require.Equal(t, expected.Bytes(), actual.Bytes())
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		errCases := []Parameter{
+			{Type: InteropInterfaceType},
+			{Type: VoidType},
+			{Type: AnyType, Value: big.NewInt(1)},
+			{Type: IntegerType, Value: "1"},
+			{Type: ArrayType, Value: []Parameter{{Type: VoidType}}},
+			{Type: MapType, Value: []ParameterPair{{Key: Parameter{Type: StringType, Value: 1}}}},
+		}
+		for _, p := range errCases {
+			w := io.NewBufBinWriter()
+			require.Error(t, EmitParameter(w.BinWriter, p), p.Type.String())
+		}
+	})
+}
@@ -0,0 +1,80 @@
+/*
+Package clock provides time source abstraction allowing to replace the system
+clock with a manually controlled one in tests.
+*/
+package clock
+
+import (
+	"time"
+)
+
+// Clock is a source of time and timers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// NewTimer creates a new Timer that sends the current time on its channel
+	// after at least duration d.
+	NewTimer(d time.Duration) Timer
+	// NewTicker returns a new Ticker that sends the current time on its
+	// channel with a period specified by d which must be greater than zero.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is an abstract timer similar to time.Timer.
+type Timer interface {
+	// C returns the channel the time is delivered to.
+	C() <-chan time.Time
+	// Stop prevents the Timer from firing, it returns false if the timer has
+	// already expired or been stopped.
+	Stop() bool
+	// Reset changes the timer to expire after duration d, it returns true if
+	// the timer had been active.
+	Reset(d time.Duration) bool
+}
+
+// Ticker is an abstract ticker similar to time.Ticker.
+type Ticker interface {
+	// C returns the channel the ticks are delivered to.
+	C() <-chan time.Time
+	// Stop turns off the ticker.
+	Stop()
+	// Reset stops the ticker and resets its period to d.
+	Reset(d time.Duration)
+}
+
+// Real is the Clock implementation based on the system clock and standard
+// timers.
+type Real struct{}
+
+type realTimer struct {
+	*time.Timer
+}
+
+type realTicker struct {
+	*time.Ticker
+}
+
+// Now implements Clock interface.
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// NewTimer implements Clock interface.
+func (Real) NewTimer(d time.Duration) Timer {
+	return realTimer{time.NewTimer(d)}
+}
+
+// NewTicker implements Clock interface.
+func (Real) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+// C implements Timer interface.
+func (t realTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+// C implements Ticker interface.
+func (t realTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
@@ -0,0 +1,110 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func requireFired(t *testing.T, c <-chan time.Time, expected time.Time) {
+	select {
+	case tm := <-c:
+		require.Equal(t, expected, tm)
+	default:
+		t.Fatal("not fired")
+	}
+}
+
+func requireNotFired(t *testing.T, c <-chan time.Time) {
+	select {
+	case <-c:
+		t.Fatal("unexpectedly fired")
+	default:
+	}
+}
+
+func TestReal(t *testing.T) {
+	var c Clock = Real{}
+
+	start := c.Now()
+	tm := c.NewTimer(time.Millisecond)
+	<-tm.C()
+	require.False(t, tm.Stop())
+	require.False(t, tm.Reset(time.Millisecond))
+	<-tm.C()
+	require.True(t, c.Now().After(start))
+
+	tk := c.NewTicker(time.Millisecond)
+	<-tk.C()
+	<-tk.C()
+	tk.Stop()
+}
+
+func TestManual(t *testing.T) {
+	start := time.Unix(1000, 0)
+	m := NewManual(start)
+	require.Equal(t, start, m.Now())
+
+	t.Run("timer", func(t *testing.T) {
+		tm := m.NewTimer(time.Second)
+		m.Advance(time.Second - 1)
+		requireNotFired(t, tm.C())
+		m.Advance(2)
+		requireFired(t, tm.C(), start.Add(time.Second))
+		require.Equal(t, start.Add(time.Second+1), m.Now())
+		require.False(t, tm.Stop())
+
+		require.False(t, tm.Reset(time.Second))
+		require.True(t, tm.Stop())
+		m.Advance(time.Hour)
+		requireNotFired(t, tm.C())
+
+		require.False(t, tm.Reset(0))
+		requireFired(t, tm.C(), m.Now())
+	})
+	t.Run("ticker", func(t *testing.T) {
+		now := m.Now()
+		tk := m.NewTicker(time.Second)
+		m.Advance(time.Second)
+		requireFired(t, tk.C(), now.Add(time.Second))
+		m.Advance(3 * time.Second) // Ticks are dropped.
+		requireFired(t, tk.C(), now.Add(2*time.Second))
+		requireNotFired(t, tk.C())
+
+		tk.Reset(time.Minute)
+		m.Advance(time.Second)
+		requireNotFired(t, tk.C())
+		m.Set(m.Now().Add(time.Minute))
+		requireFired(t, tk.C(), now.Add(4*time.Second+time.Minute))
+
+		tk.Stop()
+		m.Advance(time.Hour)
+		requireNotFired(t, tk.C())
+		require.Panics(t, func() { tk.Reset(0) })
+		require.Panics(t, func() { m.NewTicker(0) })
+	})
+	t.Run("order", func(t *testing.T) {
+		now := m.Now()
+		t1 := m.NewTimer(2 * time.Second)
+		t2 := m.NewTimer(time.Second)
+		m.Set(now.Add(-time.Second))
+		require.Equal(t, now, m.Now())
+		m.Advance(time.Minute)
+		requireFired(t, t1.C(), now.Add(2*time.Second))
+		requireFired(t, t2.C(), now.Add(time.Second))
+	})
+	t.Run("BlockUntil", func(t *testing.T) {
+		m.BlockUntil(0)
+		done := make(chan struct{})
+		go func() {
+			m.BlockUntil(2)
+			close(done)
+		}()
+		tm := m.NewTimer(time.Second)
+		tk := m.NewTicker(time.Second)
+		<-done
+		tm.Stop()
+		tk.Stop()
+	})
+}
@@ -0,0 +1,202 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Manual is the Clock implementation which time only changes when it's
+// explicitly advanced. Timers and tickers fire synchronously during Advance
+// and Set calls, like the standard ones they have channels with buffer of one
+// element and drop the ticks a receiver doesn't keep up with. It's safe for
+// concurrent use.
+type Manual struct {
+	lock    sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters map[*manualWaiter]struct{}
+}
+
+// manualWaiter is a timer or a ticker of the Manual clock, tickers have
+// non-zero period.
+type manualWaiter struct {
+	clk    *Manual
+	c      chan time.Time
+	when   time.Time
+	period time.Duration
+}
+
+type manualTimer struct {
+	*manualWaiter
+}
+
+type manualTicker struct {
+	*manualWaiter
+}
+
+// NewManual returns a Manual clock set to the given time.
+func NewManual(start time.Time) *Manual {
+	m := &Manual{
+		now:     start,
+		waiters: make(map[*manualWaiter]struct{}),
+	}
+	m.cond = sync.NewCond(&m.lock)
+	return m
+}
+
+// Now implements Clock interface.
+func (m *Manual) Now() time.Time {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.now
+}
+
+// NewTimer implements Clock interface.
+func (m *Manual) NewTimer(d time.Duration) Timer {
+	return manualTimer{m.newWaiter(d, 0)}
+}
+
+// NewTicker implements Clock interface.
+func (m *Manual) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("non-positive interval for NewTicker")
+	}
+	return manualTicker{m.newWaiter(d, d)}
+}
+
+func (m *Manual) newWaiter(d time.Duration, period time.Duration) *manualWaiter {
+	w := &manualWaiter{
+		clk:    m,
+		c:      make(chan time.Time, 1),
+		period: period,
+	}
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.schedule(w, d)
+	return w
+}
+
+// Advance moves the clock forward by d firing all timers and tickers expiring
+// in this period in order.
+func (m *Manual) Advance(d time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.advanceTo(m.now.Add(d))
+}
+
+// Set moves the clock forward to t firing all timers and tickers expiring
+// before it. It does nothing if t is before the current clock time.
+func (m *Manual) Set(t time.Time) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.advanceTo(t)
+}
+
+// BlockUntil blocks until there are at least n active (not yet fired and not
+// stopped) timers and tickers. It allows to wait for the code under test to
+// (re)start its timers after the previous ones fired.
+func (m *Manual) BlockUntil(n int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for len(m.waiters) < n {
+		m.cond.Wait()
+	}
+}
+
+// advanceTo fires expiring waiters until the clock reaches t, it must be
+// called with the lock held.
+func (m *Manual) advanceTo(t time.Time) {
+	for {
+		var next *manualWaiter
+		for w := range m.waiters {
+			if !w.when.After(t) && (next == nil || w.when.Before(next.when)) {
+				next = w
+			}
+		}
+		if next == nil {
+			break
+		}
+		if next.when.After(m.now) {
+			m.now = next.when
+		}
+		m.fire(next)
+	}
+	if t.After(m.now) {
+		m.now = t
+	}
+}
+
+// schedule makes w expire after d, it must be called with the lock held.
+func (m *Manual) schedule(w *manualWaiter, d time.Duration) {
+	w.when = m.now.Add(d)
+	m.waiters[w] = struct{}{}
+	if d <= 0 {
+		m.fire(w)
+	}
+	m.cond.Broadcast()
+}
+
+// fire sends the current time to w and reschedules tickers, it must be
+// called with the lock held.
+func (m *Manual) fire(w *manualWaiter) {
+	select {
+	case w.c <- m.now:
+	default:
+	}
+	if w.period != 0 {
+		w.when = w.when.Add(w.period)
+	} else {
+		delete(m.waiters, w)
+	}
+}
+
+// C implements Timer and Ticker interfaces.
+func (w *manualWaiter) C() <-chan time.Time {
+	return w.c
+}
+
+// stop removes w from the set of active waiters and returns true if it was
+// active.
+func (w *manualWaiter) stop() bool {
+	w.clk.lock.Lock()
+	defer w.clk.lock.Unlock()
+	_, active := w.clk.waiters[w]
+	delete(w.clk.waiters, w)
+	w.clk.cond.Broadcast()
+	return active
+}
+
+// reset reschedules w to expire after d and returns true if it was active.
+func (w *manualWaiter) reset(d time.Duration) bool {
+	w.clk.lock.Lock()
+	defer w.clk.lock.Unlock()
+	_, active := w.clk.waiters[w]
+	if w.period != 0 {
+		w.period = d
+	}
+	w.clk.schedule(w, d)
+	return active
+}
+
+// Stop implements Timer interface.
+func (t manualTimer) Stop() bool {
+	return t.stop()
+}
+
+// Reset implements Timer interface.
+func (t manualTimer) Reset(d time.Duration) bool {
+	return t.reset(d)
+}
+
+// Stop implements Ticker interface.
+func (t manualTicker) Stop() {
+	t.stop()
+}
+
+// Reset implements Ticker interface.
+func (t manualTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("non-positive interval for Ticker.Reset")
+	}
+	t.reset(d)
+}
@@ -54,6 +54,9 @@ type Context struct {
 	NEF *nef.File
 	// invTree is an invocation tree (or branch of it) for this context.
 	invTree *InvocationTree
+	// depth is the number of scripts loaded in the invocation chain up to
+	// (and including) this one, internal CALLs don't change it.
+	depth int
 }
 
 var errNoInstParam = errors.New("failed to read instruction parameter")
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math/big"
 	"math/bits"
+	"sort"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
@@ -39,6 +40,23 @@ func Bool(w *io.BinWriter, ok bool) {
 	Instruction(w, opcode.CONVERT, []byte{byte(stackitem.BooleanT)})
 }
 
+// BoolAnd emits BOOLAND instruction replacing two top stack items with their
+// logical AND.
+func BoolAnd(w *io.BinWriter) {
+	Opcodes(w, opcode.BOOLAND)
+}
+
+// BoolOr emits BOOLOR instruction replacing two top stack items with their
+// logical OR.
+func BoolOr(w *io.BinWriter) {
+	Opcodes(w, opcode.BOOLOR)
+}
+
+// Not emits NOT instruction replacing the top stack item with its negation.
+func Not(w *io.BinWriter) {
+	Opcodes(w, opcode.NOT)
+}
+
 func padRight(s int, buf []byte) []byte {
 	l := len(buf)
 	buf = buf[:s]
@@ -101,52 +119,116 @@ func bigInt(w *io.BinWriter, n *big.Int, trySmall bool) {
 
 // Array emits array of elements to the given buffer.
 func Array(w *io.BinWriter, es ...interface{}) {
+	pack(w, opcode.NEWARRAY0, opcode.PACK, es)
+}
+
+// Struct emits structure with the given fields to the given buffer. Nested
+// slices are emitted as arrays.
+func Struct(w *io.BinWriter, fields ...interface{}) {
+	pack(w, opcode.NEWSTRUCT0, opcode.PACKSTRUCT, fields)
+}
+
+// Map emits a map with the given key-value pairs (keys and values
+// alternating, so the number of arguments must be even) to the given buffer.
+// Pairs are emitted in the order given, nested slices are emitted as arrays
+// and nested map[string]interface{} values are emitted as maps via MapFromGo.
+func Map(w *io.BinWriter, kvs ...interface{}) {
+	if len(kvs)%2 != 0 {
+		w.Err = errors.New("odd number of map elements")
+		return
+	}
+	if len(kvs) == 0 {
+		Opcodes(w, opcode.NEWMAP)
+		return
+	}
+	// PACKMAP takes the key first and then the value.
+	for i := len(kvs) - 2; i >= 0; i -= 2 {
+		item(w, kvs[i+1])
+		item(w, kvs[i])
+		if w.Err != nil {
+			return
+		}
+	}
+	Int(w, int64(len(kvs)/2))
+	Opcodes(w, opcode.PACKMAP)
+}
+
+// MapFromGo emits a map with the contents of the given Go map to the given
+// buffer. Go map iteration order is random, so elements are emitted in the
+// sorted key order to make the resulting script deterministic. Values are
+// handled the same way Map does it.
+func MapFromGo(w *io.BinWriter, m map[string]interface{}) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	kvs := make([]interface{}, 0, 2*len(keys))
+	for _, k := range keys {
+		kvs = append(kvs, k, m[k])
+	}
+	Map(w, kvs...)
+}
+
+// pack emits elements of es followed by packing opcode op or emptyOp if there
+// are no elements.
+func pack(w *io.BinWriter, emptyOp opcode.Opcode, op opcode.Opcode, es []interface{}) {
 	if len(es) == 0 {
-		Opcodes(w, opcode.NEWARRAY0)
+		Opcodes(w, emptyOp)
 		return
 	}
 	for i := len(es) - 1; i >= 0; i-- {
-		switch e := es[i].(type) {
-		case []interface{}:
-			Array(w, e...)
-		case int64:
-			Int(w, e)
-		case int32:
-			Int(w, int64(e))
-		case uint32:
-			Int(w, int64(e))
-		case int16:
-			Int(w, int64(e))
-		case uint16:
-			Int(w, int64(e))
-		case int8:
-			Int(w, int64(e))
-		case uint8:
-			Int(w, int64(e))
-		case int:
-			Int(w, int64(e))
-		case *big.Int:
-			BigInt(w, e)
-		case string:
-			String(w, e)
-		case util.Uint160:
-			Bytes(w, e.BytesBE())
-		case util.Uint256:
-			Bytes(w, e.BytesBE())
-		case []byte:
-			Bytes(w, e)
-		case bool:
-			Bool(w, e)
-		default:
-			if es[i] != nil {
-				w.Err = fmt.Errorf("unsupported type: %T", e)
-				return
-			}
-			Opcodes(w, opcode.PUSHNULL)
+		item(w, es[i])
+		if w.Err != nil {
+			return
 		}
 	}
 	Int(w, int64(len(es)))
-	Opcodes(w, opcode.PACK)
+	Opcodes(w, op)
+}
+
+// item emits a single element of an array, struct or map.
+func item(w *io.BinWriter, e interface{}) {
+	switch e := e.(type) {
+	case []interface{}:
+		Array(w, e...)
+	case map[string]interface{}:
+		MapFromGo(w, e)
+	case int64:
+		Int(w, e)
+	case int32:
+		Int(w, int64(e))
+	case uint32:
+		Int(w, int64(e))
+	case int16:
+		Int(w, int64(e))
+	case uint16:
+		Int(w, int64(e))
+	case int8:
+		Int(w, int64(e))
+	case uint8:
+		Int(w, int64(e))
+	case int:
+		Int(w, int64(e))
+	case *big.Int:
+		BigInt(w, e)
+	case string:
+		String(w, e)
+	case util.Uint160:
+		Bytes(w, e.BytesBE())
+	case util.Uint256:
+		Bytes(w, e.BytesBE())
+	case []byte:
+		Bytes(w, e)
+	case bool:
+		Bool(w, e)
+	default:
+		if e != nil {
+			w.Err = fmt.Errorf("unsupported type: %T", e)
+			return
+		}
+		Opcodes(w, opcode.PUSHNULL)
+	}
 }
 
 // String emits a string to the given buffer.
@@ -154,10 +236,18 @@ func String(w *io.BinWriter, s string) {
 	Bytes(w, []byte(s))
 }
 
-// Bytes emits a byte array to the given buffer.
+// Bytes emits a byte array to the given buffer. It sets w.Err if the array is
+// larger than stackitem.MaxSize, since such a script can't be executed by
+// the VM.
 func Bytes(w *io.BinWriter, b []byte) {
 	var n = len(b)
 
+	if n > stackitem.MaxSize {
+		if w.Err == nil {
+			w.Err = fmt.Errorf("byte array is too large: %d bytes while the limit is %d", n, stackitem.MaxSize)
+		}
+		return
+	}
 	switch {
 	case n < 0x100:
 		Instruction(w, opcode.PUSHDATA1, []byte{byte(n)})
@@ -173,6 +263,24 @@ func Bytes(w *io.BinWriter, b []byte) {
 	w.WriteBytes(b)
 }
 
+// LargeData emits data using appropriate PUSHDATA instruction just like Bytes
+// does, but it also checks data length against maxSize and sets w.Err if data
+// is too large, since such a script can be built, but it will always fail at
+// runtime. maxSize is clamped to stackitem.MaxSize, VM can't push larger items
+// irrespective of the limit requested.
+func LargeData(w *io.BinWriter, data []byte, maxSize int) {
+	if maxSize > stackitem.MaxSize {
+		maxSize = stackitem.MaxSize
+	}
+	if w.Err != nil {
+		return
+	} else if len(data) > maxSize {
+		w.Err = fmt.Errorf("data is too large: %d bytes while the limit is %d", len(data), maxSize)
+		return
+	}
+	Bytes(w, data)
+}
+
 // Syscall emits the syscall API to the given buffer.
 // Syscall API string cannot be 0.
 func Syscall(w *io.BinWriter, api string) {
@@ -219,6 +327,22 @@ func AppCall(w *io.BinWriter, scriptHash util.Uint160, operation string, f callf
 	AppCallNoArgs(w, scriptHash, operation, f)
 }
 
+// AppCallWithOperationAndArgs emits an APPCALL of the given operation with the
+// given arguments and callflag.All flags. It's the same as AppCall with
+// callflag.All.
+func AppCallWithOperationAndArgs(w *io.BinWriter, scriptHash util.Uint160, operation string, args ...interface{}) {
+	Array(w, args...)
+	AppCallNoArgs(w, scriptHash, operation, callflag.All)
+}
+
+// AppCallStruct emits an APPCALL of the given operation passing a structure
+// with the given fields as its only argument.
+func AppCallStruct(w *io.BinWriter, scriptHash util.Uint160, operation string, f callflag.CallFlag, fields ...interface{}) {
+	Struct(w, fields...)
+	Opcodes(w, opcode.PUSH1, opcode.PACK)
+	AppCallNoArgs(w, scriptHash, operation, f)
+}
+
 // CheckSig emits a single-key verification script using given []bytes as a key.
 // It does not check for key correctness, so you can get an invalid script if the
 // data passed is not really a public key.
@@ -227,6 +351,68 @@ func CheckSig(w *io.BinWriter, key []byte) {
 	Syscall(w, interopnames.SystemCryptoCheckSig)
 }
 
+// Try emits TRYL instruction with the given catch and finally block offsets
+// relative to the instruction, zero offset means there is no such block.
+func Try(w *io.BinWriter, catchOffset, finallyOffset int32) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint32(buf, uint32(catchOffset))
+	binary.LittleEndian.PutUint32(buf[4:], uint32(finallyOffset))
+	Instruction(w, opcode.TRYL, buf)
+}
+
+// EndTry emits ENDTRYL instruction with the given offset of the code to
+// continue with after try (or catch) block completion relative to the
+// instruction.
+func EndTry(w *io.BinWriter, offset int32) {
+	buf := make([]byte, 4)
+	binary.LittleEndian.PutUint32(buf, uint32(offset))
+	Instruction(w, opcode.ENDTRYL, buf)
+}
+
+// TryCatch emits try-catch block with the code emitted by tryBody and
+// catchBody. Both bodies must be position-independent (which is true for any
+// code using relative jumps only). Catch body is executed with the exception
+// on top of the stack if try body throws, it either handles the exception or
+// rethrows it. In both cases when the block completes normally the execution
+// continues right after it. Nil body is treated as an empty one.
+func TryCatch(w *io.BinWriter, tryBody, catchBody func(*io.BinWriter)) {
+	if w.Err != nil {
+		return
+	}
+	tryScript, err := emitBody(tryBody)
+	if err != nil {
+		w.Err = fmt.Errorf("try body: %w", err)
+		return
+	}
+	catchScript, err := emitBody(catchBody)
+	if err != nil {
+		w.Err = fmt.Errorf("catch body: %w", err)
+		return
+	}
+	const (
+		tryLen    = 1 + 4 + 4 // TRYL with catch and finally offsets.
+		endTryLen = 1 + 4     // ENDTRYL with offset.
+	)
+	Try(w, int32(tryLen+len(tryScript)+endTryLen), 0)
+	w.WriteBytes(tryScript)
+	EndTry(w, int32(endTryLen+len(catchScript)+endTryLen))
+	w.WriteBytes(catchScript)
+	EndTry(w, endTryLen)
+}
+
+// emitBody returns the code emitted by f.
+func emitBody(f func(*io.BinWriter)) ([]byte, error) {
+	if f == nil {
+		return nil, nil
+	}
+	buf := io.NewBufBinWriter()
+	f(buf.BinWriter)
+	if buf.Err != nil {
+		return nil, buf.Err
+	}
+	return buf.Bytes(), nil
+}
+
 func isInstructionJmp(op opcode.Opcode) bool {
 	return opcode.JMP <= op && op <= opcode.CALLL || op == opcode.ENDTRYL
 }
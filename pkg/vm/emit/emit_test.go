@@ -5,11 +5,14 @@ import (
 	"errors"
 	"math"
 	"math/big"
+	"strconv"
 	"testing"
 
 	"github.com/nspcc-dev/neo-go/pkg/core/interop/interopnames"
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
 	"github.com/stretchr/testify/assert"
@@ -211,6 +214,61 @@ func TestBytes(t *testing.T) {
 		assert.EqualValues(t, size, binary.LittleEndian.Uint32(result[1:5]))
 		assert.Equal(t, getSlice(size), result[5:])
 	})
+
+	t.Run("max size", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		Bytes(buf.BinWriter, make([]byte, stackitem.MaxSize))
+		require.NoError(t, buf.Err)
+		assert.Equal(t, 5+stackitem.MaxSize, buf.Len())
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		Bytes(buf.BinWriter, make([]byte, stackitem.MaxSize+1))
+		require.Error(t, buf.Err)
+		assert.Equal(t, 0, buf.Len())
+	})
+}
+
+func TestLargeData(t *testing.T) {
+	t.Run("fits", func(t *testing.T) {
+		const size = 100000
+
+		buf := io.NewBufBinWriter()
+		LargeData(buf.BinWriter, getSlice(size), stackitem.MaxSize)
+		require.NoError(t, buf.Err)
+
+		result := buf.Bytes()
+		assert.EqualValues(t, opcode.PUSHDATA4, result[0])
+		assert.EqualValues(t, size, binary.LittleEndian.Uint32(result[1:5]))
+		assert.Equal(t, getSlice(size), result[5:])
+	})
+	t.Run("exact limit", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		LargeData(buf.BinWriter, getSlice(200), 200)
+		require.NoError(t, buf.Err)
+		assert.Equal(t, 202, len(buf.Bytes()))
+	})
+	t.Run("too large", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		LargeData(buf.BinWriter, getSlice(201), 200)
+		require.Error(t, buf.Err)
+		assert.Equal(t, 0, buf.Len())
+	})
+	t.Run("limit above MaxSize", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		LargeData(buf.BinWriter, make([]byte, stackitem.MaxSize+1), 2*stackitem.MaxSize)
+		require.Error(t, buf.Err)
+		require.Contains(t, buf.Err.Error(), strconv.Itoa(stackitem.MaxSize))
+		assert.Equal(t, 0, buf.Len())
+	})
+	t.Run("previous error", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		buf.Err = errors.New("oops")
+		LargeData(buf.BinWriter, getSlice(10), 200)
+		assert.Equal(t, "oops", buf.Err.Error())
+		assert.Equal(t, 0, buf.Len())
+	})
 }
 
 func TestEmitArray(t *testing.T) {
@@ -257,6 +315,82 @@ func TestEmitArray(t *testing.T) {
 	})
 }
 
+func TestEmitMap(t *testing.T) {
+	t.Run("good", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		Map(buf.BinWriter, "a", int64(1), int64(2), []interface{}{true})
+		require.NoError(t, buf.Err)
+		// Pairs are emitted backwards, value first.
+		assert.Equal(t, []byte{
+			byte(opcode.PUSHT), byte(opcode.CONVERT), byte(stackitem.BooleanT),
+			byte(opcode.PUSH1), byte(opcode.PACK),
+			byte(opcode.PUSH2),
+			byte(opcode.PUSH1),
+			byte(opcode.PUSHDATA1), 1, 'a',
+			byte(opcode.PUSH2), byte(opcode.PACKMAP),
+		}, buf.Bytes())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		Map(buf.BinWriter)
+		require.NoError(t, buf.Err)
+		assert.EqualValues(t, []byte{byte(opcode.NEWMAP)}, buf.Bytes())
+	})
+
+	t.Run("odd number of elements", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		Map(buf.BinWriter, "a", int64(1), "b")
+		require.Error(t, buf.Err)
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		Map(buf.BinWriter, "a", struct{}{})
+		require.Error(t, buf.Err)
+	})
+}
+
+func TestEmitMapFromGo(t *testing.T) {
+	m := map[string]interface{}{
+		"nested": map[string]interface{}{"y": int64(2), "x": int64(1)},
+		"array":  []interface{}{int64(1), "two"},
+		"null":   nil,
+	}
+	for i := 0; i < 10; i++ {
+		m[string(rune('a'+i))] = int64(i)
+	}
+
+	emitGo := func() []byte {
+		buf := io.NewBufBinWriter()
+		MapFromGo(buf.BinWriter, m)
+		require.NoError(t, buf.Err)
+		return buf.Bytes()
+	}
+	expected := emitGo()
+	for i := 0; i < 20; i++ {
+		require.Equal(t, expected, emitGo())
+	}
+
+	// The same as Map with sorted keys.
+	kvs := []interface{}{"a", int64(0), "array", []interface{}{int64(1), "two"}}
+	for i := 1; i < 10; i++ {
+		kvs = append(kvs, string(rune('a'+i)), int64(i))
+	}
+	kvs = append(kvs, "nested", map[string]interface{}{"x": int64(1), "y": int64(2)}, "null", nil)
+	buf := io.NewBufBinWriter()
+	Map(buf.BinWriter, kvs...)
+	require.NoError(t, buf.Err)
+	require.Equal(t, expected, buf.Bytes())
+
+	t.Run("empty", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		MapFromGo(buf.BinWriter, nil)
+		require.NoError(t, buf.Err)
+		assert.EqualValues(t, []byte{byte(opcode.NEWMAP)}, buf.Bytes())
+	})
+}
+
 func TestEmitBool(t *testing.T) {
 	buf := io.NewBufBinWriter()
 	Bool(buf.BinWriter, true)
@@ -270,6 +404,15 @@ func TestEmitBool(t *testing.T) {
 	assert.EqualValues(t, stackitem.BooleanT, result[5])
 }
 
+func TestEmitBoolOps(t *testing.T) {
+	buf := io.NewBufBinWriter()
+	BoolAnd(buf.BinWriter)
+	BoolOr(buf.BinWriter)
+	Not(buf.BinWriter)
+	require.NoError(t, buf.Err)
+	require.Equal(t, []byte{byte(opcode.BOOLAND), byte(opcode.BOOLOR), byte(opcode.NOT)}, buf.Bytes())
+}
+
 func TestEmitOpcode(t *testing.T) {
 	w := io.NewBufBinWriter()
 	Opcodes(w.BinWriter, opcode.PUSH1, opcode.NEWMAP)
@@ -360,3 +503,97 @@ func TestEmitCall(t *testing.T) {
 	label := binary.LittleEndian.Uint16(result[1:3])
 	assert.Equal(t, label, uint16(100))
 }
+
+func TestEmitStruct(t *testing.T) {
+	t.Run("good", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		Struct(buf.BinWriter, int64(1), []interface{}{"str"})
+		require.NoError(t, buf.Err)
+		require.Equal(t, []byte{
+			byte(opcode.PUSHDATA1), 3, 's', 't', 'r', byte(opcode.PUSH1), byte(opcode.PACK),
+			byte(opcode.PUSH1), byte(opcode.PUSH2), byte(opcode.PACKSTRUCT),
+		}, buf.Bytes())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		Struct(buf.BinWriter)
+		require.NoError(t, buf.Err)
+		assert.EqualValues(t, []byte{byte(opcode.NEWSTRUCT0)}, buf.Bytes())
+	})
+
+	t.Run("invalid type", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		Struct(buf.BinWriter, struct{}{})
+		require.Error(t, buf.Err)
+	})
+}
+
+func TestAppCallStruct(t *testing.T) {
+	h := util.Uint160{1, 2, 3}
+
+	buf := io.NewBufBinWriter()
+	AppCallStruct(buf.BinWriter, h, "method", callflag.ReadOnly, int64(1), true)
+	require.NoError(t, buf.Err)
+
+	expected := io.NewBufBinWriter()
+	Struct(expected.BinWriter, int64(1), true)
+	Opcodes(expected.BinWriter, opcode.PUSH1, opcode.PACK)
+	AppCallNoArgs(expected.BinWriter, h, "method", callflag.ReadOnly)
+	require.NoError(t, expected.Err)
+	require.Equal(t, expected.Bytes(), buf.Bytes())
+}
+
+func TestAppCallWithOperationAndArgs(t *testing.T) {
+	h := util.Uint160{1, 2, 3}
+
+	buf := io.NewBufBinWriter()
+	AppCallWithOperationAndArgs(buf.BinWriter, h, "method", int64(1), "arg")
+	require.NoError(t, buf.Err)
+
+	expected := io.NewBufBinWriter()
+	AppCall(expected.BinWriter, h, "method", callflag.All, int64(1), "arg")
+	require.NoError(t, expected.Err)
+	require.Equal(t, expected.Bytes(), buf.Bytes())
+}
+
+func TestTryCatch(t *testing.T) {
+	t.Run("good", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		TryCatch(buf.BinWriter, func(w *io.BinWriter) {
+			Opcodes(w, opcode.PUSH1, opcode.THROW)
+		}, func(w *io.BinWriter) {
+			Opcodes(w, opcode.DROP)
+		})
+		require.NoError(t, buf.Err)
+		require.Equal(t, []byte{
+			byte(opcode.TRYL), 16, 0, 0, 0, 0, 0, 0, 0,
+			byte(opcode.PUSH1), byte(opcode.THROW),
+			byte(opcode.ENDTRYL), 11, 0, 0, 0,
+			byte(opcode.DROP),
+			byte(opcode.ENDTRYL), 5, 0, 0, 0,
+		}, buf.Bytes())
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		buf := io.NewBufBinWriter()
+		TryCatch(buf.BinWriter, nil, nil)
+		require.NoError(t, buf.Err)
+		require.Equal(t, []byte{
+			byte(opcode.TRYL), 14, 0, 0, 0, 0, 0, 0, 0,
+			byte(opcode.ENDTRYL), 10, 0, 0, 0,
+			byte(opcode.ENDTRYL), 5, 0, 0, 0,
+		}, buf.Bytes())
+	})
+
+	t.Run("bad body", func(t *testing.T) {
+		bad := func(w *io.BinWriter) { Syscall(w, "") }
+		buf := io.NewBufBinWriter()
+		TryCatch(buf.BinWriter, bad, nil)
+		require.Error(t, buf.Err)
+
+		buf = io.NewBufBinWriter()
+		TryCatch(buf.BinWriter, nil, bad)
+		require.Error(t, buf.Err)
+	})
+}
@@ -0,0 +1,137 @@
+package vm
+
+import (
+	"crypto/elliptic"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/crypto/hash"
+	"github.com/nspcc-dev/neo-go/pkg/crypto/keys"
+	"github.com/stretchr/testify/require"
+)
+
+type multisigFunc func(v *VM, curve elliptic.Curve, h []byte, pkeys [][]byte, sigs [][]byte) bool
+
+func initMultisig(t testing.TB, n int) ([]byte, [][]byte, [][]byte) {
+	h := hash.Sha256([]byte("multisig")).BytesBE()
+	pubs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	for i := range pubs {
+		pk, err := keys.NewPrivateKey()
+		require.NoError(t, err)
+		pubs[i] = pk.PublicKey().Bytes()
+		sigs[i] = pk.SignHash(hash.Sha256([]byte("multisig")))
+	}
+	return h, pubs, sigs
+}
+
+// runMultisig returns the result of f along with the panic message if any.
+func runMultisig(f multisigFunc, h []byte, pkeys, sigs [][]byte) (res bool, msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = fmt.Sprint(r)
+		}
+	}()
+	return f(nil, elliptic.P256(), h, pkeys, sigs), ""
+}
+
+func TestCheckMultisigParEquivalence(t *testing.T) {
+	const n = 21
+	h, allPubs, allSigs := initMultisig(t, n)
+	badSig := make([]byte, 64)
+	badKey := []byte{2, 1, 2, 3}
+
+	for i := 0; i < 300; i++ {
+		nKeys := 1 + rand.Intn(n)
+		nSigs := 1 + rand.Intn(nKeys)
+		perm := rand.Perm(n)
+		pubs := make([][]byte, nKeys)
+		for j := range pubs {
+			pubs[j] = allPubs[perm[j]]
+		}
+		signers := rand.Perm(nKeys)[:nSigs]
+		sort.Ints(signers)
+		sigs := make([][]byte, nSigs)
+		for j := range sigs {
+			sigs[j] = allSigs[perm[signers[j]]]
+		}
+
+		var desc string
+		switch rand.Intn(6) {
+		case 0:
+			desc = "valid"
+		case 1:
+			j, k := rand.Intn(nSigs), rand.Intn(nSigs)
+			sigs[j], sigs[k] = sigs[k], sigs[j]
+			desc = fmt.Sprintf("swapped signatures %d and %d", j, k)
+		case 2:
+			j := rand.Intn(nSigs)
+			sigs[j] = badSig
+			desc = fmt.Sprintf("bad signature %d", j)
+		case 3:
+			j := rand.Intn(nSigs)
+			sigs[j] = allSigs[perm[n-1]]
+			desc = fmt.Sprintf("foreign signature %d", j)
+		case 4:
+			j := rand.Intn(nKeys)
+			pubs[j] = badKey
+			desc = fmt.Sprintf("bad key %d", j)
+		case 5:
+			j, k := rand.Intn(nKeys), rand.Intn(nKeys)
+			pubs[j] = pubs[k]
+			desc = fmt.Sprintf("duplicate key %d", k)
+		}
+
+		expected, expectedPanic := runMultisig(CheckMultisig, h, pubs, sigs)
+		actual, actualPanic := runMultisig(CheckMultisigPar, h, pubs, sigs)
+		require.Equal(t, expected, actual, "%d-of-%d, %s", nSigs, nKeys, desc)
+		require.Equal(t, expectedPanic, actualPanic, "%d-of-%d, %s", nSigs, nKeys, desc)
+		if desc == "valid" {
+			require.True(t, actual)
+		}
+	}
+}
+
+func TestCheckMultisigInvalidKey(t *testing.T) {
+	h, pubs, sigs := initMultisig(t, 4)
+	pubs[3] = []byte{2, 1, 2, 3}
+
+	// The last key is not needed to check signatures.
+	for _, f := range []multisigFunc{CheckMultisig, CheckMultisigPar} {
+		res, msg := runMultisig(f, h, pubs, sigs[:2])
+		require.Equal(t, "", msg)
+		require.True(t, res)
+
+		_, msg = runMultisig(f, h, pubs, sigs[2:])
+		require.NotEqual(t, "", msg)
+	}
+}
+
+func benchmarkMultisig(b *testing.B, m, n int) {
+	h, pubs, sigs := initMultisig(b, n)
+	// Every other key signs, so that the check can't be completed with the
+	// first keys only.
+	signers := make([][]byte, 0, m)
+	for i := 0; len(signers) < m; i++ {
+		if i%2 == 0 || n-i <= m-len(signers) {
+			signers = append(signers, sigs[i])
+		}
+	}
+	for name, f := range map[string]multisigFunc{"serial": CheckMultisig, "parallel": CheckMultisigPar} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if !f(nil, elliptic.P256(), h, pubs, signers) {
+					b.FailNow()
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCheckMultisig(b *testing.B) {
+	b.Run("7_11", func(b *testing.B) { benchmarkMultisig(b, 7, 11) })
+	b.Run("15_21", func(b *testing.B) { benchmarkMultisig(b, 15, 21) })
+}
@@ -0,0 +1,586 @@
+package vm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/nef"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/trigger"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+)
+
+// snapshotVersion is the current VM snapshot format version.
+const snapshotVersion = 1
+
+// Special item tags used in snapshots in addition to stackitem.Type values.
+const (
+	// snapshotNilTag is used for empty slot entries and missing exceptions.
+	snapshotNilTag = 0xfe
+	// snapshotRefTag is used for compound items that are already serialized.
+	snapshotRefTag = 0xff
+)
+
+// ErrInteropInSnapshot is returned from Snapshot when VM state contains
+// interop items (iterators, storage contexts, etc.) that can't be serialized.
+var ErrInteropInSnapshot = errors.New("VM state contains interop items")
+
+// snapshotWriter contains serialization context of a VM snapshot.
+type snapshotWriter struct {
+	w       *io.BinWriter
+	scripts map[util.Uint160]int
+	items   map[stackitem.Item]int
+}
+
+// snapshotReader contains deserialization context of a VM snapshot.
+type snapshotReader struct {
+	r       *io.BinReader
+	scripts [][]byte
+	hashes  []util.Uint160
+	items   []stackitem.Item
+}
+
+// snapshotContext is a decoded context waiting to be assembled.
+type snapshotContext struct {
+	ctx       *Context
+	estack    int
+	static    int
+	local     []stackitem.Item
+	arguments []stackitem.Item
+}
+
+// Snapshot serializes the whole VM execution state (invocation stack with
+// instruction pointers, evaluation stacks, slots, consumed GAS and limit,
+// loaded scripts) into a versioned binary blob that can be loaded with
+// LoadSnapshot into another VM to continue execution. It's intended to be
+// used when VM is in BreakState. Interop items can't be serialized, if there
+// are any an error wrapping ErrInteropInSnapshot listing all of them is
+// returned. Invocation tree is not saved.
+func (v *VM) Snapshot() ([]byte, error) {
+	if !v.Ready() {
+		return nil, errors.New("no program loaded")
+	}
+	var (
+		ctxs    = v.istack.elems
+		stacks  = []*Stack{v.estack}
+		statics []*slot
+		nefs    []*nef.File
+		scripts []*Context
+	)
+	sw := &snapshotWriter{
+		scripts: make(map[util.Uint160]int),
+		items:   make(map[stackitem.Item]int),
+	}
+	for _, e := range ctxs {
+		ctx := e.value.(*Context)
+		if _, ok := sw.scripts[ctx.ScriptHash()]; !ok {
+			sw.scripts[ctx.ScriptHash()] = len(scripts)
+			scripts = append(scripts, ctx)
+		}
+		if stackIndex(stacks, ctx.estack) < 0 {
+			stacks = append(stacks, ctx.estack)
+		}
+		if slotIndex(statics, ctx.static) < 0 {
+			statics = append(statics, ctx.static)
+		}
+		if ctx.NEF != nil && nefIndex(nefs, ctx.NEF) < 0 {
+			nefs = append(nefs, ctx.NEF)
+		}
+	}
+	if err := v.checkInterops(stacks, statics); err != nil {
+		return nil, err
+	}
+
+	buf := io.NewBufBinWriter()
+	sw.w = buf.BinWriter
+	sw.w.WriteB(snapshotVersion)
+	sw.w.WriteB(byte(v.trigger))
+	sw.w.WriteB(byte(v.state))
+	sw.w.WriteU64LE(uint64(v.gasConsumed))
+	sw.w.WriteU64LE(uint64(v.GasLimit))
+
+	sw.w.WriteVarUint(uint64(len(scripts)))
+	for _, ctx := range scripts {
+		sw.w.WriteBytes(ctx.scriptHash.BytesBE())
+		sw.w.WriteVarBytes(ctx.prog)
+	}
+	sw.w.WriteVarUint(uint64(len(nefs)))
+	for _, n := range nefs {
+		n.EncodeBinary(sw.w)
+	}
+	sw.w.WriteVarUint(uint64(len(stacks)))
+	for _, s := range stacks {
+		sw.w.WriteVarUint(uint64(len(s.elems)))
+		for _, e := range s.elems {
+			if err := sw.writeItem(e.value); err != nil {
+				return nil, err
+			}
+		}
+	}
+	sw.w.WriteVarUint(uint64(len(statics)))
+	for _, s := range statics {
+		if err := sw.writeSlot(*s); err != nil {
+			return nil, err
+		}
+	}
+
+	sw.w.WriteVarUint(uint64(len(ctxs)))
+	for _, e := range ctxs {
+		ctx := e.value.(*Context)
+		sw.w.WriteVarUint(uint64(sw.scripts[ctx.scriptHash]))
+		sw.w.WriteU32LE(uint32(ctx.ip))
+		sw.w.WriteU32LE(uint32(ctx.nextip))
+		sw.w.WriteVarUint(uint64(len(ctx.breakPoints)))
+		for _, bp := range ctx.breakPoints {
+			sw.w.WriteU32LE(uint32(bp))
+		}
+		sw.w.WriteVarUint(uint64(stackIndex(stacks, ctx.estack)))
+		sw.w.WriteVarUint(uint64(slotIndex(statics, ctx.static)))
+		if err := sw.writeSlot(ctx.local); err != nil {
+			return nil, err
+		}
+		if err := sw.writeSlot(ctx.arguments); err != nil {
+			return nil, err
+		}
+		sw.w.WriteVarUint(uint64(len(ctx.tryStack.elems)))
+		for _, te := range ctx.tryStack.elems {
+			ectx := te.value.(*exceptionHandlingContext)
+			sw.w.WriteU32LE(uint32(ectx.CatchOffset))
+			sw.w.WriteU32LE(uint32(ectx.FinallyOffset))
+			sw.w.WriteU32LE(uint32(ectx.EndOffset))
+			sw.w.WriteB(byte(ectx.State))
+		}
+		sw.w.WriteBytes(ctx.callingScriptHash.BytesBE())
+		sw.w.WriteB(byte(ctx.callFlag))
+		sw.w.WriteU32LE(uint32(ctx.retCount))
+		sw.w.WriteU32LE(uint32(ctx.depth))
+		// NEF index is shifted by one, zero means no NEF.
+		sw.w.WriteVarUint(uint64(nefIndex(nefs, ctx.NEF) + 1))
+	}
+	if err := sw.writeItem(v.uncaughtException); err != nil {
+		return nil, err
+	}
+	if sw.w.Err != nil {
+		return nil, sw.w.Err
+	}
+	return buf.Bytes(), nil
+}
+
+func stackIndex(list []*Stack, s *Stack) int {
+	for i := range list {
+		if list[i] == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func slotIndex(list []*slot, s *slot) int {
+	for i := range list {
+		if list[i] == s {
+			return i
+		}
+	}
+	return -1
+}
+
+func nefIndex(list []*nef.File, n *nef.File) int {
+	for i := range list {
+		if list[i] == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// checkInterops returns an error listing all interop items reachable from
+// the VM state.
+func (v *VM) checkInterops(stacks []*Stack, statics []*slot) error {
+	var (
+		found   []string
+		visited = make(map[stackitem.Item]bool)
+	)
+	check := func(item stackitem.Item, format string, args ...interface{}) {
+		for _, it := range findInterops(item, visited) {
+			found = append(found, fmt.Sprintf(format+": %T", append(args, it.Value())...))
+		}
+	}
+	for i, s := range stacks {
+		for j, e := range s.elems {
+			check(e.value, "evaluation stack %d item %d", i, j)
+		}
+	}
+	for i, s := range statics {
+		for j, item := range *s {
+			check(item, "static slot %d item %d", i, j)
+		}
+	}
+	for i, e := range v.istack.elems {
+		ctx := e.value.(*Context)
+		for j, item := range ctx.local {
+			check(item, "context %d local %d", i, j)
+		}
+		for j, item := range ctx.arguments {
+			check(item, "context %d argument %d", i, j)
+		}
+	}
+	check(v.uncaughtException, "uncaught exception")
+	if len(found) != 0 {
+		return fmt.Errorf("%w: %s", ErrInteropInSnapshot, strings.Join(found, ", "))
+	}
+	return nil
+}
+
+// findInterops returns all interop items contained in item skipping already
+// visited compound items.
+func findInterops(item stackitem.Item, visited map[stackitem.Item]bool) []*stackitem.Interop {
+	var res []*stackitem.Interop
+	switch t := item.(type) {
+	case *stackitem.Interop:
+		res = append(res, t)
+	case *stackitem.Array, *stackitem.Struct:
+		if visited[t] {
+			return nil
+		}
+		visited[t] = true
+		for _, it := range t.Value().([]stackitem.Item) {
+			res = append(res, findInterops(it, visited)...)
+		}
+	case *stackitem.Map:
+		if visited[t] {
+			return nil
+		}
+		visited[t] = true
+		for _, e := range t.Value().([]stackitem.MapElement) {
+			res = append(res, findInterops(e.Value, visited)...)
+		}
+	}
+	return res
+}
+
+func (sw *snapshotWriter) writeSlot(s slot) error {
+	sw.w.WriteBool(s != nil)
+	if s == nil {
+		return nil
+	}
+	sw.w.WriteVarUint(uint64(len(s)))
+	for _, item := range s {
+		if err := sw.writeItem(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeItem serializes item, compound items (and buffers) are given
+// identifiers on the first occurrence and referenced by them later, so that
+// shared and cyclic items are restored properly.
+func (sw *snapshotWriter) writeItem(item stackitem.Item) error {
+	if item == nil {
+		sw.w.WriteB(snapshotNilTag)
+		return nil
+	}
+	switch item.(type) {
+	case *stackitem.Buffer, *stackitem.Array, *stackitem.Struct, *stackitem.Map:
+		if id, ok := sw.items[item]; ok {
+			sw.w.WriteB(snapshotRefTag)
+			sw.w.WriteVarUint(uint64(id))
+			return nil
+		}
+		sw.items[item] = len(sw.items)
+	}
+	sw.w.WriteB(byte(item.Type()))
+	switch t := item.(type) {
+	case stackitem.Null:
+	case stackitem.Bool:
+		sw.w.WriteBool(bool(t))
+	case *stackitem.BigInteger:
+		sw.w.WriteVarBytes(bigint.ToBytes(t.Big()))
+	case *stackitem.ByteArray, *stackitem.Buffer:
+		sw.w.WriteVarBytes(t.Value().([]byte))
+	case *stackitem.Pointer:
+		id, ok := sw.scripts[t.ScriptHash()]
+		if !ok {
+			return fmt.Errorf("pointer to unknown script %s", t.ScriptHash().StringLE())
+		}
+		sw.w.WriteVarUint(uint64(id))
+		sw.w.WriteU32LE(uint32(t.Position()))
+	case *stackitem.Array, *stackitem.Struct:
+		items := t.Value().([]stackitem.Item)
+		sw.w.WriteVarUint(uint64(len(items)))
+		for _, it := range items {
+			if err := sw.writeItem(it); err != nil {
+				return err
+			}
+		}
+	case *stackitem.Map:
+		elems := t.Value().([]stackitem.MapElement)
+		sw.w.WriteVarUint(uint64(len(elems)))
+		for _, e := range elems {
+			if err := sw.writeItem(e.Key); err != nil {
+				return err
+			}
+			if err := sw.writeItem(e.Value); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrInteropInSnapshot, item.Type())
+	}
+	return sw.w.Err
+}
+
+// LoadSnapshot restores VM execution state from the data created by Snapshot,
+// so that execution can be continued with Run. All of the previously loaded
+// scripts are unloaded, VM handlers (SyscallHandler, LoadToken and price
+// getter) are not changed and should be set up by the caller.
+func (v *VM) LoadSnapshot(data []byte) error {
+	sr := &snapshotReader{r: io.NewBinReaderFromBuf(data)}
+	if ver := sr.r.ReadB(); sr.r.Err == nil && ver != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", ver)
+	}
+	trig := trigger.Type(sr.r.ReadB())
+	state := State(sr.r.ReadB())
+	gasConsumed := int64(sr.r.ReadU64LE())
+	gasLimit := int64(sr.r.ReadU64LE())
+
+	n := sr.readCount(MaxInvocationStackSize)
+	for i := 0; i < n; i++ {
+		var h util.Uint160
+		sr.r.ReadBytes(h[:])
+		sr.hashes = append(sr.hashes, h)
+		sr.scripts = append(sr.scripts, sr.r.ReadVarBytes())
+	}
+	nefs := make([]*nef.File, sr.readCount(MaxInvocationStackSize))
+	for i := range nefs {
+		nefs[i] = new(nef.File)
+		nefs[i].DecodeBinary(sr.r)
+	}
+	stacks := make([][]stackitem.Item, sr.readCount(MaxInvocationStackSize+1))
+	for i := range stacks {
+		stacks[i] = make([]stackitem.Item, sr.readCount(MaxStackSize))
+		for j := range stacks[i] {
+			stacks[i][j] = sr.readItem()
+		}
+	}
+	if sr.r.Err == nil && len(stacks) == 0 {
+		return errors.New("no evaluation stack")
+	}
+	statics := make([][]stackitem.Item, sr.readCount(MaxInvocationStackSize))
+	for i := range statics {
+		statics[i] = sr.readSlot()
+	}
+
+	ctxs := make([]snapshotContext, sr.readCount(MaxInvocationStackSize))
+	if sr.r.Err == nil && len(ctxs) == 0 {
+		return errors.New("no contexts")
+	}
+	for i := range ctxs {
+		ctx := new(Context)
+		script := sr.readIndex(len(sr.scripts))
+		ctx.ip = int(int32(sr.r.ReadU32LE()))
+		ctx.nextip = int(int32(sr.r.ReadU32LE()))
+		ctx.breakPoints = make([]int, sr.readCount(MaxStackSize))
+		for j := range ctx.breakPoints {
+			ctx.breakPoints[j] = int(int32(sr.r.ReadU32LE()))
+		}
+		ctxs[i].estack = sr.readIndex(len(stacks))
+		ctxs[i].static = sr.readIndex(len(statics))
+		ctxs[i].local = sr.readSlot()
+		ctxs[i].arguments = sr.readSlot()
+		initStack(&ctx.tryStack, "exception", nil)
+		tries := sr.readCount(MaxTryNestingDepth)
+		for j := 0; j < tries; j++ {
+			ctx.tryStack.PushItem(&exceptionHandlingContext{
+				CatchOffset:   int(int32(sr.r.ReadU32LE())),
+				FinallyOffset: int(int32(sr.r.ReadU32LE())),
+				EndOffset:     int(int32(sr.r.ReadU32LE())),
+				State:         exceptionHandlingState(sr.r.ReadB()),
+			})
+		}
+		sr.r.ReadBytes(ctx.callingScriptHash[:])
+		ctx.callFlag = callflag.CallFlag(sr.r.ReadB())
+		ctx.retCount = int(int32(sr.r.ReadU32LE()))
+		ctx.depth = int(int32(sr.r.ReadU32LE()))
+		if id := sr.readIndex(len(nefs) + 1); id > 0 {
+			ctx.NEF = nefs[id-1]
+		}
+		if sr.r.Err != nil {
+			break
+		}
+		ctx.prog = sr.scripts[script]
+		ctx.scriptHash = sr.hashes[script]
+		if ctx.ip < 0 || ctx.ip > len(ctx.prog) || ctx.nextip < 0 || ctx.nextip > len(ctx.prog) {
+			return fmt.Errorf("context %d: invalid instruction pointer", i)
+		}
+		ctxs[i].ctx = ctx
+	}
+	exception := sr.readItem()
+	if sr.r.Err != nil {
+		return fmt.Errorf("failed to decode snapshot: %w", sr.r.Err)
+	}
+	if sr.r.Len() != 0 {
+		return errors.New("trailing data in snapshot")
+	}
+
+	// Everything is decoded, now the state can be replaced.
+	v.istack.Clear()
+	v.estack.Clear()
+	v.refs = 0
+	v.invTree = nil
+	v.trigger = trig
+	v.state = state
+	v.gasConsumed = gasConsumed
+	v.GasLimit = gasLimit
+	v.uncaughtException = exception
+
+	estacks := make([]*Stack, len(stacks))
+	for i := range stacks {
+		estacks[i] = newStack("evaluation", &v.refs)
+		for _, item := range stacks[i] {
+			estacks[i].PushItem(item)
+		}
+	}
+	v.estack = estacks[0]
+	slots := make([]*slot, len(statics))
+	for i := range statics {
+		slots[i] = v.newSlot(statics[i])
+	}
+	for _, c := range ctxs {
+		c.ctx.estack = estacks[c.estack]
+		c.ctx.static = slots[c.static]
+		c.ctx.local = *v.newSlot(c.local)
+		c.ctx.arguments = *v.newSlot(c.arguments)
+		v.istack.PushItem(c.ctx)
+	}
+	return nil
+}
+
+// newSlot creates a slot with the given items accounting them in the
+// reference counter.
+func (v *VM) newSlot(items []stackitem.Item) *slot {
+	var s slot
+	if items != nil {
+		s = make(slot, len(items))
+		for i := range items {
+			if items[i] != nil {
+				s.Set(i, items[i], &v.refs)
+			}
+		}
+	}
+	return &s
+}
+
+// readCount reads the number of elements that shouldn't exceed max.
+func (sr *snapshotReader) readCount(max int) int {
+	n := sr.r.ReadVarUint()
+	if sr.r.Err == nil && n > uint64(max) {
+		sr.r.Err = fmt.Errorf("too many elements: %d", n)
+	}
+	if sr.r.Err != nil {
+		return 0
+	}
+	return int(n)
+}
+
+// readIndex reads an index of the table with the given length.
+func (sr *snapshotReader) readIndex(length int) int {
+	id := sr.r.ReadVarUint()
+	if sr.r.Err == nil && id >= uint64(length) {
+		sr.r.Err = fmt.Errorf("invalid index %d", id)
+	}
+	if sr.r.Err != nil {
+		return 0
+	}
+	return int(id)
+}
+
+func (sr *snapshotReader) readSlot() []stackitem.Item {
+	if !sr.r.ReadBool() {
+		return nil
+	}
+	items := make([]stackitem.Item, sr.readCount(MaxStackSize))
+	for i := range items {
+		items[i] = sr.readItem()
+	}
+	return items
+}
+
+// readItem deserializes an item written by writeItem.
+func (sr *snapshotReader) readItem() stackitem.Item {
+	tag := sr.r.ReadB()
+	if sr.r.Err != nil {
+		return nil
+	}
+	switch tag {
+	case snapshotNilTag:
+		return nil
+	case snapshotRefTag:
+		id := sr.readIndex(len(sr.items))
+		if sr.r.Err != nil {
+			return nil
+		}
+		return sr.items[id]
+	}
+	switch typ := stackitem.Type(tag); typ {
+	case stackitem.AnyT:
+		return stackitem.Null{}
+	case stackitem.BooleanT:
+		return stackitem.NewBool(sr.r.ReadBool())
+	case stackitem.IntegerT:
+		b := sr.r.ReadVarBytes(stackitem.MaxBigIntegerSizeBits / 8)
+		return stackitem.NewBigInteger(bigint.FromBytes(b))
+	case stackitem.ByteArrayT:
+		return stackitem.NewByteArray(sr.r.ReadVarBytes(stackitem.MaxSize))
+	case stackitem.BufferT:
+		b := stackitem.NewBuffer(nil)
+		sr.items = append(sr.items, b)
+		*b = sr.r.ReadVarBytes(stackitem.MaxSize)
+		return b
+	case stackitem.PointerT:
+		id := sr.readIndex(len(sr.scripts))
+		pos := int(int32(sr.r.ReadU32LE()))
+		if sr.r.Err != nil {
+			return nil
+		}
+		return stackitem.NewPointerWithHash(pos, sr.scripts[id], sr.hashes[id])
+	case stackitem.ArrayT, stackitem.StructT:
+		var (
+			item  stackitem.Item
+			items = make([]stackitem.Item, sr.readCount(MaxStackSize))
+		)
+		if typ == stackitem.ArrayT {
+			item = stackitem.NewArray(items)
+		} else {
+			item = stackitem.NewStruct(items)
+		}
+		sr.items = append(sr.items, item)
+		for i := range items {
+			items[i] = sr.readItem()
+		}
+		return item
+	case stackitem.MapT:
+		m := stackitem.NewMap()
+		sr.items = append(sr.items, m)
+		n := sr.readCount(MaxStackSize)
+		for i := 0; i < n && sr.r.Err == nil; i++ {
+			k := sr.readItem()
+			val := sr.readItem()
+			if sr.r.Err == nil && stackitem.IsValidMapKey(k) != nil {
+				sr.r.Err = fmt.Errorf("invalid map key: %v", k)
+			}
+			if sr.r.Err != nil {
+				break
+			}
+			m.Add(k, val)
+		}
+		return m
+	default:
+		sr.r.Err = fmt.Errorf("invalid item type %d", tag)
+		return nil
+	}
+}
@@ -0,0 +1,133 @@
+package vm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nspcc-dev/neo-go/pkg/io"
+	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
+	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
+	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVM_Snapshot(t *testing.T) {
+	prog := makeProgram(
+		opcode.INITSSLOT, 1, opcode.NEWARRAY0, opcode.STSFLD0, opcode.PUSH5,
+		opcode.DUP, opcode.JMPIFNOT, 8, opcode.DUP, opcode.CALL, 9, opcode.DEC, opcode.JMP, 0xF9, // loop
+		opcode.DROP, opcode.LDSFLD0, opcode.DUP, opcode.RET,
+		opcode.INITSLOT, 1, 1, opcode.LDARG0, opcode.DUP, opcode.MUL, opcode.STLOC0, // f(x) appends x*x
+		opcode.LDSFLD0, opcode.LDLOC0, opcode.APPEND, opcode.RET)
+	newVM := func() *VM {
+		v := newTestVM()
+		v.GasLimit = 1000
+		v.SetPriceGetter(func(opcode.Opcode, []byte) int64 { return 3 })
+		return v
+	}
+
+	expected := newVM()
+	expected.LoadScript(prog)
+	runVM(t, expected)
+
+	v := newVM()
+	v.LoadScript(prog)
+	v.AddBreakPoint(25)
+	require.NoError(t, v.Run())
+	require.NoError(t, v.Run())
+	require.Equal(t, BreakState, v.State())
+	require.Equal(t, 2, v.istack.Len())
+
+	data, err := v.Snapshot()
+	require.NoError(t, err)
+
+	actual := newVM()
+	actual.GasLimit = -1
+	require.NoError(t, actual.LoadSnapshot(data))
+	require.Equal(t, v.GasConsumed(), actual.GasConsumed())
+	require.Equal(t, int64(1000), actual.GasLimit)
+	require.Equal(t, 25, actual.Context().NextIP())
+	require.Equal(t, v.InvocationDepth(), actual.InvocationDepth())
+	again, err := actual.Snapshot()
+	require.NoError(t, err)
+	require.Equal(t, data, again)
+
+	for _, e := range actual.istack.elems {
+		e.value.(*Context).breakPoints = nil
+	}
+	runVM(t, actual)
+	require.Equal(t, HaltState, actual.State())
+	require.Equal(t, expected.GasConsumed(), actual.GasConsumed())
+	require.Equal(t, expected.estack.ToArray(), actual.estack.ToArray())
+	require.Equal(t, 2, actual.estack.Len())
+	require.True(t, actual.estack.Peek(0).value == actual.estack.Peek(1).value)
+	require.Equal(t, expected.refs, actual.refs)
+
+	t.Run("bad data", func(t *testing.T) {
+		bad := append([]byte{}, data...)
+		bad[0] = snapshotVersion + 1
+		require.Error(t, newTestVM().LoadSnapshot(bad))
+		require.Error(t, newTestVM().LoadSnapshot(data[:len(data)-1]))
+		require.Error(t, newTestVM().LoadSnapshot(append(data, 0)))
+	})
+	t.Run("invocation depth", func(t *testing.T) {
+		v := newTestVM()
+		v.LoadScript(makeProgram(opcode.NOP))
+		v.LoadScriptWithHash(makeProgram(opcode.RET), util.Uint160{1, 2, 3}, callflag.All)
+		require.Equal(t, 2, v.InvocationDepth())
+
+		data, err := v.Snapshot()
+		require.NoError(t, err)
+		actual := newTestVM()
+		require.NoError(t, actual.LoadSnapshot(data))
+		require.Equal(t, 2, actual.InvocationDepth())
+	})
+	t.Run("reference to unknown item", func(t *testing.T) {
+		sr := &snapshotReader{r: io.NewBinReaderFromBuf([]byte{snapshotRefTag, 0})}
+		require.Nil(t, sr.readItem())
+		require.Error(t, sr.r.Err)
+	})
+}
+
+func TestVM_SnapshotCompound(t *testing.T) {
+	v := newTestVM()
+	v.LoadScript(makeProgram(opcode.NOP))
+
+	arr := stackitem.NewArray([]stackitem.Item{stackitem.Make(1)})
+	arr.Append(arr)
+	m := stackitem.NewMap()
+	m.Add(stackitem.Make("arr"), arr)
+	m.Add(stackitem.Make(2), stackitem.NewStruct([]stackitem.Item{stackitem.NewBuffer([]byte{1, 2})}))
+	v.estack.PushItem(m)
+	v.estack.PushItem(arr)
+	v.estack.PushItem(stackitem.NewPointer(1, v.Context().Program()))
+
+	data, err := v.Snapshot()
+	require.NoError(t, err)
+	actual := newTestVM()
+	require.NoError(t, actual.LoadSnapshot(data))
+	require.Equal(t, v.refs, actual.refs)
+	require.Equal(t, 3, actual.estack.Len())
+
+	p := actual.estack.Pop().value.(*stackitem.Pointer)
+	require.Equal(t, 1, p.Position())
+	require.Equal(t, v.Context().ScriptHash(), p.ScriptHash())
+	a := actual.estack.Pop().value.(*stackitem.Array)
+	require.True(t, a == a.Value().([]stackitem.Item)[1])
+	rm := actual.estack.Pop().value.(*stackitem.Map)
+	require.True(t, a == rm.Value().([]stackitem.MapElement)[0].Value)
+	require.Equal(t, []byte{1, 2}, rm.Value().([]stackitem.MapElement)[1].Value.Value().([]stackitem.Item)[0].Value())
+}
+
+func TestVM_SnapshotInterop(t *testing.T) {
+	v := newTestVM()
+	v.LoadScript(makeProgram(opcode.NOP))
+	v.estack.PushItem(stackitem.Make(1))
+	v.estack.PushItem(stackitem.NewArray([]stackitem.Item{stackitem.NewInterop(42)}))
+	v.estack.PushItem(stackitem.NewInterop("str"))
+
+	_, err := v.Snapshot()
+	require.True(t, errors.Is(err, ErrInteropInSnapshot), err)
+	require.Contains(t, err.Error(), "evaluation stack 0 item 1: int")
+	require.Contains(t, err.Error(), "evaluation stack 0 item 2: string")
+}
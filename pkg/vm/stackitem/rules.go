@@ -0,0 +1,153 @@
+package stackitem
+
+// EqualityRule describes how Equals (and thus EQUAL/NOTEQUAL opcodes) compares
+// items of two given types.
+type EqualityRule byte
+
+// This block defines all possible equality rules.
+const (
+	// EqualityNever means that items are never equal, whatever their values
+	// are. This is the case for any pair of different types, e.g. Buffer and
+	// ByteString with the same contents are not equal.
+	EqualityNever EqualityRule = iota
+	// EqualityByValue means that items are equal if their values are equal.
+	EqualityByValue
+	// EqualityByReference means that items are equal only if they are the
+	// same item, items with the same contents are not equal.
+	EqualityByReference
+	// EqualityDeep means that items are equal if all of their elements are
+	// equal, it's used for Struct.
+	EqualityDeep
+)
+
+// ConversionRule describes the behavior of Convert method (and thus CONVERT
+// opcode) for a pair of source and target types.
+type ConversionRule byte
+
+// This block defines all possible conversion rules.
+const (
+	// ConversionInvalid means that conversion always fails.
+	ConversionInvalid ConversionRule = iota
+	// ConversionIdentity means that the source item itself is returned.
+	ConversionIdentity
+	// ConversionCopy means that a new item with the same contents is
+	// returned, for compound types elements are copied by reference.
+	ConversionCopy
+	// ConversionValue means that a new item is made from the source item
+	// value, it can fail for some values (like ByteString longer than
+	// allowed for Integer).
+	ConversionValue
+)
+
+// String implements fmt.Stringer interface.
+func (r EqualityRule) String() string {
+	switch r {
+	case EqualityNever:
+		return "Never"
+	case EqualityByValue:
+		return "ByValue"
+	case EqualityByReference:
+		return "ByReference"
+	case EqualityDeep:
+		return "Deep"
+	default:
+		return "INVALID"
+	}
+}
+
+// String implements fmt.Stringer interface.
+func (r ConversionRule) String() string {
+	switch r {
+	case ConversionInvalid:
+		return "Invalid"
+	case ConversionIdentity:
+		return "Identity"
+	case ConversionCopy:
+		return "Copy"
+	case ConversionValue:
+		return "Value"
+	default:
+		return "INVALID"
+	}
+}
+
+// EqualityRuleOf returns the rule used by Equals to compare an item of type a
+// with an item of type b. Anything other than EqualityByValue and
+// EqualityDeep means that the result of comparison doesn't depend on item
+// values, so the compiler can use it to warn about such comparisons. Note
+// that Null has AnyT type and is equal to any other Null.
+func EqualityRuleOf(a, b Type) EqualityRule {
+	if a != b {
+		return EqualityNever
+	}
+	switch a {
+	case AnyT, PointerT, BooleanT, IntegerT, ByteArrayT, InteropT:
+		return EqualityByValue
+	case StructT:
+		return EqualityDeep
+	case BufferT, ArrayT, MapT:
+		return EqualityByReference
+	default:
+		return EqualityNever
+	}
+}
+
+// ConversionRuleOf returns the rule used by Convert to convert an item of
+// type from to type to. Null (AnyT) can be converted to any valid type except
+// AnyT and the result is Null itself.
+func ConversionRuleOf(from, to Type) ConversionRule {
+	if !from.IsValid() || !to.IsValid() {
+		return ConversionInvalid
+	}
+	if from == AnyT {
+		if to == AnyT {
+			return ConversionInvalid
+		}
+		return ConversionIdentity
+	}
+	if from == to {
+		return ConversionIdentity
+	}
+	switch from {
+	case BooleanT, IntegerT, ByteArrayT:
+		switch to {
+		case BooleanT, IntegerT, ByteArrayT:
+			return ConversionValue
+		case BufferT:
+			if from == ByteArrayT {
+				return ConversionCopy
+			}
+			return ConversionValue
+		}
+	case BufferT:
+		switch to {
+		case BooleanT, IntegerT:
+			return ConversionValue
+		case ByteArrayT:
+			return ConversionCopy
+		}
+	case ArrayT, StructT:
+		switch to {
+		case BooleanT:
+			return ConversionValue
+		case ArrayT, StructT:
+			return ConversionCopy
+		}
+	case PointerT, MapT, InteropT:
+		if to == BooleanT {
+			return ConversionValue
+		}
+	}
+	return ConversionInvalid
+}
+
+// IsValidMapKeyType checks whether items of the given type can be used as Map
+// keys. Items of these types are also subject to IsValidMapKey size check.
+func IsValidMapKeyType(t Type) bool {
+	switch t {
+	case BooleanT, IntegerT, ByteArrayT:
+		return true
+	default:
+		return false
+	}
+}
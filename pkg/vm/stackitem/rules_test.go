@@ -0,0 +1,169 @@
+package stackitem
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var allTypes = []Type{AnyT, PointerT, BooleanT, IntegerT, ByteArrayT, BufferT, ArrayT, StructT, MapT, InteropT}
+
+// newRulesSample returns a new item of the given type, items returned for the
+// same type are different, but have the same value.
+func newRulesSample(t Type) Item {
+	switch t {
+	case AnyT:
+		return Null{}
+	case PointerT:
+		return NewPointer(1, []byte{1, 2, 3})
+	case BooleanT:
+		return NewBool(true)
+	case IntegerT:
+		return NewBigInteger(big.NewInt(1))
+	case ByteArrayT:
+		return NewByteArray([]byte{1})
+	case BufferT:
+		return NewBuffer([]byte{1})
+	case ArrayT:
+		return NewArray([]Item{NewBigInteger(big.NewInt(1))})
+	case StructT:
+		return NewStruct([]Item{NewBigInteger(big.NewInt(1))})
+	case MapT:
+		return NewMapWithValue([]MapElement{{Key: NewBigInteger(big.NewInt(1)), Value: NewBool(true)}})
+	case InteropT:
+		return NewInterop(1)
+	}
+	panic("unknown type")
+}
+
+func TestEqualityRuleOf(t *testing.T) {
+	for _, ta := range allTypes {
+		for _, tb := range allTypes {
+			a, b := newRulesSample(ta), newRulesSample(tb)
+			rule := EqualityRuleOf(ta, tb)
+			name := ta.String() + "/" + tb.String()
+			switch rule {
+			case EqualityNever:
+				require.NotEqual(t, ta, tb, name)
+				require.False(t, a.Equals(b), name)
+				require.False(t, b.Equals(a), name)
+			case EqualityByValue, EqualityDeep:
+				require.True(t, a.Equals(b), name)
+				require.True(t, b.Equals(a), name)
+			case EqualityByReference:
+				require.True(t, a.Equals(a), name)
+				require.False(t, a.Equals(b), name)
+				require.False(t, b.Equals(a), name)
+			default:
+				t.Fatalf("unexpected rule %s for %s", rule, name)
+			}
+		}
+	}
+	require.Equal(t, EqualityNever, EqualityRuleOf(InvalidT, InvalidT))
+
+	t.Run("Buffer/ByteString", func(t *testing.T) {
+		ba := NewByteArray([]byte{1, 2, 3})
+		buf := NewBuffer([]byte{1, 2, 3})
+		require.False(t, ba.Equals(buf))
+		require.False(t, buf.Equals(ba))
+		require.False(t, buf.Equals(NewBuffer([]byte{1, 2, 3})))
+		require.True(t, buf.Equals(buf))
+
+		converted, err := buf.Convert(ByteArrayT)
+		require.NoError(t, err)
+		require.True(t, ba.Equals(converted))
+		require.True(t, converted.Equals(ba))
+	})
+	t.Run("Integer/ByteString", func(t *testing.T) {
+		for _, tc := range []struct {
+			i int64
+			b []byte
+		}{{0, []byte{}}, {1, []byte{1}}, {-1, []byte{0xff}}, {128, []byte{0x80, 0}}, {-128, []byte{0x80}}} {
+			i, b := NewBigInteger(big.NewInt(tc.i)), NewByteArray(tc.b)
+			require.False(t, i.Equals(b))
+			require.False(t, b.Equals(i))
+
+			converted, err := i.Convert(ByteArrayT)
+			require.NoError(t, err)
+			require.True(t, b.Equals(converted), tc.i)
+			converted, err = b.Convert(IntegerT)
+			require.NoError(t, err)
+			require.True(t, i.Equals(converted), tc.i)
+		}
+	})
+}
+
+func TestConversionRuleOf(t *testing.T) {
+	targets := append([]Type{InvalidT}, allTypes...)
+	for _, from := range allTypes {
+		for _, to := range targets {
+			item := newRulesSample(from)
+			res, err := item.Convert(to)
+			rule := ConversionRuleOf(from, to)
+			name := from.String() + "/" + to.String()
+			switch rule {
+			case ConversionInvalid:
+				require.Error(t, err, name)
+			case ConversionIdentity:
+				require.NoError(t, err, name)
+				require.True(t, res == item, name)
+			case ConversionCopy:
+				require.NoError(t, err, name)
+				require.Equal(t, to, res.Type(), name)
+				require.Equal(t, item.Value(), res.Value(), name)
+			case ConversionValue:
+				require.NoError(t, err, name)
+				require.Equal(t, to, res.Type(), name)
+			default:
+				t.Fatalf("unexpected rule %s for %s", rule, name)
+			}
+		}
+	}
+	require.Equal(t, ConversionInvalid, ConversionRuleOf(InvalidT, BooleanT))
+
+	t.Run("boundary", func(t *testing.T) {
+		maxInt := make([]byte, MaxBigIntegerSizeBits/8)
+		for _, item := range []Item{NewByteArray(maxInt), NewBuffer(maxInt)} {
+			_, err := item.Convert(IntegerT)
+			require.NoError(t, err, item.Type())
+		}
+		tooBig := make([]byte, MaxBigIntegerSizeBits/8+1)
+		for _, item := range []Item{NewByteArray(tooBig), NewBuffer(tooBig)} {
+			_, err := item.Convert(IntegerT)
+			require.ErrorIs(t, err, ErrTooBig, item.Type())
+		}
+		_, err := NewByteArray(tooBig).Convert(BooleanT)
+		require.ErrorIs(t, err, ErrTooBig)
+	})
+	t.Run("Buffer copy", func(t *testing.T) {
+		ba := NewByteArray([]byte{1, 2, 3})
+		res, err := ba.Convert(BufferT)
+		require.NoError(t, err)
+		buf := res.(*Buffer)
+		(*buf)[0] = 42
+		require.Equal(t, []byte{1, 2, 3}, ba.Value())
+	})
+}
+
+func TestIsValidMapKeyType(t *testing.T) {
+	for _, typ := range allTypes {
+		item := newRulesSample(typ)
+		err := IsValidMapKey(item)
+		if IsValidMapKeyType(typ) {
+			require.NoError(t, err, typ)
+			require.NotPanics(t, func() { NewMap().Add(item, Null{}) }, typ)
+		} else {
+			require.ErrorIs(t, err, ErrInvalidType, typ)
+			require.Panics(t, func() { NewMap().Add(item, Null{}) }, typ)
+		}
+	}
+	require.NoError(t, IsValidMapKey(NewByteArray(make([]byte, MaxKeySize))))
+	require.ErrorIs(t, IsValidMapKey(NewByteArray(make([]byte, MaxKeySize+1))), ErrTooBig)
+
+	m := NewMap()
+	m.Add(NewByteArray([]byte{1}), NewBool(true))
+	require.True(t, m.Has(NewByteArray([]byte{1})))
+	require.False(t, m.Has(NewBuffer([]byte{1})))
+	require.False(t, m.Has(NewBigInteger(big.NewInt(1))))
+}
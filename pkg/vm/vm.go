@@ -326,9 +326,13 @@ func (v *VM) loadScriptWithCallingHash(b []byte, exe *nef.File, caller util.Uint
 	ctx.scriptHash = hash
 	ctx.callingScriptHash = caller
 	ctx.NEF = exe
+	ctx.depth = 1
+	parent := v.Context()
+	if parent != nil {
+		ctx.depth = parent.depth + 1
+	}
 	if v.invTree != nil {
 		curTree := v.invTree
-		parent := v.Context()
 		if parent != nil {
 			curTree = parent.invTree
 		}
@@ -348,6 +352,18 @@ func (v *VM) Context() *Context {
 	return v.istack.Peek(0).value.(*Context)
 }
 
+// InvocationDepth returns the number of scripts in the current invocation
+// chain: 1 for the entry script, incremented by every loaded script (like
+// contract calls, including re-entrant ones). 0 is returned if no program is
+// loaded.
+func (v *VM) InvocationDepth() int {
+	ctx := v.Context()
+	if ctx == nil {
+		return 0
+	}
+	return ctx.depth
+}
+
 // PopResult is used to pop the first item of the evaluation stack. This allows
 // us to test compiler and vm in a bi-directional way.
 func (v *VM) PopResult() interface{} {
@@ -1686,12 +1702,40 @@ func throwUnhandledException(item stackitem.Item) {
 	panic(msg)
 }
 
-// CheckMultisigPar checks if sigs contains sufficient valid signatures.
+// CheckMultisig checks if sigs contains sufficient valid signatures verifying
+// them one by one. Signatures must be given in the same order as the keys they
+// were made with, the check stops as soon as there are not enough keys left
+// for the remaining signatures. Keys are only decoded when they're needed, an
+// invalid key causes panic.
+func CheckMultisig(v *VM, curve elliptic.Curve, h []byte, pkeys [][]byte, sigs [][]byte) bool {
+	var k, s int
+	for s < len(sigs) && len(pkeys)-k >= len(sigs)-s {
+		if bytesToPublicKey(pkeys[k], curve).Verify(sigs[s], h) {
+			s++
+		}
+		k++
+	}
+	return s == len(sigs)
+}
+
+// CheckMultisigPar checks if sigs contains sufficient valid signatures
+// verifying them concurrently. Its result is always the same as CheckMultisig
+// one: if any of the keys can't be decoded it falls back to CheckMultisig, so
+// that invalid keys are handled exactly the same way.
 func CheckMultisigPar(v *VM, curve elliptic.Curve, h []byte, pkeys [][]byte, sigs [][]byte) bool {
 	if len(sigs) == 1 {
 		return checkMultisig1(v, curve, h, pkeys, sigs[0])
 	}
 
+	pubs := make([]*keys.PublicKey, len(pkeys))
+	for i := range pkeys {
+		pub, err := keys.NewPublicKeyFromBytes(pkeys[i], curve)
+		if err != nil {
+			return CheckMultisig(v, curve, h, pkeys, sigs)
+		}
+		pubs[i] = pub
+	}
+
 	k1, k2 := 0, len(pkeys)-1
 	s1, s2 := 0, len(sigs)-1
 
@@ -1726,8 +1770,8 @@ func CheckMultisigPar(v *VM, curve elliptic.Curve, h []byte, pkeys [][]byte, sig
 		go worker(tasks, results)
 	}
 
-	tasks <- task{pub: bytesToPublicKey(pkeys[k1], curve), signum: s1}
-	tasks <- task{pub: bytesToPublicKey(pkeys[k2], curve), signum: s2}
+	tasks <- task{pub: pubs[k1], signum: s1}
+	tasks <- task{pub: pubs[k2], signum: s2}
 
 	sigok := true
 	taskCount := 2
@@ -1771,7 +1815,7 @@ loop:
 			nextKey = k2
 		}
 		taskCount++
-		tasks <- task{pub: bytesToPublicKey(pkeys[nextKey], curve), signum: nextSig}
+		tasks <- task{pub: pubs[nextKey], signum: nextSig}
 	}
 
 	close(tasks)
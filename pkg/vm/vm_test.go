@@ -17,6 +17,7 @@ import (
 	"github.com/nspcc-dev/neo-go/pkg/encoding/bigint"
 	"github.com/nspcc-dev/neo-go/pkg/io"
 	"github.com/nspcc-dev/neo-go/pkg/smartcontract/callflag"
+	"github.com/nspcc-dev/neo-go/pkg/util"
 	"github.com/nspcc-dev/neo-go/pkg/vm/emit"
 	"github.com/nspcc-dev/neo-go/pkg/vm/opcode"
 	"github.com/nspcc-dev/neo-go/pkg/vm/stackitem"
@@ -630,6 +631,31 @@ func TestCALL(t *testing.T) {
 	runWithArgs(t, prog, 3)
 }
 
+func TestInvocationDepth(t *testing.T) {
+	script := []byte{
+		byte(opcode.CALL), 3, // CALL shouldn't affect invocation depth.
+		byte(opcode.RET),
+		byte(opcode.SYSCALL), 0, 0, 0, 0,
+		byte(opcode.RET),
+	}
+
+	var depths []int
+	v := newTestVM()
+	v.SyscallHandler = func(v *VM, _ uint32) error {
+		depths = append(depths, v.InvocationDepth())
+		if len(depths) < 3 {
+			v.LoadScriptWithHash(script, util.Uint160{byte(len(depths))}, callflag.All)
+		}
+		return nil
+	}
+	require.Equal(t, 0, v.InvocationDepth())
+	v.LoadScript(script)
+	require.Equal(t, 1, v.InvocationDepth())
+	require.NoError(t, v.Run())
+	require.Equal(t, []int{1, 2, 3}, depths)
+	require.Equal(t, 0, v.InvocationDepth())
+}
+
 func TestNOT(t *testing.T) {
 	prog := makeProgram(opcode.NOT)
 	t.Run("Bool", getTestFuncForVM(prog, true, false))
@@ -643,6 +669,36 @@ func TestNOT(t *testing.T) {
 	t.Run("Buffer1", getTestFuncForVM(prog, false, stackitem.NewBuffer([]byte{1})))
 }
 
+func TestEmitBoolOps(t *testing.T) {
+	for _, a := range []bool{false, true} {
+		for _, b := range []bool{false, true} {
+			for name, tc := range map[string]struct {
+				f        func(*io.BinWriter)
+				expected bool
+			}{
+				"BoolAnd": {emit.BoolAnd, a && b},
+				"BoolOr":  {emit.BoolOr, a || b},
+			} {
+				w := io.NewBufBinWriter()
+				emit.Bool(w.BinWriter, a)
+				emit.Bool(w.BinWriter, b)
+				tc.f(w.BinWriter)
+				require.NoError(t, w.Err)
+				v := load(w.Bytes())
+				runVM(t, v)
+				require.Equal(t, 1, v.estack.Len())
+				require.Equal(t, stackitem.NewBool(tc.expected), v.estack.Pop().value, "%s(%t, %t)", name, a, b)
+			}
+		}
+		w := io.NewBufBinWriter()
+		emit.Bool(w.BinWriter, a)
+		emit.Not(w.BinWriter)
+		v := load(w.Bytes())
+		runVM(t, v)
+		require.Equal(t, stackitem.NewBool(!a), v.estack.Pop().value, "Not(%t)", a)
+	}
+}
+
 // getBigInt returns 2^a+b.
 func getBigInt(a, b int64) *big.Int {
 	p := new(big.Int).Exp(big.NewInt(2), big.NewInt(a), nil)
@@ -977,6 +1033,29 @@ func getTRYTestFunc(result interface{}, tryBlock, catchBlock, finallyBlock []byt
 	}
 }
 
+func TestTryCatchEmit(t *testing.T) {
+	buf := io.NewBufBinWriter()
+	emit.Opcodes(buf.BinWriter, opcode.PUSH1)
+	emit.TryCatch(buf.BinWriter, func(w *io.BinWriter) {
+		emit.Opcodes(w, opcode.PUSH2)
+		emit.String(w, "error")
+		emit.Opcodes(w, opcode.THROW)
+	}, func(w *io.BinWriter) {
+		// The exception is on the stack.
+		emit.String(w, "error")
+		emit.Opcodes(w, opcode.EQUAL, opcode.ASSERT, opcode.PUSH3)
+	})
+	emit.Opcodes(buf.BinWriter, opcode.PUSH4, opcode.RET)
+	require.NoError(t, buf.Err)
+
+	v := load(buf.Bytes())
+	runVM(t, v)
+	require.Equal(t, 4, v.Estack().Len())
+	for i, expected := range []int64{4, 3, 2, 1} {
+		require.Equal(t, big.NewInt(expected), v.Estack().Peek(i).BigInt())
+	}
+}
+
 func TestTRY(t *testing.T) {
 	throw := []byte{byte(opcode.PUSH13), byte(opcode.THROW)}
 	push1 := []byte{byte(opcode.PUSH1)}